@@ -0,0 +1,28 @@
+// Package category is the runtime counterpart of the category generator
+// option. Generated error enums report their coarse-grained category, such
+// as "validation", "auth" or "quota", through a Category method, so
+// middleware can route whole groups of errors (e.g. every auth error triggers
+// a re-login) without enumerating codes.
+package category
+
+import "errors"
+
+// categorizer is implemented by error enums generated with categories.
+type categorizer interface {
+	Category() string
+}
+
+// Of returns the category declared by err's chain, or "" when it declares
+// none.
+func Of(err error) string {
+	var c categorizer
+	if errors.As(err, &c) {
+		return c.Category()
+	}
+	return ""
+}
+
+// Is reports whether err's chain declares the category category.
+func Is(err error, category string) bool {
+	return category != "" && Of(err) == category
+}
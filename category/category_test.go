@@ -0,0 +1,26 @@
+package category
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// testError mimics a generated error enum.
+type testError string
+
+func (e testError) Error() string    { return string(e) }
+func (e testError) Category() string { return "auth" }
+
+func TestOf(t *testing.T) {
+	err := fmt.Errorf("login: %w", testError("token expired"))
+	if got := Of(err); got != "auth" {
+		t.Errorf("Of = %q, want auth", got)
+	}
+	if !Is(err, "auth") || Is(err, "quota") {
+		t.Error("Is should match only the declared category")
+	}
+	if Of(errors.New("plain")) != "" || Is(errors.New("plain"), "") {
+		t.Error("plain errors have no category")
+	}
+}
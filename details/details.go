@@ -0,0 +1,172 @@
+// Package details is the runtime counterpart of the detail_type and
+// message_error generator parameters. Generated constructors attach a typed
+// protobuf message (a google.rpc.QuotaViolation, a service specific detail)
+// to an error, and transports read the messages back with From, e.g. to add
+// them to a gRPC status. Any packs them as google.protobuf.Any for other
+// transports, and clients decode them back with a Types registry.
+package details
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// Error wraps an error with detail messages. It is returned by Wrap and by
+// the generated detail constructors. It is immutable: its messages are copies
+// owned by the Error, and must be treated as read-only by callers of Details
+// and From.
+type Error struct {
+	err     error
+	details []proto.Message
+}
+
+// Wrap returns err carrying deep copies of details, so later changes to the
+// messages do not reach the error shared by other goroutines. Nil messages are
+// dropped. It returns nil when err is nil.
+func Wrap(err error, details ...proto.Message) error {
+	if err == nil {
+		return nil
+	}
+	e := &Error{err: err}
+	for _, d := range details {
+		if d != nil {
+			e.details = append(e.details, proto.Clone(d))
+		}
+	}
+	return e
+}
+
+// Fields returns the populated fields of m as metadata pairs keyed by their
+// proto name, the value of each being its protobuf JSON form, without the
+// quotes of strings: "42", "1.5s", "LIMIT_EXCEEDED", "[\"a\",\"b\"]". The
+// generated typed errors of the message_error parameter attach them with
+// metadata.Wrap, so transports rendering metadata write the fields of the
+// message too. It returns nil for nil messages and messages whose JSON form is
+// not an object, such as the wrapper types.
+func Fields(m proto.Message) map[string]string {
+	if m == nil || !m.ProtoReflect().IsValid() {
+		return nil
+	}
+	b, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(m)
+	if err != nil {
+		return nil
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil || len(raw) == 0 {
+		return nil
+	}
+	fields := make(map[string]string, len(raw))
+	for k, v := range raw {
+		var s string
+		if err := json.Unmarshal(v, &s); err == nil {
+			fields[k] = s
+			continue
+		}
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, v); err != nil {
+			return nil
+		}
+		fields[k] = compact.String()
+	}
+	return fields
+}
+
+// Error returns the message of the wrapped error.
+func (e *Error) Error() string { return e.err.Error() }
+
+// Unwrap returns the wrapped error.
+func (e *Error) Unwrap() error { return e.err }
+
+// Details returns the messages attached directly to e, without those of
+// errors it wraps.
+func (e *Error) Details() []proto.Message { return slices.Clone(e.details) }
+
+// From returns the detail messages attached anywhere along the Unwrap chain
+// of err, outermost first. It returns nil when err carries none.
+func From(err error) []proto.Message {
+	var out []proto.Message
+	for err != nil {
+		if e, ok := err.(*Error); ok {
+			out = append(out, e.details...)
+		}
+		err = errors.Unwrap(err)
+	}
+	return out
+}
+
+// Any returns the detail messages of From packed as google.protobuf.Any,
+// outermost first. Messages that fail to marshal are dropped. It returns nil
+// when err carries none.
+func Any(err error) []*anypb.Any {
+	var out []*anypb.Any
+	for _, d := range From(err) {
+		if a, err := anypb.New(d); err == nil {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+// Types is a registry of the detail message types a client decodes. Unlike
+// the global protobuf registry, it only resolves the types registered with
+// it, so a detail is decoded into a message the client expects or not at all.
+// A Types is safe for concurrent use once registration is done.
+type Types struct {
+	types protoregistry.Types
+}
+
+// NewTypes returns a registry of the types of msgs. It panics when two of
+// them share a full name but not a type.
+func NewTypes(msgs ...proto.Message) *Types {
+	t := &Types{}
+	if err := t.Register(msgs...); err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// Register adds the types of msgs to t. Registering a type again is a no-op;
+// another type of the same full name is an error.
+func (t *Types) Register(msgs ...proto.Message) error {
+	for _, m := range msgs {
+		mt := m.ProtoReflect().Type()
+		if found, err := t.types.FindMessageByName(mt.Descriptor().FullName()); err == nil {
+			if found != mt {
+				return fmt.Errorf("details: conflicting types registered for %s", mt.Descriptor().FullName())
+			}
+			continue
+		}
+		if err := t.types.RegisterMessage(mt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Decode unpacks the details of anys whose type is registered with t, in
+// order. Details of other types are skipped, as sent by a newer server or
+// meant for another client. Nested Any fields resolve with t as well. A
+// detail of a registered type that does not unmarshal is an error.
+func (t *Types) Decode(anys []*anypb.Any) ([]proto.Message, error) {
+	var out []proto.Message
+	for _, a := range anys {
+		mt, err := t.types.FindMessageByURL(a.GetTypeUrl())
+		if err != nil {
+			continue
+		}
+		m := mt.New().Interface()
+		if err := (proto.UnmarshalOptions{Resolver: &t.types}).Unmarshal(a.GetValue(), m); err != nil {
+			return nil, fmt.Errorf("details: decode %s: %w", a.GetTypeUrl(), err)
+		}
+		out = append(out, m)
+	}
+	return out, nil
+}
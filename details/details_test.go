@@ -0,0 +1,105 @@
+package details
+
+import (
+	"errors"
+	"maps"
+	"testing"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestWrapAndFrom(t *testing.T) {
+	base := errors.New("quota exceeded")
+	inner := Wrap(base, wrapperspb.String("inner"))
+	err := Wrap(inner, wrapperspb.String("outer"), nil)
+
+	if err.Error() != "quota exceeded" {
+		t.Errorf("Error() = %q", err.Error())
+	}
+	if !errors.Is(err, base) {
+		t.Error("errors.Is lost the wrapped error")
+	}
+	got := From(err)
+	if len(got) != 2 || !proto.Equal(got[0], wrapperspb.String("outer")) || !proto.Equal(got[1], wrapperspb.String("inner")) {
+		t.Errorf("From = %v", got)
+	}
+	var e *Error
+	if !errors.As(err, &e) || len(e.Details()) != 1 {
+		t.Errorf("Details() = %v, want only the outer message", e.Details())
+	}
+}
+
+func TestWrapCopiesDetails(t *testing.T) {
+	msg := wrapperspb.String("v")
+	err := Wrap(errors.New("x"), msg)
+	msg.Value = "changed"
+	if got := From(err); len(got) != 1 || !proto.Equal(got[0], wrapperspb.String("v")) {
+		t.Errorf("From = %v, want the message as wrapped", got)
+	}
+}
+
+func TestNil(t *testing.T) {
+	if Wrap(nil, wrapperspb.String("x")) != nil {
+		t.Error("Wrap(nil) should be nil")
+	}
+	if From(errors.New("plain")) != nil {
+		t.Error("From of an error without details should be nil")
+	}
+}
+
+func TestAnyAndDecode(t *testing.T) {
+	err := Wrap(errors.New("quota exceeded"), wrapperspb.String("quota"), wrapperspb.Int64(42))
+	anys := Any(err)
+	if len(anys) != 2 {
+		t.Fatalf("Any = %v, want both details", anys)
+	}
+	if Any(errors.New("plain")) != nil {
+		t.Error("Any of an error without details should be nil")
+	}
+
+	types := NewTypes(&wrapperspb.StringValue{}, &wrapperspb.StringValue{})
+	got, decErr := types.Decode(anys)
+	if decErr != nil {
+		t.Fatal(decErr)
+	}
+	if len(got) != 1 || !proto.Equal(got[0], wrapperspb.String("quota")) {
+		t.Errorf("Decode = %v, want only the registered StringValue", got)
+	}
+
+	if err := types.Register(&wrapperspb.Int64Value{}); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := types.Decode(anys); len(got) != 2 || !proto.Equal(got[1], wrapperspb.Int64(42)) {
+		t.Errorf("Decode = %v, want both details once Int64Value is registered", got)
+	}
+
+	anys[0].Value = []byte{0xff}
+	if _, err := types.Decode(anys); err == nil {
+		t.Error("Decode of a malformed detail of a registered type succeeded")
+	}
+}
+
+func TestFields(t *testing.T) {
+	got := Fields(&errdetails.ResourceInfo{ResourceType: "user", ResourceName: "users/42"})
+	if want := map[string]string{"resource_type": "user", "resource_name": "users/42"}; !maps.Equal(got, want) {
+		t.Errorf("Fields(ResourceInfo) = %v, want %v", got, want)
+	}
+	got = Fields(&errdetails.RetryInfo{RetryDelay: durationpb.New(1500 * time.Millisecond)})
+	if want := map[string]string{"retry_delay": "1.500s"}; !maps.Equal(got, want) {
+		t.Errorf("Fields(RetryInfo) = %v, want %v", got, want)
+	}
+	got = Fields(&errdetails.BadRequest{FieldViolations: []*errdetails.BadRequest_FieldViolation{{Field: "id"}}})
+	if want := map[string]string{"field_violations": `[{"field":"id"}]`}; !maps.Equal(got, want) {
+		t.Errorf("Fields(BadRequest) = %v, want %v", got, want)
+	}
+	if got := Fields(wrapperspb.Int64(42)); got != nil {
+		t.Errorf("Fields(Int64Value) = %v, want nil", got)
+	}
+	if got := Fields((*errdetails.ResourceInfo)(nil)); got != nil {
+		t.Errorf("Fields(nil) = %v, want nil", got)
+	}
+}
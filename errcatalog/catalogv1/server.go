@@ -0,0 +1,123 @@
+package catalogv1
+
+import (
+	"cmp"
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/go-sphere/protoc-gen-sphere-errors/errcatalog"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// ErrorCatalogServiceServer is the server API of the ErrorCatalogService.
+type ErrorCatalogServiceServer interface {
+	ListErrors(context.Context, *ListErrorsRequest) (*ListErrorsResponse, error)
+}
+
+// ErrorCatalogService_ServiceDesc is the grpc.ServiceDesc of the
+// ErrorCatalogService, for RegisterErrorCatalogServiceServer and
+// grpc.ServiceRegistrar implementations other than *grpc.Server.
+var ErrorCatalogService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "sphere.errors.catalog.v1.ErrorCatalogService",
+	HandlerType: (*ErrorCatalogServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListErrors",
+			Handler:    listErrorsHandler,
+		},
+	},
+	Metadata: "errcatalog/catalogv1/catalog.proto",
+}
+
+// RegisterErrorCatalogServiceServer registers srv as the ErrorCatalogService
+// of s, e.g. a *grpc.Server.
+func RegisterErrorCatalogServiceServer(s grpc.ServiceRegistrar, srv ErrorCatalogServiceServer) {
+	s.RegisterService(&ErrorCatalogService_ServiceDesc, srv)
+}
+
+// listErrorsHandler decodes a ListErrors request and calls the server,
+// through the interceptor of the gRPC server when it has one.
+func listErrorsHandler(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	in := new(ListErrorsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ErrorCatalogServiceServer).ListErrors(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sphere.errors.catalog.v1.ErrorCatalogService/ListErrors",
+	}
+	return interceptor(ctx, in, info, func(ctx context.Context, req any) (any, error) {
+		return srv.(ErrorCatalogServiceServer).ListErrors(ctx, req.(*ListErrorsRequest))
+	})
+}
+
+// Server is an ErrorCatalogServiceServer serving a fixed list of
+// descriptors, such as the Catalog of generated packages. It is also an
+// http.Handler answering with the ListErrorsResponse as JSON, filtered by the
+// package query parameter, so one value serves tooling over gRPC and HTTP:
+//
+//	srv := catalogv1.NewServer(slices.Concat(userspb.Catalog(), orderspb.Catalog()))
+//	catalogv1.RegisterErrorCatalogServiceServer(grpcServer, srv)
+//	http.Handle("GET /errors", srv)
+type Server struct {
+	errors []*ErrorDescriptor
+}
+
+// NewServer returns a Server listing descriptors, ordered by code, then by
+// enum and value.
+func NewServer(descriptors []errcatalog.Descriptor) *Server {
+	sorted := slices.Clone(descriptors)
+	slices.SortStableFunc(sorted, func(a, b errcatalog.Descriptor) int {
+		return cmp.Or(cmp.Compare(a.Code, b.Code), strings.Compare(a.Enum, b.Enum), strings.Compare(a.Value, b.Value))
+	})
+	s := &Server{errors: make([]*ErrorDescriptor, 0, len(sorted))}
+	for _, d := range sorted {
+		s.errors = append(s.errors, &ErrorDescriptor{
+			Package:     d.Package,
+			Enum:        d.Enum,
+			Value:       d.Value,
+			Code:        d.Code,
+			PublicCode:  d.PublicCode,
+			Status:      d.Status,
+			GrpcCode:    d.GRPCCode,
+			Reason:      d.Reason,
+			Message:     d.Message,
+			Description: d.Description,
+			Deprecated:  d.Deprecated,
+			Source:      d.Source,
+		})
+	}
+	return s
+}
+
+// ListErrors returns the errors of s declared in the proto package of req,
+// or every error when it is empty.
+func (s *Server) ListErrors(_ context.Context, req *ListErrorsRequest) (*ListErrorsResponse, error) {
+	resp := &ListErrorsResponse{}
+	for _, d := range s.errors {
+		if req.GetPackage() == "" || d.Package == req.GetPackage() {
+			resp.Errors = append(resp.Errors, d)
+		}
+	}
+	return resp, nil
+}
+
+// ServeHTTP answers with the ListErrors response for the package query
+// parameter, as protojson with the proto field names of the catalog_out
+// entries.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	resp, _ := s.ListErrors(r.Context(), &ListErrorsRequest{Package: r.URL.Query().Get("package")})
+	body, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(resp)
+	if err != nil {
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_, _ = w.Write(body)
+}
@@ -0,0 +1,70 @@
+package catalogv1
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-sphere/protoc-gen-sphere-errors/errcatalog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+var testDescriptors = []errcatalog.Descriptor{
+	{Package: "orders.v1", Enum: "OrderError", Value: "ORDER_ERROR_OUT_OF_STOCK", Code: 2, Status: 409},
+	{Package: "users.v1", Enum: "UserError", Value: "USER_ERROR_NOT_FOUND", Code: 1, Status: 404, GRPCCode: "NOT_FOUND"},
+}
+
+func TestServer_ListErrors(t *testing.T) {
+	srv := NewServer(testDescriptors)
+	resp, err := srv.ListErrors(context.Background(), &ListErrorsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.GetErrors()) != 2 || resp.GetErrors()[0].GetCode() != 1 || resp.GetErrors()[0].GetGrpcCode() != "NOT_FOUND" {
+		t.Errorf("ListErrors = %v, want both errors ordered by code", resp.GetErrors())
+	}
+	resp, _ = srv.ListErrors(context.Background(), &ListErrorsRequest{Package: "orders.v1"})
+	if len(resp.GetErrors()) != 1 || resp.GetErrors()[0].GetValue() != "ORDER_ERROR_OUT_OF_STOCK" {
+		t.Errorf("ListErrors(orders.v1) = %v", resp.GetErrors())
+	}
+}
+
+func TestServer_ServeHTTP(t *testing.T) {
+	rec := httptest.NewRecorder()
+	NewServer(testDescriptors).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/errors?package=users.v1", nil))
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json; charset=utf-8" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	body := rec.Body.String()
+	if !strings.Contains(body, `"USER_ERROR_NOT_FOUND"`) || !strings.Contains(body, `"grpc_code"`) || strings.Contains(body, "ORDER_ERROR") {
+		t.Errorf("body = %s", body)
+	}
+}
+
+func TestRegisterErrorCatalogServiceServer(t *testing.T) {
+	lis := bufconn.Listen(1 << 16)
+	s := grpc.NewServer()
+	RegisterErrorCatalogServiceServer(s, NewServer(testDescriptors))
+	go func() { _ = s.Serve(lis) }()
+	defer s.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	resp := new(ListErrorsResponse)
+	if err := conn.Invoke(context.Background(), "/sphere.errors.catalog.v1.ErrorCatalogService/ListErrors", &ListErrorsRequest{Package: "users.v1"}, resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.GetErrors()) != 1 || resp.GetErrors()[0].GetCode() != 1 {
+		t.Errorf("ListErrors over gRPC = %v", resp.GetErrors())
+	}
+}
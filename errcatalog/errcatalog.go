@@ -0,0 +1,72 @@
+// Package errcatalog is the runtime counterpart of the embed_catalog generator
+// option. Generated packages embed the catalog of their errors, the entries of
+// the catalog_out output, and return it from Catalog, so a binary can serve
+// its own error documentation:
+//
+//	http.Handle("GET /errors", errcatalog.Handler(slices.Concat(userspb.Catalog(), orderspb.Catalog())))
+package errcatalog
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// Descriptor describes a single error enum value, as an entry of the
+// catalog_out output plus its proto package.
+type Descriptor struct {
+	// Package is the proto package of the enum, e.g. "shared.v1".
+	Package    string `json:"package"`
+	Enum       string `json:"enum"`
+	Value      string `json:"value"`
+	Code       int32  `json:"code"`
+	PublicCode string `json:"public_code,omitempty"`
+	Status     int32  `json:"status"`
+	GRPCCode   string `json:"grpc_code"`
+	Reason     string `json:"reason"`
+	Message    string `json:"message,omitempty"`
+	// Description is the leading comment of the value, meant for engineers
+	// rather than end users.
+	Description string `json:"description,omitempty"`
+	Deprecated  bool   `json:"deprecated,omitempty"`
+	// Source is the proto file and line declaring the value, e.g.
+	// "shared/v1/errors.proto:12".
+	Source string `json:"source"`
+}
+
+// Load decodes the catalog named name in fsys, a JSON array of descriptors.
+func Load(fsys fs.FS, name string) ([]Descriptor, error) {
+	b, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	var descriptors []Descriptor
+	if err := json.Unmarshal(b, &descriptors); err != nil {
+		return nil, fmt.Errorf("errcatalog: decode %s: %w", name, err)
+	}
+	return descriptors, nil
+}
+
+// Handler returns a handler answering every request with descriptors as a
+// JSON object {"errors": [...]}, ordered by code, then by enum and value.
+func Handler(descriptors []Descriptor) http.Handler {
+	sorted := append([]Descriptor{}, descriptors...)
+	slices.SortStableFunc(sorted, func(a, b Descriptor) int {
+		return cmp.Or(cmp.Compare(a.Code, b.Code), strings.Compare(a.Enum, b.Enum), strings.Compare(a.Value, b.Value))
+	})
+	body, err := json.Marshal(struct {
+		Errors []Descriptor `json:"errors"`
+	}{Errors: sorted})
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if err != nil {
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		_, _ = w.Write(body)
+	})
+}
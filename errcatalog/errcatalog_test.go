@@ -0,0 +1,60 @@
+package errcatalog
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoad(t *testing.T) {
+	fsys := fstest.MapFS{
+		"errors.embed.json": {Data: []byte(`[{"package":"tests.basic","enum":"UserError","value":"USER_ERROR_NOT_FOUND","code":2,"status":404,"grpc_code":"NotFound","reason":"user not found","source":"basic_errors.proto"}]`)},
+		"broken.json":       {Data: []byte(`{`)},
+	}
+	descriptors, err := Load(fsys, "errors.embed.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(descriptors) != 1 || descriptors[0].Code != 2 || descriptors[0].Package != "tests.basic" {
+		t.Errorf("Load = %+v", descriptors)
+	}
+	if _, err := Load(fsys, "broken.json"); err == nil {
+		t.Error("expected a decode error")
+	}
+	if _, err := Load(fsys, "missing.json"); err == nil {
+		t.Error("expected a missing file error")
+	}
+}
+
+func TestHandler(t *testing.T) {
+	h := Handler([]Descriptor{
+		{Enum: "OrderError", Value: "ORDER_ERROR_OUT_OF_STOCK", Code: 2},
+		{Enum: "UserError", Value: "USER_ERROR_NOT_FOUND", Code: 1},
+		{Enum: "OrderError", Value: "ORDER_ERROR_GONE", Code: 1},
+	})
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/errors", nil))
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json; charset=utf-8" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	var body struct{ Errors []Descriptor }
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, d := range body.Errors {
+		got = append(got, d.Value)
+	}
+	want := []string{"ORDER_ERROR_GONE", "USER_ERROR_NOT_FOUND", "ORDER_ERROR_OUT_OF_STOCK"}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] || got[2] != want[2] {
+		t.Errorf("order = %v, want %v", got, want)
+	}
+
+	rec = httptest.NewRecorder()
+	Handler(nil).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/errors", nil))
+	if rec.Body.String() != `{"errors":[]}` {
+		t.Errorf("empty catalog = %s", rec.Body.String())
+	}
+}
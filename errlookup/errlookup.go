@@ -0,0 +1,67 @@
+// Package errlookup implements the errlookup command generated with the
+// lookup_cmd option. The generated main imports every package generated with
+// registry=true and calls Run, so engineers can resolve a raw code seen in a
+// log or a client report:
+//
+//	$ errlookup 40401
+//	40401 tests.basic.UserError.USER_ERROR_NOT_FOUND
+//	  status:  404
+//	  reason:  user not found
+//	  message: user does not exist
+//	  source:  basic_errors.proto:22
+package errlookup
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/go-sphere/protoc-gen-sphere-errors/registry"
+)
+
+// Run prints the registered errors matching each code of args to w, every
+// registered error when args is empty. It returns the exit status of the
+// command: 0 on success, 1 when a code is unknown and 2 when an argument is
+// not a code.
+func Run(args []string, w io.Writer) int {
+	all := registry.All()
+	if len(args) == 0 {
+		for _, d := range all {
+			describe(w, d)
+		}
+		return 0
+	}
+	status := 0
+	for _, arg := range args {
+		code, err := strconv.ParseInt(arg, 10, 32)
+		if err != nil {
+			fmt.Fprintf(w, "errlookup: invalid code %q\n", arg)
+			return 2
+		}
+		found := false
+		for _, d := range all {
+			if d.Code == int32(code) {
+				describe(w, d)
+				found = true
+			}
+		}
+		if !found {
+			fmt.Fprintf(w, "errlookup: no error registered for code %d\n", code)
+			status = 1
+		}
+	}
+	return status
+}
+
+// describe writes d to w. Codes shared by several enums print every match.
+func describe(w io.Writer, d registry.ErrorDescriptor) {
+	fmt.Fprintf(w, "%d %s.%s\n", d.Code, d.Enum, d.Value)
+	fmt.Fprintf(w, "  status:  %d\n", d.Status)
+	fmt.Fprintf(w, "  reason:  %s\n", d.Reason)
+	if d.Message != "" {
+		fmt.Fprintf(w, "  message: %s\n", d.Message)
+	}
+	if d.Source != "" {
+		fmt.Fprintf(w, "  source:  %s\n", d.Source)
+	}
+}
@@ -0,0 +1,59 @@
+package errlookup
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-sphere/protoc-gen-sphere-errors/registry"
+)
+
+func init() {
+	registry.Register(
+		registry.ErrorDescriptor{
+			Enum:    "tests.UserError",
+			Value:   "USER_ERROR_NOT_FOUND",
+			Code:    40401,
+			Status:  404,
+			Reason:  "user not found",
+			Message: "user does not exist",
+			Source:  "tests/errors.proto:12",
+		},
+		registry.ErrorDescriptor{Enum: "tests.UserError", Value: "USER_ERROR_INVALID", Code: 40001, Status: 400, Reason: "invalid"},
+	)
+}
+
+func TestRun(t *testing.T) {
+	var b strings.Builder
+	if status := Run([]string{"40401"}, &b); status != 0 {
+		t.Fatalf("Run(40401) = %d, want 0", status)
+	}
+	want := "40401 tests.UserError.USER_ERROR_NOT_FOUND\n" +
+		"  status:  404\n" +
+		"  reason:  user not found\n" +
+		"  message: user does not exist\n" +
+		"  source:  tests/errors.proto:12\n"
+	if b.String() != want {
+		t.Errorf("Run(40401) printed\n%s\nwant\n%s", b.String(), want)
+	}
+}
+
+func TestRun_All(t *testing.T) {
+	var b strings.Builder
+	if status := Run(nil, &b); status != 0 {
+		t.Fatalf("Run() = %d, want 0", status)
+	}
+	out := b.String()
+	if i, j := strings.Index(out, "40001 "), strings.Index(out, "40401 "); i < 0 || j < i {
+		t.Errorf("Run() should list every error ordered by code, got\n%s", out)
+	}
+}
+
+func TestRun_Unknown(t *testing.T) {
+	var b strings.Builder
+	if status := Run([]string{"1"}, &b); status != 1 {
+		t.Errorf("Run(1) = %d, want 1", status)
+	}
+	if status := Run([]string{"x"}, &b); status != 2 {
+		t.Errorf("Run(x) = %d, want 2", status)
+	}
+}
@@ -0,0 +1,96 @@
+package catalog
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/errors"
+	"google.golang.org/protobuf/compiler/protogen"
+	"gopkg.in/yaml.v3"
+)
+
+// Load reads a catalog previously written by catalog_out. The format is taken
+// from the file extension: .json, or .yaml and .yml.
+func Load(name string) (*Catalog, error) {
+	b, err := os.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("read baseline: %w", err)
+	}
+	var c Catalog
+	switch ext := filepath.Ext(name); ext {
+	case ".json":
+		err = json.Unmarshal(b, &c)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(b, &c)
+	default:
+		return nil, fmt.Errorf("invalid baseline %q: unknown extension %q, expected .json, .yaml or .yml", name, ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid baseline %q: %w", name, err)
+	}
+	return &c, nil
+}
+
+// Breaking returns the incompatible changes of current against baseline, the
+// catalog of the same package as published earlier: removed values and values
+// whose code, client code, HTTP status, reason or message changed. New values
+// and deprecations are compatible. current may be nil when the package no
+// longer declares any error.
+func Breaking(baseline, current *Catalog) []string {
+	byName := map[string]*Entry{}
+	if current != nil {
+		for _, e := range current.Errors {
+			byName[e.Enum+"."+e.Value] = e
+		}
+	}
+	var problems []string
+	for _, old := range baseline.Errors {
+		where := baseline.Package + "." + old.Enum + "." + old.Value
+		e, ok := byName[old.Enum+"."+old.Value]
+		if !ok {
+			problems = append(problems, where+" was removed")
+			continue
+		}
+		if e.Code != old.Code {
+			problems = append(problems, fmt.Sprintf("%s changed code from %d to %d", where, old.Code, e.Code))
+		}
+		if e.ClientCode != old.ClientCode {
+			problems = append(problems, fmt.Sprintf("%s changed client code from %d to %d", where, old.ClientCode, e.ClientCode))
+		}
+		if e.Status != old.Status {
+			problems = append(problems, fmt.Sprintf("%s changed status from %d to %d", where, old.Status, e.Status))
+		}
+		if e.Reason != old.Reason {
+			problems = append(problems, fmt.Sprintf("%s changed reason from %q to %q", where, old.Reason, e.Reason))
+		}
+		if e.Message != old.Message {
+			problems = append(problems, fmt.Sprintf("%s changed message from %q to %q", where, old.Message, e.Message))
+		}
+	}
+	return problems
+}
+
+// CheckBaselines compares the errors of the files marked for generation,
+// resolved with config, against baselines. Baselines of proto packages
+// without a file marked for generation are skipped, so each invocation only
+// checks the packages it generates.
+func CheckBaselines(gen *protogen.Plugin, config *errors.Config, baselines []*Catalog) []string {
+	files := GeneratedFiles(gen)
+	generated := map[string]bool{}
+	for _, f := range files {
+		generated[string(f.Desc.Package())] = true
+	}
+	current := map[string]*Catalog{}
+	for _, c := range Build(files, config) {
+		current[c.Package] = c
+	}
+	var problems []string
+	for _, b := range baselines {
+		if generated[b.Package] {
+			problems = append(problems, Breaking(b, current[b.Package])...)
+		}
+	}
+	return problems
+}
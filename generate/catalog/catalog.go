@@ -0,0 +1,187 @@
+// Package catalog implements the machine-readable error catalog output of
+// protoc-gen-sphere-errors. It writes one errors.catalog.json (or .yaml) per
+// proto package, listing every generated error with its code, HTTP status,
+// gRPC code, reason, message and deprecation state.
+package catalog
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/errors"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"gopkg.in/yaml.v3"
+)
+
+// Supported catalog formats.
+const (
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+)
+
+// Catalog describes every error enum declared in one proto package.
+type Catalog struct {
+	Package string   `json:"package" yaml:"package"`
+	Errors  []*Entry `json:"errors" yaml:"errors"`
+
+	// Dir is the output directory of the package: the directory of its first
+	// generated file. Package-level outputs are written there.
+	Dir string `json:"-" yaml:"-"`
+}
+
+// Entry describes a single error enum value.
+type Entry struct {
+	Enum       string `json:"enum" yaml:"enum"`
+	Value      string `json:"value" yaml:"value"`
+	Code       int32  `json:"code" yaml:"code"`
+	PublicCode string `json:"public_code,omitempty" yaml:"public_code,omitempty"`
+	Status     int32  `json:"status" yaml:"status"`
+	GRPCCode   string `json:"grpc_code" yaml:"grpc_code"`
+	// ClientCode is the code clients see in place of Code, shared by the
+	// values of its equivalence class, or 0 when they see Code.
+	ClientCode int32  `json:"client_code,omitempty" yaml:"client_code,omitempty"`
+	Reason     string `json:"reason" yaml:"reason"`
+	Message    string `json:"message,omitempty" yaml:"message,omitempty"`
+	// Description is the leading comment of the value, meant for engineers
+	// rather than end users.
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	Deprecated  bool   `json:"deprecated,omitempty" yaml:"deprecated,omitempty"`
+	// Stability is BETA or EXPERIMENTAL, or empty for stable values.
+	// Experimental values are only listed with IncludeExperimental.
+	Stability string `json:"stability,omitempty" yaml:"stability,omitempty"`
+	// Example is the example error response body of the value, decoded from
+	// its JSON, or nil.
+	Example any `json:"example,omitempty" yaml:"example,omitempty"`
+	// Methods are the full gRPC names of the methods declaring they may
+	// return the value, e.g. "/shop.v1.UserService/GetUser", ordered by
+	// name.
+	Methods []string `json:"methods,omitempty" yaml:"methods,omitempty"`
+	// Source is the proto file and line declaring the value, e.g.
+	// "shared/v1/errors.proto:12".
+	Source string `json:"source" yaml:"source"`
+}
+
+// Build groups the error enums of files, resolved with config, by proto
+// package. Packages are returned sorted by name, so the output does not depend
+// on the order of files in the request; within a package errors keep their
+// declaration order. Experimental values are left out, so their codes are not
+// frozen by baselines, unless config.IncludeExperimental is set. Packages
+// without errors are omitted.
+func Build(files []*protogen.File, config *errors.Config) []*Catalog {
+	var out []*Catalog
+	byPkg := map[string]*Catalog{}
+	methods := map[string][]string{}
+	for method, values := range errors.MethodErrors(files, config) {
+		for _, value := range values {
+			methods[value] = append(methods[value], method)
+		}
+	}
+	for _, m := range methods {
+		slices.Sort(m)
+	}
+	for _, f := range files {
+		for _, ew := range errors.ErrorEnums(f, config) {
+			pkg := string(f.Desc.Package())
+			c, ok := byPkg[pkg]
+			if !ok {
+				c = &Catalog{Package: pkg, Dir: path.Dir(f.GeneratedFilenamePrefix)}
+				byPkg[pkg] = c
+				out = append(out, c)
+			}
+			for _, info := range ew.Errors {
+				if info.Stability == errors.StabilityExperimental && !config.IncludeExperimental {
+					continue
+				}
+				c.Errors = append(c.Errors, &Entry{
+					Enum:        info.Name,
+					Value:       info.Value,
+					Code:        info.Code,
+					PublicCode:  info.PublicCode,
+					Status:      info.Status,
+					GRPCCode:    info.GRPCCode,
+					ClientCode:  info.ClientCode,
+					Reason:      info.Reason,
+					Message:     info.Message,
+					Description: info.Description,
+					Deprecated:  info.Deprecated,
+					Stability:   stability(info.Stability),
+					Source:      info.Source,
+					Example:     example(info.Example),
+					Methods:     methods[string(protoreflect.FullName(ew.FullName).Parent().Append(protoreflect.Name(info.Value)))],
+				})
+			}
+		}
+	}
+	// Packages declaring experimental values only have no errors left.
+	out = slices.DeleteFunc(out, func(c *Catalog) bool { return len(c.Errors) == 0 })
+	slices.SortStableFunc(out, func(a, b *Catalog) int {
+		return strings.Compare(a.Package, b.Package)
+	})
+	return out
+}
+
+// stability returns the Stability of an entry of stability s: empty when
+// stable.
+func stability(s string) string {
+	if s == errors.StabilityStable {
+		return ""
+	}
+	return s
+}
+
+// example decodes the JSON example body s, nil when empty.
+func example(s string) any {
+	var v any
+	if s == "" || json.Unmarshal([]byte(s), &v) != nil {
+		return nil
+	}
+	return v
+}
+
+// Marshal encodes c in the given format.
+func Marshal(c *Catalog, format string) ([]byte, error) {
+	switch format {
+	case FormatJSON:
+		b, err := json.MarshalIndent(c, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(b, '\n'), nil
+	case FormatYAML:
+		return yaml.Marshal(c)
+	default:
+		return nil, fmt.Errorf("invalid catalog format %q, expected %s or %s", format, FormatJSON, FormatYAML)
+	}
+}
+
+// GeneratedFiles returns the files of gen marked for generation.
+func GeneratedFiles(gen *protogen.Plugin) []*protogen.File {
+	var files []*protogen.File
+	for _, f := range gen.Files {
+		if f.Generate {
+			files = append(files, f)
+		}
+	}
+	return files
+}
+
+// GenerateFiles writes one catalog per proto package among the files marked
+// for generation. The catalog is placed next to the first generated file of
+// its package.
+func GenerateFiles(gen *protogen.Plugin, config *errors.Config, format string) error {
+	for _, c := range Build(GeneratedFiles(gen), config) {
+		b, err := Marshal(c, format)
+		if err != nil {
+			return err
+		}
+		g := gen.NewGeneratedFile(path.Join(c.Dir, "errors.catalog."+format), "")
+		if _, err := g.Write(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,308 @@
+package catalog
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/errors"
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/internal/testutil"
+	"google.golang.org/protobuf/compiler/protogen"
+	"gopkg.in/yaml.v3"
+)
+
+func TestBuild(t *testing.T) {
+	plugin := testutil.PluginFromPB(t, "../errors/testdata/pb/basic_errors.pb", "basic_errors.proto")
+	catalogs := Build([]*protogen.File{testutil.FileToGenerate(t, plugin)}, &errors.Config{})
+	if len(catalogs) != 1 {
+		t.Fatalf("len(catalogs) = %d, want 1", len(catalogs))
+	}
+	c := catalogs[0]
+	if c.Package != "tests.basic" {
+		t.Errorf("Package = %q, want tests.basic", c.Package)
+	}
+	if len(c.Errors) != 7 {
+		t.Fatalf("len(Errors) = %d, want 7", len(c.Errors))
+	}
+	got := c.Errors[2]
+	want := Entry{
+		Enum:        "UserError",
+		Value:       "USER_ERROR_NOT_FOUND",
+		Code:        2,
+		Status:      404,
+		GRPCCode:    "NOT_FOUND",
+		Reason:      "user not found",
+		Message:     "user does not exist",
+		Description: "Returned when no user matches the requested ID.",
+		Source:      "basic_errors.proto:22",
+	}
+	if !reflect.DeepEqual(*got, want) {
+		t.Errorf("Errors[2] = %+v, want %+v", *got, want)
+	}
+	if !c.Errors[4].Deprecated {
+		t.Error("expected USER_ERROR_DEFAULTED to be deprecated")
+	}
+}
+
+func TestBuild_NoErrors(t *testing.T) {
+	plugin := testutil.PluginFromPB(t, "../errors/testdata/pb/no_errors.pb", "no_errors.proto")
+	if catalogs := Build([]*protogen.File{testutil.FileToGenerate(t, plugin)}, &errors.Config{}); len(catalogs) != 0 {
+		t.Errorf("expected no catalogs, got %d", len(catalogs))
+	}
+}
+
+func TestBuild_SortedPackages(t *testing.T) {
+	mixed := testutil.PluginFromPB(t, "../errors/testdata/pb/mixed_enums.pb", "mixed_enums.proto")
+	basic := testutil.PluginFromPB(t, "../errors/testdata/pb/basic_errors.pb", "basic_errors.proto")
+	files := []*protogen.File{testutil.FileToGenerate(t, mixed), testutil.FileToGenerate(t, basic)}
+	catalogs := Build(files, &errors.Config{})
+	if len(catalogs) != 2 {
+		t.Fatalf("len(catalogs) = %d, want 2", len(catalogs))
+	}
+	if catalogs[0].Package != "tests.basic" || catalogs[1].Package != "tests.mixed" {
+		t.Errorf("packages = %s, %s, want tests.basic, tests.mixed", catalogs[0].Package, catalogs[1].Package)
+	}
+}
+
+func TestBuild_PublicCode(t *testing.T) {
+	plugin := testutil.PluginFromPB(t, "../errors/testdata/pb/basic_errors.pb", "basic_errors.proto")
+	config := &errors.Config{CodePrefixes: map[string]string{"": "AUTH-"}}
+	catalogs := Build([]*protogen.File{testutil.FileToGenerate(t, plugin)}, config)
+	if got := catalogs[0].Errors[2].PublicCode; got != "AUTH-2" {
+		t.Errorf("PublicCode = %q, want AUTH-2", got)
+	}
+}
+
+func TestBuild_Stability(t *testing.T) {
+	plugin := testutil.PluginFromPB(t, "../errors/testdata/pb/basic_errors.pb", "basic_errors.proto")
+	files := []*protogen.File{testutil.FileToGenerate(t, plugin)}
+	config := &errors.Config{Stabilities: map[string]string{
+		"tests.basic.OrderError":           errors.StabilityBeta,
+		"tests.basic.USER_ERROR_NOT_FOUND": errors.StabilityExperimental,
+	}}
+	c := Build(files, config)[0]
+	if len(c.Errors) != 6 {
+		t.Fatalf("len(Errors) = %d, want 6 without the experimental value", len(c.Errors))
+	}
+	for _, e := range c.Errors {
+		want := ""
+		if e.Enum == "OrderError" {
+			want = errors.StabilityBeta
+		}
+		if e.Stability != want {
+			t.Errorf("%s.Stability = %q, want %q", e.Value, e.Stability, want)
+		}
+	}
+	config.IncludeExperimental = true
+	c = Build(files, config)[0]
+	if len(c.Errors) != 7 || c.Errors[2].Stability != errors.StabilityExperimental {
+		t.Errorf("IncludeExperimental: Errors = %d, Errors[2].Stability = %q", len(c.Errors), c.Errors[2].Stability)
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	c := &Catalog{
+		Package: "tests.basic",
+		Errors:  []*Entry{{Enum: "UserError", Value: "USER_ERROR_NOT_FOUND", Code: 2, Status: 404, Reason: "user not found"}},
+	}
+
+	b, err := Marshal(c, FormatJSON)
+	if err != nil {
+		t.Fatalf("Marshal(json) failed: %v", err)
+	}
+	var fromJSON Catalog
+	if err := json.Unmarshal(b, &fromJSON); err != nil {
+		t.Fatalf("unmarshal json: %v", err)
+	}
+	if fromJSON.Errors[0].Status != 404 {
+		t.Errorf("json round-trip Status = %d, want 404", fromJSON.Errors[0].Status)
+	}
+
+	b, err = Marshal(c, FormatYAML)
+	if err != nil {
+		t.Fatalf("Marshal(yaml) failed: %v", err)
+	}
+	var fromYAML Catalog
+	if err := yaml.Unmarshal(b, &fromYAML); err != nil {
+		t.Fatalf("unmarshal yaml: %v", err)
+	}
+	if fromYAML.Errors[0].Reason != "user not found" {
+		t.Errorf("yaml round-trip Reason = %q", fromYAML.Errors[0].Reason)
+	}
+
+	if _, err := Marshal(c, "xml"); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+}
+
+func TestGenerateFiles(t *testing.T) {
+	plugin := testutil.PluginFromPB(t, "../errors/testdata/pb/basic_errors.pb", "basic_errors.proto")
+	if err := GenerateFiles(plugin, &errors.Config{}, FormatJSON); err != nil {
+		t.Fatalf("GenerateFiles failed: %v", err)
+	}
+	resp := plugin.Response()
+	if len(resp.File) != 1 {
+		t.Fatalf("len(File) = %d, want 1", len(resp.File))
+	}
+	if name := resp.File[0].GetName(); !strings.HasSuffix(name, "testdata/basic/errors.catalog.json") {
+		t.Errorf("catalog file name = %q", name)
+	}
+}
+
+func TestBreaking(t *testing.T) {
+	baseline := &Catalog{
+		Package: "tests.basic",
+		Errors: []*Entry{
+			{Enum: "UserError", Value: "USER_ERROR_NOT_FOUND", Code: 2, ClientCode: 404, Status: 404, Reason: "user not found", Message: "user does not exist"},
+			{Enum: "UserError", Value: "USER_ERROR_GONE", Code: 9, Status: 410, Reason: "gone"},
+		},
+	}
+	current := &Catalog{
+		Package: "tests.basic",
+		Errors: []*Entry{
+			{Enum: "UserError", Value: "USER_ERROR_NOT_FOUND", Code: 3, Status: 410, Reason: "missing", Message: "no such user", Deprecated: true},
+			{Enum: "UserError", Value: "USER_ERROR_NEW", Code: 10, Status: 400, Reason: "new"},
+		},
+	}
+	want := []string{
+		"tests.basic.UserError.USER_ERROR_NOT_FOUND changed code from 2 to 3",
+		"tests.basic.UserError.USER_ERROR_NOT_FOUND changed client code from 404 to 0",
+		"tests.basic.UserError.USER_ERROR_NOT_FOUND changed status from 404 to 410",
+		`tests.basic.UserError.USER_ERROR_NOT_FOUND changed reason from "user not found" to "missing"`,
+		`tests.basic.UserError.USER_ERROR_NOT_FOUND changed message from "user does not exist" to "no such user"`,
+		"tests.basic.UserError.USER_ERROR_GONE was removed",
+	}
+	if got := Breaking(baseline, current); !slices.Equal(got, want) {
+		t.Errorf("Breaking() = %q, want %q", got, want)
+	}
+	if got := Breaking(baseline, baseline); len(got) != 0 {
+		t.Errorf("Breaking(baseline, baseline) = %q, want none", got)
+	}
+	if got := Breaking(baseline, nil); len(got) != 2 {
+		t.Errorf("Breaking(baseline, nil) = %q, want both values removed", got)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	plugin := testutil.PluginFromPB(t, "../errors/testdata/pb/basic_errors.pb", "basic_errors.proto")
+	want := Build([]*protogen.File{testutil.FileToGenerate(t, plugin)}, &errors.Config{})[0]
+	dir := t.TempDir()
+	for _, format := range []string{FormatJSON, FormatYAML} {
+		b, err := Marshal(want, format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		name := filepath.Join(dir, "errors.catalog."+format)
+		if err := os.WriteFile(name, b, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		got, err := Load(name)
+		if err != nil {
+			t.Fatalf("Load(%s): %v", format, err)
+		}
+		if problems := Breaking(got, want); len(problems) != 0 || len(got.Errors) != len(want.Errors) {
+			t.Errorf("Load(%s) did not round-trip: %q", format, problems)
+		}
+	}
+	if _, err := Load(filepath.Join(dir, "errors.catalog.txt")); err == nil {
+		t.Error("expected error for unknown extension")
+	}
+}
+
+func TestCheckBaselines(t *testing.T) {
+	plugin := testutil.PluginFromPB(t, "../errors/testdata/pb/basic_errors.pb", "basic_errors.proto")
+	baselines := []*Catalog{
+		{Package: "tests.basic", Errors: []*Entry{{Enum: "UserError", Value: "USER_ERROR_NOT_FOUND", Code: 2, Status: 410, Reason: "user not found", Message: "user does not exist"}}},
+		{Package: "tests.other", Errors: []*Entry{{Enum: "OtherError", Value: "OTHER_ERROR_GONE", Code: 1}}},
+	}
+	want := []string{"tests.basic.UserError.USER_ERROR_NOT_FOUND changed status from 410 to 404"}
+	if got := CheckBaselines(plugin, &errors.Config{}, baselines); !slices.Equal(got, want) {
+		t.Errorf("CheckBaselines() = %q, want %q", got, want)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	baseline := &Catalog{
+		Package: "tests.basic",
+		Errors: []*Entry{
+			{Enum: "UserError", Value: "USER_ERROR_NOT_FOUND", Code: 2, Status: 404, GRPCCode: "NOT_FOUND", Reason: "user not found"},
+			{Enum: "UserError", Value: "USER_ERROR_GONE", Code: 9, Status: 410, Reason: "gone"},
+		},
+	}
+	current := &Catalog{
+		Package: "tests.basic",
+		Dir:     "testdata/basic",
+		Errors: []*Entry{
+			{Enum: "UserError", Value: "USER_ERROR_NOT_FOUND", Code: 2, Status: 410, GRPCCode: "NOT_FOUND", Reason: "user not found", Deprecated: true},
+			{Enum: "UserError", Value: "USER_ERROR_NEW", Code: 10, Status: 400, Reason: "new | shiny"},
+		},
+	}
+	l := Diff(baseline, current)
+	if l.Package != "tests.basic" || l.Dir != "testdata/basic" {
+		t.Errorf("Diff() package = %q in %q", l.Package, l.Dir)
+	}
+	if len(l.Added) != 1 || l.Added[0].Value != "USER_ERROR_NEW" {
+		t.Errorf("Added = %v, want USER_ERROR_NEW", l.Added)
+	}
+	if len(l.Removed) != 1 || l.Removed[0].Value != "USER_ERROR_GONE" {
+		t.Errorf("Removed = %v, want USER_ERROR_GONE", l.Removed)
+	}
+	if len(l.Modified) != 1 {
+		t.Fatalf("len(Modified) = %d, want 1", len(l.Modified))
+	}
+	want := []FieldChange{
+		{Field: "status", From: "404", To: "410", Breaking: true},
+		{Field: "deprecated", From: "false", To: "true"},
+	}
+	var got []FieldChange
+	for _, f := range l.Modified[0].Fields {
+		got = append(got, *f)
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Modified fields = %v, want %v", got, want)
+	}
+	for _, line := range []string{
+		"# Error changes of tests.basic\n",
+		"## Added\n\n| Error | Code | Status | Reason |\n| --- | --- | --- | --- |\n| `UserError.USER_ERROR_NEW` | 10 | 400 | new \\| shiny |\n",
+		"## Removed\n",
+		"| `UserError.USER_ERROR_NOT_FOUND` | status | 404 | 410 | yes |\n",
+		"| `UserError.USER_ERROR_NOT_FOUND` | deprecated | false | true |  |\n",
+	} {
+		if md := l.Markdown(); !strings.Contains(md, line) {
+			t.Errorf("Markdown() does not contain %q:\n%s", line, md)
+		}
+	}
+
+	if l := Diff(baseline, baseline); !l.Empty() || !strings.Contains(l.Markdown(), "No error changes.") {
+		t.Errorf("Diff(baseline, baseline) = %+v, want no changes", l)
+	}
+	if l := Diff(nil, current); len(l.Added) != 2 {
+		t.Errorf("Diff(nil, current) added %d values, want 2", len(l.Added))
+	}
+}
+
+func TestGenerateChangelogs(t *testing.T) {
+	plugin := testutil.PluginFromPB(t, "../errors/testdata/pb/basic_errors.pb", "basic_errors.proto")
+	baselines := []*Catalog{{Package: "tests.basic", Errors: []*Entry{{Enum: "UserError", Value: "USER_ERROR_REMOVED", Code: 99}}}}
+	if err := GenerateChangelogs(plugin, &errors.Config{}, baselines, ChangelogJSON); err != nil {
+		t.Fatalf("GenerateChangelogs failed: %v", err)
+	}
+	resp := plugin.Response()
+	if len(resp.File) != 1 {
+		t.Fatalf("len(File) = %d, want 1", len(resp.File))
+	}
+	if name := resp.File[0].GetName(); !strings.HasSuffix(name, "testdata/basic/CHANGES.errors.json") {
+		t.Errorf("changelog file name = %q", name)
+	}
+	var l Changelog
+	if err := json.Unmarshal([]byte(resp.File[0].GetContent()), &l); err != nil {
+		t.Fatal(err)
+	}
+	if l.Package != "tests.basic" || len(l.Removed) != 1 || len(l.Added) == 0 {
+		t.Errorf("changelog = %+v, want USER_ERROR_REMOVED removed and the current errors added", l)
+	}
+}
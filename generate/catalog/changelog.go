@@ -0,0 +1,203 @@
+package catalog
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/errors"
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// Supported changelog formats.
+const (
+	ChangelogMarkdown = "markdown"
+	ChangelogJSON     = "json"
+)
+
+// ValidateChangelogFormat reports whether format is a supported changelog
+// format.
+func ValidateChangelogFormat(format string) error {
+	switch format {
+	case ChangelogMarkdown, ChangelogJSON:
+		return nil
+	}
+	return fmt.Errorf("invalid changelog_out %q, expected %s or %s", format, ChangelogMarkdown, ChangelogJSON)
+}
+
+// Changelog lists the changes of the errors of one proto package against its
+// baseline catalog.
+type Changelog struct {
+	Package  string    `json:"package"`
+	Added    []*Entry  `json:"added,omitempty"`
+	Removed  []*Entry  `json:"removed,omitempty"`
+	Modified []*Change `json:"modified,omitempty"`
+
+	// Dir is the output directory of the package, as in Catalog.
+	Dir string `json:"-"`
+}
+
+// Change lists the changed fields of an error value present in both the
+// baseline and the current catalog.
+type Change struct {
+	Enum   string         `json:"enum"`
+	Value  string         `json:"value"`
+	Fields []*FieldChange `json:"fields"`
+}
+
+// FieldChange is a changed field of an error value. Breaking reports whether
+// the change is incompatible, as reported by Breaking.
+type FieldChange struct {
+	Field    string `json:"field"`
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Breaking bool   `json:"breaking,omitempty"`
+}
+
+// Empty reports whether l lists no change.
+func (l *Changelog) Empty() bool {
+	return len(l.Added) == 0 && len(l.Removed) == 0 && len(l.Modified) == 0
+}
+
+// Diff returns the changes of current against baseline, the catalog of the
+// same package as published earlier. Either may be nil, for a package that is
+// new or no longer declares any error. Values keep the declaration order of
+// the catalog they are listed from.
+func Diff(baseline, current *Catalog) *Changelog {
+	l := &Changelog{}
+	old := map[string]*Entry{}
+	if baseline != nil {
+		l.Package = baseline.Package
+		for _, e := range baseline.Errors {
+			old[e.Enum+"."+e.Value] = e
+		}
+	}
+	seen := map[string]bool{}
+	if current != nil {
+		l.Package, l.Dir = current.Package, current.Dir
+		for _, e := range current.Errors {
+			key := e.Enum + "." + e.Value
+			seen[key] = true
+			prev, ok := old[key]
+			if !ok {
+				l.Added = append(l.Added, e)
+				continue
+			}
+			if fields := diffEntry(prev, e); len(fields) > 0 {
+				l.Modified = append(l.Modified, &Change{Enum: e.Enum, Value: e.Value, Fields: fields})
+			}
+		}
+	}
+	if baseline != nil {
+		for _, e := range baseline.Errors {
+			if !seen[e.Enum+"."+e.Value] {
+				l.Removed = append(l.Removed, e)
+			}
+		}
+	}
+	return l
+}
+
+// diffEntry returns the changed fields of e against old.
+func diffEntry(old, e *Entry) []*FieldChange {
+	var fields []*FieldChange
+	add := func(field, from, to string, breaking bool) {
+		if from != to {
+			fields = append(fields, &FieldChange{Field: field, From: from, To: to, Breaking: breaking})
+		}
+	}
+	add("code", strconv.Itoa(int(old.Code)), strconv.Itoa(int(e.Code)), true)
+	add("public_code", old.PublicCode, e.PublicCode, false)
+	add("status", strconv.Itoa(int(old.Status)), strconv.Itoa(int(e.Status)), true)
+	add("grpc_code", old.GRPCCode, e.GRPCCode, false)
+	add("reason", old.Reason, e.Reason, true)
+	add("message", old.Message, e.Message, true)
+	add("deprecated", strconv.FormatBool(old.Deprecated), strconv.FormatBool(e.Deprecated), false)
+	return fields
+}
+
+// Changelogs diffs the errors of the files marked for generation, resolved
+// with config, against baselines: one changelog per generated proto package,
+// sorted by package name. A package without a baseline lists every error as
+// added, and baselines of proto packages without a file marked for generation
+// are skipped.
+func Changelogs(gen *protogen.Plugin, config *errors.Config, baselines []*Catalog) []*Changelog {
+	byPkg := map[string]*Catalog{}
+	for _, b := range baselines {
+		byPkg[b.Package] = b
+	}
+	var out []*Changelog
+	for _, c := range Build(GeneratedFiles(gen), config) {
+		out = append(out, Diff(byPkg[c.Package], c))
+	}
+	return out
+}
+
+// GenerateChangelogs writes the changelog of every proto package among the
+// files marked for generation, CHANGES.errors.md or CHANGES.errors.json in
+// format, next to its catalog.
+func GenerateChangelogs(gen *protogen.Plugin, config *errors.Config, baselines []*Catalog, format string) error {
+	if err := ValidateChangelogFormat(format); err != nil {
+		return err
+	}
+	for _, l := range Changelogs(gen, config, baselines) {
+		var b []byte
+		name := "CHANGES.errors.md"
+		if format == ChangelogJSON {
+			var err error
+			if b, err = json.MarshalIndent(l, "", "  "); err != nil {
+				return err
+			}
+			b = append(b, '\n')
+			name = "CHANGES.errors.json"
+		} else {
+			b = []byte(l.Markdown())
+		}
+		g := gen.NewGeneratedFile(path.Join(l.Dir, name), "")
+		if _, err := g.Write(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Markdown renders l as a Markdown document.
+func (l *Changelog) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Error changes of %s\n", l.Package)
+	if l.Empty() {
+		b.WriteString("\nNo error changes.\n")
+		return b.String()
+	}
+	entries := func(title string, list []*Entry) {
+		if len(list) == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "\n## %s\n\n| Error | Code | Status | Reason |\n| --- | --- | --- | --- |\n", title)
+		for _, e := range list {
+			fmt.Fprintf(&b, "| `%s.%s` | %d | %d | %s |\n", e.Enum, e.Value, e.Code, e.Status, markdownCell(e.Reason))
+		}
+	}
+	entries("Added", l.Added)
+	entries("Removed", l.Removed)
+	if len(l.Modified) > 0 {
+		b.WriteString("\n## Modified\n\n| Error | Field | From | To | Breaking |\n| --- | --- | --- | --- | --- |\n")
+		for _, c := range l.Modified {
+			for _, f := range c.Fields {
+				breaking := ""
+				if f.Breaking {
+					breaking = "yes"
+				}
+				fmt.Fprintf(&b, "| `%s.%s` | %s | %s | %s | %s |\n", c.Enum, c.Value, f.Field, markdownCell(f.From), markdownCell(f.To), breaking)
+			}
+		}
+	}
+	return b.String()
+}
+
+// markdownCell escapes s for a Markdown table cell.
+func markdownCell(s string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(s)
+}
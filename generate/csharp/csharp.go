@@ -0,0 +1,139 @@
+// Package csharp implements the C# output of protoc-gen-sphere-errors. It
+// mirrors every error enum as a C# enum with extension methods returning the
+// code, HTTP status, reason and default message of each value, plus an
+// exception class carrying it, so partner SDKs handle the same errors as the
+// backend.
+package csharp
+
+import (
+	_ "embed"
+	"fmt"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/errors"
+	errtemplate "github.com/go-sphere/protoc-gen-sphere-errors/generate/internal/template"
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+//go:embed csharp.tmpl
+var csharpTemplate string
+
+// fileData is the template root for one generated .cs file.
+type fileData struct {
+	Source    string
+	Namespace string
+	Enums     []*errtemplate.ErrorWrapper
+}
+
+// GenerateFile generates the <prefix>.errors.cs file for file, resolving the
+// errors with config. The namespace is the csharp_namespace option or, when
+// unset, the PascalCased proto package, as protoc names it. It returns a nil
+// GeneratedFile (and nil error) when file declares no error enums.
+func GenerateFile(gen *protogen.Plugin, file *protogen.File, config *errors.Config) (*protogen.GeneratedFile, error) {
+	enums := errors.ErrorEnums(file, config)
+	if len(enums) == 0 {
+		return nil, nil
+	}
+	tmpl, err := template.New("csharp").Funcs(template.FuncMap{
+		"quote":      quote,
+		"memberName": memberName,
+		"doc":        doc,
+	}).Parse(csharpTemplate)
+	if err != nil {
+		return nil, err
+	}
+	ns := file.Proto.GetOptions().GetCsharpNamespace()
+	if ns == "" {
+		ns = namespace(string(file.Desc.Package()))
+	}
+	var buf strings.Builder
+	if err := tmpl.Execute(&buf, &fileData{Source: file.Desc.Path(), Namespace: ns, Enums: enums}); err != nil {
+		return nil, err
+	}
+	g := gen.NewGeneratedFile(file.GeneratedFilenamePrefix+".errors.cs", "")
+	g.P(buf.String())
+	return g, nil
+}
+
+// namespace returns the C# namespace protoc derives from the proto package
+// pkg: every segment PascalCased, underscores dropped, e.g. Shop.V1 for
+// shop.v1. A file without a package gets the Global namespace.
+func namespace(pkg string) string {
+	if pkg == "" {
+		return "Global"
+	}
+	parts := strings.Split(pkg, ".")
+	for i, p := range parts {
+		var b strings.Builder
+		upper := true
+		for _, r := range p {
+			if r == '_' {
+				upper = true
+				continue
+			}
+			if upper {
+				r = unicode.ToUpper(r)
+				upper = false
+			}
+			b.WriteRune(r)
+		}
+		parts[i] = b.String()
+	}
+	return strings.Join(parts, ".")
+}
+
+// memberName returns the PascalCase enum member name of the value valueName
+// of enumName: UserError.USER_ERROR_NOT_FOUND becomes NotFound.
+func memberName(enumName, valueName string) string {
+	name := errors.ValueName(enumName, valueName)
+	if name == "" || unicode.IsDigit(rune(name[0])) {
+		return "_" + name
+	}
+	return name
+}
+
+// doc renders text as an XML documentation summary indented by indent and
+// terminated by a newline. It returns "" for empty text.
+func doc(indent, text string) string {
+	if text == "" {
+		return ""
+	}
+	escape := strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+	var b strings.Builder
+	b.WriteString(indent + "/// <summary>\n")
+	for _, line := range strings.Split(text, "\n") {
+		b.WriteString(strings.TrimRight(indent+"/// "+escape.Replace(line), " ") + "\n")
+	}
+	b.WriteString(indent + "/// </summary>\n")
+	return b.String()
+}
+
+// quote renders s as a C# string literal.
+func quote(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&b, `\u%04x`, r)
+				continue
+			}
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
@@ -0,0 +1,82 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"testing"
+
+	sphereerrors "github.com/go-sphere/errors/sphere/errors"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+// largeRequest returns a plugin for a request of files proto files, each
+// declaring enums error enums of values values with error options, the shape
+// of descriptor sets embedding vendored protos.
+func largeRequest(b *testing.B, files, enums, values int) *protogen.Plugin {
+	b.Helper()
+	req := &pluginpb.CodeGeneratorRequest{}
+	for f := range files {
+		fd := &descriptorpb.FileDescriptorProto{
+			Name:    proto.String(fmt.Sprintf("large/v1/errors%d.proto", f)),
+			Package: proto.String(fmt.Sprintf("large.p%d.v1", f)),
+			Options: &descriptorpb.FileOptions{GoPackage: proto.String(fmt.Sprintf("github.com/example/large/p%d", f))},
+		}
+		for e := range enums {
+			enumOpts := &descriptorpb.EnumOptions{}
+			proto.SetExtension(enumOpts, sphereerrors.E_DefaultStatus, int32(500))
+			enum := &descriptorpb.EnumDescriptorProto{Name: proto.String(fmt.Sprintf("Error%d", e)), Options: enumOpts}
+			for v := range values {
+				opts := &descriptorpb.EnumValueOptions{}
+				proto.SetExtension(opts, sphereerrors.E_Options, &sphereerrors.Error{
+					Status:  404,
+					Reason:  fmt.Sprintf("reason %d", v),
+					Message: fmt.Sprintf("message %d", v),
+				})
+				enum.Value = append(enum.Value, &descriptorpb.EnumValueDescriptorProto{
+					Name:    proto.String(fmt.Sprintf("ERROR%d_VALUE_%d", e, v)),
+					Number:  proto.Int32(int32(v)),
+					Options: opts,
+				})
+			}
+			fd.EnumType = append(fd.EnumType, enum)
+		}
+		req.ProtoFile = append(req.ProtoFile, fd)
+		req.FileToGenerate = append(req.FileToGenerate, fd.GetName())
+	}
+	plugin, err := protogen.Options{}.New(req)
+	if err != nil {
+		b.Fatalf("create plugin: %v", err)
+	}
+	return plugin
+}
+
+func BenchmarkGenerateFile(b *testing.B) {
+	config := &Config{NewErrorsFunc: testConfig.NewErrorsFunc}
+	b.ReportAllocs()
+	for range b.N {
+		// Each iteration gets its own plugin, holding only its own files.
+		b.StopTimer()
+		plugin := largeRequest(b, 20, 20, 20)
+		b.StartTimer()
+		for _, f := range plugin.Files {
+			if _, err := GenerateFile(plugin, f, config); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkRender(b *testing.B) {
+	plugin := largeRequest(b, 1, 1, 200)
+	ew := buildErrorWrapper(plugin.Files[0].Enums[0], &Config{}, "httpx.NewError", "errors.Join")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		if err := ew.Render(io.Discard); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
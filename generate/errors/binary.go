@@ -0,0 +1,42 @@
+package errors
+
+import (
+	"fmt"
+
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/internal/template"
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// Binary encodings of the httperrors body generated per error enum, listed
+// in BinaryFormats.
+const (
+	BinaryFormatCBOR    = "cbor"
+	BinaryFormatMsgpack = "msgpack"
+)
+
+// ValidateBinaryFormats reports whether every format is a supported binary
+// format.
+func ValidateBinaryFormats(formats []string) error {
+	for _, format := range formats {
+		if format != BinaryFormatCBOR && format != BinaryFormatMsgpack {
+			return fmt.Errorf("invalid binary_format %q, expected %s or %s", format, BinaryFormatCBOR, BinaryFormatMsgpack)
+		}
+	}
+	return nil
+}
+
+// qualifyBinary fills in the identifiers of the binary encoding methods of
+// ew, one per format.
+func qualifyBinary(ew *template.ErrorWrapper, g *protogen.GeneratedFile, formats []string) {
+	ew.Binary = &template.BinaryIdents{
+		FromError: g.QualifiedGoIdent(httpErrorsPackage.Ident("FromError")),
+	}
+	for _, format := range formats {
+		switch format {
+		case BinaryFormatCBOR:
+			ew.Binary.CBOR = true
+		case BinaryFormatMsgpack:
+			ew.Binary.Msgpack = true
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package errors
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseCacheTTL parses a cache_ttl parameter of the form "name=DURATION",
+// where name is a fully-qualified enum or enum value name and DURATION a
+// time.ParseDuration string of at least one second, such as 60s or 5m.
+func ParseCacheTTL(s string) (string, time.Duration, error) {
+	name, ttl, ok := strings.Cut(s, "=")
+	name, ttl = strings.TrimSpace(name), strings.TrimSpace(ttl)
+	if !ok || name == "" {
+		return "", 0, fmt.Errorf("invalid cache ttl %q, expected 'name=DURATION'", s)
+	}
+	d, err := time.ParseDuration(ttl)
+	if err != nil || d < time.Second {
+		return "", 0, fmt.Errorf("invalid cache ttl %q, expected a duration of at least 1s", s)
+	}
+	return name, d, nil
+}
+
+// cacheControl returns the Cache-Control header suggested for responses
+// carrying the enum value value of enum, "" when no cache_ttl lists it or its
+// enum.
+func (c *Config) cacheControl(enum, value string) string {
+	ttl, ok := c.CacheTTLs[value]
+	if !ok {
+		ttl, ok = c.CacheTTLs[enum]
+	}
+	if !ok {
+		return ""
+	}
+	return "public, max-age=" + strconv.Itoa(int(ttl/time.Second))
+}
@@ -0,0 +1,82 @@
+package errors
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// categoryPackage is the runtime package the generated category predicates
+// call.
+const categoryPackage = protogen.GoImportPath("github.com/go-sphere/protoc-gen-sphere-errors/category")
+
+// categoryName matches a valid category: lower snake case, so it maps to a Go
+// identifier such as RateLimit for rate_limit.
+var categoryName = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// ParseCategory parses a category parameter of the form "name=category",
+// where name is a fully-qualified enum or enum value name and category a
+// lower snake case name such as "validation".
+func ParseCategory(s string) (string, string, error) {
+	name, category, ok := strings.Cut(s, "=")
+	name, category = strings.TrimSpace(name), strings.TrimSpace(category)
+	if !ok || name == "" || !categoryName.MatchString(category) {
+		return "", "", fmt.Errorf("invalid category %q, expected 'name=category' with a lower snake case category", s)
+	}
+	return name, category, nil
+}
+
+// category returns the category of the enum value value of enum, numbered
+// number, or "" when neither declares one. An enum's category does not apply
+// to its zero value, which conventionally means no error.
+func (c *Config) category(enum, value string, number int32) string {
+	if category, ok := c.Categories[value]; ok {
+		return category
+	}
+	if number == 0 {
+		return ""
+	}
+	return c.Categories[enum]
+}
+
+// generateCategoryHelpers writes the package-level helpers of every category
+// used by the error enums generated into the Go package of file: a
+// <Category>Errors slice of its values and an Is<Category>Error predicate.
+func generateCategoryHelpers(gen *protogen.Plugin, file *protogen.File, g *protogen.GeneratedFile, config *Config) {
+	importPath := config.outputFor(file).importPath
+	members := map[string][]string{}
+	for _, f := range gen.Files {
+		if !f.Generate || config.outputFor(f).importPath != importPath {
+			continue
+		}
+		for _, ew := range ErrorEnums(f, config) {
+			for _, info := range ew.Errors {
+				if info.Category != "" {
+					members[info.Category] = append(members[info.Category], ew.Name+"_"+info.Value)
+				}
+			}
+		}
+	}
+	is := g.QualifiedGoIdent(categoryPackage.Ident("Is"))
+	for _, category := range slices.Sorted(maps.Keys(members)) {
+		name := camelCase(category)
+		g.P("// ", name, "Errors are the error values of this package in the ", category)
+		g.P("// category.")
+		g.P("var ", name, "Errors = []error{")
+		for _, value := range members[category] {
+			g.P(value, ",")
+		}
+		g.P("}")
+		g.P()
+		g.P("// Is", name, "Error reports whether err is or wraps an error in the ", category)
+		g.P("// category, including errors of other packages.")
+		g.P("func Is", name, "Error(err error) bool {")
+		g.P("return ", is, "(err, ", fmt.Sprintf("%q", category), ")")
+		g.P("}")
+		g.P()
+	}
+}
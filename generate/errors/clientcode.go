@@ -0,0 +1,85 @@
+package errors
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// ParseClientCode parses a client_code parameter of the form "name=CODE",
+// where name is a fully-qualified enum or enum value name and CODE the
+// positive code clients see in place of the codes of its values.
+func ParseClientCode(s string) (string, int32, error) {
+	name, code, ok := strings.Cut(s, "=")
+	name = strings.TrimSpace(name)
+	n, err := strconv.ParseInt(strings.TrimSpace(code), 10, 32)
+	if !ok || name == "" || err != nil || n <= 0 {
+		return "", 0, fmt.Errorf("invalid client code %q, expected 'name=CODE' with a positive CODE", s)
+	}
+	return name, int32(n), nil
+}
+
+// clientCodeHelpers reports whether the error enums get ClientCode methods:
+// when ClientCodes lists a name, or OptionsType declares a client_code field.
+func (c *Config) clientCodeHelpers() bool {
+	return len(c.ClientCodes) > 0 || c.custom != nil && c.custom.clientCode != nil
+}
+
+// clientCodeOption returns the client code declared for v by the client_code
+// field of a custom OptionsType, and whether it declares one.
+func (c *Config) clientCodeOption(v *protogen.EnumValue) (int32, bool) {
+	if c.custom == nil || c.custom.value == nil || c.custom.clientCode == nil {
+		return 0, false
+	}
+	ext, ok := c.custom.extension(v.Desc.Options(), c.custom.value)
+	if !ok || !ext.Message().Has(c.custom.clientCode) {
+		return 0, false
+	}
+	return intValue(c.custom.clientCode, ext.Message().Get(c.custom.clientCode)), true
+}
+
+// clientCode returns the code clients see for v, a value of the enum named
+// enum: by the client_code field of a custom OptionsType, which wins, or by
+// ClientCodes naming v or, failing that, its enum, which skips the zero
+// value. It returns 0 when v keeps its own code.
+func (c *Config) clientCode(enum string, v *protogen.EnumValue) int32 {
+	if code, ok := c.clientCodeOption(v); ok {
+		return code
+	}
+	if code, ok := c.ClientCodes[string(v.Desc.FullName())]; ok {
+		return code
+	}
+	if v.Desc.Number() == 0 {
+		return 0
+	}
+	return c.ClientCodes[enum]
+}
+
+// ValidateClientCodes rejects client codes read from the client_code field of
+// a custom OptionsType that are not positive. All offending values are
+// reported together in a single error.
+func ValidateClientCodes(files []*protogen.File, config *Config) error {
+	if config.custom == nil || config.custom.clientCode == nil {
+		return nil
+	}
+	var problems []Diagnostic
+	for _, f := range files {
+		for _, enum := range f.Enums {
+			if _, ok := config.defaultStatus(enum); !ok || !config.selects(enum) {
+				continue
+			}
+			for _, v := range enum.Values {
+				if code, ok := config.clientCodeOption(v); ok && code <= 0 {
+					problems = append(problems, valueDiagnostic(v, "client code %d of %s.%s (%s)", code, enum.Desc.FullName(), v.Desc.Name(), sourceLocation(v)))
+				}
+			}
+		}
+	}
+	if len(problems) > 0 {
+		sortDiagnostics(problems)
+		return &Diagnostics{Summary: "invalid client codes, expected a positive code", Problems: problems}
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package errors
+
+import "google.golang.org/protobuf/compiler/protogen"
+
+// generateConcreteError writes the package-level Error type returned by the
+// constructors of the package with ConcreteReturn, whose With methods enrich
+// it in a chain.
+func generateConcreteError(g *protogen.GeneratedFile) {
+	g.P("// Error is the error returned by the constructors of this package. It wraps")
+	g.P("// the constructed error, so errors.Is and errors.As see through it. Its With")
+	g.P("// methods return a new *Error carrying more details, so callers chain them")
+	g.P("// onto a constructor without a type assertion, as in")
+	g.P("// Join(err).WithField(key, value).")
+	g.P("type Error struct {")
+	g.P("err error")
+	g.P("}")
+	g.P()
+	g.P("// Error returns the message of the wrapped error.")
+	g.P("func (e *Error) Error() string { return e.err.Error() }")
+	g.P()
+	g.P("// Unwrap returns the wrapped error.")
+	g.P("func (e *Error) Unwrap() error { return e.err }")
+	g.P()
+	g.P("// WithMetadata returns e carrying md as structured details, read back by")
+	g.P("// transports with metadata.From.")
+	g.P("func (e *Error) WithMetadata(md map[string]string) *Error {")
+	g.P("return &Error{err: ", g.QualifiedGoIdent(metadataPackage.Ident("Wrap")), "(e.err, md)}")
+	g.P("}")
+	g.P()
+	g.P("// WithField returns e carrying the single detail key=value.")
+	g.P("func (e *Error) WithField(key, value string) *Error {")
+	g.P("return &Error{err: ", g.QualifiedGoIdent(metadataPackage.Ident("WithField")), "(e.err, key, value)}")
+	g.P("}")
+	g.P()
+	g.P("// WithDetails returns e carrying msgs as typed details, read back by")
+	g.P("// transports with details.From.")
+	g.P("func (e *Error) WithDetails(msgs ...", g.QualifiedGoIdent(protoPackage.Ident("Message")), ") *Error {")
+	g.P("return &Error{err: ", g.QualifiedGoIdent(detailsPackage.Ident("Wrap")), "(e.err, msgs...)}")
+	g.P("}")
+	g.P()
+}
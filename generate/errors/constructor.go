@@ -0,0 +1,125 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/internal/template"
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// ParseValueNewErrorsFunc parses a value_new_errors_func parameter of the
+// form 'name=path;ident', name being a fully-qualified enum or enum value
+// whose errors the constructor ident of the Go package path builds instead
+// of NewErrorsFunc.
+func ParseValueNewErrorsFunc(s string) (string, protogen.GoIdent, error) {
+	name, fn, ok := strings.Cut(s, "=")
+	name = strings.TrimSpace(name)
+	ident, err := parseGoIdent(fn)
+	if !ok || name == "" || err != nil {
+		return "", protogen.GoIdent{}, fmt.Errorf("invalid value_new_errors_func %q, expected 'name=path;ident'", s)
+	}
+	return name, ident, nil
+}
+
+// parseGoIdent parses a constructor of the form 'path;ident'.
+func parseGoIdent(s string) (protogen.GoIdent, error) {
+	path, name, ok := strings.Cut(strings.TrimSpace(s), ";")
+	if !ok || path == "" || name == "" || strings.Contains(name, ";") {
+		return protogen.GoIdent{}, fmt.Errorf("invalid constructor %q, expected 'path;ident'", s)
+	}
+	return protogen.GoImportPath(path).Ident(name), nil
+}
+
+// newErrorsFuncOption returns the constructor declared for v by the
+// new_errors_func field of a custom OptionsType, as 'path;ident', or "" when
+// it declares none.
+func (c *Config) newErrorsFuncOption(v *protogen.EnumValue) string {
+	if c.custom == nil || c.custom.value == nil || c.custom.newErrorsFunc == nil {
+		return ""
+	}
+	ext, ok := c.custom.extension(v.Desc.Options(), c.custom.value)
+	if !ok || !ext.Message().Has(c.custom.newErrorsFunc) {
+		return ""
+	}
+	return ext.Message().Get(c.custom.newErrorsFunc).String()
+}
+
+// valueNewErrorsFunc returns the constructor building the errors of v, a
+// value of the enum named enum, in place of NewErrorsFunc: by the
+// new_errors_func field of a custom OptionsType, which wins, or else by
+// ValueNewErrorsFuncs naming v or, failing that, its enum. It reports false
+// when v is built by NewErrorsFunc.
+func (c *Config) valueNewErrorsFunc(enum string, v *protogen.EnumValue) (protogen.GoIdent, bool) {
+	if option := c.newErrorsFuncOption(v); option != "" {
+		ident, err := parseGoIdent(option)
+		return ident, err == nil
+	}
+	if ident, ok := c.ValueNewErrorsFuncs[string(v.Desc.FullName())]; ok {
+		return ident, true
+	}
+	ident, ok := c.ValueNewErrorsFuncs[enum]
+	return ident, ok
+}
+
+// ValidateValueNewErrorsFuncs rejects constructors read from the
+// new_errors_func field of a custom OptionsType not of the form 'path;ident',
+// and constructor overrides of enums whose runtime builds errors with its own
+// adapter (kratos and connect). All offending values are reported together
+// in a single error.
+func ValidateValueNewErrorsFuncs(files []*protogen.File, config *Config) error {
+	var problems []Diagnostic
+	for _, f := range files {
+		runtime := config.runtime(string(f.Desc.Package()))
+		for _, enum := range f.Enums {
+			if _, ok := config.defaultStatus(enum); !ok || !config.selects(enum) {
+				continue
+			}
+			for _, v := range enum.Values {
+				if option := config.newErrorsFuncOption(v); option != "" {
+					if _, err := parseGoIdent(option); err != nil {
+						problems = append(problems, valueDiagnostic(v, "new_errors_func %q of %s.%s (%s), expected 'path;ident'", option, enum.Desc.FullName(), v.Desc.Name(), sourceLocation(v)))
+						continue
+					}
+				}
+				if _, ok := config.valueNewErrorsFunc(string(enum.Desc.FullName()), v); ok && (runtime == RuntimeKratos || runtime == RuntimeConnect || runtime == RuntimeTyped) {
+					problems = append(problems, valueDiagnostic(v, "new_errors_func of %s.%s (%s) is not supported with runtime=%s", enum.Desc.FullName(), v.Desc.Name(), sourceLocation(v), runtime))
+				}
+			}
+		}
+	}
+	if len(problems) > 0 {
+		sortDiagnostics(problems)
+		return &Diagnostics{Summary: "invalid value constructors", Problems: problems}
+	}
+	return nil
+}
+
+// qualifyValueNewErrorsFuncs fills in the constructors of the values of ew,
+// the wrapper of enum, overriding NewErrorsFunc. When any does, the Join
+// helpers call them through the newError method, adapting the default
+// signature unless a custom one already is.
+func qualifyValueNewErrorsFuncs(ew *template.ErrorWrapper, enum *protogen.Enum, g *protogen.GeneratedFile, config *Config) {
+	byNumber := map[int32]*protogen.EnumValue{}
+	for _, v := range enum.Values {
+		if _, ok := byNumber[int32(v.Desc.Number())]; !ok {
+			byNumber[int32(v.Desc.Number())] = v
+		}
+	}
+	overrides := false
+	for _, info := range ew.Errors {
+		if ident, ok := config.valueNewErrorsFunc(ew.FullName, byNumber[info.Number]); ok {
+			info.NewErrorsFunc = g.QualifiedGoIdent(ident)
+			overrides = true
+		}
+	}
+	if !overrides || ew.Adapter != nil {
+		return
+	}
+	ew.Adapter = &template.AdapterIdents{
+		Runtime: RuntimeHTTPX,
+		New:     ew.NewErrorsFunc,
+		Args:    strings.Join(defaultNewErrorsArgs, ", "),
+	}
+	ew.NewErrorsFunc = adapterNewErrorsFunc
+}
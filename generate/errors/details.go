@@ -0,0 +1,118 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/internal/template"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// detailsPackage is the runtime package the generated detail constructors
+// attach their typed detail with.
+const detailsPackage = protogen.GoImportPath("github.com/go-sphere/protoc-gen-sphere-errors/details")
+
+// ParseDetailType parses a detail_type parameter of the form
+// "proto.package.VALUE=proto.package.Message".
+func ParseDetailType(s string) (string, string, error) {
+	value, message, ok := strings.Cut(s, "=")
+	value, message = strings.TrimSpace(value), strings.TrimSpace(message)
+	if !ok || value == "" || message == "" {
+		return "", "", fmt.Errorf("invalid detail type %q, expected 'proto.package.VALUE=proto.package.Message'", s)
+	}
+	return value, message, nil
+}
+
+// qualifyDetails fills in the detail type identifiers of ew, so the template
+// emits a <GoName>Error(d *Message) constructor per value with a detail type.
+// The detail messages must be declared in a file of the request.
+func qualifyDetails(gen *protogen.Plugin, ew *template.ErrorWrapper, g *protogen.GeneratedFile) error {
+	for _, info := range append(ew.Errors[:len(ew.Errors):len(ew.Errors)], ew.Aliases...) {
+		if info.DetailType == "" {
+			continue
+		}
+		m := findMessage(gen.Files, protoreflect.FullName(info.DetailType))
+		if m == nil {
+			return fmt.Errorf("detail type %s of %s.%s is not a message of this request; import the file declaring it", info.DetailType, ew.FullName, info.Value)
+		}
+		info.DetailIdent = g.QualifiedGoIdent(m.GoIdent)
+		ew.DetailWrap = g.QualifiedGoIdent(detailsPackage.Ident("Wrap"))
+	}
+	return nil
+}
+
+// findMessage returns the message named name declared in any of files, or
+// nil.
+func findMessage(files []*protogen.File, name protoreflect.FullName) *protogen.Message {
+	var find func([]*protogen.Message) *protogen.Message
+	find = func(messages []*protogen.Message) *protogen.Message {
+		for _, m := range messages {
+			if m.Desc.FullName() == name {
+				return m
+			}
+			if nested := find(m.Messages); nested != nil {
+				return nested
+			}
+		}
+		return nil
+	}
+	for _, f := range files {
+		if m := find(f.Messages); m != nil {
+			return m
+		}
+	}
+	return nil
+}
+
+// anyPackage is the package of google.protobuf.Any, which the generated
+// detail accessors pack details into.
+const anyPackage = protogen.GoImportPath("google.golang.org/protobuf/types/known/anypb")
+
+// generateAnyDetails writes the package-level DetailTypes registry of the
+// detail types of the error values generated into the Go package of file,
+// with the Details and DecodeDetails accessors packing and unpacking details
+// as google.protobuf.Any.
+func generateAnyDetails(gen *protogen.Plugin, file *protogen.File, g *protogen.GeneratedFile, config *Config) {
+	var messages []protogen.GoIdent
+	seen := map[protogen.GoIdent]bool{}
+	for _, f := range packageFiles(gen, file, config) {
+		for _, ew := range ErrorEnums(f, config) {
+			for _, info := range append(ew.Errors[:len(ew.Errors):len(ew.Errors)], ew.Aliases...) {
+				if info.DetailType == "" {
+					continue
+				}
+				// qualifyDetails already rejected detail types missing
+				// from the request.
+				if m := findMessage(gen.Files, protoreflect.FullName(info.DetailType)); m != nil && !seen[m.GoIdent] {
+					seen[m.GoIdent] = true
+					messages = append(messages, m.GoIdent)
+				}
+			}
+		}
+	}
+	anyType := g.QualifiedGoIdent(anyPackage.Ident("Any"))
+	g.P("// DetailTypes is the registry of the detail types of the errors of this")
+	g.P("// package, decoding the details a client receives packed as")
+	g.P("// google.protobuf.Any. Register the types of further details attached with")
+	g.P("// details.Wrap before decoding.")
+	g.P("var DetailTypes = ", g.QualifiedGoIdent(detailsPackage.Ident("NewTypes")), "(")
+	for _, m := range messages {
+		g.P("&", g.QualifiedGoIdent(m), "{},")
+	}
+	g.P(")")
+	g.P()
+	g.P("// Details returns the detail messages attached anywhere in the chain of err")
+	g.P("// packed as google.protobuf.Any, outermost first, for transports carrying")
+	g.P("// them outside of a gRPC status. It returns nil when err carries none.")
+	g.P("func Details(err error) []*", anyType, " {")
+	g.P("return ", g.QualifiedGoIdent(detailsPackage.Ident("Any")), "(err)")
+	g.P("}")
+	g.P()
+	g.P("// DecodeDetails unpacks the details of anys whose type is registered with")
+	g.P("// DetailTypes, skipping the others.")
+	g.P("func DecodeDetails(anys []*", anyType, ") ([]", g.QualifiedGoIdent(protoPackage.Ident("Message")), ", error) {")
+	g.P("return DetailTypes.Decode(anys)")
+	g.P("}")
+	g.P()
+}
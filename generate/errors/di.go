@@ -0,0 +1,95 @@
+package errors
+
+import (
+	"fmt"
+	"path"
+	"slices"
+
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// Dependency injection frameworks supported by GenerateDI.
+const (
+	DIWire = "wire"
+	DIFx   = "fx"
+)
+
+// Import paths used by the generated dependency injection glue.
+const (
+	wirePackage = protogen.GoImportPath("github.com/google/wire")
+	fxPackage   = protogen.GoImportPath("go.uber.org/fx")
+)
+
+// ValidateDI reports whether kind is a supported dependency injection
+// framework.
+func ValidateDI(kind string) error {
+	switch kind {
+	case "", DIWire, DIFx:
+		return nil
+	}
+	return fmt.Errorf("invalid di %q, expected wire or fx", kind)
+}
+
+// GenerateDI writes errors_di.go into the Go package pkg, named name or after
+// its last path element: the dependency injection glue of kind providing the
+// registry.Registry and the httperrors.Encoder, as a wire ProviderSet or an fx
+// Module. The package imports the Go package of every generated file with
+// error enums, so that their registry init functions run in any binary it is
+// wired into. It requires config.Registry. Nothing is written when no file
+// being generated declares error enums.
+func GenerateDI(gen *protogen.Plugin, config *Config, kind string, pkg protogen.GoImportPath, name protogen.GoPackageName) *protogen.GeneratedFile {
+	var imports []protogen.GoImportPath
+	for _, f := range gen.Files {
+		if !f.Generate || !hasErrorEnums(f.Enums, config) {
+			continue
+		}
+		if importPath := config.outputFor(f).importPath; importPath != pkg && !slices.Contains(imports, importPath) {
+			imports = append(imports, importPath)
+		}
+	}
+	if len(imports) == 0 {
+		return nil
+	}
+	slices.Sort(imports)
+	if name == "" {
+		name = protogen.GoPackageName(path.Base(string(pkg)))
+	}
+	g := gen.NewGeneratedFile(config.goPackageFile(pkg, path.Join(string(pkg), "errors_di.go")), pkg)
+	g.P("// Code generated by ", config.generatedBy(), ". DO NOT EDIT.")
+	g.P("// versions:")
+	g.P("// - protoc             ", protocVersion(gen))
+	g.P()
+	if config.BuildTag != "" {
+		g.P("//go:build ", config.BuildTag)
+		g.P()
+	}
+	g.P("// Package ", name, " wires the sphere error subsystem into ", kind, " applications.")
+	g.P("package ", name)
+	g.P()
+	for _, importPath := range imports {
+		g.Import(importPath)
+	}
+	registry := g.QualifiedGoIdent(registryPackage.Ident("Registry"))
+	encoder := g.QualifiedGoIdent(httpErrorsPackage.Ident("Encoder"))
+	g.P("// NewRegistry returns the error registry, holding the errors of every package")
+	g.P("// generated with this one.")
+	g.P("func NewRegistry() ", registry, " {")
+	g.P("return ", registry, "{}")
+	g.P("}")
+	g.P()
+	g.P("// NewEncoder returns the encoder writing errors as JSON HTTP responses.")
+	g.P("func NewEncoder() ", encoder, " {")
+	g.P("return ", httpEncoder(g, config))
+	g.P("}")
+	g.P()
+	switch kind {
+	case DIWire:
+		g.P("// ProviderSet provides the error registry and encoder to wire injectors.")
+		g.P("var ProviderSet = ", g.QualifiedGoIdent(wirePackage.Ident("NewSet")), "(NewRegistry, NewEncoder)")
+	case DIFx:
+		g.P("// Module provides the error registry and encoder to fx applications.")
+		g.P("var Module = ", g.QualifiedGoIdent(fxPackage.Ident("Module")), "(\"sphere-errors\", ",
+			g.QualifiedGoIdent(fxPackage.Ident("Provide")), "(NewRegistry, NewEncoder))")
+	}
+	return g
+}
@@ -0,0 +1,105 @@
+package errors
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/internal/template"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Diagnostic is one problem found while checking a generation request, with
+// the proto element it concerns when there is one.
+type Diagnostic struct {
+	// File and Line locate the element, Line being 0 when the request
+	// carries no source info.
+	File string `json:"file,omitempty"`
+	Line int    `json:"line,omitempty"`
+	// Enum is the fully-qualified name of the enum concerned, and Value the
+	// name of its value.
+	Enum  string `json:"enum,omitempty"`
+	Value string `json:"value,omitempty"`
+	// Method is the fully-qualified name of the method concerned.
+	Method string `json:"method,omitempty"`
+	// Message describes the problem on its own, element and location
+	// included.
+	Message string `json:"message"`
+}
+
+// Diagnostics is the error of a failed check, reporting every problem it
+// found under a one-line summary. Its text lists the messages of the
+// problems below the summary, one per line.
+type Diagnostics struct {
+	Summary  string       `json:"error"`
+	Problems []Diagnostic `json:"diagnostics"`
+}
+
+func (d *Diagnostics) Error() string {
+	if len(d.Problems) == 0 {
+		return d.Summary
+	}
+	messages := make([]string, len(d.Problems))
+	for i, p := range d.Problems {
+		messages[i] = p.Message
+	}
+	return d.Summary + ":\n  " + strings.Join(messages, "\n  ")
+}
+
+// sortDiagnostics sorts problems by message.
+func sortDiagnostics(problems []Diagnostic) {
+	sort.SliceStable(problems, func(i, j int) bool { return problems[i].Message < problems[j].Message })
+}
+
+// diagnosticf returns a problem concerning no single proto element.
+func diagnosticf(format string, args ...any) Diagnostic {
+	return Diagnostic{Message: fmt.Sprintf(format, args...)}
+}
+
+// valueDiagnostic returns a problem of the enum value v.
+func valueDiagnostic(v *protogen.EnumValue, format string, args ...any) Diagnostic {
+	d := descriptorDiagnostic(v.Desc, format, args...)
+	d.Enum, d.Value = string(v.Desc.Parent().FullName()), string(v.Desc.Name())
+	return d
+}
+
+// enumDiagnostic returns a problem of enum.
+func enumDiagnostic(enum *protogen.Enum, format string, args ...any) Diagnostic {
+	d := descriptorDiagnostic(enum.Desc, format, args...)
+	d.Enum = string(enum.Desc.FullName())
+	return d
+}
+
+// methodDiagnostic returns a problem of method.
+func methodDiagnostic(method *protogen.Method, format string, args ...any) Diagnostic {
+	d := descriptorDiagnostic(method.Desc, format, args...)
+	d.Method = string(method.Desc.FullName())
+	return d
+}
+
+// fileDiagnostic returns a problem of the proto file f.
+func fileDiagnostic(f *protogen.File, format string, args ...any) Diagnostic {
+	return Diagnostic{File: f.Desc.Path(), Message: fmt.Sprintf(format, args...)}
+}
+
+// infoDiagnostic returns a problem of info, a value of ew.
+func infoDiagnostic(ew *template.ErrorWrapper, info *template.ErrorInfo, format string, args ...any) Diagnostic {
+	return Diagnostic{
+		File:    info.SourceFile,
+		Line:    info.SourceLine,
+		Enum:    ew.FullName,
+		Value:   info.Value,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+// descriptorDiagnostic returns a problem located at the declaration of desc.
+func descriptorDiagnostic(desc protoreflect.Descriptor, format string, args ...any) Diagnostic {
+	file := desc.ParentFile()
+	d := Diagnostic{File: file.Path(), Message: fmt.Sprintf(format, args...)}
+	if loc := file.SourceLocations().ByDescriptor(desc); len(loc.Path) > 0 {
+		d.Line = loc.StartLine + 1
+	}
+	return d
+}
@@ -0,0 +1,98 @@
+package errors
+
+import (
+	"encoding/json"
+	"path"
+
+	"github.com/go-sphere/protoc-gen-sphere-errors/errcatalog"
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// Import paths used by the generated embedded catalog.
+const (
+	embedPackage      = protogen.GoImportPath("embed")
+	slicesPackage     = protogen.GoImportPath("slices")
+	syncPackage       = protogen.GoImportPath("sync")
+	errcatalogPackage = protogen.GoImportPath("github.com/go-sphere/protoc-gen-sphere-errors/errcatalog")
+	catalogv1Package  = protogen.GoImportPath("github.com/go-sphere/protoc-gen-sphere-errors/errcatalog/catalogv1")
+)
+
+// EmbeddedCatalogName is the name of the catalog written next to the errors
+// of each Go package with EmbedCatalog.
+const EmbeddedCatalogName = "errors.embed.json"
+
+// embeddedCatalog returns the descriptors of the errors generated into the Go
+// package of file, aliases excluded, in request and declaration order.
+// Experimental values are left out unless IncludeExperimental is set.
+func embeddedCatalog(gen *protogen.Plugin, file *protogen.File, config *Config) []errcatalog.Descriptor {
+	descriptors := []errcatalog.Descriptor{}
+	for _, f := range packageFiles(gen, file, config) {
+		for _, ew := range ErrorEnums(f, config) {
+			for _, info := range ew.Errors {
+				if info.Stability == StabilityExperimental && !config.IncludeExperimental {
+					continue
+				}
+				descriptors = append(descriptors, errcatalog.Descriptor{
+					Package:     string(f.Desc.Package()),
+					Enum:        info.Name,
+					Value:       info.Value,
+					Code:        info.Code,
+					PublicCode:  info.PublicCode,
+					Status:      info.Status,
+					GRPCCode:    info.GRPCCode,
+					Reason:      info.Reason,
+					Message:     info.Message,
+					Description: info.Description,
+					Deprecated:  info.Deprecated,
+					Source:      info.Source,
+				})
+			}
+		}
+	}
+	return descriptors
+}
+
+// generateEmbeddedCatalog writes the catalog of the errors of the Go package
+// of file to EmbeddedCatalogName, in the directory of its generated files, and
+// the package-level Catalog returning it through an embed.FS, plus
+// CatalogService with config.CatalogService.
+func generateEmbeddedCatalog(gen *protogen.Plugin, file *protogen.File, g *protogen.GeneratedFile, config *Config) error {
+	b, err := json.MarshalIndent(embeddedCatalog(gen, file, config), "", "  ")
+	if err != nil {
+		return err
+	}
+	jg := gen.NewGeneratedFile(path.Join(path.Dir(config.outputFor(file).prefix), EmbeddedCatalogName), "")
+	if _, err := jg.Write(append(b, '\n')); err != nil {
+		return err
+	}
+	descriptor := g.QualifiedGoIdent(errcatalogPackage.Ident("Descriptor"))
+	g.P("//go:embed ", EmbeddedCatalogName)
+	g.P("var errorCatalogFS ", g.QualifiedGoIdent(embedPackage.Ident("FS")))
+	g.P()
+	g.P("var errorCatalog = ", g.QualifiedGoIdent(syncPackage.Ident("OnceValue")), "(func() []", descriptor, " {")
+	g.P("descriptors, err := ", g.QualifiedGoIdent(errcatalogPackage.Ident("Load")), "(errorCatalogFS, ", `"`+EmbeddedCatalogName+`"`, ")")
+	g.P("if err != nil {")
+	g.P("panic(err)")
+	g.P("}")
+	g.P("return descriptors")
+	g.P("})")
+	g.P()
+	g.P("// Catalog returns the descriptors of every error of this package, in")
+	g.P("// declaration order, read from the catalog embedded at generation time.")
+	g.P("// Binaries serve their own error documentation with errcatalog.Handler.")
+	g.P("func Catalog() []", descriptor, " {")
+	g.P("return ", g.QualifiedGoIdent(slicesPackage.Ident("Clone")), "(errorCatalog())")
+	g.P("}")
+	g.P()
+	if config.CatalogService {
+		server := g.QualifiedGoIdent(catalogv1Package.Ident("ErrorCatalogServiceServer"))
+		g.P("// CatalogService returns an ErrorCatalogService listing the errors of this")
+		g.P("// package, to mount with catalogv1.RegisterErrorCatalogServiceServer. Serve")
+		g.P("// several packages at once with catalogv1.NewServer over their catalogs.")
+		g.P("func CatalogService() ", server, " {")
+		g.P("return ", g.QualifiedGoIdent(catalogv1Package.Ident("NewServer")), "(errorCatalog())")
+		g.P("}")
+		g.P()
+	}
+	return nil
+}
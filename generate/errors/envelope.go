@@ -0,0 +1,64 @@
+package errors
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// HTTP error envelopes written by the generated framework adapters, gateway
+// handler and dependency injection encoder, selected by HTTPEnvelope. They
+// name the httperrors Envelope constants.
+const (
+	EnvelopeSphere = "sphere"
+	EnvelopeGoogle = "google"
+	EnvelopeAWS    = "aws"
+)
+
+// envelopeIdents are the httperrors constants of the non-default envelopes.
+var envelopeIdents = map[string]string{
+	EnvelopeGoogle: "EnvelopeGoogle",
+	EnvelopeAWS:    "EnvelopeAWS",
+}
+
+// ValidateHTTPEnvelope reports whether envelope is a supported HTTPEnvelope.
+func ValidateHTTPEnvelope(envelope string) error {
+	switch envelope {
+	case "", EnvelopeSphere, EnvelopeGoogle, EnvelopeAWS:
+		return nil
+	}
+	return fmt.Errorf("invalid http_envelope %q, expected %s, %s or %s", envelope, EnvelopeSphere, EnvelopeGoogle, EnvelopeAWS)
+}
+
+// httpEncoder returns the httperrors.Encoder literal writing the envelope of
+// config, e.g. httperrors.Encoder{Envelope: httperrors.EnvelopeGoogle}.
+func httpEncoder(g *protogen.GeneratedFile, config *Config) string {
+	encoder := g.QualifiedGoIdent(httpErrorsPackage.Ident("Encoder"))
+	if ident, ok := envelopeIdents[config.HTTPEnvelope]; ok {
+		return encoder + "{Envelope: " + g.QualifiedGoIdent(httpErrorsPackage.Ident(ident)) + "}"
+	}
+	return encoder + "{}"
+}
+
+// httpEncode returns the function the generated adapters write errors with:
+// httperrors.Encode for the default envelope, the Encode method of
+// httpEncoder otherwise.
+func httpEncode(g *protogen.GeneratedFile, config *Config) string {
+	if _, ok := envelopeIdents[config.HTTPEnvelope]; ok {
+		return httpEncoder(g, config) + ".Encode"
+	}
+	return g.QualifiedGoIdent(httpErrorsPackage.Ident("Encode"))
+}
+
+// envelopeName returns the name of the envelope of config in generated doc
+// comments.
+func envelopeName(config *Config) string {
+	switch config.HTTPEnvelope {
+	case EnvelopeGoogle:
+		return "Google API error envelope"
+	case EnvelopeAWS:
+		return "AWS error envelope"
+	default:
+		return "JSON envelope of httperrors"
+	}
+}
@@ -4,31 +4,741 @@
 package errors
 
 import (
+	"path"
+	"time"
+
 	"google.golang.org/protobuf/compiler/protogen"
 )
 
 // errorsPackage resolves to the standard library "errors" package, used for the
-// errors.Join call in the generated Join helpers.
+// errors.Join call in the generated Join helpers and errors.Is in the sentinel
+// predicates.
 const errorsPackage = protogen.GoImportPath("errors")
 
+// fmtPackage resolves to the standard library "fmt" package, used by the
+// generated Errorf helpers.
+const fmtPackage = protogen.GoImportPath("fmt")
+
+// jsonPackage is the standard library "encoding/json" package, used by the
+// generated <Enum>FromHTTPResponse helpers.
+const jsonPackage = protogen.GoImportPath("encoding/json")
+
+// testingPackage is the standard library "testing" package, used by the
+// generated mapping tests.
+const testingPackage = protogen.GoImportPath("testing")
+
+// registryPackage is the runtime package generated init functions register
+// their error enums with.
+const registryPackage = protogen.GoImportPath("github.com/go-sphere/protoc-gen-sphere-errors/registry")
+
+// metadataPackage is the runtime package the generated WithMetadata,
+// WithField, WithRequestID and WithTraceContext helpers attach details with.
+const metadataPackage = protogen.GoImportPath("github.com/go-sphere/protoc-gen-sphere-errors/metadata")
+
+// stackPackage is the runtime package the constructors generated with
+// WithStack record the call stack with.
+const stackPackage = protogen.GoImportPath("github.com/go-sphere/protoc-gen-sphere-errors/stack")
+
+// contextPackage is the standard library "context" package, used by the
+// generated WithTraceContext helpers.
+const contextPackage = protogen.GoImportPath("context")
+
+// otelTracePackage is the OpenTelemetry trace API the generated
+// WithTraceContext helpers read the span context with.
+const otelTracePackage = protogen.GoImportPath("go.opentelemetry.io/otel/trace")
+
 // Config controls error code generation.
 type Config struct {
-	// NewErrorsFunc is the constructor the generated Join helpers call. It must
-	// have the signature func(status, code int32, message string, err error) error.
+	// NewErrorsFunc is the constructor the generated Join helpers call. By
+	// default it must have the signature
+	// func(status, code int32, message string, err error) error.
 	NewErrorsFunc protogen.GoIdent
+	// NewErrorsArgs, when set, lists the parameters of NewErrorsFunc in order
+	// (ArgContext, ArgStatus, ArgCode, ArgMessage, ArgErr), e.g. ctx, code,
+	// message. The helpers then call it through a generated newError method,
+	// and a context argument adds a JoinContext helper. It may return any type
+	// implementing error.
+	NewErrorsArgs []string
+	// Runtime selects the error runtime the Join helpers construct errors
+	// for: RuntimeHTTPX (the default, using NewErrorsFunc), RuntimeStdlib,
+	// RuntimeKratos, RuntimeConnect or RuntimeTyped.
+	Runtime string
+	// Runtimes override Runtime for the error enums of a proto package, keyed
+	// by proto package.
+	Runtimes map[string]string
+	// MinimalRuntime makes the Join helpers of RuntimeHTTPX packages build a
+	// package-local error type instead of calling NewErrorsFunc, so the
+	// generated code needs no import outside the standard library.
+	MinimalRuntime bool
+	// Template, when non-empty, is a text/template source used instead of the
+	// built-in template. It is executed once per error enum with a
+	// template.ErrorWrapper as its root value.
+	Template string
+	// GRPCStatus adds GetGRPCCode and GRPCStatus methods, making the generated
+	// errors convertible by google.golang.org/grpc/status.FromError. The gRPC
+	// code is derived from the HTTP status.
+	GRPCStatus bool
+	// SentinelErrors adds an Err<GoName> variable and an Is<GoName>(err)
+	// predicate per enum value, e.g. ErrUserNotFound and IsUserNotFound.
+	SentinelErrors bool
+	// KratosCompat adds the Error<GoName>(format, args...) constructors and
+	// Is<GoName>(err) predicates of kratos protoc-gen-go-errors per enum
+	// value, building and matching github.com/go-kratos/kratos/v2/errors
+	// errors, so call sites of services migrating from kratos keep compiling.
+	// It cannot be combined with SentinelErrors, whose predicates share the
+	// names.
+	KratosCompat bool
+	// Registry adds an init function per error enum registering its non-zero
+	// values with the registry runtime package.
+	Registry bool
+	// ErrorCodes adds a typed <Enum>Code type with one constant per value and
+	// an ErrorCode method. The type implements fmt.Stringer, json.Marshaler
+	// and encoding.TextMarshaler with symbolic names such as USER_NOT_FOUND.
+	ErrorCodes bool
+	// Exhaustive adds <Enum>Values, <Enum>Codes and ForEach<Enum> functions
+	// per error enum, enumerating its non-zero values in declaration order,
+	// so tools and tests iterate every error without listing them. It
+	// implies Registry and ErrorCodes.
+	Exhaustive bool
+	// Metrics selects the metrics mode. With MetricsPrometheus, Join and
+	// JoinWithMessage increment the sphere_errors_total counter labeled by
+	// enum, code and HTTP status.
+	Metrics string
+	// LogHelpers adds Severity() slog.Level and slog.LogValuer methods.
+	// Severities default by HTTP status (see severityFromStatus) and are
+	// overridden by Severities.
+	LogHelpers bool
+	// Severities override the severity of error values, keyed by the
+	// fully-qualified name of an enum (all its values) or of a single value,
+	// which takes precedence.
+	Severities map[string]string
+	// LogSampleRates are the fractions of occurrences of error values worth
+	// logging, from 0 to 1, keyed like Severities. When set, every error enum
+	// gets LogSampleRate and ShouldLog methods; values without a rate are
+	// always logged.
+	LogSampleRates map[string]float64
+	// CacheTTLs are how long responses carrying an error value may be cached,
+	// keyed by fully-qualified enum or enum value name, the value winning.
+	// When set, every error enum gets a CacheControl method suggesting the
+	// Cache-Control header of its responses, which httperrors sets.
+	CacheTTLs map[string]time.Duration
+	// HTTPHeaders are the response headers of HTTP errors carrying an enum
+	// or value, keyed by fully-qualified enum or enum value name and then by
+	// canonical header name; a value's header overrides its enum's. Values
+	// may hold {key} placeholders, which httperrors fills from the metadata
+	// of the error. When set, every error enum gets an HTTPHeaders method.
+	HTTPHeaders map[string]map[string]string
+	// RetryHelpers adds an IsRetryable method, which the retry runtime package
+	// and grpcerrors rely on. Values are retryable when their HTTP status is
+	// 408, 429, 502, 503 or 504, or when listed in RetryableValues.
+	RetryHelpers bool
+	// RetryableValues are additional retryable values, keyed by the
+	// fully-qualified proto name of the enum value, e.g.
+	// "shared.v1.USER_ERROR_BUSY".
+	RetryableValues map[string]bool
+	// SLOExemptValues are the enums and values whose errors burn no error
+	// budget, keyed by fully-qualified enum or enum value name, for values
+	// whose options carry no slo_exempt field. When set, every error enum
+	// gets an SLOExempt method and every package IsSLOExempt.
+	SLOExemptValues map[string]bool
+	// SpanHelpers adds OTelEventName and MarksSpanError methods per error
+	// enum and a package-level RecordToSpan recording errors on
+	// OpenTelemetry spans. OTelEvents and SpanErrors imply it.
+	SpanHelpers bool
+	// OTelEvents are the names of the span events recording the errors of
+	// enums and values, keyed by fully-qualified enum or enum value name, for
+	// values whose options carry no otel_event field; "sphere.error" by
+	// default.
+	OTelEvents map[string]string
+	// SpanErrors report whether the errors of enums and values mark their
+	// span as failed, keyed by fully-qualified enum or enum value name, for
+	// values whose options carry no span_error field; by default only
+	// server errors do.
+	SpanErrors map[string]bool
+	// Visibilities are the visibilities, VisibilityInternal or
+	// VisibilityPublic, of enums and values, keyed by fully-qualified enum or
+	// enum value name, for values whose options carry no visibility field. A
+	// value entry wins over its enum. When set, every error enum gets an
+	// IsInternal method, and the constructors of internal values are written
+	// to the InternalPackage sub-package.
+	Visibilities map[string]string
+	// Stabilities are the stabilities, StabilityStable, StabilityBeta or
+	// StabilityExperimental, of enums and values, keyed by fully-qualified
+	// enum or enum value name, for values whose options carry no stability
+	// field. A value entry wins over its enum. When set, every error enum
+	// gets a Stability method.
+	Stabilities map[string]string
+	// ExperimentalCodePrefix and ExperimentalCodeSuffix wrap the public codes
+	// of experimental values, e.g. "X-" for "X-40401", so clients never take
+	// them for frozen codes. Either makes every enum with experimental values
+	// get a PublicCode method.
+	ExperimentalCodePrefix string
+	ExperimentalCodeSuffix string
+	// IncludeExperimental keeps experimental values in catalogs and the
+	// outputs built from them, which leave them out by default.
+	IncludeExperimental bool
+	// ValueNewErrorsFuncs are the constructors building the errors of enums
+	// and values in place of NewErrorsFunc, keyed by fully-qualified enum or
+	// value name, the value taking precedence. They are called with the
+	// arguments of NewErrorsFunc; the new_errors_func field of a custom
+	// OptionsType, as 'path;ident', overrides them.
+	ValueNewErrorsFuncs map[string]protogen.GoIdent
+	// DetailTypes are the protobuf messages carried as typed details, keyed by
+	// the fully-qualified enum value name and naming a message of the request,
+	// e.g. "shared.v1.QUOTA_ERROR_EXCEEDED": "shared.v1.QuotaViolation". Each
+	// value with a detail type gets a <GoName>Error(d *Message) error
+	// constructor attaching d through the details runtime package.
+	DetailTypes map[string]string
+	// ValidationErrors designate the error value protovalidate violations are
+	// converted to by the generated FromValidationError, keyed by the
+	// fully-qualified name of the validated message, or "" for every other
+	// message, e.g. "shop.v1.CreateOrderRequest": "shop.v1.ORDER_ERROR_INVALID".
+	// FromValidationError is generated into the Go package declaring the
+	// values.
+	ValidationErrors map[string]string
+	// MessageErrors are the error values of the messages carried by typed
+	// errors, keyed by the fully-qualified message name and naming a message of
+	// the request, e.g. "shop.v1.QuotaViolation": "shop.v1.SHOP_ERROR_QUOTA".
+	// Each message gets a <Message>Error struct embedding it and a
+	// New<Message>Error(d *Message, errs ...error) constructor, generated into
+	// the Go package declaring the value.
+	MessageErrors map[string]string
+	// Supersedes maps error values to the values of older API versions they
+	// replace, keyed by the fully-qualified name of the superseding value,
+	// e.g. "shared.v2.USER_ERROR_NOT_FOUND": "shared.v1.USER_NOT_FOUND". The Go
+	// package of the superseding values gets ToSuperseded and FromSuperseded
+	// translating errors in both directions.
+	Supersedes map[string]string
+	// OriginHelpers adds an Origin method reporting whether a value is caused
+	// by the client, the server or an upstream dependency, which the origin
+	// runtime package relies on. Origins default by HTTP status (see
+	// originFromStatus) and are overridden by Origins.
+	OriginHelpers bool
+	// Categories assign coarse-grained categories such as "validation" to
+	// error values, keyed by fully-qualified enum or enum value name. A value
+	// entry overrides its enum's, which skips the zero value. When set, every
+	// error enum gains a Category method, and each Go package
+	// <Category>Errors slices and Is<Category>Error predicates.
+	Categories map[string]string
+	// UnexportedConstructors unexports the package-level constructors of
+	// error values: New<Value>, <Value>Params and <Value>Error, and the
+	// Error<Value> functions of the kratos runtime. A hand-written file of the
+	// package then exports a reviewed facade wrapping them.
+	UnexportedConstructors bool
+	// RecoverErrors designates the error values, by fully-qualified name,
+	// that panics recovered in their Go package convert to, e.g.
+	// "shop.v1.ORDER_ERROR_INTERNAL". At most one value per Go package; the
+	// package gets RecoverAsError and RecoverMiddleware built on the recovery
+	// runtime package.
+	RecoverErrors []string
+	// FallbackErrors designates the error values, by fully-qualified name,
+	// that foreign errors of their Go package are wrapped into by the
+	// generated AsSphereError, e.g. "shop.v1.ORDER_ERROR_INTERNAL". At most
+	// one value per Go package; the fallback file option wins over them.
+	FallbackErrors []string
+	// FallbackOption is the fully-qualified name of the string extension of
+	// google.protobuf.FileOptions designating the fallback of the Go package
+	// of a file, sphere.errors.fallback_error when empty.
+	FallbackOption string
+	// ProblemJSON adds a Problem method per error enum returning the RFC 9457
+	// problem details of a value, which the problem runtime package encodes
+	// as application/problem+json.
+	ProblemJSON bool
+	// ProblemType is the URI template of the problem types, with the
+	// placeholders {package}, {enum}, {value} and {code}, e.g.
+	// "https://errors.example.com/{enum}/{value}". Empty means about:blank.
+	ProblemType string
+	// BinaryFormats are the binary encodings, BinaryFormatCBOR and
+	// BinaryFormatMsgpack, of the httperrors body of the error enum values,
+	// each adding a MarshalCBOR or MarshalMsgpack method per error enum for
+	// clients that don't speak JSON.
+	BinaryFormats []string
+	// MessageResolver adds a package-level SetMessageResolver hook, consulted
+	// by the GetMessage methods and therefore by every constructor, so that
+	// user-facing messages can be overridden at runtime.
+	MessageResolver bool
+	// LocaleFallbacks map a language tag to the tags tried after it, as set
+	// by AddLocaleFallback. When set, the package gets SetTranslator and
+	// ResolveMessage, looking up the translated message of a code along the
+	// fallbacks of the requested languages.
+	LocaleFallbacks map[string][]string
+	// LocalizedMessages are the messages of enum values in other languages,
+	// keyed by fully-qualified enum value name and then by language tag, as
+	// set by AddLocalizedMessage. The message_i18n map field of a custom
+	// OptionsType takes precedence. Error enums with localized messages get
+	// a <Enum>Messages bundle and LocalizedMessage and WithLocale methods.
+	LocalizedMessages map[string]map[string]string
+	// StatusProto adds package-level ToStatusProto and FromStatusProto
+	// functions converting the errors of the package to and from
+	// google.rpc.Status messages through the grpcerrors runtime package.
+	StatusProto bool
+	// Propagation adds package-level PropagationHeader and
+	// FromPropagationHeader functions carrying errors across service
+	// boundaries through the propagation runtime package.
+	Propagation bool
+	// MessageHeaders adds package-level InjectMessageHeaders and
+	// FromMessageHeaders functions carrying errors in the headers of message
+	// bus messages through the msgheaders runtime package.
+	MessageHeaders bool
+	// JoinErrors adds a package-level JoinErrors function aggregating errors
+	// into one through the multierror runtime package.
+	JoinErrors bool
+	// Gateway adds a package-level GatewayErrorHandler rendering the errors
+	// of the package behind grpc-gateway with the httperrors JSON envelope.
+	Gateway bool
+	// HTTPFramework adds a package-level error adapter for one of the
+	// Framework HTTP routers, rendering the errors with the httperrors
+	// envelope: a gin middleware, an echo HTTPErrorHandler or a chi handler
+	// adapter.
+	HTTPFramework string
+	// HTTPEnvelope selects the envelope, EnvelopeSphere (the default),
+	// EnvelopeGoogle or EnvelopeAWS, written by the Gateway handler, the
+	// HTTPFramework adapter and the encoder of GenerateDI. EnvelopeAWS also
+	// adds an AWSErrorType method to the error enums.
+	HTTPEnvelope string
+	// Origins override the origin of error values, keyed by the
+	// fully-qualified name of an enum (all its values) or of a single value,
+	// which takes precedence.
+	Origins map[string]string
+	// ClientCodes group error values under the small, stable set of codes
+	// clients see, keyed by the fully-qualified name of an enum (all its
+	// values) or of a single value, which takes precedence, for values whose
+	// options carry no client_code field. When set, every error enum gets a
+	// ClientCode method, which the httperrors Encoder exposes in place of the
+	// code with ClientCodes.
+	ClientCodes map[string]int32
+	// LegacyPatterns are the regular expressions matching the bodies of
+	// legacy upstream responses that denote an error value, keyed by its
+	// fully-qualified name, for values whose options carry no
+	// legacy_pattern field. Every error enum with a pattern gets a
+	// ClassifyLegacy<Enum>(status, body) function.
+	LegacyPatterns map[string]string
+	// ParseHelpers adds Parse<Enum>(code) and <Enum>FromHTTPResponse(status,
+	// body) functions turning codes and error responses back into values.
+	ParseHelpers bool
+	// Metadata adds WithMetadata, WithField and the chainable WithRequestID
+	// methods attaching string details to the error through the metadata
+	// runtime package.
+	Metadata bool
+	// TraceContext adds WithTraceContext(ctx) methods attaching the
+	// OpenTelemetry trace and span IDs of ctx. It implies Metadata.
+	TraceContext bool
+	// FailOnDeprecatedUse moves the per-value helpers of deprecated values
+	// into <prefix>.errors_deprecated.pb.go, guarded by the
+	// !sphere_errors_strict build constraint, so building with
+	// -tags sphere_errors_strict rejects any remaining use of them.
+	FailOnDeprecatedUse bool
+	// Domains are the google.rpc.ErrorInfo domains of the error enums, keyed by
+	// proto package. The "" key applies to packages without an entry of
+	// their own.
+	Domains map[string]string
+	// CodePrefixes are the service identifiers prefixed to the codes returned
+	// by the generated PublicCode methods, e.g. "AUTH-" for "AUTH-40401",
+	// keyed by proto package. The "" key applies to packages without an
+	// entry of their own.
+	CodePrefixes map[string]string
+	// DefaultMessages are the messages of values without one of their own,
+	// keyed by the fully-qualified enum name. They complement the enum-level
+	// default_status option.
+	DefaultMessages map[string]string
+	// StatusRanges are the statuses of values without one of their own by
+	// numeric band, keyed by the fully-qualified enum name. They take
+	// precedence over the enum-level default_status option.
+	StatusRanges map[string][]StatusRange
+	// GRPCCodes are the gRPC code names, such as NOT_FOUND, of values
+	// keyed by fully-qualified enum value name, for values whose options
+	// carry no grpc_code field. A value declaring a gRPC code but no status
+	// gets the canonical HTTP status of the code.
+	GRPCCodes map[string]string
+	// LogMessages are internal messages keyed by fully-qualified enum value
+	// name. A value with one returns it from Error(), so it ends up in logs of
+	// the constructed error, while the encoded error keeps exposing only the
+	// public message; the reason moves to a generated GetReason method.
+	LogMessages map[string]string
+	// Messages are the messages of enum values keyed by fully-qualified enum
+	// value name, read from a messages_file. They take precedence over the
+	// message of the value options, so copywriters edit user-facing text
+	// without touching the proto files.
+	Messages map[string]string
+	// Strict makes ValidateOptions reject non-zero error values without a
+	// (sphere.errors.options) annotation or without a message.
+	Strict bool
+	// GenTests adds <prefix>.errors.pb_test.go, asserting the code, status,
+	// reason and message of every error value so edits to the proto
+	// annotations show up as test failures.
+	GenTests bool
+	// GenFuzz adds <prefix>.errors.pb_fuzz_test.go, asserting every value
+	// parses back from its code and from an error response, fuzzing
+	// Parse<Enum> and <Enum>FromHTTPResponse, and asserting no two values of
+	// the Go package share a public code. It implies ParseHelpers.
+	GenFuzz bool
+	// ErrorTest writes the errortest sub-package next to the errors of each
+	// Go package, with AssertCode, an Assert<Enum> per error enum and the
+	// MatchCode matcher for gomock and testify, so service tests can assert
+	// on error identity.
+	ErrorTest bool
+	// UniqueCodes makes ValidateCodes reject two non-zero values sharing a
+	// code.
+	UniqueCodes bool
+	// ReservedCodes are code ranges ValidateCodes rejects for non-zero values.
+	ReservedCodes []CodeRange
+	// CodeOffsets are added to the enum value number to form the error code,
+	// keyed by proto package. The "" key applies to packages without an
+	// entry of their own.
+	CodeOffsets map[string]int32
+	// OutputPackage, when set, is the Go import path the errors of every file
+	// are generated into instead of the package of the message types.
+	// OutputPackageName is its package name and defaults to the last path
+	// element.
+	OutputPackage     protogen.GoImportPath
+	OutputPackageName protogen.GoPackageName
+	// OutputPackages override OutputPackage for the files of a proto
+	// package, keyed by proto package.
+	OutputPackages map[string]GoPackage
+	// PackageSuffix, when set and OutputPackage is not, generates the errors
+	// into a sub-package of the message types' package, e.g. "apierrors".
+	PackageSuffix string
+	// GoPackageDirs place the Go files generated into a Go package, keyed by
+	// its import path, in a directory relative to the output directory
+	// instead of where paths= puts them, e.g. "billing/api" for a package of
+	// a nested Go module of a monorepo.
+	GoPackageDirs map[protogen.GoImportPath]string
+	// Module is the module= parameter of the request, the import path prefix
+	// protogen strips from the names of the generated files. Files placed
+	// relative to the output directory, by GoPackageDirs and LookupCmd, are
+	// prefixed with it so that they survive the stripping.
+	Module string
+	// IncludeEnums, when non-empty, limits generation to the error enums whose
+	// fully-qualified name matches one of these path.Match patterns, e.g.
+	// "shared.v1.*Error".
+	IncludeEnums []string
+	// ExcludeEnums skips the error enums whose fully-qualified name matches
+	// one of these patterns, even when IncludeEnums selects them.
+	ExcludeEnums []string
+	// ExcludeFiles skips every enum of the proto files whose path matches one
+	// of these patterns, e.g. "legacy/*.proto".
+	ExcludeFiles []string
+	// RawStatus renders HTTP statuses as int32 literals instead of net/http
+	// constants such as http.StatusNotFound.
+	RawStatus bool
+	// WithStack makes the Join, JoinWithMessage and JoinContext constructors,
+	// and therefore every helper built on them, record the call stack through
+	// the stack runtime package. Without it nothing is recorded.
+	WithStack bool
+	// Prebuilt adds an Err method per error enum returning the error Join()
+	// builds, constructed once per value at package initialization, for hot
+	// paths that cannot afford an allocation per error. With GenTests the
+	// tests also assert Err does not allocate and benchmark it against Join.
+	Prebuilt bool
+	// DocURLBase is the URI template of the documentation URL of every error
+	// value, with the placeholders {package}, {enum}, {value} and {code},
+	// e.g. "https://kb.example.com/errors/{code}". DocURLs override it per
+	// value, keyed by the fully-qualified enum value name. When either is
+	// set, every error enum gets a HelpLink method, and GRPCStatus and
+	// grpcerrors attach the link as a google.rpc.Help detail.
+	DocURLBase string
+	DocURLs    map[string]string
+	// ConcreteReturn makes the constructors (Join, WithCause, New<Name>...)
+	// return a package-level *Error type instead of error, whose
+	// WithMetadata, WithField and WithDetails methods chain without a type
+	// assertion.
+	ConcreteReturn bool
+	// ErrorFuncs adds package-level Code, HTTPStatus and Message functions
+	// reading the first generated error of any error chain, with defaults for
+	// nil and foreign errors.
+	ErrorFuncs bool
+	// AnyDetails adds a package-level DetailTypes registry of the detail
+	// types of the package, with Details and DecodeDetails packing the
+	// details of an error as google.protobuf.Any and decoding them back on
+	// clients, for transports carrying nested structured details that
+	// metadata cannot.
+	AnyDetails bool
+	// EmbedCatalog writes the catalog of each Go package, as JSON, next to
+	// its errors and adds a package-level Catalog returning it through an
+	// embed.FS, so binaries can serve their own error documentation.
+	EmbedCatalog bool
+	// CatalogService adds a package-level CatalogService returning a
+	// catalogv1.ErrorCatalogServiceServer over the embedded catalog, for
+	// servers exposing their errors to tooling. It requires EmbedCatalog.
+	CatalogService bool
+	// ErrorText selects the text returned by the Error method of the error
+	// values, one of the ErrorText constants; empty means ErrorTextReason.
+	// ErrorTextCodeMessage formats "code: message" when the code is
+	// generated, from the declared message, so Error() returns a constant;
+	// the reason then moves to GetReason as with LogMessages.
+	ErrorText string
+	// ZeroValue is the policy for the zero value of error enums, one of the
+	// ZeroValue constants; empty means ZeroValueInclude.
+	ZeroValue string
+	// NameStyle styles the Go identifiers generated per error value, one of
+	// the NameStyle constants; empty means NameStyleEnumPrefixed.
+	NameStyle string
+	// OptionsType is the fully-qualified name of a message read as the error
+	// options of enum values in place of (sphere.errors.options), e.g.
+	// "mycorp.errors.v1.ErrorOptions". It is looked up as the type of an
+	// extension of google.protobuf.EnumValueOptions declared in the request,
+	// and its status, reason, message, grpc_code, slo_exempt, visibility,
+	// new_errors_func and example fields are read, renamed by OptionFields.
+	OptionsType string
+	// OptionFields map status, reason, message, grpc_code, slo_exempt,
+	// visibility, new_errors_func and example to the names of the fields of
+	// OptionsType holding them.
+	OptionFields map[string]string
+	// DefaultStatusOption is the fully-qualified name of an integer extension
+	// of google.protobuf.EnumOptions read in place of
+	// (sphere.errors.default_status). Enums setting it are error enums.
+	DefaultStatusOption string
+	// MethodErrorsOption is the fully-qualified name of a repeated string
+	// extension of google.protobuf.MethodOptions by which RPC methods
+	// declare the error enums and error enum values they may return, e.g.
+	// "mycorp.api.v1.errors". Each file declaring such methods gets a
+	// <Service>_<Method>_PossibleErrors function per method, and the
+	// catalog and OpenAPI outputs list the methods of each error.
+	MethodErrorsOption string
+	// GenerateOption is the fully-qualified name of a bool extension of
+	// google.protobuf.FileOptions gating the generation of each file, by
+	// default sphere.errors.generate. See GateFiles.
+	GenerateOption string
+	// GeneratePolicy decides the generation of files not setting
+	// GenerateOption: one of PolicyAll (the default) or PolicyOptIn.
+	GeneratePolicy string
+	// FileSuffix replaces .errors.pb.go as the suffix of the generated Go
+	// files, e.g. "_errors.go". It does not apply with Aggregate.
+	FileSuffix string
+	// BuildTag is a //go:build expression added to every generated Go file,
+	// e.g. "!tinygo", combined with the constraint of the deprecated helpers.
+	BuildTag string
+	// GeneratedBy names the generator in the "Code generated by ... DO NOT
+	// EDIT." header, protoc-gen-sphere-errors by default.
+	GeneratedBy string
+	// custom reads OptionsType, DefaultStatusOption and MethodErrorsOption,
+	// set up by ResolveOptions.
+	custom *customOptions
+	// goNames are the Go names of the error values keyed by full value name,
+	// picked by AssignGoNames.
+	goNames map[string]string
+	// fallbackFiles are the fallback values designated by the fallback file
+	// option keyed by proto file path, read by ResolveFallbackErrors.
+	fallbackFiles map[string]string
+	// Aggregate generates the errors of every proto file of a Go package into
+	// one errors.sphere.go instead of one file per proto file.
+	Aggregate bool
+	// MergePackageErrors treats the error enums of each proto package as one
+	// set: ValidateMergedPackages requires its files to share a Go package
+	// and its codes to be unique, and the package gets ErrorByCode and Errors
+	// spanning its files. Combine it with Aggregate for one generated file.
+	MergePackageErrors bool
+}
+
+// codeOffset returns the code offset configured for the proto package pkg.
+func (c *Config) codeOffset(pkg string) int32 {
+	if offset, ok := c.CodeOffsets[pkg]; ok {
+		return offset
+	}
+	return c.CodeOffsets[""]
+}
+
+// domain returns the error domain configured for the proto package pkg.
+func (c *Config) domain(pkg string) string {
+	if domain, ok := c.Domains[pkg]; ok {
+		return domain
+	}
+	return c.Domains[""]
+}
+
+// codePrefix returns the public code prefix of proto package pkg.
+func (c *Config) codePrefix(pkg string) string {
+	if prefix, ok := c.CodePrefixes[pkg]; ok {
+		return prefix
+	}
+	return c.CodePrefixes[""]
 }
 
-// GenerateFile generates the <prefix>.errors.pb.go file for file. It returns a
-// nil GeneratedFile (and nil error) when file declares no error enums.
+// severity returns the severity of the enum value value of enum, which has
+// the HTTP status status.
+func (c *Config) severity(enum, value string, status int32) string {
+	if level, ok := c.Severities[value]; ok {
+		return level
+	}
+	if level, ok := c.Severities[enum]; ok {
+		return level
+	}
+	return severityFromStatus(status)
+}
+
+// origin returns the origin of the enum value value of enum, which has the
+// HTTP status status.
+func (c *Config) origin(enum, value string, status int32) string {
+	if origin, ok := c.Origins[value]; ok {
+		return origin
+	}
+	if origin, ok := c.Origins[enum]; ok {
+		return origin
+	}
+	return originFromStatus(status)
+}
+
+// strictBuildTag is the build tag that excludes the helpers of deprecated
+// values when FailOnDeprecatedUse is set.
+const strictBuildTag = "sphere_errors_strict"
+
+// GenerateFile generates the <prefix>.errors.pb.go file for file, in the
+// output package selected by config. It returns a
+// nil GeneratedFile (and nil error) when file declares no error enums. With
+// FailOnDeprecatedUse it may also emit <prefix>.errors_deprecated.pb.go and
+// with GenTests <prefix>.errors.pb_test.go and with GenFuzz
+// <prefix>.errors.pb_fuzz_test.go, and with ErrorTest the first file of each
+// Go package emits errortest/errortest.sphere.go; only the main file is
+// returned. A
+// FileSuffix such as _errors.go replaces .errors.pb.go, naming the files
+// <prefix>_errors.go, <prefix>_errors_deprecated.go, <prefix>_errors_test.go
+// and <prefix>_errors_fuzz_test.go. With MethodErrorsOption, a file whose
+// services declare the errors of their methods also emits
+// <prefix>.errors_methods.pb.go, even without error enums.
+//
+// With Aggregate the first file of each Go package instead generates
+// errors.sphere.go holding the errors of every file of the package, and the
+// other files return a nil GeneratedFile.
 func GenerateFile(gen *protogen.Plugin, file *protogen.File, config *Config) (*protogen.GeneratedFile, error) {
-	if len(file.Enums) == 0 || !hasErrorEnums(file.Enums) {
+	if config.MethodErrorsOption != "" {
+		generateMethodErrors(gen, file, config)
+	}
+	if len(file.Enums) == 0 || !hasErrorEnums(file.Enums, config) {
 		return nil, nil
 	}
-	filename := file.GeneratedFilenamePrefix + ".errors.pb.go"
-	g := gen.NewGeneratedFile(filename, file.GoImportPath)
-	generateFileHeader(gen, file, g)
-	if err := generateFileContent(file, g, config); err != nil {
-		return nil, err
+	out := config.outputFor(file)
+	files := []*protogen.File{file}
+	stem, suffix := config.fileSuffix()
+	prefix := out.prefix + stem
+	if config.Aggregate {
+		if !declaresPackageHelpers(gen, file, config) {
+			return nil, nil
+		}
+		files = packageFiles(gen, file, config)
+		prefix = path.Join(path.Dir(out.prefix), "errors")
+		suffix = ".sphere"
+	}
+	g := gen.NewGeneratedFile(prefix+suffix+".go", out.importPath)
+	generateFileHeader(gen, files, g, out.packageName, config, "")
+	for _, f := range files {
+		if err := generateFileContent(gen, f, g, config, config.outputFor(f).separate); err != nil {
+			return nil, err
+		}
+	}
+	if declaresPackageHelpers(gen, file, config) {
+		if config.MinimalRuntime {
+			generateMinimalRuntime(g)
+		}
+		if config.ConcreteReturn {
+			generateConcreteError(g)
+		}
+		if len(config.Categories) > 0 {
+			generateCategoryHelpers(gen, file, g, config)
+		}
+		if config.ErrorFuncs {
+			generateErrorFuncs(g)
+		}
+		if config.sloHelpers() {
+			generateSLOHelpers(g)
+		}
+		if config.spanHelpers() {
+			generateSpanHelpers(g)
+		}
+		if config.MessageResolver {
+			generateMessageResolver(g)
+		}
+		if config.StatusProto {
+			generateStatusProto(g)
+		}
+		if config.Propagation {
+			generatePropagation(g)
+		}
+		if config.MessageHeaders {
+			generateMessageHeaders(g)
+		}
+		if config.JoinErrors {
+			generateJoinErrors(g)
+		}
+		if config.Gateway {
+			generateGateway(g, config)
+		}
+		if config.HTTPFramework != "" {
+			generateFrameworkAdapter(g, config.HTTPFramework, config)
+		}
+		if len(config.ValidationErrors) > 0 {
+			generateValidationBridge(gen, file, g, config)
+		}
+		if len(config.MessageErrors) > 0 {
+			generateMessageErrors(gen, file, g, config)
+		}
+		if len(config.Supersedes) > 0 {
+			generateSupersedes(gen, file, g, config)
+		}
+		if config.AnyDetails {
+			generateAnyDetails(gen, file, g, config)
+		}
+		if config.EmbedCatalog {
+			if err := generateEmbeddedCatalog(gen, file, g, config); err != nil {
+				return nil, err
+			}
+		}
+		if config.MergePackageErrors {
+			generateMergedErrors(gen, file, g, config)
+		}
+		if len(config.RecoverErrors) > 0 {
+			generateRecovery(gen, file, g, config)
+		}
+		if len(config.FallbackErrors) > 0 || len(config.fallbackFiles) > 0 {
+			generateFallback(gen, file, g, config)
+		}
+		if len(config.LocaleFallbacks) > 0 {
+			generateLocalizedMessages(g, config)
+		}
+		if config.StatusProto || config.Propagation || config.MessageHeaders || config.Gateway || config.MergePackageErrors || len(config.LocaleFallbacks) > 0 {
+			generateErrorByCode(gen, file, g, config)
+		}
+	}
+	if config.GenTests {
+		tg := gen.NewGeneratedFile(prefix+suffix+"_test.go", out.importPath)
+		generateFileHeader(gen, files, tg, out.packageName, config, "")
+		for _, f := range files {
+			if err := generateTestContent(f, tg, config); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if config.GenFuzz {
+		fg := gen.NewGeneratedFile(prefix+suffix+"_fuzz_test.go", out.importPath)
+		generateFileHeader(gen, files, fg, out.packageName, config, "")
+		for _, f := range files {
+			if err := generateFuzzContent(f, fg, config); err != nil {
+				return nil, err
+			}
+		}
+		if declaresPackageHelpers(gen, file, config) {
+			generateUniqueCodesTest(gen, file, fg, config)
+		}
+	}
+	if config.ErrorTest && declaresPackageHelpers(gen, file, config) {
+		generateErrorTestPackage(gen, file, config)
+	}
+	if config.visibilityHelpers() && declaresPackageHelpers(gen, file, config) {
+		generateInternalPackage(gen, file, config)
+	}
+	if config.FailOnDeprecatedUse {
+		dg := gen.NewGeneratedFile(prefix+"_deprecated"+suffix+".go", out.importPath)
+		generateFileHeader(gen, files, dg, out.packageName, config, "!"+strictBuildTag)
+		written := false
+		for _, f := range files {
+			w, err := generateDeprecatedContent(gen, f, dg, config)
+			if err != nil {
+				return nil, err
+			}
+			written = written || w
+		}
+		if !written {
+			dg.Skip()
+		}
 	}
 	return g, nil
 }
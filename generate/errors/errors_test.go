@@ -1,13 +1,23 @@
 package errors
 
 import (
+	stderrors "errors"
+	"maps"
+	"path"
+	"reflect"
+	"slices"
 	"strings"
 	"testing"
+	"testing/fstest"
 
 	sphereerrors "github.com/go-sphere/errors/sphere/errors"
+	"github.com/go-sphere/protoc-gen-sphere-errors/errcatalog"
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/internal/template"
 	"github.com/go-sphere/protoc-gen-sphere-errors/generate/internal/testutil"
 	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/encoding/protowire"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
 	"google.golang.org/protobuf/types/descriptorpb"
 	"google.golang.org/protobuf/types/pluginpb"
 )
@@ -21,6 +31,54 @@ var testConfig = &Config{
 
 // --- Pure function unit tests (no protogen involved) ---
 
+func TestCodeMessageText(t *testing.T) {
+	for _, tt := range []struct {
+		info template.ErrorInfo
+		want string
+	}{
+		{template.ErrorInfo{Code: 2, Reason: "user not found", Message: "user does not exist"}, "2: user does not exist"},
+		{template.ErrorInfo{Code: 40401, PublicCode: "AUTH-40401", Reason: "missing", Message: "no such user"}, "AUTH-40401: no such user"},
+		{template.ErrorInfo{Code: 3, Reason: "permission denied"}, "3: permission denied"},
+		{template.ErrorInfo{Code: 4, Reason: "gone", Message: "gone for good", LogMessage: "row deleted"}, "4: row deleted"},
+	} {
+		if got := codeMessageText(&tt.info); got != tt.want {
+			t.Errorf("codeMessageText(%+v) = %q, want %q", tt.info, got, tt.want)
+		}
+	}
+}
+
+func TestParseLocaleFallback(t *testing.T) {
+	tags, err := ParseLocaleFallback("zh-HK > zh>en")
+	if err != nil || !slices.Equal(tags, []string{"zh-HK", "zh", "en"}) {
+		t.Errorf("ParseLocaleFallback = %q, %v", tags, err)
+	}
+	for _, s := range []string{"zh", "zh-HK>", ">en", "zh HK>zh"} {
+		if _, err := ParseLocaleFallback(s); err == nil {
+			t.Errorf("ParseLocaleFallback(%q) succeeded, want an error", s)
+		}
+	}
+	var config Config
+	config.AddLocaleFallback([]string{"zh-HK", "zh", "en"})
+	config.AddLocaleFallback([]string{"zh-HK", "zh-Hant"})
+	config.AddLocaleFallback([]string{"zh", "en"})
+	want := map[string][]string{"zh-HK": {"zh", "zh-Hant"}, "zh": {"en"}}
+	if !maps.EqualFunc(config.LocaleFallbacks, want, slices.Equal) {
+		t.Errorf("LocaleFallbacks = %v, want %v", config.LocaleFallbacks, want)
+	}
+}
+
+func TestParseLocalizedMessage(t *testing.T) {
+	value, lang, msg, err := ParseLocalizedMessage("shop.v1.SHOP_ERROR_SOLD_OUT:zh-HK=已售罄: 請稍後再試")
+	if err != nil || value != "shop.v1.SHOP_ERROR_SOLD_OUT" || lang != "zh-HK" || msg != "已售罄: 請稍後再試" {
+		t.Errorf("ParseLocalizedMessage = %q, %q, %q, %v", value, lang, msg, err)
+	}
+	for _, s := range []string{"shop.v1.SHOP_ERROR_SOLD_OUT=sold out", ":zh=x", "shop.v1.SHOP_ERROR_SOLD_OUT:=x", "shop.v1.SHOP_ERROR_SOLD_OUT:zh HK=x"} {
+		if _, _, _, err := ParseLocalizedMessage(s); err == nil {
+			t.Errorf("ParseLocalizedMessage(%q) succeeded, want an error", s)
+		}
+	}
+}
+
 func TestResolveErrorInfo(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -113,6 +171,624 @@ func TestFormatProtocVersion(t *testing.T) {
 	}
 }
 
+func TestGRPCCodeFromHTTP(t *testing.T) {
+	tests := []struct {
+		status int32
+		want   string
+	}{
+		{400, "INVALID_ARGUMENT"},
+		{401, "UNAUTHENTICATED"},
+		{403, "PERMISSION_DENIED"},
+		{404, "NOT_FOUND"},
+		{409, "ALREADY_EXISTS"},
+		{429, "RESOURCE_EXHAUSTED"},
+		{503, "UNAVAILABLE"},
+		{418, "FAILED_PRECONDITION"},
+		{507, "INTERNAL"},
+		{302, "UNKNOWN"},
+	}
+	for _, tt := range tests {
+		got := grpcCodeFromHTTP(tt.status)
+		if got != tt.want {
+			t.Errorf("grpcCodeFromHTTP(%d) = %q, want %q", tt.status, got, tt.want)
+		}
+		if _, ok := grpcCodeGoNames[got]; !ok {
+			t.Errorf("grpcCodeFromHTTP(%d) = %q has no Go identifier", tt.status, got)
+		}
+	}
+}
+
+func TestValueGoName(t *testing.T) {
+	tests := []struct {
+		enum, value, want string
+	}{
+		{"UserError", "USER_ERROR_NOT_FOUND", "UserNotFound"},
+		{"TestError", "TEST_ERROR_INVALID_FIELD_TEST1", "TestInvalidFieldTest1"},
+		{"HTTPError", "HTTP_ERROR_BAD_GATEWAY", "HTTPBadGateway"},
+		{"AuthError", "TOKEN_EXPIRED", "AuthTokenExpired"},
+		{"Reason", "REASON_QUOTA", "ReasonQuota"},
+	}
+	for _, tt := range tests {
+		if got := valueGoName(tt.enum, tt.value); got != tt.want {
+			t.Errorf("valueGoName(%q, %q) = %q, want %q", tt.enum, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestParseCodeRange(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    CodeRange
+		wantErr bool
+	}{
+		{in: "40000-40099", want: CodeRange{Start: 40000, End: 40099}},
+		{in: "7", want: CodeRange{Start: 7, End: 7}},
+		{in: "9-1", wantErr: true},
+		{in: "a-b", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseCodeRange(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseCodeRange(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseCodeRange(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestValidateRuntime(t *testing.T) {
+	for _, runtime := range []string{"", RuntimeHTTPX, RuntimeStdlib, RuntimeKratos, RuntimeConnect} {
+		if err := ValidateRuntime(runtime); err != nil {
+			t.Errorf("ValidateRuntime(%q) = %v", runtime, err)
+		}
+	}
+	if err := ValidateRuntime("twirp"); err == nil {
+		t.Error("expected error for unsupported runtime")
+	}
+}
+
+func TestValidateNewErrorsArgs(t *testing.T) {
+	valid := [][]string{
+		{ArgStatus, ArgCode, ArgMessage, ArgErr},
+		{ArgContext, ArgCode, ArgMessage},
+		{ArgMessage},
+	}
+	for _, args := range valid {
+		if err := ValidateNewErrorsArgs(args); err != nil {
+			t.Errorf("ValidateNewErrorsArgs(%v) = %v", args, err)
+		}
+	}
+	invalid := [][]string{nil, {"ctx", "reason"}, {ArgCode, ArgCode}}
+	for _, args := range invalid {
+		if err := ValidateNewErrorsArgs(args); err == nil {
+			t.Errorf("ValidateNewErrorsArgs(%v): expected an error", args)
+		}
+	}
+}
+
+func TestAdaptsNewErrorsFunc(t *testing.T) {
+	config := &Config{
+		NewErrorsFunc: testConfig.NewErrorsFunc,
+		NewErrorsArgs: []string{ArgStatus, ArgCode, ArgMessage, ArgErr},
+	}
+	if config.adaptsNewErrorsFunc("tests.basic") {
+		t.Error("the default signature should call NewErrorsFunc directly")
+	}
+	config.NewErrorsArgs = []string{ArgCode, ArgMessage}
+	config.Runtimes = map[string]string{"tests.kratos": RuntimeKratos}
+	if !config.adaptsNewErrorsFunc("tests.basic") || config.adaptsNewErrorsFunc("tests.kratos") {
+		t.Error("a custom signature should be adapted for httpx packages only")
+	}
+}
+
+func TestParseRuntime(t *testing.T) {
+	tests := []struct {
+		in          string
+		wantPkg     string
+		wantRuntime string
+		wantErr     bool
+	}{
+		{in: "kratos", wantRuntime: RuntimeKratos},
+		{in: "shared.v1 = connect", wantPkg: "shared.v1", wantRuntime: RuntimeConnect},
+		{in: "typed", wantRuntime: RuntimeTyped},
+		{in: "shared.v1=rust", wantErr: true},
+	}
+	for _, tt := range tests {
+		pkg, runtime, err := ParseRuntime(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseRuntime(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if pkg != tt.wantPkg || runtime != tt.wantRuntime {
+			t.Errorf("ParseRuntime(%q) = %q, %q, want %q, %q", tt.in, pkg, runtime, tt.wantPkg, tt.wantRuntime)
+		}
+	}
+}
+
+func TestParseOutputPackage(t *testing.T) {
+	tests := []struct {
+		in      string
+		wantPkg string
+		want    GoPackage
+		wantErr bool
+	}{
+		{in: "example.com/api/apierrors", want: GoPackage{ImportPath: "example.com/api/apierrors"}},
+		{in: "shared.v1=example.com/api/shared;sharederrors", wantPkg: "shared.v1", want: GoPackage{"example.com/api/shared", "sharederrors"}},
+		{in: "shared.v1=", wantErr: true},
+	}
+	for _, tt := range tests {
+		pkg, target, err := ParseOutputPackage(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseOutputPackage(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if pkg != tt.wantPkg || target != tt.want {
+			t.Errorf("ParseOutputPackage(%q) = %q, %+v, want %q, %+v", tt.in, pkg, target, tt.wantPkg, tt.want)
+		}
+	}
+}
+
+func TestParseOrigin(t *testing.T) {
+	tests := []struct {
+		in         string
+		wantName   string
+		wantOrigin string
+		wantErr    bool
+	}{
+		{in: "shared.v1.PaymentError=upstream", wantName: "shared.v1.PaymentError", wantOrigin: OriginUpstream},
+		{in: "shared.v1.USER_ERROR_GONE=CLIENT", wantName: "shared.v1.USER_ERROR_GONE", wantOrigin: OriginClient},
+		{in: "shared.v1.UserError=USER", wantErr: true},
+		{in: "=SERVER", wantErr: true},
+	}
+	for _, tt := range tests {
+		name, origin, err := ParseOrigin(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseOrigin(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if name != tt.wantName || origin != tt.wantOrigin {
+			t.Errorf("ParseOrigin(%q) = %q, %q, want %q, %q", tt.in, name, origin, tt.wantName, tt.wantOrigin)
+		}
+	}
+}
+
+func TestParseSeverity(t *testing.T) {
+	tests := []struct {
+		in        string
+		wantName  string
+		wantLevel string
+		wantErr   bool
+	}{
+		{in: "shared.v1.UserError=warn", wantName: "shared.v1.UserError", wantLevel: SeverityWarn},
+		{in: "shared.v1.USER_ERROR_GONE=CRITICAL", wantName: "shared.v1.USER_ERROR_GONE", wantLevel: SeverityCritical},
+		{in: "shared.v1.UserError=DEBUG", wantErr: true},
+		{in: "=INFO", wantErr: true},
+	}
+	for _, tt := range tests {
+		name, level, err := ParseSeverity(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseSeverity(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if name != tt.wantName || level != tt.wantLevel {
+			t.Errorf("ParseSeverity(%q) = %q, %q, want %q, %q", tt.in, name, level, tt.wantName, tt.wantLevel)
+		}
+	}
+}
+
+func TestParseLogSampleRate(t *testing.T) {
+	tests := []struct {
+		in       string
+		wantName string
+		wantRate float64
+		wantErr  bool
+	}{
+		{in: "shared.v1.AuthError=0.01", wantName: "shared.v1.AuthError", wantRate: 0.01},
+		{in: "shared.v1.AUTH_ERROR_TOKEN_EXPIRED = 0", wantName: "shared.v1.AUTH_ERROR_TOKEN_EXPIRED"},
+		{in: "shared.v1.AuthError=1.5", wantErr: true},
+		{in: "shared.v1.AuthError=often", wantErr: true},
+		{in: "=0.5", wantErr: true},
+	}
+	for _, tt := range tests {
+		name, rate, err := ParseLogSampleRate(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseLogSampleRate(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if name != tt.wantName || rate != tt.wantRate {
+			t.Errorf("ParseLogSampleRate(%q) = %q, %v, want %q, %v", tt.in, name, rate, tt.wantName, tt.wantRate)
+		}
+	}
+}
+
+func TestParseStatusRange(t *testing.T) {
+	tests := []struct {
+		in       string
+		wantEnum string
+		want     StatusRange
+		wantErr  bool
+	}{
+		{in: "shared.v1.UserError:1000-1999=400", wantEnum: "shared.v1.UserError", want: StatusRange{CodeRange{1000, 1999}, 400}},
+		{in: "shared.v1.UserError: 7 = 404", wantEnum: "shared.v1.UserError", want: StatusRange{CodeRange{7, 7}, 404}},
+		{in: "shared.v1.UserError:2000-1000=400", wantErr: true},
+		{in: "shared.v1.UserError:1000-1999=4040", wantErr: true},
+		{in: "shared.v1.UserError=400", wantErr: true},
+		{in: ":1-2=400", wantErr: true},
+	}
+	for _, tt := range tests {
+		enum, r, err := ParseStatusRange(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseStatusRange(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if enum != tt.wantEnum || r != tt.want {
+			t.Errorf("ParseStatusRange(%q) = %q, %+v, want %q, %+v", tt.in, enum, r, tt.wantEnum, tt.want)
+		}
+	}
+}
+
+func TestStatusRanges(t *testing.T) {
+	config := &Config{StatusRanges: map[string][]StatusRange{
+		"tests.basic.UserError": {{CodeRange{1, 2}, 409}, {CodeRange{4, 9}, 422}},
+	}}
+	if err := ValidateStatusRanges(config); err != nil {
+		t.Fatalf("ValidateStatusRanges = %v", err)
+	}
+	plugin := testutil.PluginFromPB(t, "testdata/pb/basic_errors.pb", "basic_errors.proto")
+	statuses := map[string]int32{}
+	for _, ew := range ErrorEnums(testutil.FileToGenerate(t, plugin), config) {
+		for _, info := range ew.Errors {
+			statuses[info.Value] = info.Status
+		}
+	}
+	for value, want := range map[string]int32{
+		"USER_ERROR_UNSPECIFIED":   400, // outside every range: default_status
+		"USER_ERROR_INVALID_ID":    400, // own status
+		"USER_ERROR_NOT_FOUND":     404, // own status
+		"USER_ERROR_DEFAULTED":     422,
+		"ORDER_ERROR_UNSPECIFIED":  500, // ranges are per enum
+		"ORDER_ERROR_OUT_OF_STOCK": 400,
+	} {
+		if statuses[value] != want {
+			t.Errorf("status of %s = %d, want %d", value, statuses[value], want)
+		}
+	}
+
+	config.StatusRanges["tests.basic.UserError"] = append(config.StatusRanges["tests.basic.UserError"], StatusRange{CodeRange{9, 20}, 400})
+	if err := ValidateStatusRanges(config); err == nil || !strings.Contains(err.Error(), "4-9 and 9-20 overlap") {
+		t.Errorf("ValidateStatusRanges = %v, want an overlap", err)
+	}
+}
+
+func TestValidateMetrics(t *testing.T) {
+	for _, metrics := range []string{"", MetricsPrometheus} {
+		if err := ValidateMetrics(metrics); err != nil {
+			t.Errorf("ValidateMetrics(%q) = %v", metrics, err)
+		}
+	}
+	if err := ValidateMetrics("statsd"); err == nil {
+		t.Error("expected error for unsupported metrics mode")
+	}
+}
+
+func TestParseDomain(t *testing.T) {
+	tests := []struct {
+		in         string
+		wantPkg    string
+		wantDomain string
+		wantErr    bool
+	}{
+		{in: "example.com", wantDomain: "example.com"},
+		{in: "shared.v1=users.example.com", wantPkg: "shared.v1", wantDomain: "users.example.com"},
+		{in: "shared.v1=", wantErr: true},
+	}
+	for _, tt := range tests {
+		pkg, domain, err := ParseDomain(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseDomain(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if pkg != tt.wantPkg || domain != tt.wantDomain {
+			t.Errorf("ParseDomain(%q) = %q, %q, want %q, %q", tt.in, pkg, domain, tt.wantPkg, tt.wantDomain)
+		}
+	}
+}
+
+func TestParseCategory(t *testing.T) {
+	tests := []struct {
+		in           string
+		wantName     string
+		wantCategory string
+		wantErr      bool
+	}{
+		{in: "shared.v1.AuthError=auth", wantName: "shared.v1.AuthError", wantCategory: "auth"},
+		{in: "shared.v1.QUOTA_ERROR_RATE=rate_limit", wantName: "shared.v1.QUOTA_ERROR_RATE", wantCategory: "rate_limit"},
+		{in: "shared.v1.AuthError=Auth", wantErr: true},
+		{in: "=auth", wantErr: true},
+	}
+	for _, tt := range tests {
+		name, category, err := ParseCategory(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseCategory(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if name != tt.wantName || category != tt.wantCategory {
+			t.Errorf("ParseCategory(%q) = %q, %q, want %q, %q", tt.in, name, category, tt.wantName, tt.wantCategory)
+		}
+	}
+}
+
+func TestValidateProblemType(t *testing.T) {
+	for _, valid := range []string{"", "https://errors.example.com/{package}/{enum}/{value}", "urn:problem:{code}"} {
+		if err := ValidateProblemType(valid); err != nil {
+			t.Errorf("ValidateProblemType(%q) = %v", valid, err)
+		}
+	}
+	if err := ValidateProblemType("https://errors.example.com/{reason}"); err == nil {
+		t.Error("expected error for unknown placeholder")
+	}
+}
+
+func TestProblemInfo(t *testing.T) {
+	info := &template.ErrorInfo{Value: "USER_ERROR_NOT_FOUND", Code: 40402, Status: 404, Reason: "user not found"}
+	problemInfo(info, "", "tests.basic", "UserError")
+	if info.ProblemType != "about:blank" || info.ProblemTitle != "Not Found" {
+		t.Errorf("about:blank problem = %q, %q", info.ProblemType, info.ProblemTitle)
+	}
+	problemInfo(info, "urn:{package}:{enum}:{value}:{code}", "tests.basic", "UserError")
+	if info.ProblemType != "urn:tests.basic:UserError:USER_ERROR_NOT_FOUND:40402" || info.ProblemTitle != "user not found" {
+		t.Errorf("typed problem = %q, %q", info.ProblemType, info.ProblemTitle)
+	}
+}
+
+func TestParseCodePrefix(t *testing.T) {
+	tests := []struct {
+		in         string
+		wantPkg    string
+		wantPrefix string
+		wantErr    bool
+	}{
+		{in: "AUTH-", wantPrefix: "AUTH-"},
+		{in: "auth.v1=AUTH-", wantPkg: "auth.v1", wantPrefix: "AUTH-"},
+		{in: "auth.v1= ", wantErr: true},
+	}
+	for _, tt := range tests {
+		pkg, prefix, err := ParseCodePrefix(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseCodePrefix(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if pkg != tt.wantPkg || prefix != tt.wantPrefix {
+			t.Errorf("ParseCodePrefix(%q) = %q, %q, want %q, %q", tt.in, pkg, prefix, tt.wantPkg, tt.wantPrefix)
+		}
+	}
+}
+
+func TestParseDefaultMessage(t *testing.T) {
+	tests := []struct {
+		in       string
+		wantEnum string
+		wantMsg  string
+		wantErr  bool
+	}{
+		{in: "tests.basic.UserError=invalid request", wantEnum: "tests.basic.UserError", wantMsg: "invalid request"},
+		{in: "tests.basic.UserError=a=b", wantEnum: "tests.basic.UserError", wantMsg: "a=b"},
+		{in: "tests.basic.UserError", wantErr: true},
+		{in: "=invalid request", wantErr: true},
+		{in: "tests.basic.UserError=", wantErr: true},
+	}
+	for _, tt := range tests {
+		enum, msg, err := ParseDefaultMessage(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseDefaultMessage(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if enum != tt.wantEnum || msg != tt.wantMsg {
+			t.Errorf("ParseDefaultMessage(%q) = %q, %q, want %q, %q", tt.in, enum, msg, tt.wantEnum, tt.wantMsg)
+		}
+	}
+}
+
+func TestParseDocURL(t *testing.T) {
+	value, url, err := ParseDocURL("shared.v1.USER_ERROR_NOT_FOUND = https://kb.example.com/article?id=7")
+	if err != nil || value != "shared.v1.USER_ERROR_NOT_FOUND" || url != "https://kb.example.com/article?id=7" {
+		t.Errorf("ParseDocURL() = %q, %q, %v", value, url, err)
+	}
+	for _, s := range []string{"", "https://kb.example.com", "=https://kb.example.com", "shared.v1.USER_ERROR_NOT_FOUND="} {
+		if _, _, err := ParseDocURL(s); err == nil {
+			t.Errorf("ParseDocURL(%q): expected error", s)
+		}
+	}
+	if err := ValidateDocURLBase("https://kb.example.com/{package}/{id}"); err == nil {
+		t.Error("ValidateDocURLBase accepted an unknown placeholder")
+	}
+}
+
+func TestParseDetailType(t *testing.T) {
+	value, message, err := ParseDetailType("shared.v1.QUOTA_ERROR_EXCEEDED = shared.v1.QuotaViolation")
+	if err != nil || value != "shared.v1.QUOTA_ERROR_EXCEEDED" || message != "shared.v1.QuotaViolation" {
+		t.Errorf("ParseDetailType() = %q, %q, %v", value, message, err)
+	}
+	for _, s := range []string{"", "shared.v1.QUOTA_ERROR_EXCEEDED", "=shared.v1.QuotaViolation", "shared.v1.QUOTA_ERROR_EXCEEDED="} {
+		if _, _, err := ParseDetailType(s); err == nil {
+			t.Errorf("ParseDetailType(%q): expected error", s)
+		}
+	}
+}
+
+func TestParseValidationError(t *testing.T) {
+	tests := []struct {
+		in          string
+		wantMessage string
+		wantValue   string
+	}{
+		{"tests.basic.USER_ERROR_INVALID_ID", "", "tests.basic.USER_ERROR_INVALID_ID"},
+		{"tests.basic.CreateOrderRequest = tests.basic.ORDER_ERROR_OUT_OF_STOCK", "tests.basic.CreateOrderRequest", "tests.basic.ORDER_ERROR_OUT_OF_STOCK"},
+	}
+	for _, tt := range tests {
+		message, value, err := ParseValidationError(tt.in)
+		if err != nil || message != tt.wantMessage || value != tt.wantValue {
+			t.Errorf("ParseValidationError(%q) = %q, %q, %v", tt.in, message, value, err)
+		}
+	}
+	for _, s := range []string{"", "tests.basic.CreateOrderRequest=", "=tests.basic.USER_ERROR_INVALID_ID"} {
+		if _, _, err := ParseValidationError(s); err == nil {
+			t.Errorf("ParseValidationError(%q): expected error", s)
+		}
+	}
+}
+
+func TestValidateValidationErrors(t *testing.T) {
+	plugin := testutil.PluginFromPB(t, "testdata/pb/basic_errors.pb", "basic_errors.proto")
+	files := []*protogen.File{testutil.FileToGenerate(t, plugin)}
+	config := &Config{ValidationErrors: map[string]string{"": "tests.basic.USER_ERROR_INVALID_ID"}}
+	if err := ValidateValidationErrors(files, config); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	config.ValidationErrors["tests.basic.CreateOrderRequest"] = "tests.basic.ORDER_ERROR_MISSING"
+	err := ValidateValidationErrors(files, config)
+	if want := "tests.basic.ORDER_ERROR_MISSING (validation error of tests.basic.CreateOrderRequest) is not an error enum value of this request"; err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("err = %v, want it to contain %q", err, want)
+	}
+}
+
+func TestParseSupersedes(t *testing.T) {
+	value, superseded, err := ParseSupersedes("shared.v2.USER_ERROR_NOT_FOUND = shared.v1.USER_NOT_FOUND")
+	if err != nil || value != "shared.v2.USER_ERROR_NOT_FOUND" || superseded != "shared.v1.USER_NOT_FOUND" {
+		t.Errorf("ParseSupersedes() = %q, %q, %v", value, superseded, err)
+	}
+	for _, s := range []string{"", "shared.v2.USER_ERROR_NOT_FOUND", "=shared.v1.USER_NOT_FOUND", "shared.v2.USER_ERROR_NOT_FOUND="} {
+		if _, _, err := ParseSupersedes(s); err == nil {
+			t.Errorf("ParseSupersedes(%q): expected error", s)
+		}
+	}
+}
+
+func TestValidateSupersedes(t *testing.T) {
+	plugin := testutil.PluginFromPB(t, "testdata/pb/versioned_errors.pb", "versioned_errors.proto")
+	config := &Config{Supersedes: map[string]string{"tests.versioned.v2.USER_ERROR_NOT_FOUND": "tests.versioned.v1.USER_NOT_FOUND"}}
+	if err := ValidateSupersedes(plugin.Files, config); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	config.Supersedes["tests.versioned.v2.USER_ERROR_DELETED"] = "tests.versioned.v1.USER_DELETED"
+	config.Supersedes["tests.versioned.v2.USER_ERROR_SUSPENDED"] = "tests.versioned.v2.USER_ERROR_SUSPENDED"
+	err := ValidateSupersedes(plugin.Files, config)
+	for _, want := range []string{
+		"tests.versioned.v1.USER_DELETED (in tests.versioned.v2.USER_ERROR_DELETED=tests.versioned.v1.USER_DELETED) is not an error enum value of this request",
+		"tests.versioned.v2.USER_ERROR_SUSPENDED supersedes itself",
+	} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("err = %v, want it to contain %q", err, want)
+		}
+	}
+}
+
+func TestValidateRecoverErrors(t *testing.T) {
+	plugin := testutil.PluginFromPB(t, "testdata/pb/basic_errors.pb", "basic_errors.proto")
+	config := &Config{RecoverErrors: []string{"tests.basic.USER_ERROR_PERMISSION_DENIED"}}
+	if err := ValidateRecoverErrors(plugin.Files, config); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	config.RecoverErrors = append(config.RecoverErrors, "tests.basic.USER_ERROR_NOT_FOUND", "tests.basic.NO_SUCH_VALUE")
+	err := ValidateRecoverErrors(plugin.Files, config)
+	for _, want := range []string{
+		"tests.basic.NO_SUCH_VALUE is not an error enum value of this request",
+		"tests.basic.USER_ERROR_PERMISSION_DENIED and tests.basic.USER_ERROR_NOT_FOUND are both designated for Go package",
+	} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("err = %v, want it to contain %q", err, want)
+		}
+	}
+}
+
+func TestResolveFallbackErrors(t *testing.T) {
+	plugin := testutil.PluginFromPB(t, "testdata/pb/fallback_errors.pb", "fallback_errors.proto")
+	config := &Config{
+		NewErrorsFunc:  testConfig.NewErrorsFunc,
+		FallbackErrors: []string{"tests.fallback.FALLBACK_ERROR_NOT_FOUND"},
+		FallbackOption: "tests.fallback.fallback_error",
+	}
+	if err := ResolveFallbackErrors(plugin.Files, config); err != nil {
+		t.Fatal(err)
+	}
+	if err := ValidateFallbackErrors(plugin.Files, config); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	g, err := GenerateFile(plugin, testutil.FileToGenerate(t, plugin), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if content := mustContent(t, g); !strings.Contains(content, "return FallbackError_FALLBACK_ERROR_INTERNAL.WithCause(err)") {
+		t.Errorf("AsSphereError does not wrap into the value of the file option:\n%s", content)
+	}
+
+	for _, tt := range []struct {
+		option, wantErr string
+	}{
+		{"tests.fallback.missing", "fallback option tests.fallback.missing is not an extension"},
+		{"sphere.errors.default_status", "must be a string extension of google.protobuf.FileOptions"},
+	} {
+		if err := ResolveFallbackErrors(plugin.Files, &Config{FallbackOption: tt.option}); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("ResolveFallbackErrors(%s) = %v, want error containing %q", tt.option, err, tt.wantErr)
+		}
+	}
+}
+
+func TestValidateFallbackErrors(t *testing.T) {
+	plugin := testutil.PluginFromPB(t, "testdata/pb/basic_errors.pb", "basic_errors.proto")
+	config := &Config{FallbackErrors: []string{"tests.basic.USER_ERROR_PERMISSION_DENIED"}}
+	if err := ValidateFallbackErrors(plugin.Files, config); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	config.FallbackErrors = append(config.FallbackErrors, "tests.basic.USER_ERROR_NOT_FOUND", "tests.basic.NO_SUCH_VALUE")
+	err := ValidateFallbackErrors(plugin.Files, config)
+	for _, want := range []string{
+		"tests.basic.NO_SUCH_VALUE (fallback_error) is not an error enum value of this request",
+		"tests.basic.USER_ERROR_PERMISSION_DENIED (fallback_error) and tests.basic.USER_ERROR_NOT_FOUND (fallback_error) are both designated for Go package",
+	} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("err = %v, want it to contain %q", err, want)
+		}
+	}
+}
+
+func TestGenerateFile_UnknownDetailType(t *testing.T) {
+	plugin := testutil.PluginFromPB(t, "testdata/pb/formatted_errors.pb", "formatted_errors.proto")
+	config := &Config{
+		NewErrorsFunc: testConfig.NewErrorsFunc,
+		DetailTypes:   map[string]string{"tests.formatted.QUOTA_ERROR_EXCEEDED": "tests.formatted.Missing"},
+	}
+	_, err := GenerateFile(plugin, testutil.FileToGenerate(t, plugin), config)
+	if err == nil || !strings.Contains(err.Error(), "detail type tests.formatted.Missing of tests.formatted.QuotaError.QUOTA_ERROR_EXCEEDED is not a message of this request") {
+		t.Errorf("err = %v, want unknown detail type error", err)
+	}
+}
+
+func TestParseCodeOffset(t *testing.T) {
+	tests := []struct {
+		in         string
+		wantPkg    string
+		wantOffset int32
+		wantErr    bool
+	}{
+		{in: "10000", wantOffset: 10000},
+		{in: "shared.v1=20000", wantPkg: "shared.v1", wantOffset: 20000},
+		{in: "shared.v1=x", wantErr: true},
+	}
+	for _, tt := range tests {
+		pkg, offset, err := ParseCodeOffset(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseCodeOffset(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if pkg != tt.wantPkg || offset != tt.wantOffset {
+			t.Errorf("ParseCodeOffset(%q) = %q, %d, want %q, %d", tt.in, pkg, offset, tt.wantPkg, tt.wantOffset)
+		}
+	}
+}
+
 // --- Layer 1: hand-written descriptors (skip logic, no extensions, no .pb) ---
 
 func TestGenerateFile_NoEnums(t *testing.T) {
@@ -218,26 +894,2002 @@ func TestGenerateFile_NoErrorAnnotations(t *testing.T) {
 	}
 }
 
-// --- helpers ---
+func TestGenerateFile_CustomTemplate(t *testing.T) {
+	plugin := testutil.PluginFromPB(t, "testdata/pb/basic_errors.pb", "basic_errors.proto")
+	config := &Config{
+		NewErrorsFunc: testConfig.NewErrorsFunc,
+		Template: `{{range .Errors}}
+// {{.Name}}_{{.Value}} is a custom rendering.
+func (e {{.Name}}) Is{{.Value}}() bool { return e == {{.Name}}_{{.Value}} }
+{{end}}`,
+	}
+	genFile, err := GenerateFile(plugin, testutil.FileToGenerate(t, plugin), config)
+	if err != nil {
+		t.Fatalf("GenerateFile failed: %v", err)
+	}
+	content := mustContent(t, genFile)
 
-func mustPluginFromFD(t *testing.T, fd *descriptorpb.FileDescriptorProto) *protogen.Plugin {
-	t.Helper()
-	req := &pluginpb.CodeGeneratorRequest{
-		FileToGenerate: []string{fd.GetName()},
-		ProtoFile:      []*descriptorpb.FileDescriptorProto{fd},
+	if !strings.Contains(content, "func (e UserError) IsUSER_ERROR_NOT_FOUND() bool") {
+		t.Error("expected custom template output in generated content")
 	}
-	plugin, err := protogen.Options{}.New(req)
+	if strings.Contains(content, "func (e UserError) Join(") {
+		t.Error("built-in template should not be used when a custom template is set")
+	}
+}
+
+func TestGenerateFile_InvalidTemplate(t *testing.T) {
+	plugin := testutil.PluginFromPB(t, "testdata/pb/basic_errors.pb", "basic_errors.proto")
+	config := &Config{NewErrorsFunc: testConfig.NewErrorsFunc, Template: "{{.Name"}
+	if _, err := GenerateFile(plugin, testutil.FileToGenerate(t, plugin), config); err == nil {
+		t.Fatal("expected error for malformed template, got nil")
+	}
+}
+
+func TestGenerateFile_FailOnDeprecatedUse(t *testing.T) {
+	plugin := testutil.PluginFromPB(t, "testdata/pb/basic_errors.pb", "basic_errors.proto")
+	config := &Config{
+		NewErrorsFunc:       testConfig.NewErrorsFunc,
+		SentinelErrors:      true,
+		FailOnDeprecatedUse: true,
+	}
+	genFile, err := GenerateFile(plugin, testutil.FileToGenerate(t, plugin), config)
 	if err != nil {
-		t.Fatalf("create plugin: %v", err)
+		t.Fatalf("GenerateFile failed: %v", err)
+	}
+	main := mustContent(t, genFile)
+	if strings.Contains(main, "ErrUserDefaulted") {
+		t.Error("deprecated sentinel should not be in the main file")
+	}
+	if !strings.Contains(main, "ErrUserNotFound") {
+		t.Error("non-deprecated sentinel missing from the main file")
+	}
+
+	resp := plugin.Response()
+	if len(resp.File) != 2 {
+		t.Fatalf("len(File) = %d, want 2", len(resp.File))
+	}
+	deprecated := resp.File[1]
+	if !strings.HasSuffix(deprecated.GetName(), "basic_errors.errors_deprecated.pb.go") {
+		t.Errorf("deprecated file name = %q", deprecated.GetName())
+	}
+	for _, want := range []string{
+		"//go:build !sphere_errors_strict",
+		"// Deprecated: UserError_USER_ERROR_DEFAULTED is deprecated.",
+		"func IsUserDefaulted(err error) bool",
+	} {
+		if !strings.Contains(deprecated.GetContent(), want) {
+			t.Errorf("deprecated file missing: %q", want)
+		}
 	}
-	return plugin
 }
 
-func mustContent(t *testing.T, g *protogen.GeneratedFile) string {
-	t.Helper()
-	b, err := g.Content()
+func TestGenerateFile_FailOnDeprecatedUseWithoutDeprecatedValues(t *testing.T) {
+	plugin := testutil.PluginFromPB(t, "testdata/pb/mixed_enums.pb", "mixed_enums.proto")
+	config := &Config{
+		NewErrorsFunc:       testConfig.NewErrorsFunc,
+		SentinelErrors:      true,
+		FailOnDeprecatedUse: true,
+	}
+	if _, err := GenerateFile(plugin, testutil.FileToGenerate(t, plugin), config); err != nil {
+		t.Fatalf("GenerateFile failed: %v", err)
+	}
+	if n := len(plugin.Response().File); n != 1 {
+		t.Errorf("len(File) = %d, want 1 (deprecated file should be skipped)", n)
+	}
+}
+
+func TestGenerateFile_FileNaming(t *testing.T) {
+	plugin := testutil.PluginFromPB(t, "testdata/pb/basic_errors.pb", "basic_errors.proto")
+	config := &Config{
+		NewErrorsFunc:       testConfig.NewErrorsFunc,
+		SentinelErrors:      true,
+		GenTests:            true,
+		FailOnDeprecatedUse: true,
+		FileSuffix:          "_errors.go",
+		BuildTag:            "!tinygo",
+		GeneratedBy:         "acme-errors",
+	}
+	if _, err := GenerateFile(plugin, testutil.FileToGenerate(t, plugin), config); err != nil {
+		t.Fatalf("GenerateFile failed: %v", err)
+	}
+	resp := plugin.Response()
+	want := map[string]string{
+		"basic_errors_errors.go":            "//go:build !tinygo\n",
+		"basic_errors_errors_test.go":       "//go:build !tinygo\n",
+		"basic_errors_errors_deprecated.go": "//go:build !tinygo && !sphere_errors_strict\n",
+	}
+	if len(resp.File) != len(want) {
+		t.Fatalf("len(File) = %d, want %d", len(resp.File), len(want))
+	}
+	for _, f := range resp.File {
+		constraint, ok := want[path.Base(f.GetName())]
+		if !ok {
+			t.Errorf("unexpected file %q", f.GetName())
+			continue
+		}
+		if !strings.HasPrefix(f.GetContent(), "// Code generated by acme-errors. DO NOT EDIT.\n") {
+			t.Errorf("%s: header not naming generated_by", f.GetName())
+		}
+		if !strings.Contains(f.GetContent(), constraint) {
+			t.Errorf("%s: missing build constraint %q", f.GetName(), constraint)
+		}
+	}
+}
+
+func TestValidateFileSuffix(t *testing.T) {
+	for _, s := range []string{"", ".errors.pb.go", "_errors.go"} {
+		if err := ValidateFileSuffix(s); err != nil {
+			t.Errorf("ValidateFileSuffix(%q) = %v", s, err)
+		}
+	}
+	for _, s := range []string{".go", "_errors.txt", "_errors_test.go", "sub/_errors.go"} {
+		if err := ValidateFileSuffix(s); err == nil {
+			t.Errorf("ValidateFileSuffix(%q): expected error", s)
+		}
+	}
+	if err := ValidateBuildTag("!tinygo && linux"); err != nil {
+		t.Errorf("ValidateBuildTag = %v", err)
+	}
+	if err := ValidateBuildTag("!tinygo &&"); err == nil {
+		t.Error("ValidateBuildTag: expected error for a malformed expression")
+	}
+}
+
+func TestGenerateFile_GenTests(t *testing.T) {
+	plugin := testutil.PluginFromPB(t, "testdata/pb/basic_errors.pb", "basic_errors.proto")
+	config := &Config{NewErrorsFunc: testConfig.NewErrorsFunc, GenTests: true}
+	if _, err := GenerateFile(plugin, testutil.FileToGenerate(t, plugin), config); err != nil {
+		t.Fatalf("GenerateFile failed: %v", err)
+	}
+	resp := plugin.Response()
+	if len(resp.File) != 2 {
+		t.Fatalf("len(File) = %d, want 2", len(resp.File))
+	}
+	tests := resp.File[1]
+	if !strings.HasSuffix(tests.GetName(), "basic_errors.errors.pb_test.go") {
+		t.Errorf("test file name = %q", tests.GetName())
+	}
+	for _, want := range []string{
+		"package basic",
+		"func TestUserError_Mappings(t *testing.T)",
+		"func TestOrderError_Mappings(t *testing.T)",
+		`{"USER_ERROR_NOT_FOUND", UserError_USER_ERROR_NOT_FOUND, 2, http.StatusNotFound, "user not found", "user does not exist"},`,
+		"if !errors.As(err, &got) || got != tt.err {",
+	} {
+		if !strings.Contains(tests.GetContent(), want) {
+			t.Errorf("test file missing: %q", want)
+		}
+	}
+	if strings.Contains(tests.GetContent(), "ParseUserError") {
+		t.Error("test file calls ParseUserError without parse_helpers")
+	}
+}
+
+func TestGenerateFile_ParseTests(t *testing.T) {
+	plugin := testutil.PluginFromPB(t, "testdata/pb/basic_errors.pb", "basic_errors.proto")
+	config := &Config{NewErrorsFunc: testConfig.NewErrorsFunc, GenTests: true, ParseHelpers: true}
+	if _, err := GenerateFile(plugin, testutil.FileToGenerate(t, plugin), config); err != nil {
+		t.Fatalf("GenerateFile failed: %v", err)
+	}
+	resp := plugin.Response()
+	if len(resp.File) != 2 {
+		t.Fatalf("len(File) = %d, want 2", len(resp.File))
+	}
+	tests := resp.File[1].GetContent()
+	for _, want := range []string{
+		"var coded interface{ GetCode() int32 }",
+		"if parsed, ok := ParseUserError(coded.GetCode()); tt.err != 0 && (!ok || parsed != tt.err) {",
+		"if parsed, ok := ParseOrderError(coded.GetCode()); tt.err != 0 && (!ok || parsed != tt.err) {",
+	} {
+		if !strings.Contains(tests, want) {
+			t.Errorf("test file missing: %q", want)
+		}
+	}
+}
+
+func TestGenerateFile_ErrorTest(t *testing.T) {
+	plugin := testutil.PluginFromPB(t, "testdata/pb/basic_errors.pb", "basic_errors.proto")
+	config := &Config{NewErrorsFunc: testConfig.NewErrorsFunc, ErrorTest: true}
+	if _, err := GenerateFile(plugin, testutil.FileToGenerate(t, plugin), config); err != nil {
+		t.Fatalf("GenerateFile failed: %v", err)
+	}
+	resp := plugin.Response()
+	if len(resp.File) != 2 {
+		t.Fatalf("len(File) = %d, want 2", len(resp.File))
+	}
+	helpers := resp.File[1]
+	if !strings.HasSuffix(helpers.GetName(), "testdata/basic/errortest/errortest.sphere.go") {
+		t.Errorf("errortest file name = %q", helpers.GetName())
+	}
+	for _, want := range []string{
+		"// Package errortest asserts on the errors of package basic in tests.\npackage errortest",
+		"func AssertCode(t testing.TB, err error, want Coded) bool {",
+		"func AssertUserError(t testing.TB, err error, want basic.UserError) bool {",
+		"func AssertOrderError(t testing.TB, err error, want basic.OrderError) bool {",
+		"func MatchCode(want Coded) CodeMatcher {",
+		`basic "github.com/go-sphere/protoc-gen-sphere-errors/generate/errors/testdata/basic"`,
+	} {
+		if !strings.Contains(helpers.GetContent(), want) {
+			t.Errorf("errortest file missing: %q", want)
+		}
+	}
+}
+
+func TestGenerateFile_PrebuiltTests(t *testing.T) {
+	plugin := testutil.PluginFromPB(t, "testdata/pb/basic_errors.pb", "basic_errors.proto")
+	config := &Config{NewErrorsFunc: testConfig.NewErrorsFunc, GenTests: true, Prebuilt: true}
+	if _, err := GenerateFile(plugin, testutil.FileToGenerate(t, plugin), config); err != nil {
+		t.Fatalf("GenerateFile failed: %v", err)
+	}
+	resp := plugin.Response()
+	if len(resp.File) != 2 {
+		t.Fatalf("len(File) = %d, want 2", len(resp.File))
+	}
+	tests := resp.File[1].GetContent()
+	for _, want := range []string{
+		"func TestUserError_ErrAllocs(t *testing.T)",
+		"testing.AllocsPerRun(100, func() {",
+		"func BenchmarkUserError_Err(b *testing.B)",
+		"func BenchmarkOrderError_Join(b *testing.B)",
+	} {
+		if !strings.Contains(tests, want) {
+			t.Errorf("test file missing: %q", want)
+		}
+	}
+}
+
+func TestGenerateFile_ConcurrentWithTests(t *testing.T) {
+	plugin := testutil.PluginFromPB(t, "testdata/pb/basic_errors.pb", "basic_errors.proto")
+	config := &Config{NewErrorsFunc: testConfig.NewErrorsFunc, GenTests: true, Prebuilt: true, Metadata: true}
+	if _, err := GenerateFile(plugin, testutil.FileToGenerate(t, plugin), config); err != nil {
+		t.Fatalf("GenerateFile failed: %v", err)
+	}
+	tests := plugin.Response().File[1].GetContent()
+	for _, want := range []string{
+		"func TestUserError_ConcurrentWith(t *testing.T) {",
+		"base := UserError_USER_ERROR_INVALID_ID.Err()",
+		`errs[i] = metadata.WithField(base, "worker", strconv.Itoa(i))`,
+		"var wg sync.WaitGroup",
+	} {
+		if !strings.Contains(tests, want) {
+			t.Errorf("test file missing: %q", want)
+		}
+	}
+}
+
+func TestGenerateFile_EmbedCatalog(t *testing.T) {
+	plugin := testutil.PluginFromPB(t, "testdata/pb/basic_errors.pb", "basic_errors.proto")
+	config := &Config{NewErrorsFunc: testConfig.NewErrorsFunc, EmbedCatalog: true}
+	if _, err := GenerateFile(plugin, testutil.FileToGenerate(t, plugin), config); err != nil {
+		t.Fatalf("GenerateFile failed: %v", err)
+	}
+	resp := plugin.Response()
+	if len(resp.File) != 2 {
+		t.Fatalf("len(File) = %d, want 2", len(resp.File))
+	}
+	if name := resp.File[1].GetName(); path.Base(name) != EmbeddedCatalogName || path.Dir(name) != path.Dir(resp.File[0].GetName()) {
+		t.Errorf("catalog written to %q, next to %q", name, resp.File[0].GetName())
+	}
+	descriptors, err := errcatalog.Load(fstest.MapFS{EmbeddedCatalogName: {Data: []byte(resp.File[1].GetContent())}}, EmbeddedCatalogName)
 	if err != nil {
-		t.Fatalf("GeneratedFile.Content() failed: %v", err)
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(descriptors) == 0 {
+		t.Fatal("empty catalog")
+	}
+	first := descriptors[0]
+	if first.Package != "tests.basic" || first.Enum != "UserError" || first.Source != "basic_errors.proto:15" || first.Status == 0 {
+		t.Errorf("first descriptor = %+v", first)
+	}
+}
+
+func TestGenerateFile_GenFuzz(t *testing.T) {
+	plugin := testutil.PluginFromPB(t, "testdata/pb/basic_errors.pb", "basic_errors.proto")
+	config := &Config{NewErrorsFunc: testConfig.NewErrorsFunc, GenFuzz: true}
+	genFile, err := GenerateFile(plugin, testutil.FileToGenerate(t, plugin), config)
+	if err != nil {
+		t.Fatalf("GenerateFile failed: %v", err)
+	}
+	content, err := genFile.Content()
+	if err != nil {
+		t.Fatalf("Content failed: %v", err)
+	}
+	if !strings.Contains(string(content), "func ParseUserError(code int32) (UserError, bool)") {
+		t.Error("gen_fuzz should imply the parse helpers")
+	}
+	resp := plugin.Response()
+	if len(resp.File) != 2 {
+		t.Fatalf("len(File) = %d, want 2", len(resp.File))
+	}
+	fuzz := resp.File[1]
+	if !strings.HasSuffix(fuzz.GetName(), "basic_errors.errors.pb_fuzz_test.go") {
+		t.Errorf("fuzz file name = %q", fuzz.GetName())
+	}
+	for _, want := range []string{
+		"func TestUserError_ParseRoundTrip(t *testing.T)",
+		"func FuzzUserError_Parse(f *testing.F)",
+		"func FuzzOrderErrorFromHTTPResponse(f *testing.F)",
+		"f.Add(http.StatusNotFound, []byte(`{\"code\":2}`))",
+		"func TestErrorCodesUnique(t *testing.T)",
+		`{"OrderError_ORDER_ERROR_OUT_OF_STOCK", strconv.Itoa(int(OrderError_ORDER_ERROR_OUT_OF_STOCK.GetCode()))},`,
+	} {
+		if !strings.Contains(fuzz.GetContent(), want) {
+			t.Errorf("fuzz file missing: %q", want)
+		}
+	}
+	if strings.Contains(fuzz.GetContent(), "UserError_USER_ERROR_UNSPECIFIED") {
+		t.Error("fuzz file should skip zero values")
+	}
+}
+
+func TestGenerateFile_Filters(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    Config
+		wantUser  bool
+		wantOrder bool
+	}{
+		{name: "none", wantUser: true, wantOrder: true},
+		{name: "include", config: Config{IncludeEnums: []string{"tests.basic.User*"}}, wantUser: true},
+		{name: "exclude", config: Config{ExcludeEnums: []string{"*.UserError"}}, wantOrder: true},
+		{name: "exclude wins", config: Config{IncludeEnums: []string{"tests.basic.*"}, ExcludeEnums: []string{"tests.basic.OrderError"}}, wantUser: true},
+		{name: "exclude file", config: Config{ExcludeFiles: []string{"basic_*.proto"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := tt.config
+			config.NewErrorsFunc = testConfig.NewErrorsFunc
+			plugin := testutil.PluginFromPB(t, "testdata/pb/basic_errors.pb", "basic_errors.proto")
+			genFile, err := GenerateFile(plugin, testutil.FileToGenerate(t, plugin), &config)
+			if err != nil {
+				t.Fatalf("GenerateFile failed: %v", err)
+			}
+			if !tt.wantUser && !tt.wantOrder {
+				if genFile != nil {
+					t.Error("expected no generated file, got one")
+				}
+				return
+			}
+			content := mustContent(t, genFile)
+			if got := strings.Contains(content, "func (e UserError) Error() string"); got != tt.wantUser {
+				t.Errorf("UserError generated = %v, want %v", got, tt.wantUser)
+			}
+			if got := strings.Contains(content, "func (e OrderError) Error() string"); got != tt.wantOrder {
+				t.Errorf("OrderError generated = %v, want %v", got, tt.wantOrder)
+			}
+		})
+	}
+}
+
+func TestValidatePatterns(t *testing.T) {
+	if err := ValidatePatterns("include_enums", []string{"a.*", "b.?Error"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := ValidatePatterns("include_enums", []string{"a.[*"}); err == nil {
+		t.Error("expected error for malformed pattern")
+	}
+}
+
+func TestSetSupportedFeatures(t *testing.T) {
+	plugin := testutil.PluginFromPB(t, "testdata/pb/editions_errors.pb", "editions_errors.proto")
+	SetSupportedFeatures(plugin)
+	resp := plugin.Response()
+	if resp.GetSupportedFeatures()&uint64(pluginpb.CodeGeneratorResponse_FEATURE_SUPPORTS_EDITIONS) == 0 {
+		t.Error("FEATURE_SUPPORTS_EDITIONS not advertised")
+	}
+	if resp.GetMinimumEdition() != int32(descriptorpb.Edition_EDITION_PROTO2) || resp.GetMaximumEdition() != int32(descriptorpb.Edition_EDITION_2023) {
+		t.Errorf("editions = %d..%d", resp.GetMinimumEdition(), resp.GetMaximumEdition())
+	}
+}
+
+func TestValidateCodes(t *testing.T) {
+	plugin := testutil.PluginFromPB(t, "testdata/pb/basic_errors.pb", "basic_errors.proto")
+	files := []*protogen.File{testutil.FileToGenerate(t, plugin)}
+
+	if err := ValidateCodes(files, &Config{}); err != nil {
+		t.Errorf("expected no error without checks, got %v", err)
+	}
+
+	err := ValidateCodes(files, &Config{UniqueCodes: true})
+	if err == nil {
+		t.Fatal("expected duplicate code error, got nil")
+	}
+	if want := "duplicate error code 1: tests.basic.UserError.USER_ERROR_INVALID_ID (basic_errors.proto:16) and tests.basic.OrderError.ORDER_ERROR_OUT_OF_STOCK (basic_errors.proto:39)"; !strings.Contains(err.Error(), want) {
+		t.Errorf("error = %q, want it to contain %q", err, want)
+	}
+
+	err = ValidateCodes(files, &Config{ReservedCodes: []CodeRange{{Start: 3, End: 9}}})
+	if err == nil {
+		t.Fatal("expected reserved range error, got nil")
+	}
+	for _, want := range []string{
+		"error code 3 of tests.basic.UserError.USER_ERROR_PERMISSION_DENIED (basic_errors.proto:27) is in reserved range 3-9",
+		"error code 4 of tests.basic.UserError.USER_ERROR_DEFAULTED",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error = %q, want it to contain %q", err, want)
+		}
+	}
+}
+
+func TestGenerateFile_CodeOffset(t *testing.T) {
+	tests := []struct {
+		name    string
+		offsets map[string]int32
+		want    string
+	}{
+		{name: "package offset", offsets: map[string]int32{"tests.basic": 40000, "": 1}, want: "return 40002"},
+		{name: "default offset", offsets: map[string]int32{"": 10000}, want: "return 10002"},
+		{name: "other package only", offsets: map[string]int32{"tests.other": 40000}, want: "return 2\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plugin := testutil.PluginFromPB(t, "testdata/pb/basic_errors.pb", "basic_errors.proto")
+			config := &Config{NewErrorsFunc: testConfig.NewErrorsFunc, CodeOffsets: tt.offsets}
+			genFile, err := GenerateFile(plugin, testutil.FileToGenerate(t, plugin), config)
+			if err != nil {
+				t.Fatalf("GenerateFile failed: %v", err)
+			}
+			content := mustContent(t, genFile)
+			getCode := content[strings.Index(content, "func (e UserError) GetCode() int32"):]
+			getCode = getCode[:strings.Index(getCode, "\n}\n")]
+			if !strings.Contains(getCode, "case UserError_USER_ERROR_NOT_FOUND:\n\t\t"+tt.want) {
+				t.Errorf("GetCode does not contain %q:\n%s", tt.want, getCode)
+			}
+		})
+	}
+}
+
+func TestGenerateFile_DefaultMessage(t *testing.T) {
+	plugin := testutil.PluginFromPB(t, "testdata/pb/basic_errors.pb", "basic_errors.proto")
+	config := &Config{
+		NewErrorsFunc:   testConfig.NewErrorsFunc,
+		DefaultMessages: map[string]string{"tests.basic.UserError": "invalid request"},
+	}
+	genFile, err := GenerateFile(plugin, testutil.FileToGenerate(t, plugin), config)
+	if err != nil {
+		t.Fatalf("GenerateFile failed: %v", err)
+	}
+	content := mustContent(t, genFile)
+	getMessage := content[strings.Index(content, "func (e UserError) GetMessage() string"):]
+	getMessage = getMessage[:strings.Index(getMessage, "\n}\n")]
+	for _, want := range []string{
+		"case UserError_USER_ERROR_NOT_FOUND:\n\t\treturn \"user does not exist\"",
+		"case UserError_USER_ERROR_PERMISSION_DENIED:\n\t\treturn \"invalid request\"",
+		"case UserError_USER_ERROR_DEFAULTED:\n\t\treturn \"invalid request\"",
+	} {
+		if !strings.Contains(getMessage, want) {
+			t.Errorf("GetMessage does not contain %q:\n%s", want, getMessage)
+		}
+	}
+	if strings.Contains(content, "case OrderError_ORDER_ERROR_UNSPECIFIED:\n\t\treturn \"invalid request\"") {
+		t.Error("default message leaked into OrderError")
+	}
+}
+
+func TestGenerateFile_OutputPackage(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   *Config
+		wantFile string
+		wantPkg  string
+		mirror   bool
+	}{
+		{
+			name:     "output package",
+			config:   &Config{OutputPackage: "example.com/api/apierrors"},
+			wantFile: "example.com/api/apierrors/basic_errors.errors.pb.go",
+			wantPkg:  "package apierrors\n",
+			mirror:   true,
+		},
+		{
+			name:     "output package with name",
+			config:   &Config{OutputPackage: "example.com/api/v1errors", OutputPackageName: "apierrors"},
+			wantFile: "example.com/api/v1errors/basic_errors.errors.pb.go",
+			wantPkg:  "package apierrors\n",
+			mirror:   true,
+		},
+		{
+			name:     "output package wins over suffix",
+			config:   &Config{OutputPackage: "example.com/api/apierrors", PackageSuffix: "ignored"},
+			wantFile: "example.com/api/apierrors/basic_errors.errors.pb.go",
+			wantPkg:  "package apierrors\n",
+			mirror:   true,
+		},
+		{
+			name:     "package suffix",
+			config:   &Config{PackageSuffix: "apierrors"},
+			wantFile: "github.com/go-sphere/protoc-gen-sphere-errors/generate/errors/testdata/basic/apierrors/basic_errors.errors.pb.go",
+			wantPkg:  "package apierrors\n",
+			mirror:   true,
+		},
+		{
+			name:     "default",
+			config:   &Config{},
+			wantFile: "github.com/go-sphere/protoc-gen-sphere-errors/generate/errors/testdata/basic/basic_errors.errors.pb.go",
+			wantPkg:  "package basic\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plugin := testutil.PluginFromPB(t, "testdata/pb/basic_errors.pb", "basic_errors.proto")
+			tt.config.NewErrorsFunc = testConfig.NewErrorsFunc
+			genFile, err := GenerateFile(plugin, testutil.FileToGenerate(t, plugin), tt.config)
+			if err != nil {
+				t.Fatalf("GenerateFile failed: %v", err)
+			}
+			content := mustContent(t, genFile)
+			if !strings.Contains(content, tt.wantPkg) {
+				t.Errorf("generated file does not declare %q", tt.wantPkg)
+			}
+			if got := strings.Contains(content, "type UserError basic.UserError"); got != tt.mirror {
+				t.Errorf("mirror type generated = %v, want %v", got, tt.mirror)
+			}
+			resp := plugin.Response()
+			if len(resp.File) != 1 || resp.File[0].GetName() != tt.wantFile {
+				t.Errorf("generated files = %v, want [%s]", resp.File, tt.wantFile)
+			}
+		})
+	}
+}
+
+func TestValidateOptions(t *testing.T) {
+	plugin := testutil.PluginFromPB(t, "testdata/pb/basic_errors.pb", "basic_errors.proto")
+	files := []*protogen.File{testutil.FileToGenerate(t, plugin)}
+
+	if err := ValidateOptions(files, &Config{}); err != nil {
+		t.Errorf("expected no error without strict, got %v", err)
+	}
+	err := ValidateOptions(files, &Config{Strict: true})
+	if err == nil {
+		t.Fatal("expected strict error, got nil")
+	}
+	for _, want := range []string{
+		"tests.basic.UserError.USER_ERROR_PERMISSION_DENIED (basic_errors.proto:27) has no message",
+		"tests.basic.UserError.USER_ERROR_DEFAULTED (basic_errors.proto:31) has no (sphere.errors.options)",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error = %q, want it to contain %q", err, want)
+		}
+	}
+	for _, unwanted := range []string{"UNSPECIFIED", "USER_ERROR_NOT_FOUND", "ORDER_ERROR_OUT_OF_STOCK"} {
+		if strings.Contains(err.Error(), unwanted) {
+			t.Errorf("error = %q, should not mention %s", err, unwanted)
+		}
+	}
+
+	err = ValidateOptions(files, &Config{Strict: true, DefaultMessages: map[string]string{"tests.basic.UserError": "invalid request"}})
+	if err == nil || strings.Contains(err.Error(), "has no message") {
+		t.Errorf("default message should satisfy strict, got %v", err)
+	}
+}
+
+func TestValidateSymbols(t *testing.T) {
+	plugin := mustPluginFromPBs(t, map[string]string{
+		"testdata/pb/basic_errors.pb":  "basic_errors.proto",
+		"testdata/pb/shared_errors.pb": "shared_errors.proto",
+	})
+
+	if err := ValidateSymbols(plugin.Files, &Config{SentinelErrors: true}); err != nil {
+		t.Errorf("separate Go packages should not conflict, got %v", err)
+	}
+
+	err := ValidateSymbols(plugin.Files, &Config{OutputPackage: "example.com/apierrors", SentinelErrors: true})
+	if err == nil {
+		t.Fatal("expected conflict error, got nil")
+	}
+	for _, want := range []string{
+		"UserError is declared by both tests.basic.UserError (basic_errors.proto) and tests.shared.UserError (shared_errors.proto) in Go package \"example.com/apierrors\"",
+		"ErrUserNotFound is declared by both",
+		"IsUserNotFound is declared by both",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error = %q, want it to contain %q", err, want)
+		}
+	}
+
+	// Imported files are generated elsewhere and never conflict.
+	for _, f := range plugin.Files {
+		if f.Desc.Path() == "shared_errors.proto" {
+			f.Generate = false
+		}
+	}
+	if err := ValidateSymbols(plugin.Files, &Config{OutputPackage: "example.com/apierrors", SentinelErrors: true}); err != nil {
+		t.Errorf("imported enums should not conflict, got %v", err)
+	}
+}
+
+func TestGenerateFile_MessageResolverOncePerPackage(t *testing.T) {
+	for _, tt := range []struct {
+		outputPackage protogen.GoImportPath
+		want          int
+	}{
+		{want: 2},
+		{outputPackage: "example.com/apierrors", want: 1},
+	} {
+		plugin := mustPluginFromPBs(t, map[string]string{
+			"testdata/pb/basic_errors.pb":     "basic_errors.proto",
+			"testdata/pb/formatted_errors.pb": "formatted_errors.proto",
+		})
+		config := &Config{NewErrorsFunc: testConfig.NewErrorsFunc, OutputPackage: tt.outputPackage, MessageResolver: true}
+		declared := 0
+		for _, f := range plugin.Files {
+			if !f.Generate {
+				continue
+			}
+			g, err := GenerateFile(plugin, f, config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Contains(mustContent(t, g), "func SetMessageResolver(") {
+				declared++
+			}
+		}
+		if declared != tt.want {
+			t.Errorf("output_package %q: SetMessageResolver declared %d times, want %d", tt.outputPackage, declared, tt.want)
+		}
+	}
+}
+
+func TestGenerateFile_Aggregate(t *testing.T) {
+	plugin := mustPluginFromPBs(t, map[string]string{
+		"testdata/pb/basic_errors.pb":     "basic_errors.proto",
+		"testdata/pb/formatted_errors.pb": "formatted_errors.proto",
+	})
+	config := &Config{NewErrorsFunc: testConfig.NewErrorsFunc, OutputPackage: "example.com/apierrors", Aggregate: true, GenTests: true, MessageResolver: true}
+	var generated []*protogen.GeneratedFile
+	for _, f := range plugin.Files {
+		if !f.Generate {
+			continue
+		}
+		g, err := GenerateFile(plugin, f, config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if g != nil {
+			generated = append(generated, g)
+		}
+	}
+	if len(generated) != 1 {
+		t.Fatalf("generated %d files, want one for the package", len(generated))
+	}
+	content := mustContent(t, generated[0])
+	for _, want := range []string{
+		"// source: basic_errors.proto\n// source: formatted_errors.proto\n",
+		"type UserError ",
+		"type QuotaError ",
+		"func SetMessageResolver(",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("aggregated file does not contain %q", want)
+		}
+	}
+	var names []string
+	for _, f := range plugin.Response().File {
+		names = append(names, f.GetName())
+	}
+	want := []string{"example.com/apierrors/errors.sphere.go", "example.com/apierrors/errors.sphere_test.go"}
+	if !slices.Equal(names, want) {
+		t.Errorf("files = %v, want %v", names, want)
+	}
+}
+
+func TestMergePackageErrors(t *testing.T) {
+	enumOpts := &descriptorpb.EnumOptions{}
+	proto.SetExtension(enumOpts, sphereerrors.E_DefaultStatus, int32(400))
+	file := func(name, goPackage, enum, prefix string, number int32) *descriptorpb.FileDescriptorProto {
+		return &descriptorpb.FileDescriptorProto{
+			Name:    proto.String(name),
+			Package: proto.String("tests.merge"),
+			Options: &descriptorpb.FileOptions{GoPackage: proto.String(goPackage)},
+			EnumType: []*descriptorpb.EnumDescriptorProto{{
+				Name:    proto.String(enum),
+				Options: enumOpts,
+				Value: []*descriptorpb.EnumValueDescriptorProto{
+					{Name: proto.String(prefix + "_UNSPECIFIED"), Number: proto.Int32(0)},
+					{Name: proto.String(prefix + "_FAILED"), Number: proto.Int32(number)},
+				},
+			}},
+		}
+	}
+	config := &Config{NewErrorsFunc: testConfig.NewErrorsFunc, MergePackageErrors: true, Aggregate: true}
+
+	plugin := mustPluginFromFD(t,
+		file("shop.proto", "github.com/example/merge", "ShopError", "SHOP_ERROR", 1001),
+		file("cart.proto", "github.com/example/merge", "CartError", "CART_ERROR", 2001))
+	if err := ValidateMergedPackages(plugin.Files, config); err != nil {
+		t.Fatalf("ValidateMergedPackages() = %v", err)
+	}
+	g, err := GenerateFile(plugin, plugin.Files[0], config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := mustContent(t, g)
+	for _, want := range []string{
+		"func ErrorByCode(code int32) (error, bool) {",
+		"return []error{\n\t\tShopError_SHOP_ERROR_FAILED,\n\t\tCartError_CART_ERROR_FAILED,\n\t}",
+		"case 2001:\n\t\treturn CartError_CART_ERROR_FAILED, true",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("merged file does not contain %q", want)
+		}
+	}
+
+	for _, tt := range []struct {
+		name string
+		fds  []*descriptorpb.FileDescriptorProto
+		want string
+	}{
+		{
+			name: "duplicate code",
+			fds: []*descriptorpb.FileDescriptorProto{
+				file("shop.proto", "github.com/example/merge", "ShopError", "SHOP_ERROR", 1001),
+				file("cart.proto", "github.com/example/merge", "CartError", "CART_ERROR", 1001),
+			},
+			want: "duplicate error code 1001 in proto package tests.merge: tests.merge.ShopError.SHOP_ERROR_FAILED (shop.proto) and tests.merge.CartError.CART_ERROR_FAILED (cart.proto)",
+		},
+		{
+			name: "split Go package",
+			fds: []*descriptorpb.FileDescriptorProto{
+				file("shop.proto", "github.com/example/shop", "ShopError", "SHOP_ERROR", 1001),
+				file("cart.proto", "github.com/example/cart", "CartError", "CART_ERROR", 2001),
+			},
+			want: "proto package tests.merge is generated into both Go packages github.com/example/shop and github.com/example/cart (cart.proto)",
+		},
+	} {
+		err := ValidateMergedPackages(mustPluginFromFD(t, tt.fds...).Files, config)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: ValidateMergedPackages() = %v, want error containing %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestLint(t *testing.T) {
+	enumOpts := &descriptorpb.EnumOptions{}
+	proto.SetExtension(enumOpts, sphereerrors.E_DefaultStatus, int32(400))
+	valueOpts := func(status int32, message string) *descriptorpb.EnumValueOptions {
+		opts := &descriptorpb.EnumValueOptions{}
+		proto.SetExtension(opts, sphereerrors.E_Options, &sphereerrors.Error{Status: status, Message: message})
+		return opts
+	}
+	fd := &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("lint.proto"),
+		Package: proto.String("tests.lint"),
+		Options: &descriptorpb.FileOptions{GoPackage: proto.String("github.com/example/lint")},
+		EnumType: []*descriptorpb.EnumDescriptorProto{
+			{
+				Name:    proto.String("ShopError"),
+				Options: enumOpts,
+				Value: []*descriptorpb.EnumValueDescriptorProto{
+					{Name: proto.String("SHOP_ERROR_UNSPECIFIED"), Number: proto.Int32(0), Options: valueOpts(400, "")},
+					{Name: proto.String("SHOP_ERROR_CLOSED"), Number: proto.Int32(1), Options: valueOpts(409, "The shop is closed.")},
+					{Name: proto.String("SHOP_ERROR_EMPTY"), Number: proto.Int32(2), Options: valueOpts(400, "cart is empty")},
+					{Name: proto.String("SHOP_ERROR_MISSING"), Number: proto.Int32(40401), Options: valueOpts(500, "Shop not found")},
+					{Name: proto.String("SHOP_ERROR_GONE"), Number: proto.Int32(41001), Options: valueOpts(410, "Shop is gone")},
+				},
+			},
+			{
+				Name:    proto.String("CartError"),
+				Options: enumOpts,
+				Value: []*descriptorpb.EnumValueDescriptorProto{
+					{Name: proto.String("CART_ERROR_FULL"), Number: proto.Int32(1), Options: valueOpts(400, "Cart is full")},
+				},
+			},
+		},
+	}
+	plugin := mustPluginFromFD(t, fd)
+	got := Lint(plugin.Files, &Config{})
+	want := []string{
+		"lint.proto: zero value tests.lint.SHOP_ERROR_UNSPECIFIED declares status 400, but is never an error",
+		`lint.proto: message of tests.lint.ShopError.SHOP_ERROR_CLOSED "The shop is closed." ends with a period`,
+		`lint.proto: message of tests.lint.ShopError.SHOP_ERROR_EMPTY "cart is empty" does not start with an upper-case letter`,
+		"lint.proto: code 40401 of tests.lint.ShopError.SHOP_ERROR_MISSING suggests status 404, but its status is 500",
+		"lint.proto: tests.lint.CartError has no zero value",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Lint() =\n  %s\nwant\n  %s", strings.Join(got, "\n  "), strings.Join(want, "\n  "))
+	}
+
+	plugin = testutil.PluginFromPB(t, "testdata/pb/basic_errors.pb", "basic_errors.proto")
+	findings := Lint(plugin.Files, &Config{})
+	if len(findings) == 0 {
+		t.Fatal("expected findings for the lower-case messages of basic_errors.proto")
+	}
+	for _, finding := range findings {
+		if !strings.HasPrefix(finding, "basic_errors.proto:") || strings.HasPrefix(finding, "basic_errors.proto: ") {
+			t.Errorf("finding %q has no line", finding)
+		}
+	}
+}
+
+func TestAssignGoNames(t *testing.T) {
+	for _, tt := range []struct {
+		style string
+		want  map[string]string
+	}{
+		{
+			style: "",
+			want: map[string]string{
+				"tests.basic.USER_ERROR_NOT_FOUND":     "TestsBasicUserNotFound",
+				"tests.shared.USER_ERROR_NOT_FOUND":    "TestsSharedUserNotFound",
+				"tests.basic.USER_ERROR_INVALID_ID":    "UserInvalidId",
+				"tests.basic.ORDER_ERROR_OUT_OF_STOCK": "OrderOutOfStock",
+			},
+		},
+		{
+			style: NameStyleValueOnly,
+			want: map[string]string{
+				"tests.basic.USER_ERROR_NOT_FOUND":     "TestsBasicUserNotFound",
+				"tests.shared.USER_ERROR_NOT_FOUND":    "TestsSharedUserNotFound",
+				"tests.basic.USER_ERROR_INVALID_ID":    "InvalidId",
+				"tests.basic.ORDER_ERROR_OUT_OF_STOCK": "OutOfStock",
+			},
+		},
+		{
+			style: NameStyleCamel,
+			want: map[string]string{
+				"tests.basic.USER_ERROR_NOT_FOUND":     "TestsBasicUserNotFound",
+				"tests.shared.USER_ERROR_NOT_FOUND":    "TestsSharedUserNotFound",
+				"tests.basic.USER_ERROR_INVALID_ID":    "UserErrorInvalidId",
+				"tests.basic.ORDER_ERROR_OUT_OF_STOCK": "OrderErrorOutOfStock",
+			},
+		},
+	} {
+		plugin := mustPluginFromPBs(t, map[string]string{
+			"testdata/pb/basic_errors.pb":  "basic_errors.proto",
+			"testdata/pb/shared_errors.pb": "shared_errors.proto",
+		})
+		config := &Config{OutputPackage: "example.com/apierrors", NameStyle: tt.style}
+		AssignGoNames(plugin.Files, config)
+		for value, want := range tt.want {
+			if got := config.goNames[value]; got != want {
+				t.Errorf("style %q: Go name of %s = %q, want %q", tt.style, value, got, want)
+			}
+		}
+
+		// In separate Go packages nothing collides.
+		config = &Config{NameStyle: tt.style}
+		AssignGoNames(plugin.Files, config)
+		if got := config.goNames["tests.shared.USER_ERROR_NOT_FOUND"]; strings.HasPrefix(got, "Tests") {
+			t.Errorf("style %q: Go name %q is qualified without a collision", tt.style, got)
+		}
+	}
+}
+
+func TestValidateNameStyle(t *testing.T) {
+	for _, style := range []string{"", NameStyleEnumPrefixed, NameStyleValueOnly, NameStyleCamel} {
+		if err := ValidateNameStyle(style); err != nil {
+			t.Errorf("ValidateNameStyle(%q) = %v", style, err)
+		}
+	}
+	if err := ValidateNameStyle("snake"); err == nil {
+		t.Error("expected an error for an unknown style")
+	}
+}
+
+func TestGenerateFile_RawStatus(t *testing.T) {
+	plugin := testutil.PluginFromPB(t, "testdata/pb/basic_errors.pb", "basic_errors.proto")
+	config := &Config{NewErrorsFunc: testConfig.NewErrorsFunc, RawStatus: true}
+	genFile, err := GenerateFile(plugin, testutil.FileToGenerate(t, plugin), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := mustContent(t, genFile)
+	if strings.Contains(content, "net/http") || !strings.Contains(content, "return 404") {
+		t.Error("raw_status should render statuses as literals")
+	}
+}
+
+func TestValidateStatuses(t *testing.T) {
+	enumOpts := func(status int32) *descriptorpb.EnumOptions {
+		opts := &descriptorpb.EnumOptions{}
+		proto.SetExtension(opts, sphereerrors.E_DefaultStatus, status)
+		return opts
+	}
+	valueOpts := &descriptorpb.EnumValueOptions{}
+	proto.SetExtension(valueOpts, sphereerrors.E_Options, &sphereerrors.Error{Status: 4040})
+	fd := &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("statuses.proto"),
+		Package: proto.String("tests.statuses"),
+		Options: &descriptorpb.FileOptions{GoPackage: proto.String("github.com/example/statuses")},
+		EnumType: []*descriptorpb.EnumDescriptorProto{
+			{
+				Name:    proto.String("TypoError"),
+				Options: enumOpts(400),
+				Value: []*descriptorpb.EnumValueDescriptorProto{
+					{Name: proto.String("TYPO_ERROR_UNSPECIFIED"), Number: proto.Int32(0)},
+					{Name: proto.String("TYPO_ERROR_NOT_FOUND"), Number: proto.Int32(1), Options: valueOpts},
+				},
+			},
+			{
+				Name:    proto.String("RangeError"),
+				Options: enumOpts(600),
+				Value: []*descriptorpb.EnumValueDescriptorProto{
+					{Name: proto.String("RANGE_ERROR_UNSPECIFIED"), Number: proto.Int32(0)},
+				},
+			},
+		},
+	}
+	err := ValidateStatuses(mustPluginFromFD(t, fd).Files, &Config{})
+	if err == nil {
+		t.Fatal("expected an error for invalid statuses")
+	}
+	for _, want := range []string{
+		"status 4040 of tests.statuses.TypoError.TYPO_ERROR_NOT_FOUND (statuses.proto)",
+		"default_status 600 of tests.statuses.RangeError (statuses.proto)",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error = %q, want it to contain %q", err, want)
+		}
+	}
+	if strings.Contains(err.Error(), "default_status 400") {
+		t.Errorf("error = %q, 400 is a valid status", err)
+	}
+
+	plugin := testutil.PluginFromPB(t, "testdata/pb/basic_errors.pb", "basic_errors.proto")
+	if err := ValidateStatuses(plugin.Files, &Config{}); err != nil {
+		t.Errorf("valid statuses rejected: %v", err)
+	}
+}
+
+// --- helpers ---
+
+// mustPluginFromPBs builds a plugin generating several files, keyed by the
+// descriptor set each is compiled into.
+func mustPluginFromPBs(t *testing.T, files map[string]string) *protogen.Plugin {
+	t.Helper()
+	req := &pluginpb.CodeGeneratorRequest{}
+	seen := map[string]bool{}
+	for _, pb := range slices.Sorted(maps.Keys(files)) {
+		for _, fd := range testutil.LoadDescriptorSet(t, pb).File {
+			if !seen[fd.GetName()] {
+				seen[fd.GetName()] = true
+				req.ProtoFile = append(req.ProtoFile, fd)
+			}
+		}
+		req.FileToGenerate = append(req.FileToGenerate, files[pb])
+	}
+	plugin, err := protogen.Options{}.New(req)
+	if err != nil {
+		t.Fatalf("create plugin: %v", err)
+	}
+	return plugin
+}
+
+func mustPluginFromFD(t *testing.T, fds ...*descriptorpb.FileDescriptorProto) *protogen.Plugin {
+	t.Helper()
+	req := &pluginpb.CodeGeneratorRequest{ProtoFile: fds}
+	for _, fd := range fds {
+		req.FileToGenerate = append(req.FileToGenerate, fd.GetName())
+	}
+	plugin, err := protogen.Options{}.New(req)
+	if err != nil {
+		t.Fatalf("create plugin: %v", err)
+	}
+	return plugin
+}
+
+func mustContent(t *testing.T, g *protogen.GeneratedFile) string {
+	t.Helper()
+	b, err := g.Content()
+	if err != nil {
+		t.Fatalf("GeneratedFile.Content() failed: %v", err)
+	}
+	return string(b)
+}
+
+func TestGateFiles(t *testing.T) {
+	tests := []struct {
+		name         string
+		pbFile       string
+		protoName    string
+		config       *Config
+		wantGenerate bool
+		wantErr      string
+	}{
+		{"default option undeclared", "testdata/pb/basic_errors.pb", "basic_errors.proto", &Config{}, true, ""},
+		{"opt_in without option", "testdata/pb/basic_errors.pb", "basic_errors.proto", &Config{GeneratePolicy: PolicyOptIn}, false, "generate option sphere.errors.generate is not an extension"},
+		{"explicit option undeclared", "testdata/pb/basic_errors.pb", "basic_errors.proto", &Config{GenerateOption: "tests.basic.generate"}, false, "generate option tests.basic.generate is not an extension"},
+		{"opted out", "testdata/pb/gated_errors.pb", "gated_errors.proto", &Config{GenerateOption: "tests.gated.generate_errors"}, false, ""},
+		{"not a bool", "testdata/pb/custom_options.pb", "custom_options.proto", &Config{GenerateOption: "tests.custom.http_default"}, false, "must be a bool extension of google.protobuf.FileOptions"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plugin := testutil.PluginFromPB(t, tt.pbFile, tt.protoName)
+			file := testutil.FileToGenerate(t, plugin)
+			err := GateFiles(plugin.Files, tt.config)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("GateFiles() = %v, want error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GateFiles() = %v", err)
+			}
+			if file.Generate != tt.wantGenerate {
+				t.Errorf("Generate = %v, want %v", file.Generate, tt.wantGenerate)
+			}
+		})
+	}
+}
+
+func TestResolveOptions(t *testing.T) {
+	plugin := testutil.PluginFromPB(t, "testdata/pb/custom_options.pb", "custom_options.proto")
+	config := &Config{OptionsType: "tests.custom.ErrorOptions", DefaultStatusOption: "tests.custom.http_default", Strict: true}
+	if err := ResolveOptions(plugin.Files, config); err != nil {
+		t.Fatal(err)
+	}
+	err := ValidateOptions(plugin.Files, config)
+	if err == nil || !strings.Contains(err.Error(), "tests.custom.PaymentError.PAYMENT_ERROR_UNANNOTATED (custom_options.proto:38) has no (tests.custom.error)") {
+		t.Errorf("ValidateOptions = %v, want the unannotated value named with the custom option", err)
+	}
+	enums := ErrorEnums(testutil.FileToGenerate(t, plugin), config)
+	if len(enums) != 1 || enums[0].Name != "PaymentError" {
+		t.Fatalf("ErrorEnums = %v, want only PaymentError", enums)
+	}
+	// Without option_field the status is read from a field named status,
+	// which ErrorOptions lacks.
+	if got := enums[0].Errors[2].Status; got != 402 {
+		t.Errorf("status of PAYMENT_ERROR_CONFLICT = %d, want the default 402", got)
+	}
+	if got := enums[0].Errors[1].Reason; got != "card declined" {
+		t.Errorf("reason of PAYMENT_ERROR_DECLINED = %q, want card declined", got)
+	}
+
+	for _, tt := range []struct {
+		name   string
+		config Config
+		want   string
+	}{
+		{name: "unknown type", config: Config{OptionsType: "tests.custom.Missing"}, want: "is not the type of an extension"},
+		{name: "unknown default status", config: Config{DefaultStatusOption: "tests.custom.error"}, want: "is not an extension of google.protobuf.EnumOptions"},
+		{name: "missing field", config: Config{OptionsType: "tests.custom.ErrorOptions", OptionFields: map[string]string{"status": "code"}}, want: "has no field code"},
+		{name: "string status", config: Config{OptionsType: "tests.custom.ErrorOptions", OptionFields: map[string]string{"status": "reason"}}, want: "cannot hold the status"},
+		{name: "fields without type", config: Config{OptionFields: map[string]string{"status": "http_code"}}, want: "option_field requires options_type"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ResolveOptions(plugin.Files, &tt.config); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ResolveOptions = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseOptionField(t *testing.T) {
+	field, name, err := ParseOptionField("status=http_code")
+	if err != nil || field != "status" || name != "http_code" {
+		t.Errorf("ParseOptionField = %q, %q, %v", field, name, err)
+	}
+	for _, s := range []string{"status", "code=http_code", "message="} {
+		if _, _, err := ParseOptionField(s); err == nil {
+			t.Errorf("ParseOptionField(%q) should fail", s)
+		}
+	}
+}
+
+func TestGRPCCode(t *testing.T) {
+	value, code, err := ParseGRPCCode("tests.basic.USER_ERROR_DEFAULTED=not_found")
+	if err != nil || value != "tests.basic.USER_ERROR_DEFAULTED" || code != "NOT_FOUND" {
+		t.Errorf("ParseGRPCCode = %q, %q, %v", value, code, err)
+	}
+	for _, s := range []string{"tests.basic.USER_ERROR_DEFAULTED", "=NOT_FOUND", "tests.basic.USER_ERROR_DEFAULTED=MISSING"} {
+		if _, _, err := ParseGRPCCode(s); err == nil {
+			t.Errorf("ParseGRPCCode(%q) succeeded, want an error", s)
+		}
+	}
+
+	enumOpts := &descriptorpb.EnumOptions{}
+	proto.SetExtension(enumOpts, sphereerrors.E_DefaultStatus, int32(400))
+	valueOpts := func(status int32, code string) *descriptorpb.EnumValueOptions {
+		var b []byte
+		if status != 0 {
+			b = protowire.AppendTag(b, 1, protowire.VarintType)
+			b = protowire.AppendVarint(b, uint64(status))
+		}
+		b = protowire.AppendTag(b, 2, protowire.BytesType)
+		b = protowire.AppendString(b, code)
+		opts := &descriptorpb.EnumValueOptions{}
+		opts.ProtoReflect().SetUnknown(protowire.AppendBytes(protowire.AppendTag(nil, 50101, protowire.BytesType), b))
+		return opts
+	}
+	fd := &descriptorpb.FileDescriptorProto{
+		Name:       proto.String("grpc_code.proto"),
+		Package:    proto.String("tests.grpccode"),
+		Dependency: []string{"google/protobuf/descriptor.proto"},
+		Options:    &descriptorpb.FileOptions{GoPackage: proto.String("github.com/example/grpccode")},
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("ErrorOptions"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				{Name: proto.String("status"), Number: proto.Int32(1), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(), Type: descriptorpb.FieldDescriptorProto_TYPE_INT32.Enum()},
+				{Name: proto.String("rpc_code"), Number: proto.Int32(2), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(), Type: descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum()},
+			},
+		}},
+		Extension: []*descriptorpb.FieldDescriptorProto{{
+			Name:     proto.String("error"),
+			Number:   proto.Int32(50101),
+			Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:     descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(),
+			TypeName: proto.String(".tests.grpccode.ErrorOptions"),
+			Extendee: proto.String(".google.protobuf.EnumValueOptions"),
+		}},
+		EnumType: []*descriptorpb.EnumDescriptorProto{{
+			Name:    proto.String("DocError"),
+			Options: enumOpts,
+			Value: []*descriptorpb.EnumValueDescriptorProto{
+				{Name: proto.String("DOC_ERROR_UNSPECIFIED"), Number: proto.Int32(0)},
+				{Name: proto.String("DOC_ERROR_MISSING"), Number: proto.Int32(1), Options: valueOpts(0, "NOT_FOUND")},
+				{Name: proto.String("DOC_ERROR_LOCKED"), Number: proto.Int32(2), Options: valueOpts(423, "ABORTED")},
+				{Name: proto.String("DOC_ERROR_BAD"), Number: proto.Int32(3), Options: valueOpts(0, "NO_SUCH_CODE")},
+			},
+		}},
+	}
+	plugin := mustPluginFromFD(t, protodesc.ToFileDescriptorProto(descriptorpb.File_google_protobuf_descriptor_proto), fd)
+	config := &Config{OptionsType: "tests.grpccode.ErrorOptions", OptionFields: map[string]string{"grpc_code": "rpc_code"}}
+	if err := ResolveOptions(plugin.Files, config); err != nil {
+		t.Fatal(err)
+	}
+	enums := ErrorEnums(plugin.Files[1], config)
+	for i, want := range []struct {
+		status int32
+		code   string
+	}{{404, "NOT_FOUND"}, {423, "ABORTED"}} {
+		if info := enums[0].Errors[i+1]; info.Status != want.status || info.GRPCCode != want.code {
+			t.Errorf("%s: status %d, grpc code %s, want %d, %s", info.Value, info.Status, info.GRPCCode, want.status, want.code)
+		}
+	}
+	err = ValidateGRPCCodes(plugin.Files, config)
+	if err == nil || !strings.Contains(err.Error(), `grpc code "NO_SUCH_CODE" of tests.grpccode.DocError.DOC_ERROR_BAD`) {
+		t.Errorf("ValidateGRPCCodes() = %v, want DOC_ERROR_BAD rejected", err)
+	}
+}
+
+func TestValidateGRPCCodes_UnknownValue(t *testing.T) {
+	plugin := testutil.PluginFromPB(t, "testdata/pb/basic_errors.pb", "basic_errors.proto")
+	config := &Config{GRPCCodes: map[string]string{"tests.basic.USER_ERROR_NOT_FOUND": "NOT_FOUND"}}
+	if err := ValidateGRPCCodes(plugin.Files, config); err != nil {
+		t.Fatalf("ValidateGRPCCodes() = %v, want nil", err)
+	}
+	config.GRPCCodes["tests.basic.UserError.USER_ERROR_NOT_FOUND"] = "NOT_FOUND"
+	err := ValidateGRPCCodes(plugin.Files, config)
+	if err == nil || !strings.Contains(err.Error(), "grpc_code tests.basic.UserError.USER_ERROR_NOT_FOUND is not an error enum value of this request") {
+		t.Errorf("ValidateGRPCCodes() = %v, want the enum-scoped name rejected", err)
+	}
+}
+
+func TestValidateMessageErrors(t *testing.T) {
+	if _, _, err := ParseMessageError("tests.formatted.QUOTA_ERROR_EXCEEDED"); err == nil {
+		t.Error("ParseMessageError without a message succeeded, want an error")
+	}
+	message, value, err := ParseMessageError("tests.formatted.QuotaViolation = tests.formatted.QUOTA_ERROR_EXCEEDED")
+	if err != nil || message != "tests.formatted.QuotaViolation" || value != "tests.formatted.QUOTA_ERROR_EXCEEDED" {
+		t.Fatalf("ParseMessageError = %q, %q, %v", message, value, err)
+	}
+	plugin := testutil.PluginFromPB(t, "testdata/pb/formatted_errors.pb", "formatted_errors.proto")
+	config := &Config{MessageErrors: map[string]string{message: value}}
+	if err := ValidateMessageErrors(plugin.Files, config); err != nil {
+		t.Fatalf("ValidateMessageErrors() = %v, want nil", err)
+	}
+	config.MessageErrors = map[string]string{
+		"tests.formatted.NoSuchMessage":  value,
+		"tests.formatted.QuotaViolation": "tests.formatted.NO_SUCH_VALUE",
+	}
+	err = ValidateMessageErrors(plugin.Files, config)
+	for _, want := range []string{"tests.formatted.NoSuchMessage (message error of tests.formatted.QUOTA_ERROR_EXCEEDED) is not a message", "tests.formatted.NO_SUCH_VALUE (message error of tests.formatted.QuotaViolation) is not an error enum value"} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("ValidateMessageErrors() = %v, want it to contain %q", err, want)
+		}
+	}
+}
+
+func TestZeroValue(t *testing.T) {
+	for _, policy := range []string{"", ZeroValueInclude, ZeroValueSkip, ZeroValueUnknown, ZeroValueFail} {
+		if err := ValidateZeroValue(policy); err != nil {
+			t.Errorf("ValidateZeroValue(%q) = %v", policy, err)
+		}
+	}
+	if err := ValidateZeroValue("drop"); err == nil {
+		t.Error("ValidateZeroValue(drop) succeeded, want an error")
+	}
+
+	enumOpts := &descriptorpb.EnumOptions{}
+	proto.SetExtension(enumOpts, sphereerrors.E_DefaultStatus, int32(400))
+	zeroOpts := &descriptorpb.EnumValueOptions{}
+	proto.SetExtension(zeroOpts, sphereerrors.E_Options, &sphereerrors.Error{Message: "no error"})
+	fd := &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("zero.proto"),
+		Package: proto.String("tests.zero"),
+		Options: &descriptorpb.FileOptions{GoPackage: proto.String("github.com/example/zero")},
+		EnumType: []*descriptorpb.EnumDescriptorProto{
+			{
+				Name:    proto.String("UserError"),
+				Options: enumOpts,
+				Value: []*descriptorpb.EnumValueDescriptorProto{
+					{Name: proto.String("USER_ERROR_UNSPECIFIED"), Number: proto.Int32(0)},
+					{Name: proto.String("USER_ERROR_NOT_FOUND"), Number: proto.Int32(1)},
+				},
+			},
+			{
+				Name:    proto.String("NoteError"),
+				Options: enumOpts,
+				Value: []*descriptorpb.EnumValueDescriptorProto{
+					{Name: proto.String("NOTE_ERROR_NONE"), Number: proto.Int32(0), Options: zeroOpts},
+				},
+			},
+		},
+	}
+	plugin := mustPluginFromFD(t, fd)
+
+	enums := ErrorEnums(plugin.Files[0], &Config{ZeroValue: ZeroValueSkip})
+	if len(enums) != 1 || len(enums[0].Errors) != 1 || enums[0].Errors[0].Value != "USER_ERROR_NOT_FOUND" {
+		t.Errorf("skip: enums = %v, want UserError without its zero value only", enums)
+	}
+	enums = ErrorEnums(plugin.Files[0], &Config{ZeroValue: ZeroValueUnknown})
+	if info := enums[0].Errors[0]; info.Status != 500 || info.GRPCCode != "UNKNOWN" || info.Reason != "UserError:UNKNOWN_ERROR" || info.Message != "unknown error" {
+		t.Errorf("unknown: zero value = %+v", info)
+	}
+	if info := enums[1].Errors[0]; info.Message != "no error" {
+		t.Errorf("unknown: declared message = %q, want it kept", info.Message)
+	}
+
+	if err := ValidateEnumValues(plugin.Files, &Config{ZeroValue: ZeroValueSkip}); err != nil {
+		t.Errorf("ValidateEnumValues(skip) = %v", err)
+	}
+	err := ValidateEnumValues(plugin.Files, &Config{ZeroValue: ZeroValueFail})
+	if err == nil || !strings.Contains(err.Error(), "zero value tests.zero.NoteError.NOTE_ERROR_NONE (zero.proto)") || strings.Contains(err.Error(), "USER_ERROR_UNSPECIFIED") {
+		t.Errorf("ValidateEnumValues(fail) = %v, want NOTE_ERROR_NONE rejected", err)
+	}
+
+	fd.EnumType[0].Value = append(fd.EnumType[0].Value, &descriptorpb.EnumValueDescriptorProto{Name: proto.String("USER_ERROR_LEGACY"), Number: proto.Int32(-1)})
+	err = ValidateEnumValues(mustPluginFromFD(t, fd).Files, &Config{})
+	if err == nil || !strings.Contains(err.Error(), "negative value tests.zero.UserError.USER_ERROR_LEGACY = -1") {
+		t.Errorf("ValidateEnumValues() = %v, want the negative value rejected", err)
+	}
+}
+
+func TestSLOExempt(t *testing.T) {
+	enumOpts := &descriptorpb.EnumOptions{}
+	proto.SetExtension(enumOpts, sphereerrors.E_DefaultStatus, int32(400))
+	valueOpts := func(exempt bool) *descriptorpb.EnumValueOptions {
+		b := protowire.AppendTag(nil, 1, protowire.VarintType)
+		b = protowire.AppendVarint(b, protowire.EncodeBool(exempt))
+		opts := &descriptorpb.EnumValueOptions{}
+		opts.ProtoReflect().SetUnknown(protowire.AppendBytes(protowire.AppendTag(nil, 50102, protowire.BytesType), b))
+		return opts
+	}
+	fd := &descriptorpb.FileDescriptorProto{
+		Name:       proto.String("slo.proto"),
+		Package:    proto.String("tests.slo"),
+		Dependency: []string{"google/protobuf/descriptor.proto"},
+		Options:    &descriptorpb.FileOptions{GoPackage: proto.String("github.com/example/slo")},
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("ErrorOptions"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				{Name: proto.String("expected"), Number: proto.Int32(1), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(), Type: descriptorpb.FieldDescriptorProto_TYPE_BOOL.Enum()},
+			},
+		}},
+		Extension: []*descriptorpb.FieldDescriptorProto{{
+			Name:     proto.String("error"),
+			Number:   proto.Int32(50102),
+			Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:     descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(),
+			TypeName: proto.String(".tests.slo.ErrorOptions"),
+			Extendee: proto.String(".google.protobuf.EnumValueOptions"),
+		}},
+		EnumType: []*descriptorpb.EnumDescriptorProto{{
+			Name:    proto.String("CartError"),
+			Options: enumOpts,
+			Value: []*descriptorpb.EnumValueDescriptorProto{
+				{Name: proto.String("CART_ERROR_UNSPECIFIED"), Number: proto.Int32(0)},
+				{Name: proto.String("CART_ERROR_EMPTY"), Number: proto.Int32(1), Options: valueOpts(true)},
+				{Name: proto.String("CART_ERROR_STORE_DOWN"), Number: proto.Int32(2)},
+				{Name: proto.String("CART_ERROR_LOCKED"), Number: proto.Int32(3), Options: valueOpts(false)},
+			},
+		}},
+	}
+	plugin := mustPluginFromFD(t, protodesc.ToFileDescriptorProto(descriptorpb.File_google_protobuf_descriptor_proto), fd)
+	config := &Config{
+		OptionsType:     "tests.slo.ErrorOptions",
+		OptionFields:    map[string]string{"slo_exempt": "expected"},
+		SLOExemptValues: map[string]bool{"tests.slo.CART_ERROR_LOCKED": true},
+	}
+	if err := ResolveOptions(plugin.Files, config); err != nil {
+		t.Fatal(err)
+	}
+	if !config.sloHelpers() {
+		t.Error("sloHelpers() = false, want true")
+	}
+	for i, want := range []bool{false, true, false, false} {
+		if info := ErrorEnums(plugin.Files[1], config)[0].Errors[i]; info.SLOExempt != want {
+			t.Errorf("%s: SLOExempt = %v, want %v", info.Value, info.SLOExempt, want)
+		}
+	}
+
+	config = &Config{OptionsType: "tests.slo.ErrorOptions", OptionFields: map[string]string{"slo_exempt": "missing"}}
+	if err := ResolveOptions(plugin.Files, config); err == nil {
+		t.Error("ResolveOptions succeeded, want the missing slo_exempt field rejected")
+	}
+}
+
+func TestParseClientCode(t *testing.T) {
+	name, code, err := ParseClientCode("shop.v1.ORDER_ERROR_NOT_FOUND = 404")
+	if err != nil || name != "shop.v1.ORDER_ERROR_NOT_FOUND" || code != 404 {
+		t.Errorf("ParseClientCode() = %q, %d, %v", name, code, err)
+	}
+	for _, s := range []string{"", "shop.v1.OrderError", "=404", "shop.v1.OrderError=0", "shop.v1.OrderError=-4", "shop.v1.OrderError=x"} {
+		if _, _, err := ParseClientCode(s); err == nil {
+			t.Errorf("ParseClientCode(%q): expected error", s)
+		}
+	}
+}
+
+func TestClientCodes(t *testing.T) {
+	enumOpts := &descriptorpb.EnumOptions{}
+	proto.SetExtension(enumOpts, sphereerrors.E_DefaultStatus, int32(400))
+	valueOpts := func(code int32) *descriptorpb.EnumValueOptions {
+		b := protowire.AppendTag(nil, 1, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(int64(code)))
+		opts := &descriptorpb.EnumValueOptions{}
+		opts.ProtoReflect().SetUnknown(protowire.AppendBytes(protowire.AppendTag(nil, 50102, protowire.BytesType), b))
+		return opts
+	}
+	fd := &descriptorpb.FileDescriptorProto{
+		Name:       proto.String("client.proto"),
+		Package:    proto.String("tests.client"),
+		Dependency: []string{"google/protobuf/descriptor.proto"},
+		Options:    &descriptorpb.FileOptions{GoPackage: proto.String("github.com/example/client")},
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("ErrorOptions"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				{Name: proto.String("public_code"), Number: proto.Int32(1), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(), Type: descriptorpb.FieldDescriptorProto_TYPE_INT32.Enum()},
+			},
+		}},
+		Extension: []*descriptorpb.FieldDescriptorProto{{
+			Name:     proto.String("error"),
+			Number:   proto.Int32(50102),
+			Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:     descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(),
+			TypeName: proto.String(".tests.client.ErrorOptions"),
+			Extendee: proto.String(".google.protobuf.EnumValueOptions"),
+		}},
+		EnumType: []*descriptorpb.EnumDescriptorProto{{
+			Name:    proto.String("CartError"),
+			Options: enumOpts,
+			Value: []*descriptorpb.EnumValueDescriptorProto{
+				{Name: proto.String("CART_ERROR_UNSPECIFIED"), Number: proto.Int32(0)},
+				{Name: proto.String("CART_ERROR_EMPTY"), Number: proto.Int32(1), Options: valueOpts(4000)},
+				{Name: proto.String("CART_ERROR_STORE_DOWN"), Number: proto.Int32(2)},
+				{Name: proto.String("CART_ERROR_LOCKED"), Number: proto.Int32(3)},
+			},
+		}},
+	}
+	plugin := mustPluginFromFD(t, protodesc.ToFileDescriptorProto(descriptorpb.File_google_protobuf_descriptor_proto), fd)
+	config := &Config{
+		OptionsType:  "tests.client.ErrorOptions",
+		OptionFields: map[string]string{"client_code": "public_code"},
+		ClientCodes:  map[string]int32{"tests.client.CartError": 5000, "tests.client.CART_ERROR_LOCKED": 4000},
+	}
+	if err := ResolveOptions(plugin.Files, config); err != nil {
+		t.Fatal(err)
+	}
+	if err := ValidateClientCodes(plugin.Files, config); err != nil {
+		t.Errorf("ValidateClientCodes() = %v", err)
+	}
+	ew := ErrorEnums(plugin.Files[1], config)[0]
+	for i, want := range []int32{0, 4000, 5000, 4000} {
+		if info := ew.Errors[i]; info.ClientCode != want {
+			t.Errorf("%s: ClientCode = %d, want %d", info.Value, info.ClientCode, want)
+		}
+	}
+
+	fd.EnumType[0].Value[2].Options = valueOpts(-1)
+	plugin = mustPluginFromFD(t, protodesc.ToFileDescriptorProto(descriptorpb.File_google_protobuf_descriptor_proto), fd)
+	if err := ResolveOptions(plugin.Files, config); err != nil {
+		t.Fatal(err)
+	}
+	err := ValidateClientCodes(plugin.Files, config)
+	if err == nil || !strings.Contains(err.Error(), "client code -1 of tests.client.CartError.CART_ERROR_STORE_DOWN") {
+		t.Errorf("ValidateClientCodes() = %v, want the negative client code rejected", err)
+	}
+	var d *Diagnostics
+	if !stderrors.As(err, &d) || len(d.Problems) != 1 {
+		t.Fatalf("ValidateClientCodes() = %#v, want one diagnostic", err)
+	}
+	if got, want := d.Problems[0], (Diagnostic{File: "client.proto", Enum: "tests.client.CartError", Value: "CART_ERROR_STORE_DOWN", Message: "client code -1 of tests.client.CartError.CART_ERROR_STORE_DOWN (client.proto)"}); got != want {
+		t.Errorf("diagnostic = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseGoPackageDir(t *testing.T) {
+	importPath, dir, err := ParseGoPackageDir("github.com/acme/mono/billing/api = billing/api")
+	if err != nil || importPath != "github.com/acme/mono/billing/api" || dir != "billing/api" {
+		t.Errorf("ParseGoPackageDir() = %q, %q, %v", importPath, dir, err)
+	}
+	for _, s := range []string{"", "example.com/api", "=api", "example.com/api=/abs", "example.com/api=../up", "example.com/api=a/../b", "example.com/api=.."} {
+		if _, _, err := ParseGoPackageDir(s); err == nil {
+			t.Errorf("ParseGoPackageDir(%q): expected error", s)
+		}
+	}
+}
+
+func TestParseLegacyPattern(t *testing.T) {
+	value, pattern, err := ParseLegacyPattern("tests.shop.CART_ERROR_EMPTY = ^cart=empty$")
+	if err != nil || value != "tests.shop.CART_ERROR_EMPTY" || pattern != "^cart=empty$" {
+		t.Errorf("ParseLegacyPattern() = %q, %q, %v", value, pattern, err)
+	}
+	for _, s := range []string{"", "tests.shop.CART_ERROR_EMPTY", "=empty", "tests.shop.CART_ERROR_EMPTY=(empty"} {
+		if _, _, err := ParseLegacyPattern(s); err == nil {
+			t.Errorf("ParseLegacyPattern(%q): expected error", s)
+		}
+	}
+}
+
+func TestLegacyPatterns(t *testing.T) {
+	enumOpts := &descriptorpb.EnumOptions{}
+	proto.SetExtension(enumOpts, sphereerrors.E_DefaultStatus, int32(400))
+	valueOpts := func(pattern string) *descriptorpb.EnumValueOptions {
+		b := protowire.AppendString(protowire.AppendTag(nil, 1, protowire.BytesType), pattern)
+		opts := &descriptorpb.EnumValueOptions{}
+		opts.ProtoReflect().SetUnknown(protowire.AppendBytes(protowire.AppendTag(nil, 50102, protowire.BytesType), b))
+		return opts
+	}
+	fd := &descriptorpb.FileDescriptorProto{
+		Name:       proto.String("legacy.proto"),
+		Package:    proto.String("tests.legacy"),
+		Dependency: []string{"google/protobuf/descriptor.proto"},
+		Options:    &descriptorpb.FileOptions{GoPackage: proto.String("github.com/example/legacy")},
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("ErrorOptions"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				{Name: proto.String("legacy_match"), Number: proto.Int32(1), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(), Type: descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum()},
+			},
+		}},
+		Extension: []*descriptorpb.FieldDescriptorProto{{
+			Name:     proto.String("error"),
+			Number:   proto.Int32(50102),
+			Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:     descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(),
+			TypeName: proto.String(".tests.legacy.ErrorOptions"),
+			Extendee: proto.String(".google.protobuf.EnumValueOptions"),
+		}},
+		EnumType: []*descriptorpb.EnumDescriptorProto{{
+			Name:    proto.String("CartError"),
+			Options: enumOpts,
+			Value: []*descriptorpb.EnumValueDescriptorProto{
+				{Name: proto.String("CART_ERROR_UNSPECIFIED"), Number: proto.Int32(0)},
+				{Name: proto.String("CART_ERROR_EMPTY"), Number: proto.Int32(1), Options: valueOpts("cart is empty")},
+				{Name: proto.String("CART_ERROR_FULL"), Number: proto.Int32(2), Options: valueOpts("too many")},
+				{Name: proto.String("CART_ERROR_LOCKED"), Number: proto.Int32(3)},
+			},
+		}},
+	}
+	plugin := mustPluginFromFD(t, protodesc.ToFileDescriptorProto(descriptorpb.File_google_protobuf_descriptor_proto), fd)
+	config := &Config{
+		OptionsType:    "tests.legacy.ErrorOptions",
+		OptionFields:   map[string]string{"legacy_pattern": "legacy_match"},
+		LegacyPatterns: map[string]string{"tests.legacy.CART_ERROR_FULL": "full", "tests.legacy.CART_ERROR_LOCKED": "locked"},
+	}
+	if err := ResolveOptions(plugin.Files, config); err != nil {
+		t.Fatal(err)
+	}
+	if err := ValidateLegacyPatterns(plugin.Files, config); err != nil {
+		t.Errorf("ValidateLegacyPatterns() = %v", err)
+	}
+	ew := ErrorEnums(plugin.Files[1], config)[0]
+	for i, want := range []string{"", "cart is empty", "too many", "locked"} {
+		if info := ew.Errors[i]; info.LegacyPattern != want {
+			t.Errorf("%s: LegacyPattern = %q, want %q", info.Value, info.LegacyPattern, want)
+		}
+	}
+
+	fd.EnumType[0].Value[2].Options = valueOpts("(too many")
+	config.LegacyPatterns["tests.legacy.CART_ERROR_UNSPECIFIED"] = "unknown"
+	plugin = mustPluginFromFD(t, protodesc.ToFileDescriptorProto(descriptorpb.File_google_protobuf_descriptor_proto), fd)
+	if err := ResolveOptions(plugin.Files, config); err != nil {
+		t.Fatal(err)
+	}
+	err := ValidateLegacyPatterns(plugin.Files, config)
+	for _, want := range []string{`legacy pattern "(too many" of tests.legacy.CART_ERROR_FULL`, "tests.legacy.CART_ERROR_UNSPECIFIED (legacy.proto) is a zero value"} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("ValidateLegacyPatterns() = %v, want it to contain %q", err, want)
+		}
+	}
+}
+
+func TestParseStability(t *testing.T) {
+	name, stability, err := ParseStability("shop.v1.CartError = experimental")
+	if err != nil || name != "shop.v1.CartError" || stability != StabilityExperimental {
+		t.Errorf("ParseStability() = %q, %q, %v", name, stability, err)
+	}
+	for _, s := range []string{"", "shop.v1.CartError", "=BETA", "shop.v1.CartError=ALPHA"} {
+		if _, _, err := ParseStability(s); err == nil {
+			t.Errorf("ParseStability(%q): expected error", s)
+		}
+	}
+}
+
+func TestPrereleaseVersion(t *testing.T) {
+	for version, want := range map[string]bool{"v1": false, "v2": false, "v1beta1": true, "v1alpha": true, "beta": false, "shop": false} {
+		if got := prereleaseVersion.MatchString(version); got != want {
+			t.Errorf("prereleaseVersion.MatchString(%q) = %v, want %v", version, got, want)
+		}
+	}
+}
+
+func TestVisibility(t *testing.T) {
+	enumOpts := &descriptorpb.EnumOptions{}
+	proto.SetExtension(enumOpts, sphereerrors.E_DefaultStatus, int32(500))
+	valueOpts := func(visibility string) *descriptorpb.EnumValueOptions {
+		b := protowire.AppendString(protowire.AppendTag(nil, 1, protowire.BytesType), visibility)
+		opts := &descriptorpb.EnumValueOptions{}
+		opts.ProtoReflect().SetUnknown(protowire.AppendBytes(protowire.AppendTag(nil, 50102, protowire.BytesType), b))
+		return opts
+	}
+	fd := &descriptorpb.FileDescriptorProto{
+		Name:       proto.String("visibility.proto"),
+		Package:    proto.String("tests.visibility"),
+		Dependency: []string{"google/protobuf/descriptor.proto"},
+		Options:    &descriptorpb.FileOptions{GoPackage: proto.String("github.com/example/visibility")},
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("ErrorOptions"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				{Name: proto.String("visibility"), Number: proto.Int32(1), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(), Type: descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum()},
+			},
+		}},
+		Extension: []*descriptorpb.FieldDescriptorProto{{
+			Name:     proto.String("error"),
+			Number:   proto.Int32(50102),
+			Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:     descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(),
+			TypeName: proto.String(".tests.visibility.ErrorOptions"),
+			Extendee: proto.String(".google.protobuf.EnumValueOptions"),
+		}},
+		EnumType: []*descriptorpb.EnumDescriptorProto{{
+			Name:    proto.String("StoreError"),
+			Options: enumOpts,
+			Value: []*descriptorpb.EnumValueDescriptorProto{
+				{Name: proto.String("STORE_ERROR_UNSPECIFIED"), Number: proto.Int32(0)},
+				{Name: proto.String("STORE_ERROR_SHARD_LOST"), Number: proto.Int32(1), Options: valueOpts("INTERNAL")},
+				{Name: proto.String("STORE_ERROR_DISK_FULL"), Number: proto.Int32(2)},
+				{Name: proto.String("STORE_ERROR_SOLD_OUT"), Number: proto.Int32(3), Options: valueOpts("PUBLIC")},
+			},
+		}},
+	}
+	plugin := mustPluginFromFD(t, protodesc.ToFileDescriptorProto(descriptorpb.File_google_protobuf_descriptor_proto), fd)
+	config := &Config{
+		OptionsType: "tests.visibility.ErrorOptions",
+		Visibilities: map[string]string{
+			"tests.visibility.StoreError":              VisibilityInternal,
+			"tests.visibility.STORE_ERROR_UNSPECIFIED": VisibilityPublic,
+		},
+	}
+	if err := ResolveOptions(plugin.Files, config); err != nil {
+		t.Fatal(err)
+	}
+	if err := ValidateVisibilities(plugin.Files, config); err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []bool{false, true, true, false} {
+		if info := ErrorEnums(plugin.Files[1], config)[0].Errors[i]; info.Internal != want {
+			t.Errorf("%s: Internal = %v, want %v", info.Value, info.Internal, want)
+		}
+	}
+
+	fd.EnumType[0].Value[3].Options = valueOpts("secret")
+	plugin = mustPluginFromFD(t, protodesc.ToFileDescriptorProto(descriptorpb.File_google_protobuf_descriptor_proto), fd)
+	config = &Config{OptionsType: "tests.visibility.ErrorOptions"}
+	if err := ResolveOptions(plugin.Files, config); err != nil {
+		t.Fatal(err)
+	}
+	if err := ValidateVisibilities(plugin.Files, config); err == nil || !strings.Contains(err.Error(), `visibility "secret" of tests.visibility.StoreError.STORE_ERROR_SOLD_OUT`) {
+		t.Errorf("ValidateVisibilities error = %v, want the unknown visibility reported", err)
+	}
+}
+
+func TestGenerateFile_InternalPackage(t *testing.T) {
+	plugin := testutil.PluginFromPB(t, "testdata/pb/basic_errors.pb", "basic_errors.proto")
+	config := &Config{NewErrorsFunc: testConfig.NewErrorsFunc, Visibilities: map[string]string{"tests.basic.USER_ERROR_PERMISSION_DENIED": VisibilityInternal}}
+	if _, err := GenerateFile(plugin, testutil.FileToGenerate(t, plugin), config); err != nil {
+		t.Fatalf("GenerateFile failed: %v", err)
+	}
+	resp := plugin.Response()
+	if len(resp.File) != 2 {
+		t.Fatalf("len(File) = %d, want 2", len(resp.File))
+	}
+	internal := resp.File[1]
+	if !strings.HasSuffix(internal.GetName(), "testdata/basic/internal/internalerrors/internalerrors.sphere.go") {
+		t.Errorf("internal file name = %q", internal.GetName())
+	}
+	for _, want := range []string{
+		"package internalerrors",
+		"func NewUserPermissionDenied(errs ...error) error {",
+		"return basic.UserError_USER_ERROR_PERMISSION_DENIED.Join(errs...)",
+	} {
+		if !strings.Contains(internal.GetContent(), want) {
+			t.Errorf("internal file missing: %q", want)
+		}
+	}
+	if strings.Contains(internal.GetContent(), "NewUserNotFound") {
+		t.Error("internal file holds a constructor of a public value")
+	}
+}
+
+func TestParseVisibility(t *testing.T) {
+	name, visibility, err := ParseVisibility("tests.basic.UserError=Internal")
+	if err != nil || name != "tests.basic.UserError" || visibility != VisibilityInternal {
+		t.Errorf("ParseVisibility = %q, %q, %v", name, visibility, err)
+	}
+	for _, s := range []string{"tests.basic.UserError", "=internal", "tests.basic.UserError=secret"} {
+		if _, _, err := ParseVisibility(s); err == nil {
+			t.Errorf("ParseVisibility(%q) succeeded, want an error", s)
+		}
+	}
+}
+
+func TestParseOTelEvent(t *testing.T) {
+	name, event, err := ParseOTelEvent("tests.basic.UserError = user.error")
+	if err != nil || name != "tests.basic.UserError" || event != "user.error" {
+		t.Errorf("ParseOTelEvent = %q, %q, %v", name, event, err)
+	}
+	for _, s := range []string{"tests.basic.UserError", "=user.error", "tests.basic.UserError="} {
+		if _, _, err := ParseOTelEvent(s); err == nil {
+			t.Errorf("ParseOTelEvent(%q) succeeded, want an error", s)
+		}
+	}
+}
+
+func TestParseSpanError(t *testing.T) {
+	name, marks, err := ParseSpanError("tests.basic.USER_ERROR_NOT_FOUND=true")
+	if err != nil || name != "tests.basic.USER_ERROR_NOT_FOUND" || !marks {
+		t.Errorf("ParseSpanError = %q, %v, %v", name, marks, err)
+	}
+	for _, s := range []string{"tests.basic.UserError", "=false", "tests.basic.UserError=maybe"} {
+		if _, _, err := ParseSpanError(s); err == nil {
+			t.Errorf("ParseSpanError(%q) succeeded, want an error", s)
+		}
+	}
+}
+
+func TestValidatePlaceholders(t *testing.T) {
+	enumOpts := &descriptorpb.EnumOptions{}
+	proto.SetExtension(enumOpts, sphereerrors.E_DefaultStatus, int32(400))
+	valueOpts := func(message string) *descriptorpb.EnumValueOptions {
+		opts := &descriptorpb.EnumValueOptions{}
+		proto.SetExtension(opts, sphereerrors.E_Options, &sphereerrors.Error{Message: message})
+		return opts
+	}
+	fd := &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("placeholders.proto"),
+		Package: proto.String("tests.placeholders"),
+		Options: &descriptorpb.FileOptions{GoPackage: proto.String("github.com/example/placeholders")},
+		EnumType: []*descriptorpb.EnumDescriptorProto{{
+			Name:    proto.String("ShopError"),
+			Options: enumOpts,
+			Value: []*descriptorpb.EnumValueDescriptorProto{
+				{Name: proto.String("SHOP_ERROR_UNSPECIFIED"), Number: proto.Int32(0)},
+				{Name: proto.String("SHOP_ERROR_CLOSED"), Number: proto.Int32(1), Options: valueOpts("shop {shop_id} closed until {date}, see {shop_id}")},
+				{Name: proto.String("SHOP_ERROR_MIXED"), Number: proto.Int32(2), Options: valueOpts("shop {shop_id} has %d items")},
+				{Name: proto.String("SHOP_ERROR_CLASH"), Number: proto.Int32(3), Options: valueOpts("{user_id} or {user__id}")},
+			},
+		}},
+	}
+	plugin := mustPluginFromFD(t, fd)
+	got := ErrorEnums(plugin.Files[0], &Config{})[0].Errors[1].Placeholders()
+	if len(got) != 2 || got[0].Name != "shop_id" || got[0].Field != "ShopId" || got[0].Param != "shopId" || got[1].Name != "date" {
+		t.Errorf("Placeholders() = %+v, want shop_id then date", got)
+	}
+	err := ValidatePlaceholders(plugin.Files, &Config{})
+	if err == nil {
+		t.Fatal("ValidatePlaceholders succeeded, want the mixed and clashing messages rejected")
+	}
+	for _, want := range []string{
+		"message of tests.placeholders.ShopError.SHOP_ERROR_MIXED (placeholders.proto) mixes named placeholders and printf verbs",
+		"placeholders {user_id} and {user__id} of tests.placeholders.ShopError.SHOP_ERROR_CLASH (placeholders.proto) both map to the field UserId",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q missing %q", err, want)
+		}
+	}
+	if strings.Contains(err.Error(), "SHOP_ERROR_CLOSED") {
+		t.Errorf("error %q reports a valid message", err)
+	}
+}
+
+func TestValueNewErrorsFuncs(t *testing.T) {
+	enumOpts := &descriptorpb.EnumOptions{}
+	proto.SetExtension(enumOpts, sphereerrors.E_DefaultStatus, int32(401))
+	valueOpts := func(newErrorsFunc string) *descriptorpb.EnumValueOptions {
+		b := protowire.AppendString(protowire.AppendTag(nil, 1, protowire.BytesType), newErrorsFunc)
+		opts := &descriptorpb.EnumValueOptions{}
+		opts.ProtoReflect().SetUnknown(protowire.AppendBytes(protowire.AppendTag(nil, 50103, protowire.BytesType), b))
+		return opts
+	}
+	fd := &descriptorpb.FileDescriptorProto{
+		Name:       proto.String("auth.proto"),
+		Package:    proto.String("tests.auth"),
+		Dependency: []string{"google/protobuf/descriptor.proto"},
+		Options:    &descriptorpb.FileOptions{GoPackage: proto.String("github.com/example/auth")},
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("ErrorOptions"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				{Name: proto.String("new_errors_func"), Number: proto.Int32(1), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(), Type: descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum()},
+			},
+		}},
+		Extension: []*descriptorpb.FieldDescriptorProto{{
+			Name:     proto.String("error"),
+			Number:   proto.Int32(50103),
+			Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:     descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(),
+			TypeName: proto.String(".tests.auth.ErrorOptions"),
+			Extendee: proto.String(".google.protobuf.EnumValueOptions"),
+		}},
+		EnumType: []*descriptorpb.EnumDescriptorProto{{
+			Name:    proto.String("AuthError"),
+			Options: enumOpts,
+			Value: []*descriptorpb.EnumValueDescriptorProto{
+				{Name: proto.String("AUTH_ERROR_UNSPECIFIED"), Number: proto.Int32(0)},
+				{Name: proto.String("AUTH_ERROR_TOKEN_EXPIRED"), Number: proto.Int32(1), Options: valueOpts("example.com/authhttp;NewChallenge")},
+				{Name: proto.String("AUTH_ERROR_LOCKED"), Number: proto.Int32(2)},
+			},
+		}},
+	}
+	descriptor := protodesc.ToFileDescriptorProto(descriptorpb.File_google_protobuf_descriptor_proto)
+	plugin := mustPluginFromFD(t, descriptor, fd)
+	config := &Config{
+		NewErrorsFunc: testConfig.NewErrorsFunc,
+		OptionsType:   "tests.auth.ErrorOptions",
+		ValueNewErrorsFuncs: map[string]protogen.GoIdent{
+			"tests.auth.AuthError":                protogen.GoImportPath("example.com/authhttp").Ident("NewLocked"),
+			"tests.auth.AUTH_ERROR_TOKEN_EXPIRED": protogen.GoImportPath("example.com/authhttp").Ident("Ignored"),
+		},
+	}
+	if err := ResolveOptions(plugin.Files, config); err != nil {
+		t.Fatal(err)
+	}
+	if err := ValidateValueNewErrorsFuncs(plugin.Files, config); err != nil {
+		t.Fatal(err)
+	}
+	genFile, err := GenerateFile(plugin, plugin.Files[1], config)
+	if err != nil {
+		t.Fatalf("GenerateFile failed: %v", err)
+	}
+	content := mustContent(t, genFile)
+	for _, want := range []string{
+		"return e.newError(\n\t\te.GetStatus(),",
+		"case AuthError_AUTH_ERROR_TOKEN_EXPIRED:\n\t\treturn authhttp.NewChallenge(status, code, message, err)",
+		"case AuthError_AUTH_ERROR_LOCKED:\n\t\treturn authhttp.NewLocked(status, code, message, err)",
+		"return httpx.NewError(status, code, message, err)",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("generated code missing %q:\n%s", want, content)
+		}
+	}
+	if strings.Contains(content, "Ignored") {
+		t.Error("the value entry overrode the new_errors_func option")
+	}
+
+	config.Runtime = RuntimeKratos
+	if err := ValidateValueNewErrorsFuncs(plugin.Files, config); err == nil || !strings.Contains(err.Error(), "new_errors_func of tests.auth.AuthError.AUTH_ERROR_LOCKED (auth.proto) is not supported with runtime=kratos") {
+		t.Errorf("ValidateValueNewErrorsFuncs error = %v, want the kratos override rejected", err)
+	}
+
+	fd.EnumType[0].Value[1].Options = valueOpts("NewChallenge")
+	plugin = mustPluginFromFD(t, descriptor, fd)
+	config = &Config{OptionsType: "tests.auth.ErrorOptions"}
+	if err := ResolveOptions(plugin.Files, config); err != nil {
+		t.Fatal(err)
+	}
+	if err := ValidateValueNewErrorsFuncs(plugin.Files, config); err == nil || !strings.Contains(err.Error(), `new_errors_func "NewChallenge" of tests.auth.AuthError.AUTH_ERROR_TOKEN_EXPIRED`) {
+		t.Errorf("ValidateValueNewErrorsFuncs error = %v, want the malformed constructor reported", err)
+	}
+}
+
+func TestExamples(t *testing.T) {
+	enumOpts := &descriptorpb.EnumOptions{}
+	proto.SetExtension(enumOpts, sphereerrors.E_DefaultStatus, int32(400))
+	valueOpts := func(example string) *descriptorpb.EnumValueOptions {
+		b := protowire.AppendString(protowire.AppendTag(nil, 1, protowire.BytesType), example)
+		opts := &descriptorpb.EnumValueOptions{}
+		opts.ProtoReflect().SetUnknown(protowire.AppendBytes(protowire.AppendTag(nil, 50104, protowire.BytesType), b))
+		return opts
+	}
+	fd := &descriptorpb.FileDescriptorProto{
+		Name:       proto.String("cart.proto"),
+		Package:    proto.String("tests.cart"),
+		Dependency: []string{"google/protobuf/descriptor.proto"},
+		Options:    &descriptorpb.FileOptions{GoPackage: proto.String("github.com/example/cart")},
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("ErrorOptions"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				{Name: proto.String("example"), Number: proto.Int32(1), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(), Type: descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum()},
+			},
+		}},
+		Extension: []*descriptorpb.FieldDescriptorProto{{
+			Name:     proto.String("error"),
+			Number:   proto.Int32(50104),
+			Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:     descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(),
+			TypeName: proto.String(".tests.cart.ErrorOptions"),
+			Extendee: proto.String(".google.protobuf.EnumValueOptions"),
+		}},
+		EnumType: []*descriptorpb.EnumDescriptorProto{{
+			Name:    proto.String("CartError"),
+			Options: enumOpts,
+			Value: []*descriptorpb.EnumValueDescriptorProto{
+				{Name: proto.String("CART_ERROR_UNSPECIFIED"), Number: proto.Int32(0)},
+				{Name: proto.String("CART_ERROR_EMPTY"), Number: proto.Int32(1), Options: valueOpts(`{"code": 1, "message": "cart 7 is empty"}`)},
+				{Name: proto.String("CART_ERROR_FULL"), Number: proto.Int32(2)},
+			},
+		}},
+		SourceCodeInfo: &descriptorpb.SourceCodeInfo{Location: []*descriptorpb.SourceCodeInfo_Location{
+			{Path: []int32{5, 0, 2, 1}, Span: []int32{20, 2, 30}, LeadingComments: proto.String(" Example: {\"ignored\": true}\n")},
+			{Path: []int32{5, 0, 2, 2}, Span: []int32{22, 2, 30}, LeadingComments: proto.String(" The cart holds too many items.\n\n Example: {\n   \"code\": 2,\n   \"metadata\": {\"limit\": \"100\"}\n }\n")},
+		}},
+	}
+	descriptor := protodesc.ToFileDescriptorProto(descriptorpb.File_google_protobuf_descriptor_proto)
+	plugin := mustPluginFromFD(t, descriptor, fd)
+	config := &Config{OptionsType: "tests.cart.ErrorOptions"}
+	if err := ResolveOptions(plugin.Files, config); err != nil {
+		t.Fatal(err)
+	}
+	if err := ValidateExamples(plugin.Files, config); err != nil {
+		t.Fatal(err)
+	}
+	errs := ErrorEnums(plugin.Files[1], config)[0].Errors
+	if got, want := errs[1].Example, `{"code":1,"message":"cart 7 is empty"}`; got != want {
+		t.Errorf("option example = %s, want %s", got, want)
+	}
+	if errs[1].Description != "" {
+		t.Errorf("description = %q, want the example line left out", errs[1].Description)
+	}
+	if got, want := errs[2].Example, `{"code":2,"metadata":{"limit":"100"}}`; got != want {
+		t.Errorf("comment example = %s, want %s", got, want)
+	}
+	if got, want := errs[2].Description, "The cart holds too many items."; got != want {
+		t.Errorf("description = %q, want %q", got, want)
+	}
+
+	fd.EnumType[0].Value[2].Options = valueOpts(`["not", "an", "object"]`)
+	plugin = mustPluginFromFD(t, descriptor, fd)
+	config = &Config{OptionsType: "tests.cart.ErrorOptions"}
+	if err := ResolveOptions(plugin.Files, config); err != nil {
+		t.Fatal(err)
+	}
+	err := ValidateExamples(plugin.Files, config)
+	if err == nil || !strings.Contains(err.Error(), `example of tests.cart.CartError.CART_ERROR_FULL (cart.proto:23) is not a JSON object: ["not", "an", "object"]`) {
+		t.Errorf("ValidateExamples() = %v, want the array rejected", err)
+	}
+}
+
+func TestMethodErrors(t *testing.T) {
+	enumOpts := &descriptorpb.EnumOptions{}
+	proto.SetExtension(enumOpts, sphereerrors.E_DefaultStatus, int32(400))
+	methodOpts := func(names ...string) *descriptorpb.MethodOptions {
+		var b []byte
+		for _, name := range names {
+			b = protowire.AppendString(protowire.AppendTag(b, 50105, protowire.BytesType), name)
+		}
+		opts := &descriptorpb.MethodOptions{}
+		opts.ProtoReflect().SetUnknown(b)
+		return opts
+	}
+	method := func(name string, opts *descriptorpb.MethodOptions) *descriptorpb.MethodDescriptorProto {
+		return &descriptorpb.MethodDescriptorProto{Name: proto.String(name), InputType: proto.String(".tests.shop.Request"), OutputType: proto.String(".tests.shop.Request"), Options: opts}
+	}
+	fd := &descriptorpb.FileDescriptorProto{
+		Name:        proto.String("shop.proto"),
+		Package:     proto.String("tests.shop"),
+		Dependency:  []string{"google/protobuf/descriptor.proto"},
+		Options:     &descriptorpb.FileOptions{GoPackage: proto.String("github.com/example/shop")},
+		MessageType: []*descriptorpb.DescriptorProto{{Name: proto.String("Request")}},
+		Extension: []*descriptorpb.FieldDescriptorProto{{
+			Name:     proto.String("errors"),
+			Number:   proto.Int32(50105),
+			Label:    descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum(),
+			Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+			Extendee: proto.String(".google.protobuf.MethodOptions"),
+		}},
+		EnumType: []*descriptorpb.EnumDescriptorProto{{
+			Name:    proto.String("CartError"),
+			Options: enumOpts,
+			Value: []*descriptorpb.EnumValueDescriptorProto{
+				{Name: proto.String("CART_ERROR_UNSPECIFIED"), Number: proto.Int32(0)},
+				{Name: proto.String("CART_ERROR_EMPTY"), Number: proto.Int32(1)},
+				{Name: proto.String("CART_ERROR_FULL"), Number: proto.Int32(2)},
+			},
+		}},
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name: proto.String("CartService"),
+			Method: []*descriptorpb.MethodDescriptorProto{
+				method("Checkout", methodOpts("tests.shop.CART_ERROR_EMPTY")),
+				method("AddItem", methodOpts("tests.shop.CART_ERROR_FULL", "tests.shop.CartError")),
+				method("GetCart", nil),
+			},
+		}},
+	}
+	descriptor := protodesc.ToFileDescriptorProto(descriptorpb.File_google_protobuf_descriptor_proto)
+	plugin := mustPluginFromFD(t, descriptor, fd)
+	config := &Config{NewErrorsFunc: testConfig.NewErrorsFunc, MethodErrorsOption: "tests.shop.errors"}
+	if err := ResolveOptions(plugin.Files, config); err != nil {
+		t.Fatal(err)
+	}
+	if err := ValidateMethodErrors(plugin.Files, config); err != nil {
+		t.Fatal(err)
+	}
+	got := MethodErrors(plugin.Files, config)
+	want := map[string][]string{
+		"/tests.shop.CartService/Checkout": {"tests.shop.CART_ERROR_EMPTY"},
+		"/tests.shop.CartService/AddItem":  {"tests.shop.CART_ERROR_FULL", "tests.shop.CART_ERROR_EMPTY"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MethodErrors() = %v, want %v", got, want)
+	}
+	if _, err := GenerateFile(plugin, plugin.Files[1], config); err != nil {
+		t.Fatalf("GenerateFile failed: %v", err)
+	}
+	var methods string
+	for _, f := range plugin.Response().File {
+		if f.GetName() == "github.com/example/shop/shop.errors_methods.pb.go" {
+			methods = f.GetContent()
+		}
+	}
+	for _, want := range []string{
+		"package shop\n",
+		"// CartService_AddItem_PossibleErrors returns the errors the tests.shop.CartService.AddItem method\n// declares it may return.\nfunc CartService_AddItem_PossibleErrors() []error {\n\treturn []error{\n\t\tCartError_CART_ERROR_FULL,\n\t\tCartError_CART_ERROR_EMPTY,\n\t}\n}\n",
+		"func CartService_Checkout_PossibleErrors() []error {",
+	} {
+		if !strings.Contains(methods, want) {
+			t.Errorf("methods file missing %q:\n%s", want, methods)
+		}
+	}
+	if strings.Contains(methods, "GetCart") {
+		t.Error("GetCart declares no errors but got PossibleErrors")
+	}
+
+	if warnings := StabilityWarnings(plugin.Files, config); len(warnings) != 0 {
+		t.Errorf("StabilityWarnings() = %q, want none without experimental values", warnings)
+	}
+	config.Stabilities = map[string]string{"tests.shop.CART_ERROR_FULL": StabilityExperimental, "tests.shop.CART_ERROR_EMPTY": StabilityBeta}
+	want2 := []string{"AddItem of stable service tests.shop.CartService (shop.proto) may return experimental error tests.shop.CART_ERROR_FULL"}
+	if warnings := StabilityWarnings(plugin.Files, config); !slices.Equal(warnings, want2) {
+		t.Errorf("StabilityWarnings() = %q, want %q", warnings, want2)
+	}
+
+	fd.Service[0].Method[2].Options = methodOpts("tests.shop.CART_ERROR_GONE")
+	plugin = mustPluginFromFD(t, descriptor, fd)
+	config = &Config{MethodErrorsOption: "tests.shop.errors"}
+	if err := ResolveOptions(plugin.Files, config); err != nil {
+		t.Fatal(err)
+	}
+	err := ValidateMethodErrors(plugin.Files, config)
+	if err == nil || !strings.Contains(err.Error(), "tests.shop.CART_ERROR_GONE of tests.shop.CartService.GetCart (shop.proto) is not an error enum or error enum value of this request") {
+		t.Errorf("ValidateMethodErrors() = %v, want the unknown value rejected", err)
+	}
+	if err := ResolveOptions(plugin.Files, &Config{MethodErrorsOption: "tests.shop.missing"}); err == nil {
+		t.Error("expected an error for an undeclared method_errors_option")
+	}
+}
+
+func TestParseValueNewErrorsFunc(t *testing.T) {
+	name, ident, err := ParseValueNewErrorsFunc("tests.auth.AUTH_ERROR_TOKEN_EXPIRED=example.com/authhttp;NewChallenge")
+	if err != nil || name != "tests.auth.AUTH_ERROR_TOKEN_EXPIRED" || ident.GoImportPath != "example.com/authhttp" || ident.GoName != "NewChallenge" {
+		t.Errorf("ParseValueNewErrorsFunc = %q, %v, %v", name, ident, err)
+	}
+	for _, s := range []string{"tests.auth.AuthError", "=example.com/authhttp;New", "tests.auth.AuthError=NewChallenge", "tests.auth.AuthError=example.com/authhttp;New;ctx"} {
+		if _, _, err := ParseValueNewErrorsFunc(s); err == nil {
+			t.Errorf("ParseValueNewErrorsFunc(%q) succeeded, want an error", s)
+		}
+	}
+}
+
+func TestParseHTTPHeader(t *testing.T) {
+	name, header, value, err := ParseHTTPHeader("tests.basic.UserError=www-authenticate: Bearer realm=\"users\"")
+	if err != nil || name != "tests.basic.UserError" || header != "Www-Authenticate" || value != `Bearer realm="users"` {
+		t.Errorf("ParseHTTPHeader = %q, %q, %q, %v", name, header, value, err)
+	}
+	for _, s := range []string{"tests.basic.UserError", "tests.basic.UserError=Retry-After", "=Retry-After:30", "tests.basic.UserError=Retry After:30", "tests.basic.UserError=Retry-After:", "tests.basic.UserError=X-Id:a\nb"} {
+		if _, _, _, err := ParseHTTPHeader(s); err == nil {
+			t.Errorf("ParseHTTPHeader(%q) succeeded, want an error", s)
+		}
 	}
-	return string(b)
 }
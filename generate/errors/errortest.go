@@ -0,0 +1,113 @@
+package errors
+
+import (
+	"path"
+
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// ErrorTestPackage is the name of the test helper sub-package written with
+// ErrorTest next to the errors of each Go package.
+const ErrorTestPackage = "errortest"
+
+// generateErrorTestPackage writes errortest/errortest.sphere.go under the Go
+// package of file: the errortest sub-package asserting on the errors of every
+// file of the package in tests, with a typed assertion per error enum and a
+// code matcher for gomock and testify.
+func generateErrorTestPackage(gen *protogen.Plugin, file *protogen.File, config *Config) {
+	out := config.outputFor(file)
+	importPath := protogen.GoImportPath(path.Join(string(out.importPath), ErrorTestPackage))
+	g := gen.NewGeneratedFile(path.Join(path.Dir(out.prefix), ErrorTestPackage, "errortest.sphere.go"), importPath)
+	files := packageFiles(gen, file, config)
+	g.P("// Code generated by ", config.generatedBy(), ". DO NOT EDIT.")
+	g.P("// versions:")
+	g.P("// - protoc             ", protocVersion(gen))
+	for _, f := range files {
+		g.P("// source: ", f.Desc.Path())
+	}
+	g.P()
+	if config.BuildTag != "" {
+		g.P("//go:build ", config.BuildTag)
+		g.P()
+	}
+	g.P("// Package ", ErrorTestPackage, " asserts on the errors of package ", out.packageName, " in tests.")
+	g.P("package ", ErrorTestPackage)
+	g.P()
+	tb := g.QualifiedGoIdent(testingPackage.Ident("TB"))
+	errorsAs := g.QualifiedGoIdent(errorsPackage.Ident("As"))
+	g.P("// Coded is an error carrying a code, as every generated error value does.")
+	g.P("type Coded interface {")
+	g.P("error")
+	g.P("GetCode() int32")
+	g.P("}")
+	g.P()
+	g.P("// AssertCode reports whether the first generated error in the chain of err")
+	g.P("// has the code of want, failing t otherwise.")
+	g.P("func AssertCode(t ", tb, ", err error, want Coded) bool {")
+	g.P("t.Helper()")
+	g.P("var got Coded")
+	g.P("if !", errorsAs, "(err, &got) {")
+	g.P("t.Errorf(\"error %v carries no generated error, want %v (code %d)\", err, want, want.GetCode())")
+	g.P("return false")
+	g.P("}")
+	g.P("if got.GetCode() != want.GetCode() {")
+	g.P("t.Errorf(\"error %v has code %d, want %v (code %d)\", err, got.GetCode(), want, want.GetCode())")
+	g.P("return false")
+	g.P("}")
+	g.P("return true")
+	g.P("}")
+	g.P()
+	for _, f := range files {
+		for _, ew := range ErrorEnums(f, config) {
+			typ := g.QualifiedGoIdent(out.importPath.Ident(ew.Name))
+			g.P("// Assert", ew.Name, " reports whether the chain of err carries want, failing t")
+			g.P("// otherwise.")
+			g.P("func Assert", ew.Name, "(t ", tb, ", err error, want ", typ, ") bool {")
+			g.P("t.Helper()")
+			g.P("var got ", typ)
+			g.P("if !", errorsAs, "(err, &got) {")
+			g.P("t.Errorf(\"error %v carries no ", ew.Name, ", want %v\", err, want)")
+			g.P("return false")
+			g.P("}")
+			g.P("if got != want {")
+			g.P("t.Errorf(\"error %v carries %v, want %v\", err, got, want)")
+			g.P("return false")
+			g.P("}")
+			g.P("return true")
+			g.P("}")
+			g.P()
+		}
+	}
+	g.P("// CodeMatcher matches errors whose first generated error has the code of an")
+	g.P("// error value. It implements gomock.Matcher, and its Match method suits")
+	g.P("// testify's mock.MatchedBy.")
+	g.P("type CodeMatcher struct {")
+	g.P("want Coded")
+	g.P("}")
+	g.P()
+	g.P("// MatchCode returns a CodeMatcher matching errors with the code of want.")
+	g.P("func MatchCode(want Coded) CodeMatcher {")
+	g.P("return CodeMatcher{want: want}")
+	g.P("}")
+	g.P()
+	g.P("// Matches reports whether x is an error matched by m.")
+	g.P("func (m CodeMatcher) Matches(x any) bool {")
+	g.P("err, ok := x.(error)")
+	g.P("if !ok {")
+	g.P("return false")
+	g.P("}")
+	g.P("return m.Match(err)")
+	g.P("}")
+	g.P()
+	g.P("// Match reports whether the first generated error in the chain of err has")
+	g.P("// the code of the error value of m, as in mock.MatchedBy(m.Match).")
+	g.P("func (m CodeMatcher) Match(err error) bool {")
+	g.P("var got Coded")
+	g.P("return ", errorsAs, "(err, &got) && got.GetCode() == m.want.GetCode()")
+	g.P("}")
+	g.P()
+	g.P("// String describes m, as gomock reports it.")
+	g.P("func (m CodeMatcher) String() string {")
+	g.P("return ", g.QualifiedGoIdent(fmtPackage.Ident("Sprintf")), "(\"is an error with code %d (%v)\", m.want.GetCode(), m.want)")
+	g.P("}")
+}
@@ -0,0 +1,46 @@
+package errors
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/internal/template"
+)
+
+// Texts of the Error method selectable with ErrorText.
+const (
+	// ErrorTextReason returns the reason, or the log message of the values
+	// declaring one.
+	ErrorTextReason = "reason"
+	// ErrorTextCodeMessage returns "code: message", formatted when the code
+	// is generated.
+	ErrorTextCodeMessage = "code_message"
+)
+
+// ValidateErrorText reports whether text is a supported ErrorText. The empty
+// string selects ErrorTextReason.
+func ValidateErrorText(text string) error {
+	switch text {
+	case "", ErrorTextReason, ErrorTextCodeMessage:
+		return nil
+	}
+	return fmt.Errorf("invalid error_text %q, expected %s or %s", text, ErrorTextReason, ErrorTextCodeMessage)
+}
+
+// codeMessageText returns the ErrorTextCodeMessage text of info: its public
+// code, or its code without a prefix, then its log message, message or
+// reason, the first one set.
+func codeMessageText(info *template.ErrorInfo) string {
+	code := info.PublicCode
+	if code == "" {
+		code = strconv.Itoa(int(info.Code))
+	}
+	msg := info.LogMessage
+	if msg == "" {
+		msg = info.Message
+	}
+	if msg == "" {
+		msg = info.Reason
+	}
+	return code + ": " + msg
+}
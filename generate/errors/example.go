@@ -0,0 +1,86 @@
+package errors
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// examplePrefix starts the line of a leading comment from which the comment
+// holds an example error response body, in JSON.
+const examplePrefix = "Example:"
+
+// splitExample splits comment, the text of a leading comment, into the
+// description before its example line and the example following it, ""
+// when comment has no example line.
+func splitExample(comment string) (string, string) {
+	lines := strings.Split(comment, "\n")
+	for i, line := range lines {
+		if rest, ok := strings.CutPrefix(line, examplePrefix); ok {
+			example := strings.Join(append([]string{rest}, lines[i+1:]...), "\n")
+			return strings.TrimSpace(strings.Join(lines[:i], "\n")), strings.TrimSpace(example)
+		}
+	}
+	return comment, ""
+}
+
+// exampleOption returns the example declared for v by the example field of a
+// custom OptionsType, or "" when it declares none.
+func (c *Config) exampleOption(v *protogen.EnumValue) string {
+	if c.custom == nil || c.custom.value == nil || c.custom.example == nil {
+		return ""
+	}
+	ext, ok := c.custom.extension(v.Desc.Options(), c.custom.value)
+	if !ok || !ext.Message().Has(c.custom.example) {
+		return ""
+	}
+	return strings.TrimSpace(ext.Message().Get(c.custom.example).String())
+}
+
+// valueExample returns the description of v, its leading comment, and its
+// example error response body: the example field of a custom OptionsType,
+// which wins, or else the example line of the comment.
+func (c *Config) valueExample(v *protogen.EnumValue) (string, string) {
+	description, example := splitExample(commentText(v.Comments.Leading))
+	if option := c.exampleOption(v); option != "" {
+		example = option
+	}
+	return description, example
+}
+
+// compactExample returns example as compact JSON, and false when it is not a
+// JSON object.
+func compactExample(example string) (string, bool) {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, []byte(example)); err != nil || buf.Len() == 0 || buf.Bytes()[0] != '{' {
+		return "", false
+	}
+	return buf.String(), true
+}
+
+// ValidateExamples rejects the examples of error values that are not JSON
+// objects. All offending values are reported together in a single error.
+func ValidateExamples(files []*protogen.File, config *Config) error {
+	var problems []Diagnostic
+	for _, f := range files {
+		for _, enum := range f.Enums {
+			if _, ok := config.defaultStatus(enum); !ok || !config.selects(enum) {
+				continue
+			}
+			for _, v := range enum.Values {
+				if _, example := config.valueExample(v); example != "" {
+					if _, ok := compactExample(example); !ok {
+						problems = append(problems, valueDiagnostic(v, "example of %s.%s (%s) is not a JSON object: %s", enum.Desc.FullName(), v.Desc.Name(), sourceLocation(v), example))
+					}
+				}
+			}
+		}
+	}
+	if len(problems) > 0 {
+		sortDiagnostics(problems)
+		return &Diagnostics{Summary: "invalid examples", Problems: problems}
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// defaultFallbackOption is the file option read when FallbackOption is
+// unset. Like the generate option it is not part of the sphere options
+// extension: proto repositories declare it themselves, e.g.
+//
+//	extend google.protobuf.FileOptions { string fallback_error = 50101; }
+//
+// in package sphere.errors.
+const defaultFallbackOption = "sphere.errors.fallback_error"
+
+// ResolveFallbackErrors reads the fallback file option of the generated
+// files, naming the error value foreign errors of the Go package of a file
+// are wrapped into, as a fully-qualified value or a value of the file's proto
+// package. FallbackOption, or sphere.errors.fallback_error when unset, names
+// the string file option; the default need not be declared, while an
+// explicit FallbackOption must be.
+func ResolveFallbackErrors(files []*protogen.File, config *Config) error {
+	name := config.FallbackOption
+	if name == "" {
+		name = defaultFallbackOption
+	}
+	var xd protoreflect.ExtensionDescriptor
+	for _, f := range files {
+		for _, x := range appendExtensions(nil, f.Desc) {
+			if string(x.FullName()) == name {
+				xd = x
+			}
+		}
+	}
+	if xd == nil {
+		if config.FallbackOption != "" {
+			return fmt.Errorf("fallback option %s is not an extension of google.protobuf.FileOptions declared in this request", name)
+		}
+		return nil
+	}
+	if xd.ContainingMessage().FullName() != "google.protobuf.FileOptions" || xd.Kind() != protoreflect.StringKind || xd.IsList() {
+		return fmt.Errorf("fallback option %s must be a string extension of google.protobuf.FileOptions", name)
+	}
+	o := &customOptions{types: &protoregistry.Types{}}
+	xt := dynamicpb.NewExtensionType(xd)
+	if err := o.types.RegisterExtension(xt); err != nil {
+		return err
+	}
+	config.fallbackFiles = map[string]string{}
+	for _, f := range files {
+		if !f.Generate {
+			continue
+		}
+		if v, ok := o.extension(f.Desc.Options(), xt); ok && v.String() != "" {
+			value := v.String()
+			if !strings.Contains(value, ".") && f.Desc.Package() != "" {
+				value = string(f.Desc.Package()) + "." + value
+			}
+			config.fallbackFiles[f.Desc.Path()] = value
+		}
+	}
+	return nil
+}
+
+// fallbackDesignations returns the error values designated as fallbacks of
+// files, by the fallback file option first and FallbackErrors second, each
+// with the place designating it: a proto file, or "fallback_error".
+func fallbackDesignations(files []*protogen.File, config *Config) (names, origins []string) {
+	for _, f := range files {
+		if name, ok := config.fallbackFiles[f.Desc.Path()]; ok {
+			names = append(names, name)
+			origins = append(origins, f.Desc.Path())
+		}
+	}
+	for _, name := range config.FallbackErrors {
+		names = append(names, name)
+		origins = append(origins, "fallback_error")
+	}
+	return names, origins
+}
+
+// ValidateFallbackErrors rejects fallbacks naming a value that is not an
+// error enum value of files, and several values designated for one Go
+// package by the fallback file option, or by FallbackErrors. The file option
+// wins over FallbackErrors.
+func ValidateFallbackErrors(files []*protogen.File, config *Config) error {
+	names, origins := fallbackDesignations(files, config)
+	if len(names) == 0 {
+		return nil
+	}
+	values := errorValues(files, config)
+	var problems []Diagnostic
+	byOption, byParam := map[protogen.GoImportPath]int{}, map[protogen.GoImportPath]int{}
+	for i, name := range names {
+		ident, ok := values[name]
+		if !ok {
+			problems = append(problems, diagnosticf("%s (%s) is not an error enum value of this request", name, origins[i]))
+			continue
+		}
+		seen := byOption
+		if origins[i] == "fallback_error" {
+			seen = byParam
+		}
+		if prev, ok := seen[ident.GoImportPath]; ok && names[prev] != name {
+			problems = append(problems, diagnosticf("%s (%s) and %s (%s) are both designated for Go package %s", names[prev], origins[prev], name, origins[i], string(ident.GoImportPath)))
+			continue
+		}
+		seen[ident.GoImportPath] = i
+	}
+	if len(problems) > 0 {
+		sortDiagnostics(problems)
+		return &Diagnostics{Summary: "fallback_error", Problems: problems}
+	}
+	return nil
+}
+
+// generateFallback writes the package-level AsSphereError wrapping foreign
+// errors into the fallback error value of the Go package of file. It writes
+// nothing when the package has none.
+func generateFallback(gen *protogen.Plugin, file *protogen.File, g *protogen.GeneratedFile, config *Config) {
+	files := packageFiles(gen, file, config)
+	values := errorValues(files, config)
+	names, _ := fallbackDesignations(files, config)
+	var value protogen.GoIdent
+	for _, name := range names {
+		if ident, ok := values[name]; ok {
+			value = ident
+			break
+		}
+	}
+	if value.GoName == "" {
+		return
+	}
+	g.P("// AsSphereError returns err when its chain holds a generated error, and")
+	g.P("// otherwise ", value.GoName, " with err as its cause, so")
+	g.P("// everything a handler returns is a catalogued error. It returns nil for a")
+	g.P("// nil err.")
+	g.P("func AsSphereError(err error) error {")
+	g.P("if err == nil {")
+	g.P("return nil")
+	g.P("}")
+	g.P("var se interface {")
+	g.P("error")
+	g.P("GetStatus() int32")
+	g.P("GetCode() int32")
+	g.P("}")
+	g.P("if ", g.QualifiedGoIdent(errorsPackage.Ident("As")), "(err, &se) {")
+	g.P("return err")
+	g.P("}")
+	g.P("return ", g.QualifiedGoIdent(value), ".WithCause(err)")
+	g.P("}")
+	g.P()
+}
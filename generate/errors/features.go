@@ -0,0 +1,27 @@
+package errors
+
+import (
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+// Editions supported by the plugin. Generation only reads enum options and
+// values, which mean the same under proto2, proto3 and every edition up to
+// SupportedEditionsMaximum.
+const (
+	SupportedEditionsMinimum = descriptorpb.Edition_EDITION_PROTO2
+	SupportedEditionsMaximum = descriptorpb.Edition_EDITION_2023
+)
+
+// SupportedFeatures is the code generator feature set the plugin advertises.
+const SupportedFeatures = uint64(pluginpb.CodeGeneratorResponse_FEATURE_PROTO3_OPTIONAL |
+	pluginpb.CodeGeneratorResponse_FEATURE_SUPPORTS_EDITIONS)
+
+// SetSupportedFeatures declares the features and editions supported by the
+// plugin on gen, so protoc accepts proto2, proto3 and edition files.
+func SetSupportedFeatures(gen *protogen.Plugin) {
+	gen.SupportedFeatures = SupportedFeatures
+	gen.SupportedEditionsMinimum = SupportedEditionsMinimum
+	gen.SupportedEditionsMaximum = SupportedEditionsMaximum
+}
@@ -0,0 +1,43 @@
+package errors
+
+import (
+	"fmt"
+	"path"
+
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// ValidatePatterns reports the first malformed glob among the patterns of
+// the parameter name.
+func ValidatePatterns(name string, patterns []string) error {
+	for _, p := range patterns {
+		if _, err := path.Match(p, ""); err != nil {
+			return fmt.Errorf("invalid %s pattern %q: %w", name, p, err)
+		}
+	}
+	return nil
+}
+
+// selects reports whether enum is selected for generation by the
+// IncludeEnums, ExcludeEnums and ExcludeFiles filters. Patterns are assumed
+// valid (see ValidatePatterns).
+func (c *Config) selects(enum *protogen.Enum) bool {
+	if matchAny(c.ExcludeFiles, enum.Desc.ParentFile().Path()) {
+		return false
+	}
+	name := string(enum.Desc.FullName())
+	if len(c.IncludeEnums) > 0 && !matchAny(c.IncludeEnums, name) {
+		return false
+	}
+	return !matchAny(c.ExcludeEnums, name)
+}
+
+// matchAny reports whether name matches one of patterns.
+func matchAny(patterns []string, name string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, name); ok {
+			return true
+		}
+	}
+	return false
+}
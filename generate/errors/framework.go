@@ -0,0 +1,87 @@
+package errors
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// HTTP frameworks the generated error adapter targets, selected by
+// HTTPFramework.
+const (
+	FrameworkGin  = "gin"
+	FrameworkEcho = "echo"
+	FrameworkChi  = "chi"
+)
+
+// Import paths used by the generated HTTP framework adapters.
+const (
+	ginPackage  = protogen.GoImportPath("github.com/gin-gonic/gin")
+	echoPackage = protogen.GoImportPath("github.com/labstack/echo/v4")
+)
+
+// ValidateHTTPFramework reports whether framework is a supported
+// HTTPFramework.
+func ValidateHTTPFramework(framework string) error {
+	switch framework {
+	case "", FrameworkGin, FrameworkEcho, FrameworkChi:
+		return nil
+	}
+	return fmt.Errorf("invalid http_framework %q, expected %s, %s or %s", framework, FrameworkGin, FrameworkEcho, FrameworkChi)
+}
+
+// generateFrameworkAdapter writes the package-level error adapter of
+// framework, rendering generated errors with the envelope of config.
+func generateFrameworkAdapter(g *protogen.GeneratedFile, framework string, config *Config) {
+	handles := g.QualifiedGoIdent(httpErrorsPackage.Ident("Handles"))
+	encode := httpEncode(g, config)
+	envelope := envelopeName(config)
+	switch framework {
+	case FrameworkGin:
+		g.P("// GinErrorMiddleware returns a gin middleware rendering the last error of the")
+		g.P("// context, when it is a generated error of this package or any other, with")
+		g.P("// the ", envelope, " and its own HTTP status, after the handlers")
+		g.P("// ran. Handlers report errors with c.Error(err). Other errors and responses")
+		g.P("// already written are left to gin.")
+		g.P("func GinErrorMiddleware() ", g.QualifiedGoIdent(ginPackage.Ident("HandlerFunc")), " {")
+		g.P("return func(c *", g.QualifiedGoIdent(ginPackage.Ident("Context")), ") {")
+		g.P("c.Next()")
+		g.P("last := c.Errors.Last()")
+		g.P("if last == nil || c.Writer.Written() || !", handles, "(last.Err) {")
+		g.P("return")
+		g.P("}")
+		g.P(encode, "(c.Writer, last.Err)")
+		g.P("c.Abort()")
+		g.P("}")
+		g.P("}")
+	case FrameworkEcho:
+		g.P("// EchoErrorHandler is an echo HTTPErrorHandler, installed with")
+		g.P("// e.HTTPErrorHandler = EchoErrorHandler, rendering generated errors of this")
+		g.P("// package or any other with the ", envelope, " and their own")
+		g.P("// HTTP status. Other errors go to the default handler of the echo instance.")
+		g.P("func EchoErrorHandler(err error, c ", g.QualifiedGoIdent(echoPackage.Ident("Context")), ") {")
+		g.P("if c.Response().Committed {")
+		g.P("return")
+		g.P("}")
+		g.P("if !", handles, "(err) {")
+		g.P("c.Echo().DefaultHTTPErrorHandler(err, c)")
+		g.P("return")
+		g.P("}")
+		g.P(encode, "(c.Response(), err)")
+		g.P("}")
+	case FrameworkChi:
+		responseWriter := g.QualifiedGoIdent(httpPackage.Ident("ResponseWriter"))
+		request := g.QualifiedGoIdent(httpPackage.Ident("Request"))
+		g.P("// ChiHandler adapts h, a handler returning an error, to the net/http handlers")
+		g.P("// chi routes, e.g. r.Get(\"/users/{id}\", ChiHandler(getUser)). A returned error")
+		g.P("// is rendered with the ", envelope, ": generated errors of this")
+		g.P("// package or any other with their own HTTP status, other errors as a 500")
+		g.P("// internal error.")
+		g.P("func ChiHandler(h func(", responseWriter, ", *", request, ") error) ", g.QualifiedGoIdent(httpPackage.Ident("HandlerFunc")), " {")
+		g.P("return func(w ", responseWriter, ", r *", request, ") {")
+		g.P(encode, "(w, h(w, r))")
+		g.P("}")
+		g.P("}")
+	}
+	g.P()
+}
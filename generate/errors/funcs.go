@@ -0,0 +1,63 @@
+package errors
+
+import "google.golang.org/protobuf/compiler/protogen"
+
+// generateErrorFuncs writes the package-level Code, HTTPStatus and Message,
+// reading the first generated error of any error chain.
+func generateErrorFuncs(g *protogen.GeneratedFile) {
+	errorsAs := g.QualifiedGoIdent(errorsPackage.Ident("As"))
+	g.P("// codedError is the method set shared by every generated error enum.")
+	g.P("type codedError interface {")
+	g.P("error")
+	g.P("GetStatus() int32")
+	g.P("GetCode() int32")
+	g.P("GetMessage() string")
+	g.P("}")
+	g.P()
+	g.P("// Code returns the code of the first generated error in the chain of err, of")
+	g.P("// this package or any other. It returns 0 for nil and for errors carrying no")
+	g.P("// generated error.")
+	g.P("func Code(err error) int32 {")
+	g.P("var e codedError")
+	g.P("if !", errorsAs, "(err, &e) {")
+	g.P("return 0")
+	g.P("}")
+	g.P("return e.GetCode()")
+	g.P("}")
+	g.P()
+	g.P("// HTTPStatus returns the HTTP status of the first generated error in the chain")
+	g.P("// of err, of this package or any other. It returns 200 for nil and 500 for")
+	g.P("// errors carrying no generated error.")
+	g.P("func HTTPStatus(err error) int {")
+	g.P("if err == nil {")
+	g.P("return ", g.QualifiedGoIdent(httpPackage.Ident("StatusOK")))
+	g.P("}")
+	g.P("var e codedError")
+	g.P("if !", errorsAs, "(err, &e) {")
+	g.P("return ", g.QualifiedGoIdent(httpPackage.Ident("StatusInternalServerError")))
+	g.P("}")
+	g.P("return int(e.GetStatus())")
+	g.P("}")
+	g.P()
+	g.P("// Message returns the user-facing message of the first generated error in the")
+	g.P("// chain of err, of this package or any other, falling back to its reason. It")
+	g.P("// returns \"\" for nil and \"internal error\" for errors carrying no generated")
+	g.P("// error, so their internal detail never reaches clients.")
+	g.P("func Message(err error) string {")
+	g.P("if err == nil {")
+	g.P("return \"\"")
+	g.P("}")
+	g.P("var e codedError")
+	g.P("if !", errorsAs, "(err, &e) {")
+	g.P("return \"internal error\"")
+	g.P("}")
+	g.P("if msg := e.GetMessage(); msg != \"\" {")
+	g.P("return msg")
+	g.P("}")
+	g.P("if r, ok := e.(interface{ GetReason() string }); ok {")
+	g.P("return r.GetReason()")
+	g.P("}")
+	g.P("return e.Error()")
+	g.P("}")
+	g.P()
+}
@@ -0,0 +1,63 @@
+package errors
+
+import (
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// generateFuzzContent renders the parse round-trip test and the fuzz targets
+// of every error enum in file into g, a _test.go file in the package of the
+// generated helpers. They call the Parse<Enum> and <Enum>FromHTTPResponse
+// functions, so GenFuzz implies ParseHelpers.
+func generateFuzzContent(file *protogen.File, g *protogen.GeneratedFile, config *Config) error {
+	testingT := g.QualifiedGoIdent(testingPackage.Ident("T"))
+	testingF := g.QualifiedGoIdent(testingPackage.Ident("F"))
+	itoa := g.QualifiedGoIdent(strconvPackage.Ident("Itoa"))
+	for _, enum := range file.Enums {
+		ew := buildErrorWrapper(enum, config, "", "")
+		if ew == nil {
+			continue
+		}
+		if !config.RawStatus {
+			qualifyStatuses(ew, g)
+		}
+		if err := ew.RenderFuzz(g, testingT, testingF, itoa); err != nil {
+			return err
+		}
+		g.P()
+	}
+	return nil
+}
+
+// generateUniqueCodesTest writes a test asserting no two non-zero values of
+// the Go package of file share a public code: their PublicCode when the enum
+// has a code prefix, their decimal code otherwise. Aliases are skipped, since
+// they share the code of their canonical value by definition.
+func generateUniqueCodesTest(gen *protogen.Plugin, file *protogen.File, g *protogen.GeneratedFile, config *Config) {
+	itoa := g.QualifiedGoIdent(strconvPackage.Ident("Itoa"))
+	g.P("func TestErrorCodesUnique(t *", g.QualifiedGoIdent(testingPackage.Ident("T")), ") {")
+	g.P("seen := map[string]string{}")
+	g.P("for _, tt := range []struct{ name, code string }{")
+	for _, f := range packageFiles(gen, file, config) {
+		for _, ew := range ErrorEnums(f, config) {
+			for _, info := range ew.Errors {
+				if info.Number == 0 {
+					continue
+				}
+				name := ew.Name + "_" + info.Value
+				if ew.CodePrefix != "" {
+					g.P(`{"`, name, `", `, name, ".PublicCode()},")
+				} else {
+					g.P(`{"`, name, `", `, itoa, "(int(", name, ".GetCode()))},")
+				}
+			}
+		}
+	}
+	g.P("} {")
+	g.P("if prev, ok := seen[tt.code]; ok {")
+	g.P(`t.Errorf("%s and %s share the code %s", prev, tt.name, tt.code)`)
+	g.P("}")
+	g.P("seen[tt.code] = tt.name")
+	g.P("}")
+	g.P("}")
+	g.P()
+}
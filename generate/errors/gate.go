@@ -0,0 +1,85 @@
+package errors
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// Generation policies of GeneratePolicy.
+const (
+	// PolicyAll generates every requested file, except those setting the
+	// generate option to false.
+	PolicyAll = "all"
+	// PolicyOptIn generates only the requested files setting the generate
+	// option to true.
+	PolicyOptIn = "opt_in"
+)
+
+// defaultGenerateOption is the file option read when GenerateOption is unset.
+// It is not part of the sphere options extension: proto repositories gating
+// generation declare it themselves, e.g.
+//
+//	extend google.protobuf.FileOptions { bool generate = 50100; }
+//
+// in package sphere.errors.
+const defaultGenerateOption = "sphere.errors.generate"
+
+// ValidateGeneratePolicy reports whether policy is a supported GeneratePolicy.
+func ValidateGeneratePolicy(policy string) error {
+	switch policy {
+	case "", PolicyAll, PolicyOptIn:
+		return nil
+	}
+	return fmt.Errorf("invalid generate_policy %q, expected all or opt_in", policy)
+}
+
+// GateFiles applies the GeneratePolicy of config to files: every requested
+// file its generate option does not opt in is marked as not to be generated,
+// so that no output is written for it. Other files of the request still
+// resolve imports. GenerateOption, or sphere.errors.generate when unset,
+// names the bool file option; the default need not be declared unless
+// GeneratePolicy is opt_in, while an explicit GenerateOption must be.
+func GateFiles(files []*protogen.File, config *Config) error {
+	name := config.GenerateOption
+	if name == "" {
+		name = defaultGenerateOption
+	}
+	var xd protoreflect.ExtensionDescriptor
+	for _, f := range files {
+		for _, x := range appendExtensions(nil, f.Desc) {
+			if string(x.FullName()) == name {
+				xd = x
+			}
+		}
+	}
+	if xd == nil {
+		if config.GenerateOption != "" || config.GeneratePolicy == PolicyOptIn {
+			return fmt.Errorf("generate option %s is not an extension of google.protobuf.FileOptions declared in this request", name)
+		}
+		return nil
+	}
+	if xd.ContainingMessage().FullName() != "google.protobuf.FileOptions" || xd.Kind() != protoreflect.BoolKind || xd.IsList() {
+		return fmt.Errorf("generate option %s must be a bool extension of google.protobuf.FileOptions", name)
+	}
+	o := &customOptions{types: &protoregistry.Types{}}
+	xt := dynamicpb.NewExtensionType(xd)
+	if err := o.types.RegisterExtension(xt); err != nil {
+		return err
+	}
+	for _, f := range files {
+		if !f.Generate {
+			continue
+		}
+		v, ok := o.extension(f.Desc.Options(), xt)
+		if ok {
+			f.Generate = v.Bool()
+		} else {
+			f.Generate = config.GeneratePolicy != PolicyOptIn
+		}
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package errors
+
+import "google.golang.org/protobuf/compiler/protogen"
+
+// Import paths used by the generated grpc-gateway error handler.
+const (
+	gatewayRuntimePackage = protogen.GoImportPath("github.com/grpc-ecosystem/grpc-gateway/v2/runtime")
+	httpErrorsPackage     = protogen.GoImportPath("github.com/go-sphere/protoc-gen-sphere-errors/httperrors")
+)
+
+// generateGateway writes the package-level GatewayErrorHandler, a
+// grpc-gateway runtime.ErrorHandlerFunc. It resolves codes with the function
+// written by generateErrorByCode and writes them with the envelope of config.
+func generateGateway(g *protogen.GeneratedFile, config *Config) {
+	runtime := func(name string) string {
+		return g.QualifiedGoIdent(gatewayRuntimePackage.Ident(name))
+	}
+	g.P("// GatewayErrorHandler is a grpc-gateway error handler, installed with")
+	if config.HTTPEnvelope == "" || config.HTTPEnvelope == EnvelopeSphere {
+		g.P("// runtime.WithErrorHandler, writing the errors of this package with the JSON")
+		g.P("// envelope of httperrors and their own HTTP status, so gateway-fronted services")
+		g.P("// answer like native sphere HTTP services. Errors are recognized in-process and")
+	} else {
+		g.P("// runtime.WithErrorHandler, writing the errors of this package with the")
+		g.P("// ", envelopeName(config), " and their own HTTP status. Errors are recognized in-process and")
+	}
+	g.P("// from the gRPC status of backends using the grpcerrors interceptors; any")
+	g.P("// other error goes to runtime.DefaultHTTPErrorHandler.")
+	g.P("func GatewayErrorHandler(ctx ", g.QualifiedGoIdent(contextPackage.Ident("Context")),
+		", mux *", runtime("ServeMux"),
+		", m ", runtime("Marshaler"),
+		", w ", g.QualifiedGoIdent(httpPackage.Ident("ResponseWriter")),
+		", r *", g.QualifiedGoIdent(httpPackage.Ident("Request")),
+		", err error) {")
+	g.P("if e, ok := ", g.QualifiedGoIdent(grpcErrorsPackage.Ident("Resolve")), "(err, sphereErrorByCode); ok {")
+	g.P(httpEncode(g, config), "(w, e)")
+	g.P("return")
+	g.P("}")
+	g.P(runtime("DefaultHTTPErrorHandler"), "(ctx, mux, m, w, r, err)")
+	g.P("}")
+	g.P()
+}
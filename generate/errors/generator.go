@@ -1,59 +1,344 @@
 package errors
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
+
 	"github.com/go-sphere/errors/sphere/errors"
 	"github.com/go-sphere/protoc-gen-sphere-errors/generate/internal/template"
 	"google.golang.org/protobuf/compiler/protogen"
-	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
 )
 
 // generateFileContent renders the error-helper methods for every error enum in
-// file and writes them to g.
-func generateFileContent(file *protogen.File, g *protogen.GeneratedFile, config *Config) error {
-	newErrorsFunc := g.QualifiedGoIdent(config.NewErrorsFunc)
+// file and writes them to g. With mirror set every error enum is first declared
+// as a local type, because g is outside the package of the message types.
+func generateFileContent(gen *protogen.Plugin, file *protogen.File, g *protogen.GeneratedFile, config *Config, mirror bool) error {
+	pkg := string(file.Desc.Package())
+	newErrorsFunc := qualifyNewErrorsFunc(g, config, pkg)
 	errorsJoinFunc := g.QualifiedGoIdent(errorsPackage.Ident("Join"))
 	for _, enum := range file.Enums {
-		ew := buildErrorWrapper(enum, newErrorsFunc, errorsJoinFunc)
+		ew := buildErrorWrapper(enum, config, newErrorsFunc, errorsJoinFunc)
 		if ew == nil {
 			continue
 		}
-		content, err := ew.Execute()
-		if err != nil {
+		if mirror {
+			generateMirrorType(enum, g)
+		}
+		ew.SplitDeprecated = config.FailOnDeprecatedUse
+		ew.ConcreteReturn = config.ConcreteReturn
+		if ew.HasFormat() {
+			ew.SprintfFunc = g.QualifiedGoIdent(fmtPackage.Ident("Sprintf"))
+		}
+		if ew.HasPlaceholders() {
+			qualifyPlaceholders(ew, g)
+		}
+		if config.SentinelErrors {
+			ew.ErrorsIsFunc = g.QualifiedGoIdent(errorsPackage.Ident("Is"))
+		}
+		if config.KratosCompat {
+			qualifyKratos(ew, g)
+		}
+		if config.WithStack {
+			ew.StackWrap = g.QualifiedGoIdent(stackPackage.Ident("Wrap"))
+		}
+		if config.Registry || config.Exhaustive {
+			ew.Registry = &template.RegistryIdents{
+				MustRegister:   g.QualifiedGoIdent(registryPackage.Ident("MustRegister")),
+				Descriptor:     g.QualifiedGoIdent(registryPackage.Ident("ErrorDescriptor")),
+				SourceLocation: g.QualifiedGoIdent(registryPackage.Ident("SourceLocation")),
+				Registrant:     string(config.outputFor(file).importPath),
+			}
+		}
+		if config.Metadata || config.TraceContext {
+			ew.Metadata = &template.MetadataIdents{
+				Wrap:      g.QualifiedGoIdent(metadataPackage.Ident("Wrap")),
+				WithField: g.QualifiedGoIdent(metadataPackage.Ident("WithField")),
+				Chain:     g.QualifiedGoIdent(metadataPackage.Ident("Chain")),
+				Error:     g.QualifiedGoIdent(metadataPackage.Ident("Error")),
+			}
+		}
+		if config.TraceContext {
+			ew.Trace = &template.TraceIdents{
+				Context:                g.QualifiedGoIdent(contextPackage.Ident("Context")),
+				SpanContextFromContext: g.QualifiedGoIdent(otelTracePackage.Ident("SpanContextFromContext")),
+			}
+		}
+		if !config.RawStatus {
+			qualifyStatuses(ew, g)
+		}
+		if config.GRPCStatus {
+			qualifyGRPC(ew, g)
+		}
+		if config.ErrorCodes || config.Exhaustive {
+			ew.Codes = &template.CodeIdents{
+				Itoa:   g.QualifiedGoIdent(strconvPackage.Ident("Itoa")),
+				Quote:  g.QualifiedGoIdent(strconvPackage.Ident("Quote")),
+				Errorf: g.QualifiedGoIdent(fmtPackage.Ident("Errorf")),
+			}
+		}
+		if config.Metrics == MetricsPrometheus {
+			qualifyMetrics(ew, g)
+		}
+		if config.LogHelpers {
+			qualifyLog(ew, g)
+		}
+		if len(config.LogSampleRates) > 0 {
+			ew.SampleFloat = g.QualifiedGoIdent(randPackage.Ident("Float64"))
+		}
+		ew.RetryHelpers = config.RetryHelpers
+		ew.CacheHelpers = len(config.CacheTTLs) > 0
+		ew.UnexportedConstructors = config.UnexportedConstructors
+		ew.HeaderHelpers = len(config.HTTPHeaders) > 0
+		ew.SLOHelpers = config.sloHelpers()
+		ew.SpanHelpers = config.spanHelpers()
+		ew.Exhaustive = config.Exhaustive
+		ew.VisibilityHelpers = config.visibilityHelpers()
+		ew.StabilityHelpers = config.stabilityHelpers()
+		ew.AWSErrorTypes = config.HTTPEnvelope == EnvelopeAWS
+		ew.Prebuilt = config.Prebuilt
+		ew.OriginHelpers = config.OriginHelpers
+		ew.ClientCodeHelpers = config.clientCodeHelpers()
+		ew.MessageResolver = config.MessageResolver
+		ew.CategoryHelpers = len(config.Categories) > 0
+		ew.HelpLinks = config.helpLinks()
+		if config.ProblemJSON {
+			qualifyProblem(ew, g)
+		}
+		if len(config.BinaryFormats) > 0 {
+			qualifyBinary(ew, g, config.BinaryFormats)
+		}
+		if config.ParseHelpers || config.GenFuzz {
+			ew.JSONUnmarshal = g.QualifiedGoIdent(jsonPackage.Ident("Unmarshal"))
+		}
+		qualifyLegacy(ew, g)
+		qualifyAdapter(ew, g, config, pkg)
+		qualifyValueNewErrorsFuncs(ew, enum, g, config)
+		if err := qualifyDetails(gen, ew, g); err != nil {
 			return err
 		}
-		g.P(content)
+		if err := renderWrapper(g, ew, config); err != nil {
+			return err
+		}
+		g.P()
 		g.P("\n\n")
 	}
 	return nil
 }
 
+// generateDeprecatedContent renders the per-value helpers of deprecated
+// values into g, which is guarded by the !sphere_errors_strict constraint. It
+// reports whether anything was written.
+func generateDeprecatedContent(gen *protogen.Plugin, file *protogen.File, g *protogen.GeneratedFile, config *Config) (bool, error) {
+	written := false
+	for _, enum := range file.Enums {
+		ew := buildErrorWrapper(enum, config, "", "")
+		if ew == nil || len(ew.DeprecatedHelpers().Errors) == 0 {
+			continue
+		}
+		ew.ConcreteReturn = config.ConcreteReturn
+		if ew.HasPlaceholders() {
+			qualifyPlaceholders(ew, g)
+		}
+		if config.SentinelErrors {
+			ew.ErrorsIsFunc = g.QualifiedGoIdent(errorsPackage.Ident("Is"))
+		}
+		if config.KratosCompat {
+			qualifyKratos(ew, g)
+		}
+		if err := qualifyDetails(gen, ew, g); err != nil {
+			return false, err
+		}
+		content, err := ew.ExecuteDeprecated(config.Template)
+		if err != nil {
+			return false, err
+		}
+		if strings.TrimSpace(content) == "" {
+			continue
+		}
+		g.P(content)
+		g.P()
+		written = true
+	}
+	return written, nil
+}
+
+// generateTestContent renders a mapping test for every error enum in file into
+// g, a _test.go file in the package of the generated helpers.
+func generateTestContent(file *protogen.File, g *protogen.GeneratedFile, config *Config) error {
+	testingT := g.QualifiedGoIdent(testingPackage.Ident("T"))
+	errorsIs := g.QualifiedGoIdent(errorsPackage.Ident("Is"))
+	errorsAs := g.QualifiedGoIdent(errorsPackage.Ident("As"))
+	var testingB, allocsPerRun string
+	if config.Prebuilt {
+		testingB = g.QualifiedGoIdent(testingPackage.Ident("B"))
+		allocsPerRun = g.QualifiedGoIdent(testingPackage.Ident("AllocsPerRun"))
+	}
+	var race *template.RaceIdents
+	if config.Metadata || config.TraceContext {
+		race = &template.RaceIdents{
+			WaitGroup: g.QualifiedGoIdent(syncPackage.Ident("WaitGroup")),
+			WithField: g.QualifiedGoIdent(metadataPackage.Ident("WithField")),
+			From:      g.QualifiedGoIdent(metadataPackage.Ident("From")),
+			Itoa:      g.QualifiedGoIdent(strconvPackage.Ident("Itoa")),
+		}
+	}
+	for _, enum := range file.Enums {
+		ew := buildErrorWrapper(enum, config, "", "")
+		if ew == nil {
+			continue
+		}
+		if !config.RawStatus {
+			qualifyStatuses(ew, g)
+		}
+		ew.Prebuilt = config.Prebuilt
+		if err := ew.RenderTests(g, testingT, errorsIs, errorsAs, config.ParseHelpers || config.GenFuzz, testingB, allocsPerRun, race); err != nil {
+			return err
+		}
+		g.P()
+	}
+	return nil
+}
+
+// renderWrapper writes ew to g with the custom template from config when one
+// is set, falling back to the built-in template otherwise. The output streams
+// into g rather than through an intermediate string, which keeps memory flat
+// for requests with thousands of enums.
+func renderWrapper(g *protogen.GeneratedFile, ew *template.ErrorWrapper, config *Config) error {
+	if config.Template != "" {
+		return ew.RenderText(g, config.Template)
+	}
+	return ew.Render(g)
+}
+
+// qualifyGRPC fills in the gRPC identifiers of ew so the template emits the
+// GetGRPCCode and GRPCStatus helpers, the latter carrying a
+// google.rpc.ErrorInfo detail.
+func qualifyGRPC(ew *template.ErrorWrapper, g *protogen.GeneratedFile) {
+	ew.GRPC = &template.GRPCIdents{
+		CodeType:    g.QualifiedGoIdent(grpcCodesPackage.Ident("Code")),
+		UnknownCode: g.QualifiedGoIdent(grpcCodesPackage.Ident("Unknown")),
+		StatusType:  g.QualifiedGoIdent(grpcStatusPackage.Ident("Status")),
+		NewStatus:   g.QualifiedGoIdent(grpcStatusPackage.Ident("New")),
+		ErrorInfo:   g.QualifiedGoIdent(errdetailsPackage.Ident("ErrorInfo")),
+		Help:        g.QualifiedGoIdent(errdetailsPackage.Ident("Help")),
+		HelpLink:    g.QualifiedGoIdent(errdetailsPackage.Ident("Help_Link")),
+	}
+	for _, info := range ew.Errors {
+		info.GRPCCodeIdent = g.QualifiedGoIdent(grpcCodesPackage.Ident(grpcCodeGoNames[info.GRPCCode]))
+	}
+}
+
 // buildErrorWrapper builds a template.ErrorWrapper from an enum. It returns nil
-// when the enum is not an error enum (the default_status option is missing) or
-// when it has no values. newErrorsFunc and errorsJoinFunc must be the already
-// qualified Go identifiers used by the generated code.
-func buildErrorWrapper(enum *protogen.Enum, newErrorsFunc, errorsJoinFunc string) *template.ErrorWrapper {
-	if !proto.HasExtension(enum.Desc.Options(), errors.E_DefaultStatus) {
+// when the enum is not an error enum (the default_status option is missing),
+// when config filters it out or when it has no values. newErrorsFunc and
+// errorsJoinFunc must be the already qualified Go identifiers used by the
+// generated code.
+func buildErrorWrapper(enum *protogen.Enum, config *Config, newErrorsFunc, errorsJoinFunc string) *template.ErrorWrapper {
+	defaultStatus, ok := config.defaultStatus(enum)
+	if !ok || !config.selects(enum) {
 		return nil
 	}
-	defaultStatus, _ := proto.GetExtension(enum.Desc.Options(), errors.E_DefaultStatus).(int32)
 	ew := &template.ErrorWrapper{
 		Name:           string(enum.Desc.Name()),
+		FullName:       string(enum.Desc.FullName()),
+		Description:    commentText(enum.Comments.Leading),
+		Domain:         config.domain(string(enum.Desc.ParentFile().Package())),
+		CodePrefix:     config.codePrefix(string(enum.Desc.ParentFile().Package())),
 		NewErrorsFunc:  newErrorsFunc,
 		ErrorsJoinFunc: errorsJoinFunc,
 	}
+	offset := config.codeOffset(string(enum.Desc.ParentFile().Package()))
+	defaultMessage := config.DefaultMessages[ew.FullName]
+	canonical := map[int32]*template.ErrorInfo{}
 	for _, v := range enum.Values {
+		if v.Desc.Number() == 0 && config.skipsZeroValue() {
+			continue
+		}
+		if c, ok := canonical[int32(v.Desc.Number())]; ok {
+			alias := aliasErrorInfo(c, v)
+			alias.GoName = config.goName(enum, v)
+			ew.Aliases = append(ew.Aliases, alias)
+			continue
+		}
 		info := resolveErrorInfo(
 			string(enum.Desc.Name()),
 			string(v.Desc.Name()),
 			int32(v.Desc.Number()),
-			enumValueOptions(v),
-			defaultStatus,
+			config.valueOptions(v),
+			config.rangeStatus(ew.FullName, int32(v.Desc.Number()), defaultStatus),
 		)
+		if code, ok := config.grpcCode(v); ok {
+			info.GRPCCode = code
+			if config.valueOptions(v).GetStatus() == 0 {
+				info.Status = httpFromGRPCCode(code)
+			}
+		}
+		if v.Desc.Number() == 0 && config.ZeroValue == ZeroValueUnknown {
+			config.unknownErrorInfo(info, v)
+		}
+		info.Code += offset
+		info.GoName = config.goName(enum, v)
+		if ew.CodePrefix != "" {
+			info.PublicCode = ew.CodePrefix + strconv.Itoa(int(info.Code))
+		}
+		if info.Message == "" {
+			info.Message = defaultMessage
+		}
+		info.Description, info.Example = config.valueExample(v)
+		info.Example, _ = compactExample(info.Example)
+		info.Deprecated = enumValueDeprecated(v)
+		info.Source = sourceLocation(v)
+		info.SourceFile, info.SourceLine = sourcePosition(v)
+		info.Severity = config.severity(ew.FullName, string(v.Desc.FullName()), info.Status)
+		info.LogSampleRate = config.logSampleRate(ew.FullName, string(v.Desc.FullName()))
+		info.CacheControl = config.cacheControl(ew.FullName, string(v.Desc.FullName()))
+		info.HTTPHeaders = config.httpHeaders(ew.FullName, string(v.Desc.FullName()))
+		info.SLOExempt = config.sloExempt(ew.FullName, v)
+		info.OTelEvent = config.otelEvent(ew.FullName, v)
+		info.SpanError = config.spanError(ew.FullName, v, info.Status)
+		info.Internal = config.internal(ew.FullName, v)
+		info.Stability = config.stability(ew.FullName, v)
+		if info.Stability == StabilityExperimental && (config.ExperimentalCodePrefix != "" || config.ExperimentalCodeSuffix != "") {
+			code := info.PublicCode
+			if code == "" {
+				code = strconv.Itoa(int(info.Code))
+			}
+			info.PublicCode = config.experimentalCode(code)
+		}
+		info.Retryable = retryableStatus(info.Status) || config.RetryableValues[string(v.Desc.FullName())]
+		info.LogMessage = config.LogMessages[string(v.Desc.FullName())]
+		info.LocalizedMessages = config.localizedMessages(v)
+		if config.ErrorText == ErrorTextCodeMessage {
+			// Error() returns the log message of a value as a string
+			// constant, so the text costs nothing at run time.
+			info.LogMessage = codeMessageText(info)
+		}
+		info.DetailType = config.DetailTypes[string(v.Desc.FullName())]
+		info.Origin = config.origin(ew.FullName, string(v.Desc.FullName()), info.Status)
+		info.ClientCode = config.clientCode(ew.FullName, v)
+		info.LegacyPattern = config.legacyPattern(v)
+		info.Category = config.category(ew.FullName, string(v.Desc.FullName()), info.Number)
+		if config.ProblemJSON {
+			problemInfo(info, config.ProblemType, string(enum.Desc.ParentFile().Package()), ew.Name)
+		}
+		info.HelpLink = config.helpLink(info, string(enum.Desc.ParentFile().Package()), ew.Name, string(v.Desc.FullName()))
+		canonical[info.Number] = info
 		ew.Errors = append(ew.Errors, info)
 	}
 	if len(ew.Errors) == 0 {
 		return nil
 	}
+	if ew.CodePrefix == "" && ew.HasPublicCodes() {
+		// Experimental values have public codes: the other values use
+		// their decimal code as theirs.
+		for _, info := range append(ew.Errors[:len(ew.Errors):len(ew.Errors)], ew.Aliases...) {
+			if info.PublicCode == "" {
+				info.PublicCode = strconv.Itoa(int(info.Code))
+			}
+		}
+	}
 	return ew
 }
 
@@ -69,32 +354,93 @@ func resolveErrorInfo(enumName, valueName string, code int32, opt *errors.Error,
 	if reason == "" {
 		reason = enumName + ":" + valueName
 	}
+	goName := valueGoName(enumName, valueName)
 	return &template.ErrorInfo{
-		Name:    enumName,
-		Value:   valueName,
-		Status:  status,
-		Code:    code,
-		Reason:  reason,
-		Message: opt.GetMessage(),
+		Name:     enumName,
+		Value:    valueName,
+		GoName:   goName,
+		CodeName: upperSnake(goName),
+		Status:   status,
+		Code:     code,
+		Number:   code,
+		Reason:   reason,
+		Message:  opt.GetMessage(),
+		GRPCCode: grpcCodeFromHTTP(status),
 	}
 }
 
-// enumValueOptions returns the errors.Error options attached to an enum value,
-// or an empty value when none are set.
-func enumValueOptions(v *protogen.EnumValue) *errors.Error {
-	if proto.HasExtension(v.Desc.Options(), errors.E_Options) {
-		if opt, ok := proto.GetExtension(v.Desc.Options(), errors.E_Options).(*errors.Error); ok && opt != nil {
-			return opt
+// aliasErrorInfo returns the ErrorInfo of v, an allow_alias value reusing the
+// number of canonical. Its code, status, reason and message are canonical's,
+// since the switch based methods cannot tell the two apart.
+func aliasErrorInfo(canonical *template.ErrorInfo, v *protogen.EnumValue) *template.ErrorInfo {
+	info := *canonical
+	info.Value = string(v.Desc.Name())
+	info.GoName = valueGoName(info.Name, info.Value)
+	info.CodeName = upperSnake(info.GoName)
+	info.Description, _ = splitExample(commentText(v.Comments.Leading))
+	info.Deprecated = enumValueDeprecated(v)
+	info.Source = sourceLocation(v)
+	info.SourceFile, info.SourceLine = sourcePosition(v)
+	info.Canonical = canonical
+	return &info
+}
+
+// ErrorEnums returns the error enums declared in file, resolved with config, in
+// declaration order. The returned wrappers carry no Go identifiers, so they are
+// suitable for generators that emit other languages.
+func ErrorEnums(file *protogen.File, config *Config) []*template.ErrorWrapper {
+	var out []*template.ErrorWrapper
+	for _, enum := range file.Enums {
+		if ew := buildErrorWrapper(enum, config, "", ""); ew != nil {
+			out = append(out, ew)
 		}
 	}
-	return &errors.Error{}
+	return out
+}
+
+// commentText returns the text of a proto comment without the comment markers
+// and surrounding whitespace.
+func commentText(c protogen.Comments) string {
+	lines := strings.Split(strings.TrimSpace(string(c)), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// enumValueDeprecated reports whether the enum value carries deprecated = true.
+func enumValueDeprecated(v *protogen.EnumValue) bool {
+	opts, _ := v.Desc.Options().(*descriptorpb.EnumValueOptions)
+	return opts.GetDeprecated()
+}
+
+// sourceLocation returns the proto file and 1-based line declaring v, or just
+// the file when the request carries no source info.
+func sourceLocation(v *protogen.EnumValue) string {
+	file, line := sourcePosition(v)
+	if line == 0 {
+		return file
+	}
+	return fmt.Sprintf("%s:%d", file, line)
+}
+
+// sourcePosition returns the proto file and 1-based line declaring v, the
+// line being 0 when the request carries no source info.
+func sourcePosition(v *protogen.EnumValue) (string, int) {
+	file := v.Desc.ParentFile()
+	loc := file.SourceLocations().ByDescriptor(v.Desc)
+	if loc.Path == nil {
+		return file.Path(), 0
+	}
+	return file.Path(), loc.StartLine + 1
 }
 
 // hasErrorEnums reports whether enums contains at least one error enum (an enum
-// carrying the default_status option and at least one value).
-func hasErrorEnums(enums []*protogen.Enum) bool {
+// carrying the default_status option and at least one value) selected by
+// config.
+func hasErrorEnums(enums []*protogen.Enum, config *Config) bool {
 	for _, v := range enums {
-		if proto.HasExtension(v.Desc.Options(), errors.E_DefaultStatus) && len(v.Values) > 0 {
+		if _, ok := config.defaultStatus(v); ok && len(v.Values) > 0 && config.selects(v) {
 			return true
 		}
 	}
@@ -0,0 +1,133 @@
+package errors
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/internal/testutil"
+	gengo "google.golang.org/protobuf/cmd/protoc-gen-go/internal_gengo"
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+var compileGolden = flag.Bool("compile-golden", false, "build and vet the golden files in a scratch module, resolving its dependencies with go mod tidy")
+
+// goldenModule is the module path of the scratch module TestGoldenCompiles
+// builds, the import path of the testdata directory, so the Go packages of
+// the test protos resolve inside it.
+const goldenModule = "github.com/go-sphere/protoc-gen-sphere-errors/generate/errors/testdata"
+
+// TestGoldenCompiles builds and vets every golden file next to the
+// protoc-gen-go output of its proto, so a golden that matches the generator
+// byte for byte but no longer compiles fails too. It needs the go command and
+// the network, to fetch the runtimes the goldens import, hence the flag; run
+// it with `make check-golden`.
+func TestGoldenCompiles(t *testing.T) {
+	if !*compileGolden {
+		t.Skip("run with -compile-golden (make check-golden) to build the golden files")
+	}
+	goCmd, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go command not found")
+	}
+	root, err := filepath.Abs(filepath.Join("..", ".."))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	gomod := fmt.Sprintf("module %s\n\ngo 1.23.0\n\nrequire github.com/go-sphere/protoc-gen-sphere-errors v0.0.0\n\nreplace github.com/go-sphere/protoc-gen-sphere-errors => %s\n", goldenModule, root)
+	writeGoldenFile(t, filepath.Join(dir, "go.mod"), gomod)
+
+	// The versioned golden imports the errors of versioned_v1_errors.proto.
+	writeProtoPackage(t, filepath.Join(dir, "versioned", "v1"), "testdata/pb/versioned_errors.pb", "versioned_v1_errors.proto")
+	v1 := testutil.PluginFromPB(t, "testdata/pb/versioned_errors.pb", "versioned_v1_errors.proto")
+	file, err := GenerateFile(v1, testutil.FileToGenerate(t, v1), goldenConfig(t, v1))
+	if err != nil {
+		t.Fatalf("GenerateFile(versioned_v1_errors.proto) failed: %v", err)
+	}
+	writeGoldenFile(t, filepath.Join(dir, "versioned", "v1", "versioned_v1_errors.errors.pb.go"), mustContent(t, file))
+
+	goldens, err := filepath.Glob("testdata/golden/*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, golden := range goldens {
+		name := strings.TrimSuffix(filepath.Base(golden), ".errors.pb.go")
+		// package_suffix declares a package other than the one of the
+		// protoc-gen-go output it would share a directory with.
+		if strings.HasSuffix(name, "_package_suffix") {
+			continue
+		}
+		content, err := os.ReadFile(golden)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, source, ok := strings.Cut(string(content), "\n// source: ")
+		if !ok {
+			t.Fatalf("%s has no source line", golden)
+		}
+		source, _, _ = strings.Cut(source, "\n")
+		pkg := filepath.Join(dir, "golden", name)
+		writeProtoPackage(t, pkg, "testdata/pb/"+strings.TrimSuffix(source, ".proto")+".pb", source)
+		writeGoldenFile(t, filepath.Join(pkg, filepath.Base(golden)), string(content))
+		// The golden tests do not record the embedded catalog; vet only needs
+		// the file to exist.
+		if strings.Contains(string(content), "//go:embed errors.embed.json") {
+			writeGoldenFile(t, filepath.Join(pkg, "errors.embed.json"), "[]\n")
+		}
+	}
+
+	for _, args := range [][]string{{"mod", "tidy"}, {"vet", "./..."}} {
+		cmd := exec.Command(goCmd, args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("go %s: %v\n%s", strings.Join(args, " "), err, out)
+		}
+	}
+}
+
+// writeProtoPackage writes the protoc-gen-go output of the proto named
+// protoName of the descriptor set pbFile into dir.
+func writeProtoPackage(t *testing.T, dir, pbFile, protoName string) {
+	t.Helper()
+	gen := testutil.PluginFromPB(t, pbFile, protoName)
+	for _, f := range gen.Files {
+		if f.Generate {
+			gengo.GenerateFile(gen, f)
+		}
+	}
+	resp := gen.Response()
+	if resp.Error != nil {
+		t.Fatalf("protoc-gen-go %s: %s", protoName, resp.GetError())
+	}
+	for _, f := range resp.File {
+		writeGoldenFile(t, filepath.Join(dir, filepath.Base(f.GetName())), f.GetContent())
+	}
+}
+
+// goldenConfig returns testConfig resolved against the options of plugin,
+// as TestGolden prepares its configurations.
+func goldenConfig(t *testing.T, plugin *protogen.Plugin) *Config {
+	t.Helper()
+	config := *testConfig
+	if err := ResolveOptions(plugin.Files, &config); err != nil {
+		t.Fatalf("ResolveOptions failed: %v", err)
+	}
+	AssignGoNames(plugin.Files, &config)
+	return &config
+}
+
+// writeGoldenFile writes content to path, creating its directory.
+func writeGoldenFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
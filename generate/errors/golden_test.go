@@ -7,8 +7,10 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-sphere/protoc-gen-sphere-errors/generate/internal/testutil"
+	"google.golang.org/protobuf/compiler/protogen"
 )
 
 var updateGolden = flag.Bool("update-golden", false, "update golden files instead of comparing")
@@ -18,6 +20,7 @@ func TestGolden(t *testing.T) {
 		name       string
 		pbFile     string
 		protoName  string
+		config     *Config
 		wantFile   bool
 		goldenFile string
 	}{
@@ -35,6 +38,848 @@ func TestGolden(t *testing.T) {
 			wantFile:   true,
 			goldenFile: "testdata/golden/mixed_enums.errors.pb.go",
 		},
+		{
+			name:      "basic_errors_grpc",
+			pbFile:    "testdata/pb/basic_errors.pb",
+			protoName: "basic_errors.proto",
+			config: &Config{
+				NewErrorsFunc: testConfig.NewErrorsFunc,
+				GRPCStatus:    true,
+				Domains:       map[string]string{"": "users.example.com"},
+			},
+			wantFile:   true,
+			goldenFile: "testdata/golden/basic_errors_grpc.errors.pb.go",
+		},
+		{
+			name:      "basic_errors_error_text",
+			pbFile:    "testdata/pb/basic_errors.pb",
+			protoName: "basic_errors.proto",
+			config: &Config{
+				NewErrorsFunc: testConfig.NewErrorsFunc,
+				ErrorText:     ErrorTextCodeMessage,
+				Prebuilt:      true,
+			},
+			wantFile:   true,
+			goldenFile: "testdata/golden/basic_errors_error_text.errors.pb.go",
+		},
+		{
+			name:      "basic_errors_locale",
+			pbFile:    "testdata/pb/basic_errors.pb",
+			protoName: "basic_errors.proto",
+			config: &Config{
+				NewErrorsFunc:   testConfig.NewErrorsFunc,
+				LocaleFallbacks: map[string][]string{"zh-HK": {"zh"}, "zh": {"en"}},
+			},
+			wantFile:   true,
+			goldenFile: "testdata/golden/basic_errors_locale.errors.pb.go",
+		},
+		{
+			name:      "basic_errors_i18n",
+			pbFile:    "testdata/pb/basic_errors.pb",
+			protoName: "basic_errors.proto",
+			config: &Config{
+				NewErrorsFunc:   testConfig.NewErrorsFunc,
+				LocaleFallbacks: map[string][]string{"zh-HK": {"zh"}},
+				LocalizedMessages: map[string]map[string]string{
+					"tests.basic.USER_ERROR_NOT_FOUND":  {"zh": "用户不存在", "es": "usuario no encontrado"},
+					"tests.basic.USER_ERROR_INVALID_ID": {"zh": "无效的用户 ID"},
+				},
+			},
+			wantFile:   true,
+			goldenFile: "testdata/golden/basic_errors_i18n.errors.pb.go",
+		},
+		{
+			name:      "basic_errors_grpc_code",
+			pbFile:    "testdata/pb/basic_errors.pb",
+			protoName: "basic_errors.proto",
+			config: &Config{
+				NewErrorsFunc: testConfig.NewErrorsFunc,
+				GRPCStatus:    true,
+				GRPCCodes: map[string]string{
+					"tests.basic.USER_ERROR_INVALID_ID": "FAILED_PRECONDITION",
+					"tests.basic.USER_ERROR_DEFAULTED":  "NOT_FOUND",
+				},
+			},
+			wantFile:   true,
+			goldenFile: "testdata/golden/basic_errors_grpc_code.errors.pb.go",
+		},
+		{
+			name:      "basic_errors_help_link",
+			pbFile:    "testdata/pb/basic_errors.pb",
+			protoName: "basic_errors.proto",
+			config: &Config{
+				NewErrorsFunc: testConfig.NewErrorsFunc,
+				GRPCStatus:    true,
+				DocURLBase:    "https://kb.example.com/errors/{package}/{value}",
+				DocURLs:       map[string]string{"tests.basic.USER_ERROR_NOT_FOUND": "https://kb.example.com/runbooks/user-lookup?step=1"},
+			},
+			wantFile:   true,
+			goldenFile: "testdata/golden/basic_errors_help_link.errors.pb.go",
+		},
+		{
+			name:      "basic_errors_sentinels",
+			pbFile:    "testdata/pb/basic_errors.pb",
+			protoName: "basic_errors.proto",
+			config: &Config{
+				NewErrorsFunc:  testConfig.NewErrorsFunc,
+				SentinelErrors: true,
+			},
+			wantFile:   true,
+			goldenFile: "testdata/golden/basic_errors_sentinels.errors.pb.go",
+		},
+		{
+			name:      "basic_errors_value_only",
+			pbFile:    "testdata/pb/basic_errors.pb",
+			protoName: "basic_errors.proto",
+			config: &Config{
+				NewErrorsFunc:  testConfig.NewErrorsFunc,
+				SentinelErrors: true,
+				ErrorCodes:     true,
+				NameStyle:      NameStyleValueOnly,
+			},
+			wantFile:   true,
+			goldenFile: "testdata/golden/basic_errors_value_only.errors.pb.go",
+		},
+		{
+			name:      "basic_errors_registry",
+			pbFile:    "testdata/pb/basic_errors.pb",
+			protoName: "basic_errors.proto",
+			config: &Config{
+				NewErrorsFunc: testConfig.NewErrorsFunc,
+				Registry:      true,
+			},
+			wantFile:   true,
+			goldenFile: "testdata/golden/basic_errors_registry.errors.pb.go",
+		},
+		{
+			name:      "basic_errors_codes",
+			pbFile:    "testdata/pb/basic_errors.pb",
+			protoName: "basic_errors.proto",
+			config: &Config{
+				NewErrorsFunc: testConfig.NewErrorsFunc,
+				ErrorCodes:    true,
+			},
+			wantFile:   true,
+			goldenFile: "testdata/golden/basic_errors_codes.errors.pb.go",
+		},
+		{
+			name:      "basic_errors_exhaustive",
+			pbFile:    "testdata/pb/basic_errors.pb",
+			protoName: "basic_errors.proto",
+			config: &Config{
+				NewErrorsFunc: testConfig.NewErrorsFunc,
+				Exhaustive:    true,
+			},
+			wantFile:   true,
+			goldenFile: "testdata/golden/basic_errors_exhaustive.errors.pb.go",
+		},
+		{
+			name:      "basic_errors_parse",
+			pbFile:    "testdata/pb/basic_errors.pb",
+			protoName: "basic_errors.proto",
+			config: &Config{
+				NewErrorsFunc: testConfig.NewErrorsFunc,
+				ParseHelpers:  true,
+			},
+			wantFile:   true,
+			goldenFile: "testdata/golden/basic_errors_parse.errors.pb.go",
+		},
+		{
+			name:      "basic_errors_retry",
+			pbFile:    "testdata/pb/basic_errors.pb",
+			protoName: "basic_errors.proto",
+			config: &Config{
+				NewErrorsFunc:   testConfig.NewErrorsFunc,
+				RetryHelpers:    true,
+				RetryableValues: map[string]bool{"tests.basic.USER_ERROR_PERMISSION_DENIED": true},
+			},
+			wantFile:   true,
+			goldenFile: "testdata/golden/basic_errors_retry.errors.pb.go",
+		},
+		{
+			name:      "basic_errors_origin",
+			pbFile:    "testdata/pb/basic_errors.pb",
+			protoName: "basic_errors.proto",
+			config: &Config{
+				NewErrorsFunc: testConfig.NewErrorsFunc,
+				OriginHelpers: true,
+				Origins:       map[string]string{"tests.basic.OrderError": OriginUpstream, "tests.basic.ORDER_ERROR_OUT_OF_STOCK": OriginClient},
+			},
+			wantFile:   true,
+			goldenFile: "testdata/golden/basic_errors_origin.errors.pb.go",
+		},
+		{
+			name:      "basic_errors_log",
+			pbFile:    "testdata/pb/basic_errors.pb",
+			protoName: "basic_errors.proto",
+			config: &Config{
+				NewErrorsFunc: testConfig.NewErrorsFunc,
+				LogHelpers:    true,
+				Severities: map[string]string{
+					"tests.basic.OrderError":                   "CRITICAL",
+					"tests.basic.USER_ERROR_PERMISSION_DENIED": "WARN",
+				},
+			},
+			wantFile:   true,
+			goldenFile: "testdata/golden/basic_errors_log.errors.pb.go",
+		},
+		{
+			name:      "basic_errors_log_sampling",
+			pbFile:    "testdata/pb/basic_errors.pb",
+			protoName: "basic_errors.proto",
+			config: &Config{
+				NewErrorsFunc: testConfig.NewErrorsFunc,
+				LogSampleRates: map[string]float64{
+					"tests.basic.UserError":            0.5,
+					"tests.basic.USER_ERROR_NOT_FOUND": 0.01,
+				},
+			},
+			wantFile:   true,
+			goldenFile: "testdata/golden/basic_errors_log_sampling.errors.pb.go",
+		},
+		{
+			name:      "basic_errors_cache_ttl",
+			pbFile:    "testdata/pb/basic_errors.pb",
+			protoName: "basic_errors.proto",
+			config: &Config{
+				NewErrorsFunc: testConfig.NewErrorsFunc,
+				CacheTTLs: map[string]time.Duration{
+					"tests.basic.USER_ERROR_NOT_FOUND": time.Minute,
+				},
+			},
+			wantFile:   true,
+			goldenFile: "testdata/golden/basic_errors_cache_ttl.errors.pb.go",
+		},
+		{
+			name:      "basic_errors_http_headers",
+			pbFile:    "testdata/pb/basic_errors.pb",
+			protoName: "basic_errors.proto",
+			config: &Config{
+				NewErrorsFunc: testConfig.NewErrorsFunc,
+				HTTPHeaders: map[string]map[string]string{
+					"tests.basic.UserError":                    {"X-Error-Domain": "users"},
+					"tests.basic.USER_ERROR_PERMISSION_DENIED": {"Www-Authenticate": `Bearer realm="users", scope="{scope}"`, "X-Error-Domain": "auth"},
+				},
+			},
+			wantFile:   true,
+			goldenFile: "testdata/golden/basic_errors_http_headers.errors.pb.go",
+		},
+		{
+			name:      "basic_errors_slo",
+			pbFile:    "testdata/pb/basic_errors.pb",
+			protoName: "basic_errors.proto",
+			config: &Config{
+				NewErrorsFunc:   testConfig.NewErrorsFunc,
+				SLOExemptValues: map[string]bool{"tests.basic.USER_ERROR_NOT_FOUND": true},
+			},
+			wantFile:   true,
+			goldenFile: "testdata/golden/basic_errors_slo.errors.pb.go",
+		},
+		{
+			name:      "basic_errors_visibility",
+			pbFile:    "testdata/pb/basic_errors.pb",
+			protoName: "basic_errors.proto",
+			config: &Config{
+				NewErrorsFunc: testConfig.NewErrorsFunc,
+				Visibilities:  map[string]string{"tests.basic.USER_ERROR_PERMISSION_DENIED": VisibilityInternal},
+			},
+			wantFile:   true,
+			goldenFile: "testdata/golden/basic_errors_visibility.errors.pb.go",
+		},
+		{
+			name:      "basic_errors_value_new_errors_func",
+			pbFile:    "testdata/pb/basic_errors.pb",
+			protoName: "basic_errors.proto",
+			config: &Config{
+				NewErrorsFunc: testConfig.NewErrorsFunc,
+				ValueNewErrorsFuncs: map[string]protogen.GoIdent{
+					"tests.basic.USER_ERROR_PERMISSION_DENIED": statusErrorPackage.Ident("New"),
+				},
+			},
+			wantFile:   true,
+			goldenFile: "testdata/golden/basic_errors_value_new_errors_func.errors.pb.go",
+		},
+		{
+			name:      "basic_errors_metrics",
+			pbFile:    "testdata/pb/basic_errors.pb",
+			protoName: "basic_errors.proto",
+			config: &Config{
+				NewErrorsFunc: testConfig.NewErrorsFunc,
+				Metrics:       MetricsPrometheus,
+			},
+			wantFile:   true,
+			goldenFile: "testdata/golden/basic_errors_metrics.errors.pb.go",
+		},
+		{
+			name:      "basic_errors_metadata",
+			pbFile:    "testdata/pb/basic_errors.pb",
+			protoName: "basic_errors.proto",
+			config: &Config{
+				NewErrorsFunc: testConfig.NewErrorsFunc,
+				Metadata:      true,
+			},
+			wantFile:   true,
+			goldenFile: "testdata/golden/basic_errors_metadata.errors.pb.go",
+		},
+		{
+			name:      "basic_errors_trace",
+			pbFile:    "testdata/pb/basic_errors.pb",
+			protoName: "basic_errors.proto",
+			config: &Config{
+				NewErrorsFunc: testConfig.NewErrorsFunc,
+				TraceContext:  true,
+			},
+			wantFile:   true,
+			goldenFile: "testdata/golden/basic_errors_trace.errors.pb.go",
+		},
+		{
+			name:      "basic_errors_package_suffix",
+			pbFile:    "testdata/pb/basic_errors.pb",
+			protoName: "basic_errors.proto",
+			config: &Config{
+				NewErrorsFunc: testConfig.NewErrorsFunc,
+				PackageSuffix: "apierrors",
+			},
+			wantFile:   true,
+			goldenFile: "testdata/golden/basic_errors_package_suffix.errors.pb.go",
+		},
+		{
+			name:      "aliased_errors",
+			pbFile:    "testdata/pb/aliased_errors.pb",
+			protoName: "aliased_errors.proto",
+			config: &Config{
+				NewErrorsFunc:  testConfig.NewErrorsFunc,
+				SentinelErrors: true,
+			},
+			wantFile:   true,
+			goldenFile: "testdata/golden/aliased_errors.errors.pb.go",
+		},
+		{
+			name:       "basic_errors_kratos",
+			pbFile:     "testdata/pb/basic_errors.pb",
+			protoName:  "basic_errors.proto",
+			config:     &Config{Runtime: RuntimeKratos},
+			wantFile:   true,
+			goldenFile: "testdata/golden/basic_errors_kratos.errors.pb.go",
+		},
+		{
+			name:      "basic_errors_gin",
+			pbFile:    "testdata/pb/basic_errors.pb",
+			protoName: "basic_errors.proto",
+			config: &Config{
+				NewErrorsFunc: testConfig.NewErrorsFunc,
+				HTTPFramework: FrameworkGin,
+			},
+			wantFile:   true,
+			goldenFile: "testdata/golden/basic_errors_gin.errors.pb.go",
+		},
+		{
+			name:      "basic_errors_echo",
+			pbFile:    "testdata/pb/basic_errors.pb",
+			protoName: "basic_errors.proto",
+			config: &Config{
+				NewErrorsFunc: testConfig.NewErrorsFunc,
+				HTTPFramework: FrameworkEcho,
+			},
+			wantFile:   true,
+			goldenFile: "testdata/golden/basic_errors_echo.errors.pb.go",
+		},
+		{
+			name:      "basic_errors_chi",
+			pbFile:    "testdata/pb/basic_errors.pb",
+			protoName: "basic_errors.proto",
+			config: &Config{
+				NewErrorsFunc: testConfig.NewErrorsFunc,
+				HTTPFramework: FrameworkChi,
+			},
+			wantFile:   true,
+			goldenFile: "testdata/golden/basic_errors_chi.errors.pb.go",
+		},
+		{
+			name:      "basic_errors_chi_aws",
+			pbFile:    "testdata/pb/basic_errors.pb",
+			protoName: "basic_errors.proto",
+			config: &Config{
+				NewErrorsFunc: testConfig.NewErrorsFunc,
+				HTTPFramework: FrameworkChi,
+				HTTPEnvelope:  EnvelopeAWS,
+			},
+			wantFile:   true,
+			goldenFile: "testdata/golden/basic_errors_chi_aws.errors.pb.go",
+		},
+		{
+			name:      "basic_errors_kratos_compat",
+			pbFile:    "testdata/pb/basic_errors.pb",
+			protoName: "basic_errors.proto",
+			config: &Config{
+				NewErrorsFunc: testConfig.NewErrorsFunc,
+				KratosCompat:  true,
+				Domains:       map[string]string{"": "users.example.com"},
+			},
+			wantFile:   true,
+			goldenFile: "testdata/golden/basic_errors_kratos_compat.errors.pb.go",
+		},
+		{
+			name:       "basic_errors_connect",
+			pbFile:     "testdata/pb/basic_errors.pb",
+			protoName:  "basic_errors.proto",
+			config:     &Config{Runtime: RuntimeConnect},
+			wantFile:   true,
+			goldenFile: "testdata/golden/basic_errors_connect.errors.pb.go",
+		},
+		{
+			name:       "basic_errors_typed",
+			pbFile:     "testdata/pb/basic_errors.pb",
+			protoName:  "basic_errors.proto",
+			config:     &Config{Runtime: RuntimeTyped},
+			wantFile:   true,
+			goldenFile: "testdata/golden/basic_errors_typed.errors.pb.go",
+		},
+		{
+			name:       "basic_errors_stdlib",
+			pbFile:     "testdata/pb/basic_errors.pb",
+			protoName:  "basic_errors.proto",
+			config:     &Config{Runtime: RuntimeStdlib},
+			wantFile:   true,
+			goldenFile: "testdata/golden/basic_errors_stdlib.errors.pb.go",
+		},
+		{
+			name:       "basic_errors_minimal_runtime",
+			pbFile:     "testdata/pb/basic_errors.pb",
+			protoName:  "basic_errors.proto",
+			config:     &Config{MinimalRuntime: true},
+			wantFile:   true,
+			goldenFile: "testdata/golden/basic_errors_minimal_runtime.errors.pb.go",
+		},
+		{
+			name:      "basic_errors_zero_skip",
+			pbFile:    "testdata/pb/basic_errors.pb",
+			protoName: "basic_errors.proto",
+			config: &Config{
+				NewErrorsFunc:  testConfig.NewErrorsFunc,
+				ZeroValue:      ZeroValueSkip,
+				SentinelErrors: true,
+			},
+			wantFile:   true,
+			goldenFile: "testdata/golden/basic_errors_zero_skip.errors.pb.go",
+		},
+		{
+			name:      "basic_errors_categories",
+			pbFile:    "testdata/pb/basic_errors.pb",
+			protoName: "basic_errors.proto",
+			config: &Config{
+				NewErrorsFunc: testConfig.NewErrorsFunc,
+				Categories: map[string]string{
+					"tests.basic.UserError":                    "validation",
+					"tests.basic.USER_ERROR_PERMISSION_DENIED": "auth",
+					"tests.basic.ORDER_ERROR_OUT_OF_STOCK":     "out_of_stock",
+				},
+			},
+			wantFile:   true,
+			goldenFile: "testdata/golden/basic_errors_categories.errors.pb.go",
+		},
+		{
+			name:      "basic_errors_problem",
+			pbFile:    "testdata/pb/basic_errors.pb",
+			protoName: "basic_errors.proto",
+			config: &Config{
+				NewErrorsFunc: testConfig.NewErrorsFunc,
+				ProblemJSON:   true,
+				ProblemType:   "https://errors.example.com/{package}/{enum}/{value}",
+			},
+			wantFile:   true,
+			goldenFile: "testdata/golden/basic_errors_problem.errors.pb.go",
+		},
+		{
+			name:      "basic_errors_message_resolver",
+			pbFile:    "testdata/pb/basic_errors.pb",
+			protoName: "basic_errors.proto",
+			config: &Config{
+				NewErrorsFunc:   testConfig.NewErrorsFunc,
+				MessageResolver: true,
+			},
+			wantFile:   true,
+			goldenFile: "testdata/golden/basic_errors_message_resolver.errors.pb.go",
+		},
+		{
+			name:      "basic_errors_status_proto",
+			pbFile:    "testdata/pb/basic_errors.pb",
+			protoName: "basic_errors.proto",
+			config: &Config{
+				NewErrorsFunc: testConfig.NewErrorsFunc,
+				StatusProto:   true,
+			},
+			wantFile:   true,
+			goldenFile: "testdata/golden/basic_errors_status_proto.errors.pb.go",
+		},
+		{
+			name:      "basic_errors_propagation",
+			pbFile:    "testdata/pb/basic_errors.pb",
+			protoName: "basic_errors.proto",
+			config: &Config{
+				NewErrorsFunc: testConfig.NewErrorsFunc,
+				Propagation:   true,
+			},
+			wantFile:   true,
+			goldenFile: "testdata/golden/basic_errors_propagation.errors.pb.go",
+		},
+		{
+			name:      "basic_errors_message_headers",
+			pbFile:    "testdata/pb/basic_errors.pb",
+			protoName: "basic_errors.proto",
+			config: &Config{
+				NewErrorsFunc:  testConfig.NewErrorsFunc,
+				MessageHeaders: true,
+			},
+			wantFile:   true,
+			goldenFile: "testdata/golden/basic_errors_message_headers.errors.pb.go",
+		},
+		{
+			name:      "basic_errors_join",
+			pbFile:    "testdata/pb/basic_errors.pb",
+			protoName: "basic_errors.proto",
+			config: &Config{
+				NewErrorsFunc: testConfig.NewErrorsFunc,
+				JoinErrors:    true,
+			},
+			wantFile:   true,
+			goldenFile: "testdata/golden/basic_errors_join.errors.pb.go",
+		},
+		{
+			name:      "basic_errors_span",
+			pbFile:    "testdata/pb/basic_errors.pb",
+			protoName: "basic_errors.proto",
+			config: &Config{
+				NewErrorsFunc: testConfig.NewErrorsFunc,
+				OTelEvents:    map[string]string{"tests.basic.UserError": "user.error"},
+				SpanErrors:    map[string]bool{"tests.basic.USER_ERROR_NOT_FOUND": true},
+			},
+			wantFile:   true,
+			goldenFile: "testdata/golden/basic_errors_span.errors.pb.go",
+		},
+		{
+			name:      "basic_errors_binary",
+			pbFile:    "testdata/pb/basic_errors.pb",
+			protoName: "basic_errors.proto",
+			config: &Config{
+				NewErrorsFunc: testConfig.NewErrorsFunc,
+				BinaryFormats: []string{BinaryFormatCBOR, BinaryFormatMsgpack},
+			},
+			wantFile:   true,
+			goldenFile: "testdata/golden/basic_errors_binary.errors.pb.go",
+		},
+		{
+			name:      "basic_errors_gateway",
+			pbFile:    "testdata/pb/basic_errors.pb",
+			protoName: "basic_errors.proto",
+			config: &Config{
+				NewErrorsFunc: testConfig.NewErrorsFunc,
+				Gateway:       true,
+			},
+			wantFile:   true,
+			goldenFile: "testdata/golden/basic_errors_gateway.errors.pb.go",
+		},
+		{
+			name:      "basic_errors_gateway_google",
+			pbFile:    "testdata/pb/basic_errors.pb",
+			protoName: "basic_errors.proto",
+			config: &Config{
+				NewErrorsFunc: testConfig.NewErrorsFunc,
+				Gateway:       true,
+				HTTPEnvelope:  EnvelopeGoogle,
+			},
+			wantFile:   true,
+			goldenFile: "testdata/golden/basic_errors_gateway_google.errors.pb.go",
+		},
+		{
+			name:      "custom_options",
+			pbFile:    "testdata/pb/custom_options.pb",
+			protoName: "custom_options.proto",
+			config: &Config{
+				NewErrorsFunc:       testConfig.NewErrorsFunc,
+				OptionsType:         "tests.custom.ErrorOptions",
+				OptionFields:        map[string]string{"status": "http_code"},
+				DefaultStatusOption: "tests.custom.http_default",
+			},
+			wantFile:   true,
+			goldenFile: "testdata/golden/custom_options.errors.pb.go",
+		},
+		{
+			name:      "basic_errors_stack",
+			pbFile:    "testdata/pb/basic_errors.pb",
+			protoName: "basic_errors.proto",
+			config: &Config{
+				NewErrorsFunc: testConfig.NewErrorsFunc,
+				WithStack:     true,
+			},
+			wantFile:   true,
+			goldenFile: "testdata/golden/basic_errors_stack.errors.pb.go",
+		},
+		{
+			name:      "basic_errors_prebuilt",
+			pbFile:    "testdata/pb/basic_errors.pb",
+			protoName: "basic_errors.proto",
+			config: &Config{
+				NewErrorsFunc: testConfig.NewErrorsFunc,
+				Prebuilt:      true,
+			},
+			wantFile:   true,
+			goldenFile: "testdata/golden/basic_errors_prebuilt.errors.pb.go",
+		},
+		{
+			name:      "basic_errors_error_funcs",
+			pbFile:    "testdata/pb/basic_errors.pb",
+			protoName: "basic_errors.proto",
+			config: &Config{
+				NewErrorsFunc: testConfig.NewErrorsFunc,
+				ErrorFuncs:    true,
+			},
+			wantFile:   true,
+			goldenFile: "testdata/golden/basic_errors_error_funcs.errors.pb.go",
+		},
+		{
+			name:      "basic_errors_embed_catalog",
+			pbFile:    "testdata/pb/basic_errors.pb",
+			protoName: "basic_errors.proto",
+			config: &Config{
+				NewErrorsFunc: testConfig.NewErrorsFunc,
+				EmbedCatalog:  true,
+			},
+			wantFile:   true,
+			goldenFile: "testdata/golden/basic_errors_embed_catalog.errors.pb.go",
+		},
+		{
+			name:      "basic_errors_embed_catalog_service",
+			pbFile:    "testdata/pb/basic_errors.pb",
+			protoName: "basic_errors.proto",
+			config: &Config{
+				NewErrorsFunc:  testConfig.NewErrorsFunc,
+				EmbedCatalog:   true,
+				CatalogService: true,
+			},
+			wantFile:   true,
+			goldenFile: "testdata/golden/basic_errors_embed_catalog_service.errors.pb.go",
+		},
+		{
+			name:      "basic_errors_validation",
+			pbFile:    "testdata/pb/basic_errors.pb",
+			protoName: "basic_errors.proto",
+			config: &Config{
+				NewErrorsFunc: testConfig.NewErrorsFunc,
+				ValidationErrors: map[string]string{
+					"":                               "tests.basic.USER_ERROR_INVALID_ID",
+					"tests.basic.CreateOrderRequest": "tests.basic.ORDER_ERROR_OUT_OF_STOCK",
+				},
+			},
+			wantFile:   true,
+			goldenFile: "testdata/golden/basic_errors_validation.errors.pb.go",
+		},
+		{
+			name:      "versioned_errors",
+			pbFile:    "testdata/pb/versioned_errors.pb",
+			protoName: "versioned_errors.proto",
+			config: &Config{
+				NewErrorsFunc: testConfig.NewErrorsFunc,
+				Supersedes: map[string]string{
+					"tests.versioned.v2.USER_ERROR_NOT_FOUND": "tests.versioned.v1.USER_NOT_FOUND",
+					"tests.versioned.v2.USER_ERROR_SUSPENDED": "tests.versioned.v1.USER_BANNED",
+					"tests.versioned.v2.USER_ERROR_DELETED":   "tests.versioned.v1.USER_BANNED",
+				},
+			},
+			wantFile:   true,
+			goldenFile: "testdata/golden/versioned_errors.errors.pb.go",
+		},
+		{
+			name:      "basic_errors_code_prefix",
+			pbFile:    "testdata/pb/basic_errors.pb",
+			protoName: "basic_errors.proto",
+			config: &Config{
+				NewErrorsFunc: testConfig.NewErrorsFunc,
+				CodeOffsets:   map[string]int32{"": 40400},
+				CodePrefixes:  map[string]string{"tests.basic": "AUTH-"},
+			},
+			wantFile:   true,
+			goldenFile: "testdata/golden/basic_errors_code_prefix.errors.pb.go",
+		},
+		{
+			name:      "basic_errors_log_message",
+			pbFile:    "testdata/pb/basic_errors.pb",
+			protoName: "basic_errors.proto",
+			config: &Config{
+				NewErrorsFunc: testConfig.NewErrorsFunc,
+				GRPCStatus:    true,
+				LogHelpers:    true,
+				LogMessages: map[string]string{
+					"tests.basic.USER_ERROR_NOT_FOUND": "user row missing from the primary shard",
+				},
+			},
+			wantFile:   true,
+			goldenFile: "testdata/golden/basic_errors_log_message.errors.pb.go",
+		},
+		{
+			name:      "basic_errors_ctx_constructor",
+			pbFile:    "testdata/pb/basic_errors.pb",
+			protoName: "basic_errors.proto",
+			config: &Config{
+				NewErrorsFunc: protogen.GoIdent{GoName: "NewContextError", GoImportPath: "github.com/go-sphere/httpx"},
+				NewErrorsArgs: []string{ArgContext, ArgCode, ArgMessage},
+			},
+			wantFile:   true,
+			goldenFile: "testdata/golden/basic_errors_ctx_constructor.errors.pb.go",
+		},
+		{
+			name:       "proto2_errors",
+			pbFile:     "testdata/pb/proto2_errors.pb",
+			protoName:  "proto2_errors.proto",
+			wantFile:   true,
+			goldenFile: "testdata/golden/proto2_errors.errors.pb.go",
+		},
+		{
+			name:       "editions_errors",
+			pbFile:     "testdata/pb/editions_errors.pb",
+			protoName:  "editions_errors.proto",
+			wantFile:   true,
+			goldenFile: "testdata/golden/editions_errors.errors.pb.go",
+		},
+		{
+			name:       "formatted_errors",
+			pbFile:     "testdata/pb/formatted_errors.pb",
+			protoName:  "formatted_errors.proto",
+			wantFile:   true,
+			goldenFile: "testdata/golden/formatted_errors.errors.pb.go",
+		},
+		{
+			name:       "placeholder_errors",
+			pbFile:     "testdata/pb/placeholder_errors.pb",
+			protoName:  "placeholder_errors.proto",
+			wantFile:   true,
+			goldenFile: "testdata/golden/placeholder_errors.errors.pb.go",
+		},
+		{
+			name:      "placeholder_errors_unexported",
+			pbFile:    "testdata/pb/placeholder_errors.pb",
+			protoName: "placeholder_errors.proto",
+			config: &Config{
+				NewErrorsFunc:          testConfig.NewErrorsFunc,
+				UnexportedConstructors: true,
+			},
+			wantFile:   true,
+			goldenFile: "testdata/golden/placeholder_errors_unexported.errors.pb.go",
+		},
+		{
+			name:      "basic_errors_recover",
+			pbFile:    "testdata/pb/basic_errors.pb",
+			protoName: "basic_errors.proto",
+			config: &Config{
+				NewErrorsFunc: testConfig.NewErrorsFunc,
+				RecoverErrors: []string{"tests.basic.USER_ERROR_PERMISSION_DENIED"},
+			},
+			wantFile:   true,
+			goldenFile: "testdata/golden/basic_errors_recover.errors.pb.go",
+		},
+		{
+			name:      "basic_errors_fallback",
+			pbFile:    "testdata/pb/basic_errors.pb",
+			protoName: "basic_errors.proto",
+			config: &Config{
+				NewErrorsFunc:  testConfig.NewErrorsFunc,
+				FallbackErrors: []string{"tests.basic.USER_ERROR_PERMISSION_DENIED"},
+			},
+			wantFile:   true,
+			goldenFile: "testdata/golden/basic_errors_fallback.errors.pb.go",
+		},
+		{
+			name:      "basic_errors_client_codes",
+			pbFile:    "testdata/pb/basic_errors.pb",
+			protoName: "basic_errors.proto",
+			config: &Config{
+				NewErrorsFunc: testConfig.NewErrorsFunc,
+				ClientCodes: map[string]int32{
+					"tests.basic.UserError":                    4000,
+					"tests.basic.USER_ERROR_PERMISSION_DENIED": 4030,
+				},
+			},
+			wantFile:   true,
+			goldenFile: "testdata/golden/basic_errors_client_codes.errors.pb.go",
+		},
+		{
+			name:      "basic_errors_stability",
+			pbFile:    "testdata/pb/basic_errors.pb",
+			protoName: "basic_errors.proto",
+			config: &Config{
+				NewErrorsFunc: testConfig.NewErrorsFunc,
+				Stabilities: map[string]string{
+					"tests.basic.OrderError":           StabilityBeta,
+					"tests.basic.USER_ERROR_NOT_FOUND": StabilityExperimental,
+				},
+				ExperimentalCodePrefix: "X-",
+			},
+			wantFile:   true,
+			goldenFile: "testdata/golden/basic_errors_stability.errors.pb.go",
+		},
+		{
+			name:      "basic_errors_legacy",
+			pbFile:    "testdata/pb/basic_errors.pb",
+			protoName: "basic_errors.proto",
+			config: &Config{
+				NewErrorsFunc: testConfig.NewErrorsFunc,
+				LegacyPatterns: map[string]string{
+					"tests.basic.USER_ERROR_INVALID_ID":    `"field":\s*"id"`,
+					"tests.basic.USER_ERROR_NOT_FOUND":     `(?i)no such user`,
+					"tests.basic.ORDER_ERROR_OUT_OF_STOCK": `out of stock`,
+				},
+			},
+			wantFile:   true,
+			goldenFile: "testdata/golden/basic_errors_legacy.errors.pb.go",
+		},
+		{
+			name:      "formatted_errors_details",
+			pbFile:    "testdata/pb/formatted_errors.pb",
+			protoName: "formatted_errors.proto",
+			config: &Config{
+				NewErrorsFunc: testConfig.NewErrorsFunc,
+				DetailTypes:   map[string]string{"tests.formatted.QUOTA_ERROR_EXCEEDED": "tests.formatted.QuotaViolation"},
+			},
+			wantFile:   true,
+			goldenFile: "testdata/golden/formatted_errors_details.errors.pb.go",
+		},
+		{
+			name:      "formatted_errors_any_details",
+			pbFile:    "testdata/pb/formatted_errors.pb",
+			protoName: "formatted_errors.proto",
+			config: &Config{
+				NewErrorsFunc: testConfig.NewErrorsFunc,
+				DetailTypes:   map[string]string{"tests.formatted.QUOTA_ERROR_EXCEEDED": "tests.formatted.QuotaViolation"},
+				AnyDetails:    true,
+			},
+			wantFile:   true,
+			goldenFile: "testdata/golden/formatted_errors_any_details.errors.pb.go",
+		},
+		{
+			name:      "formatted_errors_message_error",
+			pbFile:    "testdata/pb/formatted_errors.pb",
+			protoName: "formatted_errors.proto",
+			config: &Config{
+				NewErrorsFunc: testConfig.NewErrorsFunc,
+				MessageErrors: map[string]string{"tests.formatted.QuotaViolation": "tests.formatted.QUOTA_ERROR_EXCEEDED"},
+			},
+			wantFile:   true,
+			goldenFile: "testdata/golden/formatted_errors_message_error.errors.pb.go",
+		},
+		{
+			name:      "formatted_errors_concrete_return",
+			pbFile:    "testdata/pb/formatted_errors.pb",
+			protoName: "formatted_errors.proto",
+			config: &Config{
+				NewErrorsFunc:  testConfig.NewErrorsFunc,
+				DetailTypes:    map[string]string{"tests.formatted.QUOTA_ERROR_EXCEEDED": "tests.formatted.QuotaViolation"},
+				Metadata:       true,
+				Prebuilt:       true,
+				ConcreteReturn: true,
+			},
+			wantFile:   true,
+			goldenFile: "testdata/golden/formatted_errors_concrete_return.errors.pb.go",
+		},
 		{
 			name:      "no_errors",
 			pbFile:    "testdata/pb/no_errors.pb",
@@ -45,8 +890,20 @@ func TestGolden(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			config := tt.config
+			if config == nil {
+				config = testConfig
+			}
 			plugin := testutil.PluginFromPB(t, tt.pbFile, tt.protoName)
-			genFile, err := GenerateFile(plugin, testutil.FileToGenerate(t, plugin), testConfig)
+			// Resolve the options and assign the Go names on a copy, as run
+			// does per request.
+			assigned := *config
+			config = &assigned
+			if err := ResolveOptions(plugin.Files, config); err != nil {
+				t.Fatalf("ResolveOptions failed: %v", err)
+			}
+			AssignGoNames(plugin.Files, config)
+			genFile, err := GenerateFile(plugin, testutil.FileToGenerate(t, plugin), config)
 			if err != nil {
 				t.Fatalf("GenerateFile failed: %v", err)
 			}
@@ -99,3 +956,47 @@ func firstDiff(want, got string) string {
 	}
 	return "files differ only in trailing content"
 }
+
+// TestStableOrder guards the stable_order guarantee: regenerating the same
+// input yields byte-identical output, whatever the iteration order of the
+// configuration maps.
+func TestStableOrder(t *testing.T) {
+	config := &Config{
+		NewErrorsFunc:   testConfig.NewErrorsFunc,
+		GRPCStatus:      true,
+		SentinelErrors:  true,
+		Registry:        true,
+		ErrorCodes:      true,
+		Metrics:         MetricsPrometheus,
+		LogHelpers:      true,
+		RetryHelpers:    true,
+		OriginHelpers:   true,
+		ParseHelpers:    true,
+		Metadata:        true,
+		TraceContext:    true,
+		Origins:         map[string]string{"a.E": OriginClient, "b.E": OriginServer, "c.E": OriginUpstream},
+		Domains:         map[string]string{"": "example.com", "a": "a.example.com", "b": "b.example.com"},
+		Severities:      map[string]string{"a.E": SeverityWarn, "b.E": SeverityError, "c.E": SeverityCritical},
+		RetryableValues: map[string]bool{"a.V": true, "b.V": true, "c.V": true},
+		DefaultMessages: map[string]string{"a.E": "a", "b.E": "b", "c.E": "c"},
+		CodeOffsets:     map[string]int32{"a": 1, "b": 2, "c": 3},
+	}
+	for _, name := range []string{"basic_errors", "mixed_enums", "aliased_errors"} {
+		t.Run(name, func(t *testing.T) {
+			var first string
+			for i := 0; i < 20; i++ {
+				plugin := testutil.PluginFromPB(t, "testdata/pb/"+name+".pb", name+".proto")
+				genFile, err := GenerateFile(plugin, testutil.FileToGenerate(t, plugin), config)
+				if err != nil {
+					t.Fatalf("GenerateFile failed: %v", err)
+				}
+				content := mustContent(t, genFile)
+				if i == 0 {
+					first = content
+				} else if content != first {
+					t.Fatalf("run %d differs from run 0.\n%s", i, firstDiff(first, content))
+				}
+			}
+		})
+	}
+}
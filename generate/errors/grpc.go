@@ -0,0 +1,75 @@
+package errors
+
+import "google.golang.org/protobuf/compiler/protogen"
+
+// Import paths used by the generated GRPCStatus helpers.
+const (
+	grpcCodesPackage  = protogen.GoImportPath("google.golang.org/grpc/codes")
+	grpcStatusPackage = protogen.GoImportPath("google.golang.org/grpc/status")
+	errdetailsPackage = protogen.GoImportPath("google.golang.org/genproto/googleapis/rpc/errdetails")
+)
+
+// grpcCodeGoNames maps canonical google.rpc.Code names to the identifiers
+// declared in google.golang.org/grpc/codes.
+var grpcCodeGoNames = map[string]string{
+	"OK":                  "OK",
+	"CANCELLED":           "Canceled",
+	"UNKNOWN":             "Unknown",
+	"INVALID_ARGUMENT":    "InvalidArgument",
+	"DEADLINE_EXCEEDED":   "DeadlineExceeded",
+	"NOT_FOUND":           "NotFound",
+	"ALREADY_EXISTS":      "AlreadyExists",
+	"PERMISSION_DENIED":   "PermissionDenied",
+	"RESOURCE_EXHAUSTED":  "ResourceExhausted",
+	"FAILED_PRECONDITION": "FailedPrecondition",
+	"ABORTED":             "Aborted",
+	"OUT_OF_RANGE":        "OutOfRange",
+	"UNIMPLEMENTED":       "Unimplemented",
+	"INTERNAL":            "Internal",
+	"UNAVAILABLE":         "Unavailable",
+	"DATA_LOSS":           "DataLoss",
+	"UNAUTHENTICATED":     "Unauthenticated",
+}
+
+// grpcCodeFromHTTP derives the canonical gRPC code name for an HTTP status,
+// following the google.rpc.Code mapping. Statuses without a dedicated code fall
+// back by class: 4xx to FAILED_PRECONDITION, 5xx to INTERNAL, anything else to
+// UNKNOWN. Keep it in sync with grpcerrors.CodeFromHTTP.
+func grpcCodeFromHTTP(status int32) string {
+	switch status {
+	case 200:
+		return "OK"
+	case 400:
+		return "INVALID_ARGUMENT"
+	case 401:
+		return "UNAUTHENTICATED"
+	case 403:
+		return "PERMISSION_DENIED"
+	case 404:
+		return "NOT_FOUND"
+	case 408, 504:
+		return "DEADLINE_EXCEEDED"
+	case 409:
+		return "ALREADY_EXISTS"
+	case 412:
+		return "FAILED_PRECONDITION"
+	case 416:
+		return "OUT_OF_RANGE"
+	case 429:
+		return "RESOURCE_EXHAUSTED"
+	case 499:
+		return "CANCELLED"
+	case 501:
+		return "UNIMPLEMENTED"
+	case 502, 503:
+		return "UNAVAILABLE"
+	}
+	switch {
+	case status >= 400 && status < 500:
+		return "FAILED_PRECONDITION"
+	case status >= 500 && status < 600:
+		return "INTERNAL"
+	default:
+		return "UNKNOWN"
+	}
+}
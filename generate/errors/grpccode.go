@@ -0,0 +1,124 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// ParseGRPCCode parses a grpc_code parameter of the form
+// "proto.package.VALUE=CODE", the fully-qualified name of an enum value as
+// protobuf scopes it, without the enum name, where CODE is a canonical
+// google.rpc.Code name such as NOT_FOUND.
+func ParseGRPCCode(s string) (string, string, error) {
+	value, code, ok := strings.Cut(s, "=")
+	value, code = strings.TrimSpace(value), strings.ToUpper(strings.TrimSpace(code))
+	if !ok || value == "" || code == "" {
+		return "", "", fmt.Errorf("invalid grpc code %q, expected 'proto.package.VALUE=CODE'", s)
+	}
+	if _, known := grpcCodeGoNames[code]; !known {
+		return "", "", fmt.Errorf("invalid grpc code %q: unknown code %s", s, code)
+	}
+	return value, code, nil
+}
+
+// httpFromGRPCCode returns the canonical HTTP status of the gRPC code name
+// code, following the google.rpc.Code mapping.
+func httpFromGRPCCode(code string) int32 {
+	switch code {
+	case "OK":
+		return 200
+	case "CANCELLED":
+		return 499
+	case "INVALID_ARGUMENT", "FAILED_PRECONDITION", "OUT_OF_RANGE":
+		return 400
+	case "UNAUTHENTICATED":
+		return 401
+	case "PERMISSION_DENIED":
+		return 403
+	case "NOT_FOUND":
+		return 404
+	case "ALREADY_EXISTS", "ABORTED":
+		return 409
+	case "RESOURCE_EXHAUSTED":
+		return 429
+	case "UNIMPLEMENTED":
+		return 501
+	case "UNAVAILABLE":
+		return 503
+	case "DEADLINE_EXCEEDED":
+		return 504
+	default:
+		return 500
+	}
+}
+
+// grpcCode returns the gRPC code name declared for v, by the grpc_code field
+// of a custom OptionsType or else by GRPCCodes, and whether one is declared.
+func (c *Config) grpcCode(v *protogen.EnumValue) (string, bool) {
+	if c.custom != nil && c.custom.value != nil && c.custom.grpcCode != nil {
+		if ext, ok := c.custom.extension(v.Desc.Options(), c.custom.value); ok {
+			m, fd := ext.Message(), c.custom.grpcCode
+			if m.Has(fd) {
+				code := m.Get(fd).String()
+				if fd.Kind() == protoreflect.EnumKind {
+					code = ""
+					if ev := fd.Enum().Values().ByNumber(m.Get(fd).Enum()); ev != nil {
+						code = string(ev.Name())
+					}
+				}
+				if code != "" {
+					return code, true
+				}
+			}
+		}
+	}
+	code, ok := c.GRPCCodes[string(v.Desc.FullName())]
+	return code, ok
+}
+
+// ValidateGRPCCodes rejects GRPCCodes naming no error enum value of the
+// request, which would otherwise be ignored, and gRPC codes read from the
+// grpc_code field of a custom OptionsType that are no canonical
+// google.rpc.Code name. All problems are reported together in a single error.
+func ValidateGRPCCodes(files []*protogen.File, config *Config) error {
+	var problems []Diagnostic
+	if len(config.GRPCCodes) > 0 {
+		values := errorValues(files, config)
+		for name := range config.GRPCCodes {
+			if _, ok := values[name]; !ok {
+				problems = append(problems, diagnosticf("grpc_code %s is not an error enum value of this request, expected proto.package.VALUE", name))
+			}
+		}
+	}
+	if config.custom == nil || config.custom.grpcCode == nil {
+		return grpcCodeDiagnostics(problems)
+	}
+	for _, f := range files {
+		for _, enum := range f.Enums {
+			if _, ok := config.defaultStatus(enum); !ok || !config.selects(enum) {
+				continue
+			}
+			for _, v := range enum.Values {
+				if code, ok := config.grpcCode(v); ok {
+					if _, known := grpcCodeGoNames[code]; !known {
+						problems = append(problems, valueDiagnostic(v, "grpc code %q of %s.%s (%s), expected a google.rpc.Code name such as NOT_FOUND", code, enum.Desc.FullName(), v.Desc.Name(), sourceLocation(v)))
+					}
+				}
+			}
+		}
+	}
+	return grpcCodeDiagnostics(problems)
+}
+
+// grpcCodeDiagnostics returns the failure reporting problems, or nil when
+// there are none.
+func grpcCodeDiagnostics(problems []Diagnostic) error {
+	if len(problems) > 0 {
+		sortDiagnostics(problems)
+		return &Diagnostics{Summary: "invalid gRPC codes", Problems: problems}
+	}
+	return nil
+}
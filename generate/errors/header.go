@@ -2,27 +2,72 @@ package errors
 
 import (
 	"fmt"
+	"go/build/constraint"
+	"strings"
 
 	"google.golang.org/protobuf/compiler/protogen"
 	"google.golang.org/protobuf/types/pluginpb"
 )
 
-// generateFileHeader writes the "DO NOT EDIT" banner, version comments and the
-// package clause for the generated file.
-func generateFileHeader(gen *protogen.Plugin, file *protogen.File, g *protogen.GeneratedFile) {
-	g.P("// Code generated by protoc-gen-sphere-errors. DO NOT EDIT.")
+// generateFileHeader writes the "DO NOT EDIT" banner naming the GeneratedBy
+// of config, version comments, an optional //go:build constraint, combining
+// buildConstraint with the BuildTag of config, and the package clause for the
+// generated file of files, one source line per file.
+func generateFileHeader(gen *protogen.Plugin, files []*protogen.File, g *protogen.GeneratedFile, pkg protogen.GoPackageName, config *Config, buildConstraint string) {
+	g.P("// Code generated by ", config.generatedBy(), ". DO NOT EDIT.")
 	g.P("// versions:")
 	g.P("// - protoc             ", protocVersion(gen))
-	if file.Proto.GetOptions().GetDeprecated() {
-		g.P("// ", file.Desc.Path(), " is a deprecated file.")
-	} else {
-		g.P("// source: ", file.Desc.Path())
+	for _, file := range files {
+		if file.Proto.GetOptions().GetDeprecated() {
+			g.P("// ", file.Desc.Path(), " is a deprecated file.")
+		} else {
+			g.P("// source: ", file.Desc.Path())
+		}
 	}
 	g.P()
-	g.P("package ", file.GoPackageName)
+	switch {
+	case config.BuildTag != "" && buildConstraint != "":
+		buildConstraint = "(" + config.BuildTag + ") && " + buildConstraint
+	case config.BuildTag != "":
+		buildConstraint = config.BuildTag
+	}
+	if buildConstraint != "" {
+		g.P("//go:build ", buildConstraint)
+		g.P()
+	}
+	g.P("package ", pkg)
 	g.P()
 }
 
+// generatedBy returns the generator named in the "Code generated by" header.
+func (c *Config) generatedBy() string {
+	if c.GeneratedBy == "" {
+		return "protoc-gen-sphere-errors"
+	}
+	return c.GeneratedBy
+}
+
+// ValidateBuildTag reports whether tag is a valid //go:build expression.
+func ValidateBuildTag(tag string) error {
+	if tag == "" {
+		return nil
+	}
+	if _, err := constraint.Parse("//go:build " + tag); err != nil {
+		return fmt.Errorf("invalid build_tag %q: %w", tag, err)
+	}
+	return nil
+}
+
+// ValidateGeneratedBy reports whether name can be written in the "Code
+// generated by" header, which must stay on one line for tools to recognize
+// generated files.
+func ValidateGeneratedBy(name string) error {
+	if strings.ContainsAny(name, "\r\n") {
+		return fmt.Errorf("invalid generated_by %q, must be a single line", name)
+	}
+	return nil
+}
+
 // protocVersion returns the formatted compiler version from the generator request.
 func protocVersion(gen *protogen.Plugin) string {
 	return formatProtocVersion(gen.Request.GetCompilerVersion())
@@ -0,0 +1,63 @@
+package errors
+
+import (
+	"fmt"
+	"net/textproto"
+	"sort"
+	"strings"
+
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/internal/template"
+)
+
+// ParseHTTPHeader parses an http_header parameter of the form
+// 'name=Header-Name:value', name being a fully-qualified enum or enum value
+// whose HTTP error responses carry the header. The value may hold {key}
+// placeholders filled from the metadata of the error by httperrors. The
+// header name is returned in canonical form.
+func ParseHTTPHeader(s string) (string, string, string, error) {
+	name, header, ok := strings.Cut(s, "=")
+	if !ok {
+		return "", "", "", fmt.Errorf("invalid http header %q, expected 'name=Header-Name:value'", s)
+	}
+	header, value, ok := strings.Cut(header, ":")
+	name, header, value = strings.TrimSpace(name), strings.TrimSpace(header), strings.TrimSpace(value)
+	if !ok || name == "" || !validHeaderName(header) || value == "" {
+		return "", "", "", fmt.Errorf("invalid http header %q, expected 'name=Header-Name:value'", s)
+	}
+	if strings.ContainsFunc(value, func(r rune) bool { return r < ' ' && r != '\t' || r == 0x7f }) {
+		return "", "", "", fmt.Errorf("invalid http header %q, the value holds control characters", s)
+	}
+	return name, textproto.CanonicalMIMEHeaderKey(header), value, nil
+}
+
+// validHeaderName reports whether name is an RFC 9110 field name token.
+func validHeaderName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if r > 0x7f || !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("!#$%&'*+-.^_`|~", r)) {
+			return false
+		}
+	}
+	return true
+}
+
+// httpHeaders returns the headers declared for HTTP responses carrying the
+// enum value value of enum, by name: those of its enum, overridden header by
+// header by those of the value.
+func (c *Config) httpHeaders(enum, value string) []template.HTTPHeader {
+	merged := map[string]string{}
+	for name, v := range c.HTTPHeaders[enum] {
+		merged[name] = v
+	}
+	for name, v := range c.HTTPHeaders[value] {
+		merged[name] = v
+	}
+	headers := make([]template.HTTPHeader, 0, len(merged))
+	for name, v := range merged {
+		headers = append(headers, template.HTTPHeader{Name: name, Value: v})
+	}
+	sort.Slice(headers, func(i, j int) bool { return headers[i].Name < headers[j].Name })
+	return headers
+}
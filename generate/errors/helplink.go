@@ -0,0 +1,44 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/internal/template"
+)
+
+// ParseDocURL parses a doc_url parameter of the form
+// "proto.package.VALUE=URL". The URL may itself contain '='.
+func ParseDocURL(s string) (string, string, error) {
+	value, url, ok := strings.Cut(s, "=")
+	value, url = strings.TrimSpace(value), strings.TrimSpace(url)
+	if !ok || value == "" || url == "" {
+		return "", "", fmt.Errorf("invalid doc url %q, expected 'proto.package.VALUE=URL'", s)
+	}
+	return value, url, nil
+}
+
+// ValidateDocURLBase reports whether t is a valid DocURLBase template: its
+// only placeholders are {package}, {enum}, {value} and {code}.
+func ValidateDocURLBase(t string) error {
+	return validateURITemplate("doc_url_base", t)
+}
+
+// helpLinks reports whether the error enums get a HelpLink method.
+func (c *Config) helpLinks() bool {
+	return c.DocURLBase != "" || len(c.DocURLs) > 0
+}
+
+// helpLink returns the documentation URL of info, the value named value of
+// the enum enum declared in proto package pkg: its DocURLs entry, or else
+// DocURLBase expanded for it. Zero values, which are never errors, only have
+// a DocURLs entry.
+func (c *Config) helpLink(info *template.ErrorInfo, pkg, enum, value string) string {
+	if url, ok := c.DocURLs[value]; ok {
+		return url
+	}
+	if c.DocURLBase == "" || info.Number == 0 {
+		return ""
+	}
+	return expandURITemplate(c.DocURLBase, info, pkg, enum)
+}
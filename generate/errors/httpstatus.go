@@ -0,0 +1,119 @@
+package errors
+
+import (
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/internal/template"
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// httpPackage declares the status constants the generated code refers to.
+const httpPackage = protogen.GoImportPath("net/http")
+
+// httpStatusGoNames maps HTTP statuses to the constants declared in net/http.
+var httpStatusGoNames = map[int32]string{
+	100: "StatusContinue",
+	101: "StatusSwitchingProtocols",
+	102: "StatusProcessing",
+	103: "StatusEarlyHints",
+	200: "StatusOK",
+	201: "StatusCreated",
+	202: "StatusAccepted",
+	203: "StatusNonAuthoritativeInfo",
+	204: "StatusNoContent",
+	205: "StatusResetContent",
+	206: "StatusPartialContent",
+	207: "StatusMultiStatus",
+	208: "StatusAlreadyReported",
+	226: "StatusIMUsed",
+	300: "StatusMultipleChoices",
+	301: "StatusMovedPermanently",
+	302: "StatusFound",
+	303: "StatusSeeOther",
+	304: "StatusNotModified",
+	305: "StatusUseProxy",
+	307: "StatusTemporaryRedirect",
+	308: "StatusPermanentRedirect",
+	400: "StatusBadRequest",
+	401: "StatusUnauthorized",
+	402: "StatusPaymentRequired",
+	403: "StatusForbidden",
+	404: "StatusNotFound",
+	405: "StatusMethodNotAllowed",
+	406: "StatusNotAcceptable",
+	407: "StatusProxyAuthRequired",
+	408: "StatusRequestTimeout",
+	409: "StatusConflict",
+	410: "StatusGone",
+	411: "StatusLengthRequired",
+	412: "StatusPreconditionFailed",
+	413: "StatusRequestEntityTooLarge",
+	414: "StatusRequestURITooLong",
+	415: "StatusUnsupportedMediaType",
+	416: "StatusRequestedRangeNotSatisfiable",
+	417: "StatusExpectationFailed",
+	418: "StatusTeapot",
+	421: "StatusMisdirectedRequest",
+	422: "StatusUnprocessableEntity",
+	423: "StatusLocked",
+	424: "StatusFailedDependency",
+	425: "StatusTooEarly",
+	426: "StatusUpgradeRequired",
+	428: "StatusPreconditionRequired",
+	429: "StatusTooManyRequests",
+	431: "StatusRequestHeaderFieldsTooLarge",
+	451: "StatusUnavailableForLegalReasons",
+	500: "StatusInternalServerError",
+	501: "StatusNotImplemented",
+	502: "StatusBadGateway",
+	503: "StatusServiceUnavailable",
+	504: "StatusGatewayTimeout",
+	505: "StatusHTTPVersionNotSupported",
+	506: "StatusVariantAlsoNegotiates",
+	507: "StatusInsufficientStorage",
+	508: "StatusLoopDetected",
+	510: "StatusNotExtended",
+	511: "StatusNetworkAuthenticationRequired",
+}
+
+// validHTTPStatus reports whether status is a three-digit HTTP status, 100 to
+// 599.
+func validHTTPStatus(status int32) bool {
+	return status >= 100 && status <= 599
+}
+
+// qualifyStatuses fills in the net/http constants of the statuses of ew and
+// its aliases. Statuses net/http has no constant for stay literals.
+func qualifyStatuses(ew *template.ErrorWrapper, g *protogen.GeneratedFile) {
+	ew.DefaultStatusIdent = g.QualifiedGoIdent(httpPackage.Ident(httpStatusGoNames[500]))
+	for _, info := range append(ew.Errors[:len(ew.Errors):len(ew.Errors)], ew.Aliases...) {
+		if name, ok := httpStatusGoNames[info.Status]; ok {
+			info.StatusIdent = g.QualifiedGoIdent(httpPackage.Ident(name))
+		}
+	}
+}
+
+// ValidateStatuses rejects error enums of files whose default_status, or a
+// value whose status, is not an HTTP status from 100 to 599, such as 4040.
+// All offending enums and values are reported together in a single error.
+func ValidateStatuses(files []*protogen.File, config *Config) error {
+	var problems []Diagnostic
+	for _, f := range files {
+		for _, enum := range f.Enums {
+			defaultStatus, ok := config.defaultStatus(enum)
+			if !ok || !config.selects(enum) {
+				continue
+			}
+			if !validHTTPStatus(defaultStatus) {
+				problems = append(problems, enumDiagnostic(enum, "default_status %d of %s (%s)", defaultStatus, enum.Desc.FullName(), enumLocation(enum)))
+			}
+			for _, v := range enum.Values {
+				if status := config.valueOptions(v).GetStatus(); status != 0 && !validHTTPStatus(status) {
+					problems = append(problems, valueDiagnostic(v, "status %d of %s.%s (%s)", status, enum.Desc.FullName(), v.Desc.Name(), sourceLocation(v)))
+				}
+			}
+		}
+	}
+	if len(problems) > 0 {
+		return &Diagnostics{Summary: "invalid HTTP statuses, expected 100 to 599", Problems: problems}
+	}
+	return nil
+}
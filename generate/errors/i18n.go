@@ -0,0 +1,103 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// ParseLocalizedMessage parses a message_i18n parameter of the form
+// "proto.package.VALUE:lang=message", the message of an enum value in the
+// language tagged lang, e.g. "shop.v1.CART_ERROR_EMPTY:zh=购物车是空的".
+func ParseLocalizedMessage(s string) (string, string, string, error) {
+	value, rest, ok := strings.Cut(s, ":")
+	lang, msg, ok2 := strings.Cut(rest, "=")
+	value, lang = strings.TrimSpace(value), strings.TrimSpace(lang)
+	if !ok || !ok2 || value == "" || !validLanguageTag(lang) || msg == "" {
+		return "", "", "", fmt.Errorf("invalid message_i18n %q, expected 'proto.package.VALUE:lang=message'", s)
+	}
+	return value, lang, msg, nil
+}
+
+// validLanguageTag reports whether tag can key a message bundle, as a
+// language tag such as "zh-HK": non-empty, without spaces or separators of
+// the Accept-Language header.
+func validLanguageTag(tag string) bool {
+	return tag != "" && !strings.ContainsAny(tag, " ;=,:")
+}
+
+// AddLocalizedMessage adds the message msg of the enum value named value in
+// the language lang to c.LocalizedMessages.
+func (c *Config) AddLocalizedMessage(value, lang, msg string) {
+	if c.LocalizedMessages == nil {
+		c.LocalizedMessages = map[string]map[string]string{}
+	}
+	if c.LocalizedMessages[value] == nil {
+		c.LocalizedMessages[value] = map[string]string{}
+	}
+	c.LocalizedMessages[value][lang] = msg
+}
+
+// localizedMessages returns the messages of v keyed by language tag: those
+// of the message_i18n map field of a custom OptionsType, which win, and
+// those of LocalizedMessages. It returns nil when v has none.
+func (c *Config) localizedMessages(v *protogen.EnumValue) map[string]string {
+	var messages map[string]string
+	for lang, msg := range c.LocalizedMessages[string(v.Desc.FullName())] {
+		if messages == nil {
+			messages = map[string]string{}
+		}
+		messages[lang] = msg
+	}
+	if c.custom != nil && c.custom.value != nil && c.custom.messageI18n != nil {
+		if ext, ok := c.custom.extension(v.Desc.Options(), c.custom.value); ok {
+			ext.Message().Get(c.custom.messageI18n).Map().Range(func(k protoreflect.MapKey, val protoreflect.Value) bool {
+				if messages == nil {
+					messages = map[string]string{}
+				}
+				messages[k.String()] = val.String()
+				return true
+			})
+		}
+	}
+	return messages
+}
+
+// ValidateLocalizedMessages rejects LocalizedMessages naming no error enum
+// value of the request, and language tags of the message_i18n field of a
+// custom OptionsType that cannot key a message bundle. All problems are
+// reported together in a single error.
+func ValidateLocalizedMessages(files []*protogen.File, config *Config) error {
+	var problems []Diagnostic
+	if len(config.LocalizedMessages) > 0 {
+		values := errorValues(files, config)
+		for name := range config.LocalizedMessages {
+			if _, ok := values[name]; !ok {
+				problems = append(problems, diagnosticf("message_i18n %s is not an error enum value of this request", name))
+			}
+		}
+	}
+	if config.custom != nil && config.custom.messageI18n != nil {
+		for _, f := range files {
+			for _, enum := range f.Enums {
+				if _, ok := config.defaultStatus(enum); !ok || !config.selects(enum) {
+					continue
+				}
+				for _, v := range enum.Values {
+					for lang := range config.localizedMessages(v) {
+						if !validLanguageTag(lang) {
+							problems = append(problems, valueDiagnostic(v, "language tag %q of %s.%s (%s)", lang, enum.Desc.FullName(), v.Desc.Name(), sourceLocation(v)))
+						}
+					}
+				}
+			}
+		}
+	}
+	if len(problems) > 0 {
+		sortDiagnostics(problems)
+		return &Diagnostics{Summary: "invalid localized messages", Problems: problems}
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package errors
+
+import "google.golang.org/protobuf/compiler/protogen"
+
+// multierrorPackage is the runtime package the generated JoinErrors calls.
+const multierrorPackage = protogen.GoImportPath("github.com/go-sphere/protoc-gen-sphere-errors/multierror")
+
+// generateJoinErrors writes the package-level MultiError type and the
+// JoinErrors function aggregating errors into one.
+func generateJoinErrors(g *protogen.GeneratedFile) {
+	g.P("// MultiError is the aggregate error returned by JoinErrors. errors.Is and")
+	g.P("// errors.As match any of its members, and its Codes method returns the code")
+	g.P("// of each.")
+	g.P("type MultiError = ", g.QualifiedGoIdent(multierrorPackage.Ident("Error")))
+	g.P()
+	g.P("// JoinErrors aggregates the non-nil errs, such as the per-item failures of a")
+	g.P("// batch request, into a *MultiError keeping each of them whole, or returns")
+	g.P("// nil when every err is nil. httperrors renders it as one body listing the")
+	g.P("// body of every member.")
+	g.P("func JoinErrors(errs ...error) error {")
+	g.P("return ", g.QualifiedGoIdent(multierrorPackage.Ident("Join")), "(errs...)")
+	g.P("}")
+	g.P()
+}
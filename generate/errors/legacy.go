@@ -0,0 +1,100 @@
+package errors
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/internal/template"
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+const regexpPackage = protogen.GoImportPath("regexp")
+
+// ParseLegacyPattern parses a legacy_pattern parameter of the form
+// "proto.package.VALUE=REGEX". The regular expression may itself contain
+// '='.
+func ParseLegacyPattern(s string) (string, string, error) {
+	value, pattern, ok := strings.Cut(s, "=")
+	value, pattern = strings.TrimSpace(value), strings.TrimSpace(pattern)
+	if !ok || value == "" || pattern == "" {
+		return "", "", fmt.Errorf("invalid legacy pattern %q, expected 'proto.package.VALUE=REGEX'", s)
+	}
+	if _, err := regexp.Compile(pattern); err != nil {
+		return "", "", fmt.Errorf("invalid legacy pattern %q: %v", s, err)
+	}
+	return value, pattern, nil
+}
+
+// legacyPatternOption returns the legacy pattern declared for v by the
+// legacy_pattern field of a custom OptionsType, or "" when it declares none.
+func (c *Config) legacyPatternOption(v *protogen.EnumValue) string {
+	if c.custom == nil || c.custom.value == nil || c.custom.legacyPattern == nil {
+		return ""
+	}
+	ext, ok := c.custom.extension(v.Desc.Options(), c.custom.value)
+	if !ok || !ext.Message().Has(c.custom.legacyPattern) {
+		return ""
+	}
+	return ext.Message().Get(c.custom.legacyPattern).String()
+}
+
+// legacyPattern returns the legacy pattern of v: the legacy_pattern field of
+// a custom OptionsType, which wins, or else its LegacyPatterns entry. Zero
+// values, which are never errors, have none.
+func (c *Config) legacyPattern(v *protogen.EnumValue) string {
+	if v.Desc.Number() == 0 {
+		return ""
+	}
+	if option := c.legacyPatternOption(v); option != "" {
+		return option
+	}
+	return c.LegacyPatterns[string(v.Desc.FullName())]
+}
+
+// qualifyLegacy sets up the ClassifyLegacy function of ew when any of its
+// values has a legacy pattern.
+func qualifyLegacy(ew *template.ErrorWrapper, g *protogen.GeneratedFile) {
+	for _, info := range ew.Errors {
+		if info.LegacyPattern != "" {
+			ew.Legacy = &template.LegacyIdents{
+				MustCompile: g.QualifiedGoIdent(regexpPackage.Ident("MustCompile")),
+				Regexp:      g.QualifiedGoIdent(regexpPackage.Ident("Regexp")),
+			}
+			return
+		}
+	}
+}
+
+// ValidateLegacyPatterns rejects legacy patterns that are not valid regular
+// expressions, read from the legacy_pattern field of a custom OptionsType,
+// and LegacyPatterns naming zero values. All offending values are reported
+// together in a single error.
+func ValidateLegacyPatterns(files []*protogen.File, config *Config) error {
+	var problems []Diagnostic
+	for _, f := range files {
+		for _, enum := range f.Enums {
+			if _, ok := config.defaultStatus(enum); !ok || !config.selects(enum) {
+				continue
+			}
+			for _, v := range enum.Values {
+				if v.Desc.Number() == 0 {
+					if _, ok := config.LegacyPatterns[string(v.Desc.FullName())]; ok || config.legacyPatternOption(v) != "" {
+						problems = append(problems, valueDiagnostic(v, "%s (%s) is a zero value, which is never an error", v.Desc.FullName(), sourceLocation(v)))
+					}
+					continue
+				}
+				if option := config.legacyPatternOption(v); option != "" {
+					if _, err := regexp.Compile(option); err != nil {
+						problems = append(problems, valueDiagnostic(v, "legacy pattern %q of %s (%s): %v", option, v.Desc.FullName(), sourceLocation(v), err))
+					}
+				}
+			}
+		}
+	}
+	if len(problems) > 0 {
+		sortDiagnostics(problems)
+		return &Diagnostics{Summary: "invalid legacy patterns", Problems: problems}
+	}
+	return nil
+}
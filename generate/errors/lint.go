@@ -0,0 +1,97 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// Lint checks the error enums of files for common problems and returns one
+// finding per problem, as "file:line: text", in declaration order:
+//
+//   - an enum without a zero value;
+//   - a zero value declaring a status, although it is never an error;
+//   - a message ending with a period or not starting with an upper-case
+//     letter;
+//   - a code of five or more digits whose leading three digits are a 4xx or
+//     5xx HTTP status other than the status of the value, as in 40401 with
+//     status 500.
+//
+// The line is omitted when the request carries no source info.
+func Lint(files []*protogen.File, config *Config) []string {
+	var findings []string
+	for _, f := range files {
+		for _, enum := range f.Enums {
+			ew := buildErrorWrapper(enum, config, "", "")
+			if ew == nil {
+				continue
+			}
+			if enum.Desc.Values().ByNumber(0) == nil {
+				findings = append(findings, fmt.Sprintf("%s: %s has no zero value", enumLocation(enum), ew.FullName))
+			}
+			for _, v := range enum.Values {
+				if status := config.valueOptions(v).GetStatus(); v.Desc.Number() == 0 && status != 0 {
+					findings = append(findings, fmt.Sprintf("%s: zero value %s declares status %d, but is never an error", sourceLocation(v), v.Desc.FullName(), status))
+				}
+			}
+			for _, info := range ew.Errors {
+				name := ew.FullName + "." + info.Value
+				if problem := lintMessage(info.Message); problem != "" {
+					findings = append(findings, fmt.Sprintf("%s: message of %s %s", info.Source, name, problem))
+				}
+				if info.Number == 0 {
+					continue
+				}
+				if status := codeStatus(info.Code); status != 0 && status != info.Status {
+					findings = append(findings, fmt.Sprintf("%s: code %d of %s suggests status %d, but its status is %d", info.Source, info.Code, name, status, info.Status))
+				}
+			}
+		}
+	}
+	return findings
+}
+
+// lintMessage returns what is wrong with the style of message, or "".
+func lintMessage(message string) string {
+	if message == "" {
+		return ""
+	}
+	first, _ := utf8.DecodeRuneInString(message)
+	switch {
+	case strings.HasSuffix(message, "."):
+		return fmt.Sprintf("%q ends with a period", message)
+	case unicode.IsLetter(first) && !unicode.IsUpper(first):
+		return fmt.Sprintf("%q does not start with an upper-case letter", message)
+	}
+	return ""
+}
+
+// codeStatus returns the 4xx or 5xx HTTP status formed by the leading three
+// digits of code, or 0 when code has fewer than five digits or its leading
+// digits are no such status.
+func codeStatus(code int32) int32 {
+	if code < 10000 {
+		return 0
+	}
+	for code >= 1000 {
+		code /= 10
+	}
+	if code < 400 || code > 599 {
+		return 0
+	}
+	return code
+}
+
+// enumLocation returns the proto file and 1-based line declaring enum, or just
+// the file when the request carries no source info.
+func enumLocation(enum *protogen.Enum) string {
+	file := enum.Desc.ParentFile()
+	loc := file.SourceLocations().ByDescriptor(enum.Desc)
+	if loc.Path == nil {
+		return file.Path()
+	}
+	return fmt.Sprintf("%s:%d", file.Path(), loc.StartLine+1)
+}
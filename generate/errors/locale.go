@@ -0,0 +1,110 @@
+package errors
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// localePackage is the runtime expanding the languages of ResolveMessage.
+const localePackage = protogen.GoImportPath("github.com/go-sphere/protoc-gen-sphere-errors/locale")
+
+// ParseLocaleFallback parses a locale_fallback parameter, a chain of language
+// tags separated by '>' such as "zh-HK>zh>en", into the tags of the chain.
+func ParseLocaleFallback(s string) ([]string, error) {
+	tags := strings.Split(s, ">")
+	for i, tag := range tags {
+		tags[i] = strings.TrimSpace(tag)
+		if tags[i] == "" || strings.ContainsAny(tags[i], " ;=") {
+			return nil, fmt.Errorf("invalid locale fallback %q, expected a chain of language tags such as 'zh-HK>zh>en'", s)
+		}
+	}
+	if len(tags) < 2 {
+		return nil, fmt.Errorf("invalid locale fallback %q, expected at least two language tags such as 'zh-HK>zh'", s)
+	}
+	return tags, nil
+}
+
+// AddLocaleFallback adds the chain tags to c.LocaleFallbacks: each tag falls
+// back to the next one, after the fallbacks added for it before.
+func (c *Config) AddLocaleFallback(tags []string) {
+	if c.LocaleFallbacks == nil {
+		c.LocaleFallbacks = map[string][]string{}
+	}
+	for i, tag := range tags[:len(tags)-1] {
+		if !slices.Contains(c.LocaleFallbacks[tag], tags[i+1]) {
+			c.LocaleFallbacks[tag] = append(c.LocaleFallbacks[tag], tags[i+1])
+		}
+	}
+}
+
+// generateLocalizedMessages writes the package-level SetTranslator hook and
+// ResolveMessage, looking up the translation of a message along the
+// LocaleFallbacks of config. It reads the function written by
+// generateErrorByCode.
+func generateLocalizedMessages(g *protogen.GeneratedFile, config *Config) {
+	pointer := g.QualifiedGoIdent(atomicPackage.Ident("Pointer"))
+	fallbacks := g.QualifiedGoIdent(localePackage.Ident("Fallbacks"))
+	tags := slices.Sorted(maps.Keys(config.LocaleFallbacks))
+	g.P("// localeFallbacks are the language fallbacks of ResolveMessage.")
+	g.P("var localeFallbacks = ", fallbacks, "{")
+	for _, tag := range tags {
+		var next []string
+		for _, n := range config.LocaleFallbacks[tag] {
+			next = append(next, strconv.Quote(n))
+		}
+		g.P(strconv.Quote(tag), ": {", strings.Join(next, ", "), "},")
+	}
+	g.P("}")
+	g.P()
+	g.P("// translator is the translator installed by SetTranslator.")
+	g.P("var translator ", pointer, "[func(code int32, lang string) (string, bool)]")
+	g.P()
+	g.P("// SetTranslator installs translate, which ResolveMessage calls with an error")
+	g.P("// code and a language tag for the message of the code in that language,")
+	g.P("// reporting whether it has one. Translations usually come from message")
+	g.P("// bundles or a CMS. A nil translator leaves the declared messages. It is")
+	g.P("// safe for concurrent use.")
+	g.P("func SetTranslator(translate func(code int32, lang string) (string, bool)) {")
+	g.P("if translate == nil {")
+	g.P("translator.Store(nil)")
+	g.P("return")
+	g.P("}")
+	g.P("translator.Store(&translate)")
+	g.P("}")
+	g.P()
+	g.P("// ResolveMessage returns the message of the error value of this package whose")
+	g.P("// code is code in the first of langs, most preferred first, or of their")
+	g.P("// fallbacks, the translator or else the message bundle of its enum has a")
+	g.P("// message for. Without one it returns the message of the value, and \"\"")
+	g.P("// for unknown codes. Its signature suits httperrors.Encoder.Localize, which")
+	g.P("// passes the Accept-Language of the request.")
+	g.P("func ResolveMessage(code int32, langs []string) string {")
+	g.P("e, ok := sphereErrorByCode(code)")
+	g.P("if !ok {")
+	g.P("return \"\"")
+	g.P("}")
+	g.P("translate := translator.Load()")
+	g.P("bundle, _ := e.(interface {")
+	g.P("localizedMessage(lang string) (string, bool)")
+	g.P("})")
+	g.P("for _, lang := range localeFallbacks.Expand(langs) {")
+	g.P("if translate != nil {")
+	g.P("if msg, ok := (*translate)(code, lang); ok {")
+	g.P("return msg")
+	g.P("}")
+	g.P("}")
+	g.P("if bundle != nil {")
+	g.P("if msg, ok := bundle.localizedMessage(lang); ok {")
+	g.P("return msg")
+	g.P("}")
+	g.P("}")
+	g.P("}")
+	g.P("return e.(interface{ GetMessage() string }).GetMessage()")
+	g.P("}")
+	g.P()
+}
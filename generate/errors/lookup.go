@@ -0,0 +1,52 @@
+package errors
+
+import (
+	"path"
+	"slices"
+
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// errlookupPackage is the runtime package implementing the generated lookup
+// command.
+const errlookupPackage = protogen.GoImportPath("github.com/go-sphere/protoc-gen-sphere-errors/errlookup")
+
+const osPackage = protogen.GoImportPath("os")
+
+// GenerateLookupCommand writes dir/main.go, an errlookup command importing the
+// Go package of every generated file with error enums, so that their registry
+// init functions run before errlookup.Run resolves the codes passed on the
+// command line. It requires config.Registry. Nothing is written when no file
+// being generated declares error enums.
+func GenerateLookupCommand(gen *protogen.Plugin, config *Config, dir string) *protogen.GeneratedFile {
+	var imports []protogen.GoImportPath
+	for _, f := range gen.Files {
+		if !f.Generate || !hasErrorEnums(f.Enums, config) {
+			continue
+		}
+		if importPath := config.outputFor(f).importPath; !slices.Contains(imports, importPath) {
+			imports = append(imports, importPath)
+		}
+	}
+	if len(imports) == 0 {
+		return nil
+	}
+	slices.Sort(imports)
+	g := gen.NewGeneratedFile(config.outputPath(path.Join(dir, "main.go")), protogen.GoImportPath(config.outputPath(dir)))
+	g.P("// Code generated by ", config.generatedBy(), ". DO NOT EDIT.")
+	g.P("// versions:")
+	g.P("// - protoc             ", protocVersion(gen))
+	g.P()
+	g.P("// Command errlookup prints the name, HTTP status, reason, message and proto")
+	g.P("// source of the error codes given as arguments, or of every error without")
+	g.P("// arguments.")
+	g.P("package main")
+	g.P()
+	for _, importPath := range imports {
+		g.Import(importPath)
+	}
+	g.P("func main() {")
+	g.P(osPackage.Ident("Exit"), "(", errlookupPackage.Ident("Run"), "(", osPackage.Ident("Args"), "[1:], ", osPackage.Ident("Stdout"), "))")
+	g.P("}")
+	return g
+}
@@ -0,0 +1,87 @@
+package errors
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// ValidateMergedPackages checks, when config.MergePackageErrors is set, that
+// the error enums of each proto package form one set: the files being
+// generated with error enums of a proto package share a Go package, and no
+// two non-zero values of the package share a code, across every file of the
+// request declaring it. All problems are reported together in a single
+// error.
+func ValidateMergedPackages(files []*protogen.File, config *Config) error {
+	if !config.MergePackageErrors {
+		return nil
+	}
+	var problems []Diagnostic
+	goPackages := map[string]string{}
+	codes := map[string]map[int32]string{}
+	for _, f := range files {
+		pkg := string(f.Desc.Package())
+		enums := ErrorEnums(f, config)
+		if len(enums) == 0 {
+			continue
+		}
+		if f.Generate {
+			importPath := string(config.outputFor(f).importPath)
+			if prev, ok := goPackages[pkg]; !ok {
+				goPackages[pkg] = importPath
+			} else if prev != importPath {
+				problems = append(problems, fileDiagnostic(f, "proto package %s is generated into both Go packages %s and %s (%s); give its files one go_package or output_package", pkg, prev, importPath, f.Desc.Path()))
+			}
+		}
+		if codes[pkg] == nil {
+			codes[pkg] = map[int32]string{}
+		}
+		for _, ew := range enums {
+			for _, info := range ew.Errors {
+				if info.Number == 0 {
+					continue
+				}
+				where := fmt.Sprintf("%s.%s (%s)", ew.FullName, info.Value, info.Source)
+				if prev, ok := codes[pkg][info.Code]; ok {
+					problems = append(problems, infoDiagnostic(ew, info, "duplicate error code %d in proto package %s: %s and %s", info.Code, pkg, prev, where))
+					continue
+				}
+				codes[pkg][info.Code] = where
+			}
+		}
+	}
+	if len(problems) > 0 {
+		sortDiagnostics(problems)
+		return &Diagnostics{Summary: "merge_package_errors", Problems: problems}
+	}
+	return nil
+}
+
+// generateMergedErrors writes the package-level ErrorByCode and Errors,
+// looking up and listing the error values of every proto file generated into
+// the Go package of file as one set. ErrorByCode reads the function written by
+// generateErrorByCode.
+func generateMergedErrors(gen *protogen.Plugin, file *protogen.File, g *protogen.GeneratedFile, config *Config) {
+	g.P("// ErrorByCode returns the error value of this package whose code is code,")
+	g.P("// whichever proto file declares it.")
+	g.P("func ErrorByCode(code int32) (error, bool) {")
+	g.P("return sphereErrorByCode(code)")
+	g.P("}")
+	g.P()
+	g.P("// Errors returns every non-zero error value of this package, in declaration")
+	g.P("// order across its proto files.")
+	g.P("func Errors() []error {")
+	g.P("return []error{")
+	for _, f := range packageFiles(gen, file, config) {
+		for _, ew := range ErrorEnums(f, config) {
+			for _, info := range ew.Errors {
+				if info.Number != 0 {
+					g.P(ew.Name, "_", info.Value, ",")
+				}
+			}
+		}
+	}
+	g.P("}")
+	g.P("}")
+	g.P()
+}
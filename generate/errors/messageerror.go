@@ -0,0 +1,114 @@
+package errors
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// ParseMessageError parses a message_error parameter of the form
+// "proto.package.Message=proto.package.VALUE", the message carried by the
+// typed errors of an error value.
+func ParseMessageError(s string) (string, string, error) {
+	message, value, ok := strings.Cut(s, "=")
+	message, value = strings.TrimSpace(message), strings.TrimSpace(value)
+	if !ok || message == "" || value == "" {
+		return "", "", fmt.Errorf("invalid message error %q, expected 'proto.package.Message=proto.package.VALUE'", s)
+	}
+	return message, value, nil
+}
+
+// ValidateMessageErrors rejects MessageErrors naming a message that is not
+// declared in files or a value that is not an error enum value of files, and
+// messages whose typed errors would share a Go name in one package.
+func ValidateMessageErrors(files []*protogen.File, config *Config) error {
+	if len(config.MessageErrors) == 0 {
+		return nil
+	}
+	values := errorValues(files, config)
+	var problems []Diagnostic
+	names := map[protogen.GoIdent]string{}
+	for message, value := range config.MessageErrors {
+		m := findMessage(files, protoreflect.FullName(message))
+		if m == nil {
+			problems = append(problems, diagnosticf("%s (message error of %s) is not a message of this request; import the file declaring it", message, value))
+		}
+		ident, ok := values[value]
+		if !ok {
+			problems = append(problems, diagnosticf("%s (message error of %s) is not an error enum value of this request", value, message))
+		}
+		if m == nil || !ok {
+			continue
+		}
+		typ := ident.GoImportPath.Ident(messageErrorName(m))
+		if other, ok := names[typ]; ok {
+			a, b := min(message, other), max(message, other)
+			problems = append(problems, diagnosticf("message errors of %s and %s are both named %s in %s", a, b, typ.GoName, typ.GoImportPath))
+		}
+		names[typ] = message
+	}
+	if len(problems) > 0 {
+		sortDiagnostics(problems)
+		return &Diagnostics{Summary: "message_error", Problems: problems}
+	}
+	return nil
+}
+
+// messageErrorName returns the name of the typed error carrying m.
+func messageErrorName(m *protogen.Message) string {
+	return m.GoIdent.GoName + "Error"
+}
+
+// generateMessageErrors writes the typed errors of the MessageErrors whose
+// value is declared in the Go package of file: per message, a struct
+// embedding it, so its fields are read off the error, and a constructor
+// attaching it to the error of the value as a typed detail and its fields
+// as metadata, which the encoders marshal into the error response. It writes
+// nothing when none is.
+func generateMessageErrors(gen *protogen.Plugin, file *protogen.File, g *protogen.GeneratedFile, config *Config) {
+	values := errorValues(packageFiles(gen, file, config), config)
+	var messages []string
+	for message, value := range config.MessageErrors {
+		if _, ok := values[value]; ok {
+			messages = append(messages, message)
+		}
+	}
+	sort.Strings(messages)
+	for _, message := range messages {
+		// ValidateMessageErrors already rejected messages missing from the
+		// request.
+		m := findMessage(gen.Files, protoreflect.FullName(message))
+		if m == nil {
+			continue
+		}
+		name := messageErrorName(m)
+		value := values[config.MessageErrors[message]].GoName
+		typ := g.QualifiedGoIdent(m.GoIdent)
+		g.P("// ", name, " is an error of ", value, " carrying")
+		g.P("// a ", message, ", whose fields it promotes.")
+		g.P("// errors.As extracts it from the chain of an error, and errors.Is matches it")
+		g.P("// against its value.")
+		g.P("type ", name, " struct {")
+		g.P("*", typ)
+		g.P("err error")
+		g.P("}")
+		g.P()
+		g.P("// New", name, " returns the error of ", value)
+		g.P("// carrying d, joined with errs as Join does. d is attached as a typed")
+		g.P("// detail, and its fields as metadata, so the encoders write them into the")
+		g.P("// error response.")
+		g.P("func New", name, "(d *", typ, ", errs ...error) *", name, " {")
+		g.P("err := ", g.QualifiedGoIdent(metadataPackage.Ident("Wrap")), "(", value, ".Join(errs...), ", g.QualifiedGoIdent(detailsPackage.Ident("Fields")), "(d))")
+		g.P("return &", name, "{", m.GoIdent.GoName, ": d, err: ", g.QualifiedGoIdent(detailsPackage.Ident("Wrap")), "(err, d)}")
+		g.P("}")
+		g.P()
+		g.P("func (e *", name, ") Error() string { return e.err.Error() }")
+		g.P()
+		g.P("// Unwrap returns the error of ", value, " e was built from.")
+		g.P("func (e *", name, ") Unwrap() error { return e.err }")
+		g.P()
+	}
+}
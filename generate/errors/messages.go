@@ -0,0 +1,54 @@
+package errors
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"gopkg.in/yaml.v3"
+)
+
+// LoadMessagesFile reads the messages_file name: a YAML (or JSON) mapping of
+// fully-qualified enum value names to messages, such as
+//
+//	shop.v1.CART_ERROR_EMPTY: Your cart is empty.
+//
+// so the user-facing text is maintained apart from the proto files.
+func LoadMessagesFile(name string) (map[string]string, error) {
+	b, err := os.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("read messages_file: %w", err)
+	}
+	var messages map[string]string
+	if err := yaml.Unmarshal(b, &messages); err != nil {
+		return nil, fmt.Errorf("invalid messages_file %q: %w", name, err)
+	}
+	for value, msg := range messages {
+		if strings.TrimSpace(value) == "" || strings.TrimSpace(msg) == "" {
+			return nil, fmt.Errorf("invalid messages_file %q: empty enum value name or message for %q", name, value)
+		}
+	}
+	return messages, nil
+}
+
+// ValidateMessages reports the Messages naming no error enum value of the
+// request, so a renamed value or a typo in the messages file fails the
+// generation instead of silently keeping the proto message.
+func ValidateMessages(files []*protogen.File, config *Config) error {
+	if len(config.Messages) == 0 {
+		return nil
+	}
+	values := errorValues(files, config)
+	var problems []Diagnostic
+	for name := range config.Messages {
+		if _, ok := values[name]; !ok {
+			problems = append(problems, diagnosticf("%s is not an error enum value of this request", name))
+		}
+	}
+	if len(problems) > 0 {
+		sortDiagnostics(problems)
+		return &Diagnostics{Summary: "messages_file", Problems: problems}
+	}
+	return nil
+}
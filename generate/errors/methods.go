@@ -0,0 +1,189 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// resolveMethodErrorsOption looks up the MethodErrorsOption of config among
+// extensions into o: a repeated string extension of
+// google.protobuf.MethodOptions.
+func resolveMethodErrorsOption(extensions []protoreflect.ExtensionDescriptor, config *Config, o *customOptions) error {
+	var xd protoreflect.ExtensionDescriptor
+	for _, x := range extensions {
+		if string(x.FullName()) == config.MethodErrorsOption {
+			xd = x
+		}
+	}
+	if xd == nil || xd.ContainingMessage().FullName() != "google.protobuf.MethodOptions" {
+		return fmt.Errorf("method_errors_option %s is not an extension of google.protobuf.MethodOptions declared in this request", config.MethodErrorsOption)
+	}
+	if !xd.IsList() || xd.Kind() != protoreflect.StringKind {
+		return fmt.Errorf("method_errors_option %s has type %s, expected a repeated string", config.MethodErrorsOption, xd.Kind())
+	}
+	o.method = dynamicpb.NewExtensionType(xd)
+	return o.types.RegisterExtension(o.method)
+}
+
+// declaredMethodErrors returns the names of the error enums and error enum
+// values method declares it may return with MethodErrorsOption, as written.
+func (c *Config) declaredMethodErrors(method *protogen.Method) []string {
+	if c.custom == nil || c.custom.method == nil {
+		return nil
+	}
+	v, ok := c.custom.extension(method.Desc.Options(), c.custom.method)
+	if !ok {
+		return nil
+	}
+	var names []string
+	for i := 0; i < v.List().Len(); i++ {
+		names = append(names, strings.TrimSpace(v.List().Get(i).String()))
+	}
+	return names
+}
+
+// methodErrorNames indexes the error enums and error enum values of files by
+// fully-qualified proto name, an enum standing for its non-zero values and a
+// value for itself.
+func methodErrorNames(files []*protogen.File, config *Config) map[string][]string {
+	names := map[string][]string{}
+	for _, f := range files {
+		for _, ew := range ErrorEnums(f, config) {
+			for _, info := range append(ew.Errors[:len(ew.Errors):len(ew.Errors)], ew.Aliases...) {
+				value := valueFullName(ew.FullName, info.Value)
+				names[value] = []string{value}
+				if info.Number != 0 && info.Canonical == nil {
+					names[ew.FullName] = append(names[ew.FullName], value)
+				}
+			}
+		}
+	}
+	return names
+}
+
+// MethodErrors returns the error values the methods of the services of files
+// declare they may return with MethodErrorsOption, keyed by full gRPC method
+// name, e.g. "/shop.v1.UserService/GetUser". Values are fully-qualified proto
+// names in declaration order, enums expanded into their non-zero values; only
+// the error enums of files are resolved. Methods declaring none are left out.
+func MethodErrors(files []*protogen.File, config *Config) map[string][]string {
+	if config.MethodErrorsOption == "" {
+		return nil
+	}
+	names := methodErrorNames(files, config)
+	out := map[string][]string{}
+	for _, f := range files {
+		for _, service := range f.Services {
+			for _, method := range service.Methods {
+				if values := possibleErrors(config.declaredMethodErrors(method), names); len(values) > 0 {
+					out[fullMethodName(method)] = values
+				}
+			}
+		}
+	}
+	return out
+}
+
+// possibleErrors expands declared, the names declared by a method, into
+// error values with names, skipping duplicates and unknown names.
+func possibleErrors(declared []string, names map[string][]string) []string {
+	var values []string
+	seen := map[string]bool{}
+	for _, name := range declared {
+		for _, value := range names[name] {
+			if !seen[value] {
+				seen[value] = true
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
+
+// fullMethodName returns the full gRPC method name of method, e.g.
+// "/shop.v1.UserService/GetUser".
+func fullMethodName(method *protogen.Method) string {
+	return "/" + string(method.Parent.Desc.FullName()) + "/" + string(method.Desc.Name())
+}
+
+// ValidateMethodErrors rejects names declared by MethodErrorsOption that are
+// not error enums or error enum values of files. All offending methods are
+// reported together in a single error.
+func ValidateMethodErrors(files []*protogen.File, config *Config) error {
+	if config.MethodErrorsOption == "" {
+		return nil
+	}
+	names := methodErrorNames(files, config)
+	var problems []Diagnostic
+	for _, f := range files {
+		for _, service := range f.Services {
+			for _, method := range service.Methods {
+				for _, name := range config.declaredMethodErrors(method) {
+					if _, ok := names[name]; !ok {
+						problems = append(problems, methodDiagnostic(method, "%s of %s (%s) is not an error enum or error enum value of this request", name, method.Desc.FullName(), methodLocation(method)))
+					}
+				}
+			}
+		}
+	}
+	if len(problems) > 0 {
+		sortDiagnostics(problems)
+		return &Diagnostics{Summary: "invalid method errors", Problems: problems}
+	}
+	return nil
+}
+
+// methodLocation returns the proto file and 1-based line declaring method,
+// or just the file when the request carries no source info.
+func methodLocation(method *protogen.Method) string {
+	file := method.Desc.ParentFile()
+	loc := file.SourceLocations().ByDescriptor(method.Desc)
+	if len(loc.Path) == 0 {
+		return file.Path()
+	}
+	return fmt.Sprintf("%s:%d", file.Path(), loc.StartLine+1)
+}
+
+// generateMethodErrors writes <prefix>.errors_methods.pb.go, in the Go
+// package of file, with a <Service>_<Method>_PossibleErrors function per
+// method of its services declaring the errors it may return. It writes
+// nothing when no method does.
+func generateMethodErrors(gen *protogen.Plugin, file *protogen.File, config *Config) {
+	names := methodErrorNames(gen.Files, config)
+	values := errorValues(gen.Files, config)
+	type method struct {
+		method *protogen.Method
+		values []string
+	}
+	var methods []method
+	for _, service := range file.Services {
+		for _, m := range service.Methods {
+			if v := possibleErrors(config.declaredMethodErrors(m), names); len(v) > 0 {
+				methods = append(methods, method{m, v})
+			}
+		}
+	}
+	if len(methods) == 0 {
+		return
+	}
+	stem, suffix := config.fileSuffix()
+	g := gen.NewGeneratedFile(config.goPackageFile(file.GoImportPath, file.GeneratedFilenamePrefix+stem+"_methods"+suffix+".go"), file.GoImportPath)
+	generateFileHeader(gen, []*protogen.File{file}, g, file.GoPackageName, config, "")
+	for _, m := range methods {
+		name := m.method.Parent.GoName + "_" + m.method.GoName + "_PossibleErrors"
+		g.P("// ", name, " returns the errors the ", m.method.Desc.FullName(), " method")
+		g.P("// declares it may return.")
+		g.P("func ", name, "() []error {")
+		g.P("return []error{")
+		for _, value := range m.values {
+			g.P(values[value], ",")
+		}
+		g.P("}")
+		g.P("}")
+		g.P()
+	}
+}
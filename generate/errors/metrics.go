@@ -0,0 +1,47 @@
+package errors
+
+import (
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/internal/template"
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// MetricsPrometheus counts created errors with the Prometheus client.
+const MetricsPrometheus = "prometheus"
+
+// prometheusPackage is the Prometheus client the generated counters use.
+const prometheusPackage = protogen.GoImportPath("github.com/prometheus/client_golang/prometheus")
+
+// ValidateMetrics reports whether metrics names a supported metrics mode. The
+// empty string disables metrics.
+func ValidateMetrics(metrics string) error {
+	switch metrics {
+	case "", MetricsPrometheus:
+		return nil
+	default:
+		return fmt.Errorf("invalid metrics %q, expected %s", metrics, MetricsPrometheus)
+	}
+}
+
+// qualifyMetrics fills in the Prometheus identifiers of ew so the template
+// emits the error counter.
+func qualifyMetrics(ew *template.ErrorWrapper, g *protogen.GeneratedFile) {
+	ew.Metrics = &template.MetricsIdents{
+		Var:                    lowerFirst(ew.Name) + "Total",
+		CounterVec:             g.QualifiedGoIdent(prometheusPackage.Ident("CounterVec")),
+		NewCounterVec:          g.QualifiedGoIdent(prometheusPackage.Ident("NewCounterVec")),
+		CounterOpts:            g.QualifiedGoIdent(prometheusPackage.Ident("CounterOpts")),
+		Register:               g.QualifiedGoIdent(prometheusPackage.Ident("Register")),
+		AlreadyRegisteredError: g.QualifiedGoIdent(prometheusPackage.Ident("AlreadyRegisteredError")),
+		Itoa:                   g.QualifiedGoIdent(strconvPackage.Ident("Itoa")),
+	}
+}
+
+// lowerFirst lower-cases the first rune of s, e.g. "UserError" -> "userError".
+func lowerFirst(s string) string {
+	r, n := utf8.DecodeRuneInString(s)
+	return string(unicode.ToLower(r)) + s[n:]
+}
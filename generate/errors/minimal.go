@@ -0,0 +1,60 @@
+package errors
+
+import "google.golang.org/protobuf/compiler/protogen"
+
+// minimalNewErrorsFunc is the constructor the Join helpers call under
+// MinimalRuntime, declared by generateMinimalRuntime in the package itself.
+const minimalNewErrorsFunc = "newSphereStatusError"
+
+// minimalRuntime reports whether the Join helpers of proto package pkg build
+// the package-local error type of MinimalRuntime.
+func (c *Config) minimalRuntime(pkg string) bool {
+	runtime := c.runtime(pkg)
+	return c.MinimalRuntime && (runtime == "" || runtime == RuntimeHTTPX)
+}
+
+// generateMinimalRuntime writes the package-local error type the Join helpers
+// construct under MinimalRuntime, a copy of statuserror.Error, so the package
+// imports nothing outside the standard library to build its errors.
+func generateMinimalRuntime(g *protogen.GeneratedFile) {
+	g.P("// sphereStatusError is the error returned by the Join helpers of this")
+	g.P("// package, carrying the HTTP status, code and message of a generated error.")
+	g.P("// Its methods mirror those of the error enums, so callers read them through")
+	g.P("// an interface. It has none of the optional methods of the enums, such as")
+	g.P("// GetReason or IsInternal: the encoders look those up on the enum value,")
+	g.P("// which Unwrap reaches.")
+	g.P("type sphereStatusError struct {")
+	g.P("status  int32")
+	g.P("code    int32")
+	g.P("message string")
+	g.P("err     error")
+	g.P("}")
+	g.P()
+	g.P("// ", minimalNewErrorsFunc, " returns a sphereStatusError wrapping err.")
+	g.P("func ", minimalNewErrorsFunc, "(status, code int32, message string, err error) error {")
+	g.P("return &sphereStatusError{status: status, code: code, message: message, err: err}")
+	g.P("}")
+	g.P()
+	g.P("// Error returns the message, or the wrapped error's text when the message is")
+	g.P("// empty.")
+	g.P("func (e *sphereStatusError) Error() string {")
+	g.P("if e.message == \"\" && e.err != nil {")
+	g.P("return e.err.Error()")
+	g.P("}")
+	g.P("return e.message")
+	g.P("}")
+	g.P()
+	g.P("// Unwrap returns the wrapped error, so errors.Is and errors.As reach the")
+	g.P("// generated enum value and any cause joined with it.")
+	g.P("func (e *sphereStatusError) Unwrap() error { return e.err }")
+	g.P()
+	g.P("// GetStatus returns the HTTP status.")
+	g.P("func (e *sphereStatusError) GetStatus() int32 { return e.status }")
+	g.P()
+	g.P("// GetCode returns the error code.")
+	g.P("func (e *sphereStatusError) GetCode() int32 { return e.code }")
+	g.P()
+	g.P("// GetMessage returns the message.")
+	g.P("func (e *sphereStatusError) GetMessage() string { return e.message }")
+	g.P()
+}
@@ -0,0 +1,175 @@
+package errors
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// valueGoName returns the Go name used for generated per-value identifiers:
+// the enum name without its "Error" suffix followed by the CamelCased value
+// name without its enum prefix. UserError.USER_ERROR_NOT_FOUND becomes
+// "UserNotFound", so its sentinel is ErrUserNotFound.
+func valueGoName(enumName, valueName string) string {
+	return strings.TrimSuffix(enumName, "Error") + ValueName(enumName, valueName)
+}
+
+// ValueName returns the CamelCased value name without its enum prefix, the
+// basis of per-value identifiers in every output language:
+// UserError.USER_ERROR_NOT_FOUND becomes "NotFound".
+func ValueName(enumName, valueName string) string {
+	return camelCase(TrimValuePrefix(enumName, valueName))
+}
+
+// TrimValuePrefix returns the value name without its enum prefix, for outputs
+// keeping value names in UPPER_SNAKE_CASE: UserError.USER_ERROR_NOT_FOUND
+// becomes "NOT_FOUND". A value named after the prefix alone keeps its name.
+func TrimValuePrefix(enumName, valueName string) string {
+	trimmed := strings.TrimPrefix(valueName, upperSnake(enumName)+"_")
+	if trimmed == "" {
+		return valueName
+	}
+	return trimmed
+}
+
+// upperSnake converts a CamelCase identifier to UPPER_SNAKE_CASE, matching the
+// value prefix convention of buf's ENUM_VALUE_PREFIX rule.
+// Acronyms stay together: HTTPError becomes HTTP_ERROR.
+func upperSnake(s string) string {
+	rs := []rune(s)
+	var b strings.Builder
+	for i, r := range rs {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := rs[i-1]
+			nextLower := i+1 < len(rs) && unicode.IsLower(rs[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
+
+// camelCase converts an UPPER_SNAKE_CASE name to CamelCase.
+func camelCase(s string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(s, "_") {
+		if part == "" {
+			continue
+		}
+		b.WriteString(strings.ToUpper(part[:1]))
+		b.WriteString(strings.ToLower(part[1:]))
+	}
+	return b.String()
+}
+
+// Name styles of the Go identifiers generated per error value.
+const (
+	// NameStyleEnumPrefixed names UserError.USER_ERROR_NOT_FOUND
+	// "UserNotFound", e.g. ErrUserNotFound. It is the default.
+	NameStyleEnumPrefixed = "enum_prefixed"
+	// NameStyleValueOnly drops the enum name: "NotFound".
+	NameStyleValueOnly = "value_only"
+	// NameStyleCamel CamelCases the whole value name: "UserErrorNotFound".
+	NameStyleCamel = "camel"
+)
+
+// ValidateNameStyle reports whether style names a supported name style. The
+// empty string selects NameStyleEnumPrefixed.
+func ValidateNameStyle(style string) error {
+	switch style {
+	case "", NameStyleEnumPrefixed, NameStyleValueOnly, NameStyleCamel:
+		return nil
+	default:
+		return fmt.Errorf("invalid name_style %q, expected %s, %s or %s", style, NameStyleEnumPrefixed, NameStyleValueOnly, NameStyleCamel)
+	}
+}
+
+// goNameCandidates returns the Go names value of enum may take, most
+// preferred first: the configured style, the enum-prefixed name, and the
+// enum-prefixed name qualified by the proto package.
+func goNameCandidates(style string, enum *protogen.Enum, value *protogen.EnumValue) []string {
+	enumName, valueName := string(enum.Desc.Name()), string(value.Desc.Name())
+	prefixed := valueGoName(enumName, valueName)
+	var names []string
+	switch style {
+	case NameStyleValueOnly:
+		names = append(names, ValueName(enumName, valueName))
+	case NameStyleCamel:
+		names = append(names, camelCase(valueName))
+	}
+	pkg := camelCase(strings.ReplaceAll(string(enum.Desc.ParentFile().Package()), ".", "_"))
+	for _, name := range []string{prefixed, pkg + prefixed} {
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// AssignGoNames picks the Go names of the values of every error enum of the
+// files marked for generation, styled by config.NameStyle. Values of one Go
+// package whose names collide fall back to the next candidate of
+// goNameCandidates until the names are distinct, so that NOT_FOUND values of
+// two enums do not both declare ErrNotFound. It must run before generation;
+// without it every value takes the first candidate.
+func AssignGoNames(files []*protogen.File, config *Config) {
+	type entry struct {
+		candidates []string
+		level      int
+	}
+	byPackage := map[protogen.GoImportPath][]*entry{}
+	entries := map[string]*entry{}
+	var order []string
+	for _, f := range files {
+		if !f.Generate {
+			continue
+		}
+		importPath := config.outputFor(f).importPath
+		for _, enum := range f.Enums {
+			if _, ok := config.defaultStatus(enum); !ok || !config.selects(enum) {
+				continue
+			}
+			for _, v := range enum.Values {
+				e := &entry{candidates: goNameCandidates(config.NameStyle, enum, v)}
+				key := string(v.Desc.FullName())
+				entries[key] = e
+				order = append(order, key)
+				byPackage[importPath] = append(byPackage[importPath], e)
+			}
+		}
+	}
+	for _, pkg := range byPackage {
+		for changed := true; changed; {
+			changed = false
+			count := map[string]int{}
+			for _, e := range pkg {
+				count[e.candidates[e.level]]++
+			}
+			for _, e := range pkg {
+				if count[e.candidates[e.level]] > 1 && e.level < len(e.candidates)-1 {
+					e.level++
+					changed = true
+				}
+			}
+		}
+	}
+	config.goNames = map[string]string{}
+	for _, key := range order {
+		e := entries[key]
+		config.goNames[key] = e.candidates[e.level]
+	}
+}
+
+// goName returns the Go name of value of enum picked by AssignGoNames, or its
+// first candidate when AssignGoNames did not run.
+func (c *Config) goName(enum *protogen.Enum, value *protogen.EnumValue) string {
+	if name, ok := c.goNames[string(value.Desc.FullName())]; ok {
+		return name
+	}
+	return goNameCandidates(c.NameStyle, enum, value)[0]
+}
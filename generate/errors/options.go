@@ -0,0 +1,345 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-sphere/errors/sphere/errors"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// Fields of the error options read from a custom OptionsType, keyed in
+// OptionFields.
+const (
+	optionStatus  = "status"
+	optionReason  = "reason"
+	optionMessage = "message"
+	optionGRPC    = "grpc_code"
+	optionSLO     = "slo_exempt"
+	optionVisible = "visibility"
+	optionNew     = "new_errors_func"
+	optionExample = "example"
+	optionClient  = "client_code"
+	optionStable  = "stability"
+	optionLegacy  = "legacy_pattern"
+	optionEvent   = "otel_event"
+	optionSpan    = "span_error"
+	optionI18n    = "message_i18n"
+)
+
+// ParseOptionField parses an option_field parameter of the form
+// 'field=name', mapping the status, reason, message, grpc_code, slo_exempt,
+// visibility, new_errors_func, example, client_code, stability,
+// legacy_pattern, otel_event, span_error or message_i18n field of the error
+// options to the field name of a custom OptionsType.
+func ParseOptionField(s string) (string, string, error) {
+	field, name, ok := strings.Cut(s, "=")
+	field, name = strings.TrimSpace(field), strings.TrimSpace(name)
+	if !ok || name == "" || (field != optionStatus && field != optionReason && field != optionMessage && field != optionGRPC && field != optionSLO && field != optionVisible && field != optionNew && field != optionExample && field != optionClient && field != optionStable && field != optionLegacy && field != optionEvent && field != optionSpan && field != optionI18n) {
+		return "", "", fmt.Errorf("invalid option field %q, expected 'status=name', 'reason=name', 'message=name', 'grpc_code=name', 'slo_exempt=name', 'visibility=name', 'new_errors_func=name', 'example=name', 'client_code=name', 'stability=name', 'legacy_pattern=name', 'otel_event=name', 'span_error=name' or 'message_i18n=name'", s)
+	}
+	return field, name, nil
+}
+
+// customOptions reads the error options of a custom schema, as set up by
+// ResolveOptions. A nil extension type stands for the sphere option it
+// replaces. It is only read after ResolveOptions, so concurrent generation
+// may share it.
+type customOptions struct {
+	types *protoregistry.Types
+	// value is the EnumValueOptions extension of type OptionsType, and
+	// status, reason, message, grpcCode, sloExempt, visibility,
+	// newErrorsFunc, example, clientCode, stability, legacyPattern,
+	// otelEvent, spanError and messageI18n its fields, nil when OptionsType
+	// has none.
+	value                                                                                                                                                     protoreflect.ExtensionType
+	status, reason, message, grpcCode, sloExempt, visibility, newErrorsFunc, example, clientCode, stability, legacyPattern, otelEvent, spanError, messageI18n protoreflect.FieldDescriptor
+	// enum is the DefaultStatusOption extension of EnumOptions.
+	enum protoreflect.ExtensionType
+	// method is the MethodErrorsOption extension of MethodOptions.
+	method protoreflect.ExtensionType
+}
+
+// ResolveOptions looks up the OptionsType and DefaultStatusOption of config
+// among the extensions declared in files, so that error options are read from
+// them instead of (sphere.errors.options) and (sphere.errors.default_status),
+// and the MethodErrorsOption. It does nothing when none is set, and fails
+// when an option is not declared in files or has a type that cannot carry
+// error options.
+func ResolveOptions(files []*protogen.File, config *Config) error {
+	if len(config.OptionFields) > 0 && config.OptionsType == "" {
+		return fmt.Errorf("option_field requires options_type")
+	}
+	if config.OptionsType == "" && config.DefaultStatusOption == "" && config.MethodErrorsOption == "" {
+		return nil
+	}
+	var extensions []protoreflect.ExtensionDescriptor
+	for _, f := range files {
+		extensions = appendExtensions(extensions, f.Desc)
+	}
+	o := &customOptions{types: &protoregistry.Types{}}
+	if config.OptionsType != "" {
+		var found []protoreflect.ExtensionDescriptor
+		for _, xd := range extensions {
+			if xd.ContainingMessage().FullName() == "google.protobuf.EnumValueOptions" && xd.Message() != nil &&
+				string(xd.Message().FullName()) == config.OptionsType && !xd.IsList() {
+				found = append(found, xd)
+			}
+		}
+		switch len(found) {
+		case 0:
+			return fmt.Errorf("options_type %s is not the type of an extension of google.protobuf.EnumValueOptions declared in this request", config.OptionsType)
+		case 1:
+		default:
+			return fmt.Errorf("options_type %s is the type of several extensions of google.protobuf.EnumValueOptions: %s and %s", config.OptionsType, found[0].FullName(), found[1].FullName())
+		}
+		o.value = dynamicpb.NewExtensionType(found[0])
+		if err := o.types.RegisterExtension(o.value); err != nil {
+			return err
+		}
+		fields := found[0].Message().Fields()
+		var err error
+		if o.status, err = optionField(config, fields, optionStatus); err != nil {
+			return err
+		}
+		if o.reason, err = optionField(config, fields, optionReason); err != nil {
+			return err
+		}
+		if o.message, err = optionField(config, fields, optionMessage); err != nil {
+			return err
+		}
+		if o.grpcCode, err = optionField(config, fields, optionGRPC); err != nil {
+			return err
+		}
+		if o.sloExempt, err = optionField(config, fields, optionSLO); err != nil {
+			return err
+		}
+		if o.visibility, err = optionField(config, fields, optionVisible); err != nil {
+			return err
+		}
+		if o.newErrorsFunc, err = optionField(config, fields, optionNew); err != nil {
+			return err
+		}
+		if o.example, err = optionField(config, fields, optionExample); err != nil {
+			return err
+		}
+		if o.clientCode, err = optionField(config, fields, optionClient); err != nil {
+			return err
+		}
+		if o.stability, err = optionField(config, fields, optionStable); err != nil {
+			return err
+		}
+		if o.legacyPattern, err = optionField(config, fields, optionLegacy); err != nil {
+			return err
+		}
+		if o.otelEvent, err = optionField(config, fields, optionEvent); err != nil {
+			return err
+		}
+		if o.spanError, err = optionField(config, fields, optionSpan); err != nil {
+			return err
+		}
+		if o.messageI18n, err = optionField(config, fields, optionI18n); err != nil {
+			return err
+		}
+	}
+	if config.DefaultStatusOption != "" {
+		var xd protoreflect.ExtensionDescriptor
+		for _, x := range extensions {
+			if string(x.FullName()) == config.DefaultStatusOption {
+				xd = x
+			}
+		}
+		if xd == nil || xd.ContainingMessage().FullName() != "google.protobuf.EnumOptions" {
+			return fmt.Errorf("default_status_option %s is not an extension of google.protobuf.EnumOptions declared in this request", config.DefaultStatusOption)
+		}
+		if !isInteger(xd) {
+			return fmt.Errorf("default_status_option %s has type %s, expected an integer", config.DefaultStatusOption, xd.Kind())
+		}
+		o.enum = dynamicpb.NewExtensionType(xd)
+		if err := o.types.RegisterExtension(o.enum); err != nil {
+			return err
+		}
+	}
+	if config.MethodErrorsOption != "" {
+		if err := resolveMethodErrorsOption(extensions, config, o); err != nil {
+			return err
+		}
+	}
+	config.custom = o
+	return nil
+}
+
+// appendExtensions appends the extensions declared in file, including those
+// nested in messages, to out.
+func appendExtensions(out []protoreflect.ExtensionDescriptor, file protoreflect.FileDescriptor) []protoreflect.ExtensionDescriptor {
+	for i := 0; i < file.Extensions().Len(); i++ {
+		out = append(out, file.Extensions().Get(i))
+	}
+	var walk func(protoreflect.MessageDescriptors)
+	walk = func(messages protoreflect.MessageDescriptors) {
+		for i := 0; i < messages.Len(); i++ {
+			m := messages.Get(i)
+			for j := 0; j < m.Extensions().Len(); j++ {
+				out = append(out, m.Extensions().Get(j))
+			}
+			walk(m.Messages())
+		}
+	}
+	walk(file.Messages())
+	return out
+}
+
+// optionField returns the field of fields read as the error option field,
+// named by OptionFields or after field itself. A field missing under its own
+// name is never read; one named by OptionFields must exist.
+func optionField(config *Config, fields protoreflect.FieldDescriptors, field string) (protoreflect.FieldDescriptor, error) {
+	name, explicit := config.OptionFields[field]
+	if !explicit {
+		name = field
+	}
+	fd := fields.ByName(protoreflect.Name(name))
+	if fd == nil {
+		if explicit {
+			return nil, fmt.Errorf("options_type %s has no field %s for the %s", config.OptionsType, name, field)
+		}
+		return nil, nil
+	}
+	valid := fd.Kind() == protoreflect.StringKind
+	switch field {
+	case optionStatus, optionClient:
+		valid = isInteger(fd)
+	case optionGRPC, optionVisible, optionStable:
+		valid = valid || fd.Kind() == protoreflect.EnumKind
+	case optionSLO, optionSpan:
+		valid = fd.Kind() == protoreflect.BoolKind
+	case optionI18n:
+		if fd.IsMap() && fd.MapKey().Kind() == protoreflect.StringKind && fd.MapValue().Kind() == protoreflect.StringKind {
+			return fd, nil
+		}
+		return nil, fmt.Errorf("field %s of options_type %s cannot hold the %s: expected a map<string, string>", name, config.OptionsType, field)
+	}
+	if !valid || fd.IsList() || fd.IsMap() {
+		return nil, fmt.Errorf("field %s of options_type %s cannot hold the %s: %s", name, config.OptionsType, field, fd.Kind())
+	}
+	return fd, nil
+}
+
+// isInteger reports whether fd is a singular integer or enum field.
+func isInteger(fd protoreflect.FieldDescriptor) bool {
+	if fd.IsList() {
+		return false
+	}
+	switch fd.Kind() {
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint32Kind, protoreflect.Fixed32Kind, protoreflect.Uint64Kind,
+		protoreflect.Fixed64Kind, protoreflect.EnumKind:
+		return true
+	}
+	return false
+}
+
+// intValue returns v, a value of the integer or enum field fd, as an int32.
+func intValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) int32 {
+	switch fd.Kind() {
+	case protoreflect.EnumKind:
+		return int32(v.Enum())
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind, protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return int32(v.Uint())
+	default:
+		return int32(v.Int())
+	}
+}
+
+// extension returns the value of the extension xt in opts, decoded from its
+// unknown fields with the resolver of o, and whether opts sets it.
+func (o *customOptions) extension(opts proto.Message, xt protoreflect.ExtensionType) (protoreflect.Value, bool) {
+	b, err := proto.Marshal(opts)
+	if err != nil {
+		return protoreflect.Value{}, false
+	}
+	m := opts.ProtoReflect().Type().New().Interface()
+	if err := (proto.UnmarshalOptions{Resolver: o.types}).Unmarshal(b, m); err != nil {
+		return protoreflect.Value{}, false
+	}
+	xd := xt.TypeDescriptor()
+	if !m.ProtoReflect().Has(xd) {
+		return protoreflect.Value{}, false
+	}
+	return m.ProtoReflect().Get(xd), true
+}
+
+// defaultStatus returns the default status of enum and whether enum declares
+// one, which makes it an error enum.
+func (c *Config) defaultStatus(enum *protogen.Enum) (int32, bool) {
+	if c.custom != nil && c.custom.enum != nil {
+		v, ok := c.custom.extension(enum.Desc.Options(), c.custom.enum)
+		if !ok {
+			return 0, false
+		}
+		return intValue(c.custom.enum.TypeDescriptor(), v), true
+	}
+	if !proto.HasExtension(enum.Desc.Options(), errors.E_DefaultStatus) {
+		return 0, false
+	}
+	status, _ := proto.GetExtension(enum.Desc.Options(), errors.E_DefaultStatus).(int32)
+	return status, true
+}
+
+// valueOptions returns the error options attached to an enum value, or an
+// empty value when none are set, with the message of Messages when it names
+// the value.
+func (c *Config) valueOptions(v *protogen.EnumValue) *errors.Error {
+	opt, _ := c.lookupValueOptions(v)
+	if msg, ok := c.Messages[string(v.Desc.FullName())]; ok {
+		opt = proto.Clone(opt).(*errors.Error)
+		opt.Message = msg
+	}
+	return opt
+}
+
+// hasValueOptions reports whether an enum value carries error options.
+func (c *Config) hasValueOptions(v *protogen.EnumValue) bool {
+	_, ok := c.lookupValueOptions(v)
+	return ok
+}
+
+func (c *Config) lookupValueOptions(v *protogen.EnumValue) (*errors.Error, bool) {
+	if c.custom != nil && c.custom.value != nil {
+		ext, ok := c.custom.extension(v.Desc.Options(), c.custom.value)
+		if !ok {
+			return &errors.Error{}, false
+		}
+		m := ext.Message()
+		opt := &errors.Error{}
+		if fd := c.custom.status; fd != nil {
+			opt.Status = intValue(fd, m.Get(fd))
+		}
+		if fd := c.custom.reason; fd != nil {
+			opt.Reason = m.Get(fd).String()
+		}
+		if fd := c.custom.message; fd != nil {
+			opt.Message = m.Get(fd).String()
+		}
+		return opt, true
+	}
+	if proto.HasExtension(v.Desc.Options(), errors.E_Options) {
+		if opt, ok := proto.GetExtension(v.Desc.Options(), errors.E_Options).(*errors.Error); ok && opt != nil {
+			return opt, true
+		}
+	}
+	return &errors.Error{}, false
+}
+
+// optionsName returns the option carrying the error options of enum values,
+// as written in proto files, for diagnostics.
+func (c *Config) optionsName() string {
+	if c.custom != nil && c.custom.value != nil {
+		return "(" + string(c.custom.value.TypeDescriptor().FullName()) + ")"
+	}
+	return "(sphere.errors.options)"
+}
@@ -0,0 +1,44 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Origins of error values: who caused the error.
+const (
+	OriginClient   = "CLIENT"
+	OriginServer   = "SERVER"
+	OriginUpstream = "UPSTREAM"
+)
+
+// originFromStatus returns the default origin of an HTTP status: UPSTREAM for
+// the gateway statuses 502, 503 and 504, SERVER for the remaining server
+// errors and CLIENT otherwise.
+func originFromStatus(status int32) string {
+	switch {
+	case status == 502 || status == 503 || status == 504:
+		return OriginUpstream
+	case status >= 500:
+		return OriginServer
+	default:
+		return OriginClient
+	}
+}
+
+// ParseOrigin parses an origin parameter of the form "name=ORIGIN", where
+// name is a fully-qualified enum or enum value name and ORIGIN one of CLIENT,
+// SERVER or UPSTREAM.
+func ParseOrigin(s string) (string, string, error) {
+	name, origin, ok := strings.Cut(s, "=")
+	name, origin = strings.TrimSpace(name), strings.ToUpper(strings.TrimSpace(origin))
+	if !ok || name == "" {
+		return "", "", fmt.Errorf("invalid origin %q, expected 'name=ORIGIN'", s)
+	}
+	switch origin {
+	case OriginClient, OriginServer, OriginUpstream:
+		return name, origin, nil
+	default:
+		return "", "", fmt.Errorf("invalid origin %q, expected CLIENT, SERVER or UPSTREAM", s)
+	}
+}
@@ -0,0 +1,197 @@
+package errors
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// outputTarget is where the Go errors of a proto file are generated.
+type outputTarget struct {
+	// prefix is the generated filename prefix, without the .errors.pb.go
+	// suffix.
+	prefix      string
+	importPath  protogen.GoImportPath
+	packageName protogen.GoPackageName
+	// separate reports whether the target differs from the package of the
+	// proto file's message types, in which case every error enum is mirrored
+	// by a local type the helpers are declared on.
+	separate bool
+}
+
+// GoPackage is a Go package errors are generated into. Name defaults to the
+// last element of ImportPath.
+type GoPackage struct {
+	ImportPath protogen.GoImportPath
+	Name       protogen.GoPackageName
+}
+
+func (p GoPackage) name() protogen.GoPackageName {
+	if p.Name == "" {
+		return protogen.GoPackageName(path.Base(string(p.ImportPath)))
+	}
+	return p.Name
+}
+
+// ParseOutputPackage parses an output_package parameter, either "path" or
+// "path;name" (applies to every proto package) or "proto.package=path;name".
+func ParseOutputPackage(s string) (string, GoPackage, error) {
+	pkg, target := "", s
+	if i := strings.LastIndex(s, "="); i >= 0 {
+		pkg, target = s[:i], s[i+1:]
+	}
+	importPath, name, _ := strings.Cut(strings.TrimSpace(target), ";")
+	if importPath == "" {
+		return "", GoPackage{}, fmt.Errorf("invalid output package %q, expected 'path;name' or 'proto.package=path;name'", s)
+	}
+	return strings.TrimSpace(pkg), GoPackage{ImportPath: protogen.GoImportPath(importPath), Name: protogen.GoPackageName(name)}, nil
+}
+
+// ParseGoPackageDir parses a go_package_dir parameter of the form
+// "import/path=dir", dir being relative to the output directory.
+func ParseGoPackageDir(s string) (protogen.GoImportPath, string, error) {
+	importPath, dir, ok := strings.Cut(s, "=")
+	importPath, dir = strings.TrimSpace(importPath), strings.TrimSpace(dir)
+	if !ok || importPath == "" || dir == "" || path.IsAbs(dir) || path.Clean(dir) != dir || dir == ".." || strings.HasPrefix(dir, "../") {
+		return "", "", fmt.Errorf("invalid go package dir %q, expected 'import/path=dir' with dir relative to the output directory", s)
+	}
+	return protogen.GoImportPath(importPath), dir, nil
+}
+
+// outputPath returns the name of the generated file name, relative to the
+// output directory, under Module when set, which protogen strips again.
+func (c *Config) outputPath(name string) string {
+	if c.Module == "" {
+		return name
+	}
+	return path.Join(c.Module, name)
+}
+
+// goPackageFile returns the name of the generated file of the Go package
+// importPath named name by default: moved into its GoPackageDirs directory
+// when it has one.
+func (c *Config) goPackageFile(importPath protogen.GoImportPath, name string) string {
+	dir, ok := c.GoPackageDirs[importPath]
+	if !ok {
+		return name
+	}
+	return c.outputPath(path.Join(dir, path.Base(name)))
+}
+
+// outputFor resolves the output target of file: alongside the message types
+// by default, in a sub-package with PackageSuffix, or in OutputPackage or the
+// OutputPackages entry of its proto package, in the GoPackageDirs directory
+// of the target package when it has one.
+func (c *Config) outputFor(file *protogen.File) outputTarget {
+	out := c.packageFor(file)
+	out.prefix = c.goPackageFile(out.importPath, out.prefix)
+	return out
+}
+
+// packageFor resolves the Go package the errors of file are generated into,
+// and the filename prefix paths= gives them there.
+func (c *Config) packageFor(file *protogen.File) outputTarget {
+	base := path.Base(file.GeneratedFilenamePrefix)
+	if pkg, ok := c.OutputPackages[string(file.Desc.Package())]; ok {
+		return outputTarget{
+			prefix:      path.Join(string(pkg.ImportPath), base),
+			importPath:  pkg.ImportPath,
+			packageName: pkg.name(),
+			separate:    pkg.ImportPath != file.GoImportPath,
+		}
+	}
+	switch {
+	case c.OutputPackage != "":
+		return outputTarget{
+			prefix:      path.Join(string(c.OutputPackage), base),
+			importPath:  c.OutputPackage,
+			packageName: GoPackage{c.OutputPackage, c.OutputPackageName}.name(),
+			separate:    c.OutputPackage != file.GoImportPath,
+		}
+	case c.PackageSuffix != "":
+		return outputTarget{
+			prefix:      path.Join(path.Dir(file.GeneratedFilenamePrefix), c.PackageSuffix, base),
+			importPath:  protogen.GoImportPath(path.Join(string(file.GoImportPath), c.PackageSuffix)),
+			packageName: protogen.GoPackageName(path.Base(c.PackageSuffix)),
+			separate:    true,
+		}
+	default:
+		return outputTarget{
+			prefix:      file.GeneratedFilenamePrefix,
+			importPath:  file.GoImportPath,
+			packageName: file.GoPackageName,
+		}
+	}
+}
+
+// ValidateOutputPaths rejects, with Module set, files of the request whose
+// errors would be generated outside of it, e.g. into an output_package of
+// another module of a monorepo, which protogen would fail on with little
+// context. GoPackageDirs place such packages explicitly. All offending files
+// are reported together in a single error.
+func ValidateOutputPaths(files []*protogen.File, config *Config) error {
+	if config.Module == "" {
+		return nil
+	}
+	var problems []Diagnostic
+	for _, f := range files {
+		if !f.Generate || !hasErrorEnums(f.Enums, config) {
+			continue
+		}
+		if out := config.outputFor(f); !strings.HasPrefix(out.prefix, config.Module+"/") {
+			problems = append(problems, fileDiagnostic(f, "errors of %s are generated into Go package %s outside module %s; place it with go_package_dir=%s=dir", f.Desc.Path(), string(out.importPath), config.Module, string(out.importPath)))
+		}
+	}
+	if len(problems) > 0 {
+		sortDiagnostics(problems)
+		return &Diagnostics{Summary: "invalid output paths", Problems: problems}
+	}
+	return nil
+}
+
+// generateMirrorType declares a local type mirroring enum, with one constant
+// per value, so the error helpers can be generated outside the package of the
+// message types.
+func generateMirrorType(enum *protogen.Enum, g *protogen.GeneratedFile) {
+	name := enum.GoIdent.GoName
+	g.P("// ", name, " mirrors ", g.QualifiedGoIdent(enum.GoIdent), " so its error helpers can live in this package.")
+	g.P("type ", name, " ", g.QualifiedGoIdent(enum.GoIdent))
+	g.P()
+	g.P("const (")
+	for _, v := range enum.Values {
+		if enumValueDeprecated(v) {
+			g.P("// Deprecated: ", v.GoIdent.GoName, " is deprecated.")
+		}
+		g.P(v.GoIdent.GoName, " = ", name, "(", g.QualifiedGoIdent(v.GoIdent), ")")
+	}
+	g.P(")")
+	g.P()
+}
+
+// defaultFileSuffix is the suffix of the generated Go error files when
+// FileSuffix is unset.
+const defaultFileSuffix = ".errors.pb.go"
+
+// ValidateFileSuffix reports whether suffix can name generated Go files: it
+// must end in .go and must not make them test files.
+func ValidateFileSuffix(suffix string) error {
+	if suffix == "" {
+		return nil
+	}
+	if !strings.HasSuffix(suffix, ".go") || strings.HasSuffix(suffix, "_test.go") || suffix == ".go" || strings.Contains(suffix, "/") {
+		return fmt.Errorf("invalid file_suffix %q, expected a suffix such as _errors.go ending in .go", suffix)
+	}
+	return nil
+}
+
+// fileSuffix splits the FileSuffix of c, without .go, into the part placed
+// before and after the _test, _fuzz_test and _deprecated markers of the
+// companion files, e.g. ".errors" and ".pb" for the default.
+func (c *Config) fileSuffix() (string, string) {
+	if c.FileSuffix == "" || c.FileSuffix == defaultFileSuffix {
+		return ".errors", ".pb"
+	}
+	return strings.TrimSuffix(c.FileSuffix, ".go"), ""
+}
@@ -0,0 +1,56 @@
+package errors
+
+import (
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/internal/template"
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// stringsPackage is the standard library "strings" package, used by the
+// generated helpers filling named placeholders.
+const stringsPackage = protogen.GoImportPath("strings")
+
+// qualifyPlaceholders fills in the identifiers of ew so the template emits
+// the <GoName>Params structs and New<GoName> constructors of messages with
+// named placeholders.
+func qualifyPlaceholders(ew *template.ErrorWrapper, g *protogen.GeneratedFile) {
+	ew.Placeholder = &template.PlaceholderIdents{
+		Sprint:      g.QualifiedGoIdent(fmtPackage.Ident("Sprint")),
+		Errorf:      g.QualifiedGoIdent(fmtPackage.Ident("Errorf")),
+		NewReplacer: g.QualifiedGoIdent(stringsPackage.Ident("NewReplacer")),
+		Join:        g.QualifiedGoIdent(stringsPackage.Ident("Join")),
+	}
+}
+
+// ValidatePlaceholders rejects messages mixing named placeholders such as
+// {user_id} with printf verbs, whose New<GoName> constructors would collide,
+// and messages whose placeholders map to the same Go field, such as {user_id}
+// and {user__id}. All offending values are reported together in a single
+// error.
+func ValidatePlaceholders(files []*protogen.File, config *Config) error {
+	var problems []Diagnostic
+	for _, f := range files {
+		for _, ew := range ErrorEnums(f, config) {
+			for _, info := range ew.Errors {
+				placeholders := info.Placeholders()
+				if len(placeholders) == 0 {
+					continue
+				}
+				if info.HasFormat() {
+					problems = append(problems, infoDiagnostic(ew, info, "message of %s.%s (%s) mixes named placeholders and printf verbs", ew.FullName, info.Value, info.Source))
+				}
+				fields := map[string]string{}
+				for _, p := range placeholders {
+					if other, ok := fields[p.Field]; ok {
+						problems = append(problems, infoDiagnostic(ew, info, "placeholders {%s} and {%s} of %s.%s (%s) both map to the field %s", other, p.Name, ew.FullName, info.Value, info.Source, p.Field))
+					}
+					fields[p.Field] = p.Name
+				}
+			}
+		}
+	}
+	if len(problems) > 0 {
+		sortDiagnostics(problems)
+		return &Diagnostics{Summary: "invalid message placeholders", Problems: problems}
+	}
+	return nil
+}
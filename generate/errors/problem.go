@@ -0,0 +1,76 @@
+package errors
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/internal/template"
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// problemPackage is the runtime package of the problem details helpers.
+const problemPackage = protogen.GoImportPath("github.com/go-sphere/protoc-gen-sphere-errors/problem")
+
+// blankProblemType is the RFC 9457 problem type used without ProblemType.
+const blankProblemType = "about:blank"
+
+// uriPlaceholder matches a placeholder of a URI template.
+var uriPlaceholder = regexp.MustCompile(`\{[^{}]*\}`)
+
+// ValidateProblemType reports whether t is a valid problem type URI template:
+// its only placeholders are {package}, {enum}, {value} and {code}.
+func ValidateProblemType(t string) error {
+	return validateURITemplate("problem_type", t)
+}
+
+// validateURITemplate reports whether t, the value of the parameter param,
+// is a valid URI template: its only placeholders are {package}, {enum},
+// {value} and {code}.
+func validateURITemplate(param, t string) error {
+	for _, p := range uriPlaceholder.FindAllString(t, -1) {
+		switch p {
+		case "{package}", "{enum}", "{value}", "{code}":
+		default:
+			return fmt.Errorf("invalid %s %q: unknown placeholder %s, expected {package}, {enum}, {value} or {code}", param, t, p)
+		}
+	}
+	return nil
+}
+
+// expandURITemplate returns the URI template t expanded for info, a value of
+// the enum enum declared in proto package pkg.
+func expandURITemplate(t string, info *template.ErrorInfo, pkg, enum string) string {
+	return strings.NewReplacer(
+		"{package}", pkg,
+		"{enum}", enum,
+		"{value}", info.Value,
+		"{code}", strconv.Itoa(int(info.Code)),
+	).Replace(t)
+}
+
+// problemInfo fills in the problem type and title of info, a value of the enum
+// enum declared in proto package pkg. Without a type template the type is
+// about:blank, whose title is the HTTP status phrase; otherwise the title is
+// the reason, which, like the type, does not change between occurrences.
+func problemInfo(info *template.ErrorInfo, typeTemplate, pkg, enum string) {
+	if typeTemplate == "" {
+		info.ProblemType = blankProblemType
+		info.ProblemTitle = http.StatusText(int(info.Status))
+		if info.ProblemTitle == "" {
+			info.ProblemTitle = info.Reason
+		}
+		return
+	}
+	info.ProblemType = expandURITemplate(typeTemplate, info, pkg, enum)
+	info.ProblemTitle = info.Reason
+}
+
+// qualifyProblem fills in the problem details identifiers of ew.
+func qualifyProblem(ew *template.ErrorWrapper, g *protogen.GeneratedFile) {
+	ew.Problem = &template.ProblemIdents{
+		Details: g.QualifiedGoIdent(problemPackage.Ident("Details")),
+	}
+}
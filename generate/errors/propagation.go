@@ -0,0 +1,59 @@
+package errors
+
+import "google.golang.org/protobuf/compiler/protogen"
+
+// propagationPackage is the runtime package the generated propagation helpers
+// call.
+const propagationPackage = protogen.GoImportPath("github.com/go-sphere/protoc-gen-sphere-errors/propagation")
+
+// msgheadersPackage is the runtime package the generated message header
+// helpers call.
+const msgheadersPackage = protogen.GoImportPath("github.com/go-sphere/protoc-gen-sphere-errors/msgheaders")
+
+// generatePropagation writes the package-level PropagationHeader and
+// FromPropagationHeader helpers. FromPropagationHeader resolves codes with the
+// function written by generateErrorByCode.
+func generatePropagation(g *protogen.GeneratedFile) {
+	g.P("// PropagationHeader encodes the code, origin and trace context of err for the")
+	g.P("// Sphere-Error header, so a downstream service can re-raise it with")
+	g.P("// FromPropagationHeader. It returns \"\" when err holds no generated error.")
+	g.P("func PropagationHeader(err error) string {")
+	g.P("return ", g.QualifiedGoIdent(propagationPackage.Ident("Encode")), "(err)")
+	g.P("}")
+	g.P()
+	g.P("// FromPropagationHeader re-raises the error of this package encoded in h by")
+	g.P("// PropagationHeader. The error reports the UPSTREAM origin and carries the")
+	g.P("// upstream trace context as metadata; errors.As with a *propagation.Error")
+	g.P("// reads the upstream code and origin. It reports false when h is not a valid")
+	g.P("// header or no value of this package has its code.")
+	g.P("func FromPropagationHeader(h string) (error, bool) {")
+	g.P("return ", g.QualifiedGoIdent(propagationPackage.Ident("Raise")), "(h, sphereErrorByCode)")
+	g.P("}")
+	g.P()
+}
+
+// generateMessageHeaders writes the package-level InjectMessageHeaders and
+// FromMessageHeaders helpers, the message bus counterparts of the
+// propagation helpers. FromMessageHeaders resolves codes with the function
+// written by generateErrorByCode.
+func generateMessageHeaders(g *protogen.GeneratedFile) {
+	carrier := g.QualifiedGoIdent(msgheadersPackage.Ident("Carrier"))
+	g.P("// InjectMessageHeaders writes the code, origin and trace context of err into")
+	g.P("// the headers c of an outgoing message, such as a NATS or Kafka message, so")
+	g.P("// the consumer can reconstruct it with FromMessageHeaders. It reports false")
+	g.P("// when err holds no generated error.")
+	g.P("func InjectMessageHeaders(err error, c ", carrier, ") bool {")
+	g.P("return ", g.QualifiedGoIdent(msgheadersPackage.Ident("Inject")), "(err, c)")
+	g.P("}")
+	g.P()
+	g.P("// FromMessageHeaders reconstructs the error of this package written into the")
+	g.P("// headers c of a consumed message by InjectMessageHeaders. The error reports")
+	g.P("// the UPSTREAM origin and carries the producer's trace context as metadata;")
+	g.P("// errors.As with a *propagation.Error reads the producer's code and origin.")
+	g.P("// It reports false when c carries no error or no value of this package has")
+	g.P("// its code.")
+	g.P("func FromMessageHeaders(c ", carrier, ") (error, bool) {")
+	g.P("return ", g.QualifiedGoIdent(msgheadersPackage.Ident("Raise")), "(c, sphereErrorByCode)")
+	g.P("}")
+	g.P()
+}
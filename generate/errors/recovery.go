@@ -0,0 +1,96 @@
+package errors
+
+import (
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// recoveryPackage is the runtime package of the generated panic recovery
+// helpers.
+const recoveryPackage = protogen.GoImportPath("github.com/go-sphere/protoc-gen-sphere-errors/recovery")
+
+// ValidateRecoverErrors rejects RecoverErrors naming a value that is not an
+// error enum value of files, and several values designated for one Go
+// package.
+func ValidateRecoverErrors(files []*protogen.File, config *Config) error {
+	if len(config.RecoverErrors) == 0 {
+		return nil
+	}
+	values := errorValues(files, config)
+	var problems []Diagnostic
+	byPackage := map[protogen.GoImportPath]string{}
+	for _, name := range config.RecoverErrors {
+		ident, ok := values[name]
+		if !ok {
+			problems = append(problems, diagnosticf("%s is not an error enum value of this request", name))
+			continue
+		}
+		if prev, ok := byPackage[ident.GoImportPath]; ok && prev != name {
+			problems = append(problems, diagnosticf("%s and %s are both designated for Go package %s", prev, name, ident.GoImportPath))
+			continue
+		}
+		byPackage[ident.GoImportPath] = name
+	}
+	if len(problems) > 0 {
+		sortDiagnostics(problems)
+		return &Diagnostics{Summary: "recover_error", Problems: problems}
+	}
+	return nil
+}
+
+// generateRecovery writes the package-level RecoverAsError and
+// RecoverMiddleware converting panics into the error value RecoverErrors
+// designates for the Go package of file. It writes nothing when the package
+// has none.
+func generateRecovery(gen *protogen.Plugin, file *protogen.File, g *protogen.GeneratedFile, config *Config) {
+	values := errorValues(packageFiles(gen, file, config), config)
+	var value protogen.GoIdent
+	for _, name := range config.RecoverErrors {
+		if ident, ok := values[name]; ok {
+			value = ident
+			break
+		}
+	}
+	if value.GoName == "" {
+		return
+	}
+	recovery := func(name string) string {
+		return g.QualifiedGoIdent(recoveryPackage.Ident(name))
+	}
+	designated := g.QualifiedGoIdent(value)
+	g.P("// RecoverAsError converts a panic of the calling function into")
+	g.P("// ", value.GoName, ", joined with the recovery.Panic holding the")
+	g.P("// panic value and its stack, and stores it in *err. Use it as the first deferred call of a function with a")
+	g.P("// named error result:")
+	g.P("//")
+	g.P("//\tdefer RecoverAsError(&err)")
+	g.P("//")
+	g.P("// The error is reported to the observer installed with recovery.SetObserver.")
+	g.P("func RecoverAsError(err *error) {")
+	g.P("if v := recover(); v != nil {")
+	g.P("p := ", recovery("New"), "(v)")
+	g.P("*err = ", recovery("Wrap"), "(", designated, ".Join(p), p)")
+	g.P("}")
+	g.P("}")
+	g.P()
+	g.P("// RecoverMiddleware recovers the panics of next, writing")
+	g.P("// ", value.GoName, " as RecoverAsError builds it, with the")
+	g.P("// ", envelopeName(config), ". The")
+	g.P("// http.ErrAbortHandler panic aborting a response is left to net/http.")
+	g.P("func RecoverMiddleware(next ", g.QualifiedGoIdent(httpPackage.Ident("Handler")), ") ", g.QualifiedGoIdent(httpPackage.Ident("Handler")), " {")
+	g.P("return ", g.QualifiedGoIdent(httpPackage.Ident("HandlerFunc")), "(func(w ", g.QualifiedGoIdent(httpPackage.Ident("ResponseWriter")), ", r *", g.QualifiedGoIdent(httpPackage.Ident("Request")), ") {")
+	g.P("defer func() {")
+	g.P("v := recover()")
+	g.P("if v == nil {")
+	g.P("return")
+	g.P("}")
+	g.P("if v == ", g.QualifiedGoIdent(httpPackage.Ident("ErrAbortHandler")), " {")
+	g.P("panic(v)")
+	g.P("}")
+	g.P("p := ", recovery("New"), "(v)")
+	g.P(httpEncode(g, config), "(w, ", recovery("Wrap"), "(", designated, ".Join(p), p))")
+	g.P("}()")
+	g.P("next.ServeHTTP(w, r)")
+	g.P("})")
+	g.P("}")
+	g.P()
+}
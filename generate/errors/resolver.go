@@ -0,0 +1,58 @@
+package errors
+
+import "google.golang.org/protobuf/compiler/protogen"
+
+// atomicPackage provides the atomic.Pointer holding the message resolver.
+const atomicPackage = protogen.GoImportPath("sync/atomic")
+
+// declaresPackageHelpers reports whether file is the first file being
+// generated into its Go package with error enums. Package-level helpers such
+// as SetMessageResolver are written with that file only, so that several
+// proto files of one Go package do not redeclare them.
+func declaresPackageHelpers(gen *protogen.Plugin, file *protogen.File, config *Config) bool {
+	files := packageFiles(gen, file, config)
+	return len(files) > 0 && files[0] == file
+}
+
+// packageFiles returns the files being generated with error enums into the Go
+// package of file, in request order.
+func packageFiles(gen *protogen.Plugin, file *protogen.File, config *Config) []*protogen.File {
+	importPath := config.outputFor(file).importPath
+	var files []*protogen.File
+	for _, f := range gen.Files {
+		if f.Generate && hasErrorEnums(f.Enums, config) && config.outputFor(f).importPath == importPath {
+			files = append(files, f)
+		}
+	}
+	return files
+}
+
+// generateMessageResolver writes the SetMessageResolver hook consulted by the
+// GetMessage methods of the package.
+func generateMessageResolver(g *protogen.GeneratedFile) {
+	pointer := g.QualifiedGoIdent(atomicPackage.Ident("Pointer"))
+	g.P("// messageResolver is the resolver installed by SetMessageResolver.")
+	g.P("var messageResolver ", pointer, "[func(code int32, def string) string]")
+	g.P()
+	g.P("// SetMessageResolver installs resolver, which the GetMessage methods of the")
+	g.P("// error enums of this package, and so their constructors, call with the error")
+	g.P("// code and the message declared in the proto. It lets operators override")
+	g.P("// user-facing messages, e.g. from a CMS, without redeploying. A nil resolver")
+	g.P("// restores the declared messages. It is safe for concurrent use.")
+	g.P("func SetMessageResolver(resolver func(code int32, def string) string) {")
+	g.P("if resolver == nil {")
+	g.P("messageResolver.Store(nil)")
+	g.P("return")
+	g.P("}")
+	g.P("messageResolver.Store(&resolver)")
+	g.P("}")
+	g.P()
+	g.P("// resolveMessage returns the message of code: def, unless a resolver is")
+	g.P("// installed.")
+	g.P("func resolveMessage(code int32, def string) string {")
+	g.P("if resolver := messageResolver.Load(); resolver != nil {")
+	g.P("return (*resolver)(code, def)")
+	g.P("}")
+	g.P("return def")
+	g.P("}")
+}
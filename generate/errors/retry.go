@@ -0,0 +1,13 @@
+package errors
+
+// retryableStatus reports whether errors with the HTTP status are safe to
+// retry unless configured otherwise: timeouts, rate limiting and unavailable
+// upstreams.
+func retryableStatus(status int32) bool {
+	switch status {
+	case 408, 429, 502, 503, 504:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,206 @@
+package errors
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/internal/template"
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// Runtimes the generated Join helpers can construct errors for.
+const (
+	// RuntimeHTTPX calls Config.NewErrorsFunc, github.com/go-sphere/httpx's
+	// NewError by default.
+	RuntimeHTTPX = "httpx"
+	// RuntimeStdlib builds statuserror.Error values, which only depend on the
+	// standard library.
+	RuntimeStdlib = "stdlib"
+	// RuntimeKratos builds github.com/go-kratos/kratos/v2/errors errors.
+	RuntimeKratos = "kratos"
+	// RuntimeConnect builds connectrpc.com/connect errors.
+	RuntimeConnect = "connect"
+	// RuntimeTyped builds typederror.Error values parametrized over the
+	// error enum, whose Code method returns the enum value.
+	RuntimeTyped = "typed"
+)
+
+// Parameters of a NewErrorsFunc with a custom signature, listed in
+// Config.NewErrorsArgs.
+const (
+	ArgContext = "ctx"
+	ArgStatus  = "status"
+	ArgCode    = "code"
+	ArgMessage = "message"
+	ArgErr     = "err"
+)
+
+// defaultNewErrorsArgs is the signature NewErrorsFunc is called with directly.
+var defaultNewErrorsArgs = []string{ArgStatus, ArgCode, ArgMessage, ArgErr}
+
+// ValidateNewErrorsArgs reports whether args is a valid NewErrorsFunc
+// signature: a non-empty list of distinct ArgContext, ArgStatus, ArgCode,
+// ArgMessage and ArgErr.
+func ValidateNewErrorsArgs(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("empty new_errors_func signature")
+	}
+	for i, arg := range args {
+		switch arg {
+		case ArgContext, ArgStatus, ArgCode, ArgMessage, ArgErr:
+		default:
+			return fmt.Errorf("invalid new_errors_func argument %q, expected %s, %s, %s, %s or %s", arg, ArgContext, ArgStatus, ArgCode, ArgMessage, ArgErr)
+		}
+		if slices.Contains(args[:i], arg) {
+			return fmt.Errorf("duplicate new_errors_func argument %q", arg)
+		}
+	}
+	return nil
+}
+
+// ParseRuntime parses a runtime parameter, either "kratos" (applies to every
+// proto package) or "proto.package=kratos".
+func ParseRuntime(s string) (string, string, error) {
+	pkg, runtime := "", s
+	if i := strings.LastIndex(s, "="); i >= 0 {
+		pkg, runtime = s[:i], s[i+1:]
+	}
+	runtime = strings.TrimSpace(runtime)
+	if err := ValidateRuntime(runtime); err != nil {
+		return "", "", err
+	}
+	return strings.TrimSpace(pkg), runtime, nil
+}
+
+// runtime returns the runtime of the error enums of proto package pkg.
+func (c *Config) runtime(pkg string) string {
+	if runtime, ok := c.Runtimes[pkg]; ok {
+		return runtime
+	}
+	return c.Runtime
+}
+
+// adaptsNewErrorsFunc reports whether the Join helpers of proto package pkg
+// call NewErrorsFunc through a generated newError method, because its
+// signature differs from the default one.
+func (c *Config) adaptsNewErrorsFunc(pkg string) bool {
+	runtime := c.runtime(pkg)
+	return (runtime == "" || runtime == RuntimeHTTPX) && !c.MinimalRuntime &&
+		len(c.NewErrorsArgs) > 0 && !slices.Equal(c.NewErrorsArgs, defaultNewErrorsArgs)
+}
+
+// Import paths of the runtime targets.
+const (
+	statusErrorPackage  = protogen.GoImportPath("github.com/go-sphere/protoc-gen-sphere-errors/statuserror")
+	kratosErrorsPackage = protogen.GoImportPath("github.com/go-kratos/kratos/v2/errors")
+	connectPackage      = protogen.GoImportPath("connectrpc.com/connect")
+	typedErrorPackage   = protogen.GoImportPath("github.com/go-sphere/protoc-gen-sphere-errors/typederror")
+	strconvPackage      = protogen.GoImportPath("strconv")
+)
+
+// adapterNewErrorsFunc is the NewErrorsFunc of runtimes served by a generated
+// newError method, which needs the receiver to reach the enum's reason or gRPC
+// code.
+const adapterNewErrorsFunc = "e.newError"
+
+// ValidateRuntime reports whether runtime names a supported target. The empty
+// string selects RuntimeHTTPX.
+func ValidateRuntime(runtime string) error {
+	switch runtime {
+	case "", RuntimeHTTPX, RuntimeStdlib, RuntimeKratos, RuntimeConnect, RuntimeTyped:
+		return nil
+	default:
+		return fmt.Errorf("invalid runtime %q, expected %s, %s, %s, %s or %s", runtime, RuntimeHTTPX, RuntimeStdlib, RuntimeKratos, RuntimeConnect, RuntimeTyped)
+	}
+}
+
+// qualifyNewErrorsFunc returns the constructor the generated Join helpers of
+// proto package pkg call for its runtime.
+func qualifyNewErrorsFunc(g *protogen.GeneratedFile, config *Config, pkg string) string {
+	switch config.runtime(pkg) {
+	case RuntimeStdlib:
+		return g.QualifiedGoIdent(statusErrorPackage.Ident("New"))
+	case RuntimeKratos, RuntimeConnect, RuntimeTyped:
+		return adapterNewErrorsFunc
+	default:
+		if config.minimalRuntime(pkg) {
+			return minimalNewErrorsFunc
+		}
+		if config.adaptsNewErrorsFunc(pkg) {
+			return adapterNewErrorsFunc
+		}
+		return g.QualifiedGoIdent(config.NewErrorsFunc)
+	}
+}
+
+// qualifyAdapter fills in the identifiers of the newError adapter method for
+// runtimes that need one, ew being an enum of proto package pkg.
+func qualifyAdapter(ew *template.ErrorWrapper, g *protogen.GeneratedFile, config *Config, pkg string) {
+	if config.adaptsNewErrorsFunc(pkg) {
+		ew.Adapter = &template.AdapterIdents{
+			Runtime: RuntimeHTTPX,
+			New:     g.QualifiedGoIdent(config.NewErrorsFunc),
+			Args:    strings.Join(config.NewErrorsArgs, ", "),
+		}
+		if slices.Contains(config.NewErrorsArgs, ArgContext) {
+			ew.Adapter.Context = g.QualifiedGoIdent(contextPackage.Ident("Context"))
+			ew.Adapter.Background = g.QualifiedGoIdent(contextPackage.Ident("Background"))
+		}
+		return
+	}
+	switch config.runtime(pkg) {
+	case RuntimeKratos:
+		ew.Adapter = &template.AdapterIdents{
+			Runtime: RuntimeKratos,
+			New:     g.QualifiedGoIdent(kratosErrorsPackage.Ident("New")),
+			Itoa:    g.QualifiedGoIdent(strconvPackage.Ident("Itoa")),
+		}
+	case RuntimeConnect:
+		ew.Adapter = &template.AdapterIdents{
+			Runtime:     RuntimeConnect,
+			New:         g.QualifiedGoIdent(connectPackage.Ident("NewError")),
+			CodeType:    g.QualifiedGoIdent(connectPackage.Ident("Code")),
+			UnknownCode: g.QualifiedGoIdent(connectPackage.Ident("CodeUnknown")),
+			StatusError: g.QualifiedGoIdent(statusErrorPackage.Ident("New")),
+			Itoa:        g.QualifiedGoIdent(strconvPackage.Ident("Itoa")),
+
+			Error:          g.QualifiedGoIdent(connectPackage.Ident("Error")),
+			NewErrorDetail: g.QualifiedGoIdent(connectPackage.Ident("NewErrorDetail")),
+			ErrorInfo:      g.QualifiedGoIdent(errdetailsPackage.Ident("ErrorInfo")),
+		}
+		if config.helpLinks() {
+			ew.Adapter.Help = g.QualifiedGoIdent(errdetailsPackage.Ident("Help"))
+			ew.Adapter.HelpLink = g.QualifiedGoIdent(errdetailsPackage.Ident("Help_Link"))
+		}
+		for _, info := range ew.Errors {
+			info.RuntimeCodeIdent = g.QualifiedGoIdent(connectPackage.Ident(connectCodeGoName(info.GRPCCode)))
+		}
+	case RuntimeTyped:
+		ew.Adapter = &template.AdapterIdents{
+			Runtime: RuntimeTyped,
+			New:     g.QualifiedGoIdent(typedErrorPackage.Ident("New")),
+			Error:   g.QualifiedGoIdent(typedErrorPackage.Ident("Error")),
+		}
+	}
+}
+
+// qualifyKratos fills in the identifiers of the kratos compatibility helpers
+// of ew.
+func qualifyKratos(ew *template.ErrorWrapper, g *protogen.GeneratedFile) {
+	ew.Kratos = &template.KratosIdents{
+		New:       g.QualifiedGoIdent(kratosErrorsPackage.Ident("New")),
+		FromError: g.QualifiedGoIdent(kratosErrorsPackage.Ident("FromError")),
+		Error:     g.QualifiedGoIdent(kratosErrorsPackage.Ident("Error")),
+		Sprintf:   g.QualifiedGoIdent(fmtPackage.Ident("Sprintf")),
+	}
+}
+
+// connectCodeGoName returns the connect.Code identifier of a canonical gRPC
+// code name. connect has no code for OK, which maps to CodeUnknown.
+func connectCodeGoName(grpcCode string) string {
+	if grpcCode == "OK" {
+		return "CodeUnknown"
+	}
+	return "Code" + grpcCodeGoNames[grpcCode]
+}
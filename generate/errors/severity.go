@@ -0,0 +1,120 @@
+package errors
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/internal/template"
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// Severity levels of error values.
+const (
+	SeverityInfo     = "INFO"
+	SeverityWarn     = "WARN"
+	SeverityError    = "ERROR"
+	SeverityCritical = "CRITICAL"
+)
+
+// slogPackage is the standard library "log/slog" package, used by the
+// generated Severity and LogValue methods.
+const slogPackage = protogen.GoImportPath("log/slog")
+
+// severityFromStatus returns the default severity of an HTTP status: ERROR
+// for server errors, WARN for timeouts and rate limiting, and INFO for the
+// remaining client errors, which are expected in normal operation.
+func severityFromStatus(status int32) string {
+	switch {
+	case status >= 500:
+		return SeverityError
+	case status == 408 || status == 429:
+		return SeverityWarn
+	default:
+		return SeverityInfo
+	}
+}
+
+// ParseSeverity parses a severity parameter of the form "name=LEVEL", where
+// name is a fully-qualified enum or enum value name and LEVEL one of INFO,
+// WARN, ERROR or CRITICAL.
+func ParseSeverity(s string) (string, string, error) {
+	name, level, ok := strings.Cut(s, "=")
+	name, level = strings.TrimSpace(name), strings.ToUpper(strings.TrimSpace(level))
+	if !ok || name == "" {
+		return "", "", fmt.Errorf("invalid severity %q, expected 'name=LEVEL'", s)
+	}
+	switch level {
+	case SeverityInfo, SeverityWarn, SeverityError, SeverityCritical:
+		return name, level, nil
+	default:
+		return "", "", fmt.Errorf("invalid severity %q, expected INFO, WARN, ERROR or CRITICAL", s)
+	}
+}
+
+// randPackage provides the random numbers the generated ShouldLog methods
+// sample occurrences with.
+const randPackage = protogen.GoImportPath("math/rand/v2")
+
+// ParseLogSampleRate parses a log_sample_rate parameter of the form
+// "name=RATE", where name is a fully-qualified enum or enum value name and
+// RATE the fraction of occurrences to log, from 0 to 1.
+func ParseLogSampleRate(s string) (string, float64, error) {
+	name, rate, ok := strings.Cut(s, "=")
+	name, rate = strings.TrimSpace(name), strings.TrimSpace(rate)
+	if !ok || name == "" {
+		return "", 0, fmt.Errorf("invalid log sample rate %q, expected 'name=RATE'", s)
+	}
+	r, err := strconv.ParseFloat(rate, 64)
+	if err != nil || r < 0 || r > 1 {
+		return "", 0, fmt.Errorf("invalid log sample rate %q, expected a rate from 0 to 1", s)
+	}
+	return name, r, nil
+}
+
+// logSampleRate returns the Go literal of the log sample rate of the enum
+// value value of enum, "1" when none is configured.
+func (c *Config) logSampleRate(enum, value string) string {
+	rate, ok := c.LogSampleRates[value]
+	if !ok {
+		rate, ok = c.LogSampleRates[enum]
+	}
+	if !ok || rate >= 1 {
+		return "1"
+	}
+	return strconv.FormatFloat(rate, 'g', -1, 64)
+}
+
+// severityLevel returns the log/slog expression of a severity level, given the
+// qualified slog.Level* identifiers. slog has no critical level, so CRITICAL is
+// rendered four steps above LevelError, the spacing slog uses between levels.
+func severityLevel(level, info, warn, errLevel string) string {
+	switch level {
+	case SeverityWarn:
+		return warn
+	case SeverityError:
+		return errLevel
+	case SeverityCritical:
+		return errLevel + " + 4"
+	default:
+		return info
+	}
+}
+
+// qualifyLog fills in the log/slog identifiers of ew so the template emits the
+// Severity and LogValue methods.
+func qualifyLog(ew *template.ErrorWrapper, g *protogen.GeneratedFile) {
+	ew.Log = &template.LogIdents{
+		Level:      g.QualifiedGoIdent(slogPackage.Ident("Level")),
+		ErrorLevel: g.QualifiedGoIdent(slogPackage.Ident("LevelError")),
+		Value:      g.QualifiedGoIdent(slogPackage.Ident("Value")),
+		GroupValue: g.QualifiedGoIdent(slogPackage.Ident("GroupValue")),
+		Int:        g.QualifiedGoIdent(slogPackage.Ident("Int")),
+		String:     g.QualifiedGoIdent(slogPackage.Ident("String")),
+	}
+	info := g.QualifiedGoIdent(slogPackage.Ident("LevelInfo"))
+	warn := g.QualifiedGoIdent(slogPackage.Ident("LevelWarn"))
+	for _, e := range ew.Errors {
+		e.SeverityLevel = severityLevel(e.Severity, info, warn, ew.Log.ErrorLevel)
+	}
+}
@@ -0,0 +1,40 @@
+package errors
+
+import (
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// sloHelpers reports whether the error enums get SLOExempt methods and their
+// packages IsSLOExempt: when SLOExemptValues lists a name, or OptionsType
+// declares a slo_exempt field.
+func (c *Config) sloHelpers() bool {
+	return len(c.SLOExemptValues) > 0 || c.custom != nil && c.custom.sloExempt != nil
+}
+
+// sloExempt reports whether v, a value of the enum named enum, is exempt from
+// the SLO: by the slo_exempt field of a custom OptionsType, which wins, or by
+// SLOExemptValues listing v or its enum.
+func (c *Config) sloExempt(enum string, v *protogen.EnumValue) bool {
+	if c.custom != nil && c.custom.value != nil && c.custom.sloExempt != nil {
+		if ext, ok := c.custom.extension(v.Desc.Options(), c.custom.value); ok {
+			if m := ext.Message(); m.Has(c.custom.sloExempt) {
+				return m.Get(c.custom.sloExempt).Bool()
+			}
+		}
+	}
+	return c.SLOExemptValues[string(v.Desc.FullName())] || c.SLOExemptValues[enum]
+}
+
+// generateSLOHelpers writes the package-level IsSLOExempt, classifying any
+// error by the SLOExempt method of the generated errors of its chain.
+func generateSLOHelpers(g *protogen.GeneratedFile) {
+	g.P("// IsSLOExempt reports whether err is an expected error that burns no error")
+	g.P("// budget, such as a business rule violation: the first error of its chain")
+	g.P("// with an SLOExempt method, generated with slo_exempt in this package or any")
+	g.P("// other, is exempt. Any other error counts against the SLO.")
+	g.P("func IsSLOExempt(err error) bool {")
+	g.P("var e interface{ SLOExempt() bool }")
+	g.P("return ", g.QualifiedGoIdent(errorsPackage.Ident("As")), "(err, &e) && e.SLOExempt()")
+	g.P("}")
+	g.P()
+}
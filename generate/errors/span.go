@@ -0,0 +1,135 @@
+package errors
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// Import paths of the OpenTelemetry packages RecordToSpan uses, next to
+// otelTracePackage.
+const (
+	otelCodesPackage     = protogen.GoImportPath("go.opentelemetry.io/otel/codes")
+	otelAttributePackage = protogen.GoImportPath("go.opentelemetry.io/otel/attribute")
+)
+
+// defaultOTelEvent is the span event name of values declaring none.
+const defaultOTelEvent = "sphere.error"
+
+// ParseOTelEvent parses an otel_event parameter of the form 'name=event',
+// name being a fully-qualified enum or enum value and event the name of the
+// OpenTelemetry span event recording its errors.
+func ParseOTelEvent(s string) (string, string, error) {
+	name, event, ok := strings.Cut(s, "=")
+	name, event = strings.TrimSpace(name), strings.TrimSpace(event)
+	if !ok || name == "" || event == "" {
+		return "", "", fmt.Errorf("invalid otel_event %q, expected 'name=event'", s)
+	}
+	return name, event, nil
+}
+
+// ParseSpanError parses a span_error parameter of the form 'name=true' or
+// 'name=false', name being a fully-qualified enum or enum value.
+func ParseSpanError(s string) (string, bool, error) {
+	name, value, ok := strings.Cut(s, "=")
+	name = strings.TrimSpace(name)
+	marks, err := strconv.ParseBool(strings.TrimSpace(value))
+	if !ok || name == "" || err != nil {
+		return "", false, fmt.Errorf("invalid span_error %q, expected 'name=true' or 'name=false'", s)
+	}
+	return name, marks, nil
+}
+
+// spanHelpers reports whether the error enums get OTelEventName and
+// MarksSpanError methods and their packages RecordToSpan: with SpanHelpers,
+// when OTelEvents or SpanErrors list a name, or when OptionsType declares an
+// otel_event or span_error field.
+func (c *Config) spanHelpers() bool {
+	return c.SpanHelpers || len(c.OTelEvents) > 0 || len(c.SpanErrors) > 0 ||
+		c.custom != nil && (c.custom.otelEvent != nil || c.custom.spanError != nil)
+}
+
+// otelEvent returns the span event name of v, a value of the enum named
+// enum: by the otel_event field of a custom OptionsType, which wins, or else
+// by OTelEvents naming v or, failing that, its enum, and defaultOTelEvent
+// otherwise.
+func (c *Config) otelEvent(enum string, v *protogen.EnumValue) string {
+	if c.custom != nil && c.custom.value != nil && c.custom.otelEvent != nil {
+		if ext, ok := c.custom.extension(v.Desc.Options(), c.custom.value); ok {
+			if m := ext.Message(); m.Has(c.custom.otelEvent) {
+				if event := strings.TrimSpace(m.Get(c.custom.otelEvent).String()); event != "" {
+					return event
+				}
+			}
+		}
+	}
+	if event, ok := c.OTelEvents[string(v.Desc.FullName())]; ok {
+		return event
+	}
+	if event, ok := c.OTelEvents[enum]; ok {
+		return event
+	}
+	return defaultOTelEvent
+}
+
+// spanError reports whether v, a value of the enum named enum with the HTTP
+// status status, marks the span recording it as failed: by the span_error
+// field of a custom OptionsType, which wins, or else by SpanErrors naming v
+// or, failing that, its enum. By default only server errors do, as the
+// OpenTelemetry HTTP conventions leave the span of a 4xx response unset.
+func (c *Config) spanError(enum string, v *protogen.EnumValue, status int32) bool {
+	if c.custom != nil && c.custom.value != nil && c.custom.spanError != nil {
+		if ext, ok := c.custom.extension(v.Desc.Options(), c.custom.value); ok {
+			if m := ext.Message(); m.Has(c.custom.spanError) {
+				return m.Get(c.custom.spanError).Bool()
+			}
+		}
+	}
+	if marks, ok := c.SpanErrors[string(v.Desc.FullName())]; ok {
+		return marks
+	}
+	if marks, ok := c.SpanErrors[enum]; ok {
+		return marks
+	}
+	return status >= 500
+}
+
+// generateSpanHelpers writes the package-level RecordToSpan, recording any
+// error on the OpenTelemetry span of a context with the OTelEventName and
+// MarksSpanError methods of the generated errors of its chain.
+func generateSpanHelpers(g *protogen.GeneratedFile) {
+	codes := func(name string) string { return g.QualifiedGoIdent(otelCodesPackage.Ident(name)) }
+	attribute := func(name string) string { return g.QualifiedGoIdent(otelAttributePackage.Ident(name)) }
+	g.P("// RecordToSpan records err on the OpenTelemetry span of ctx: the first error")
+	g.P("// of its chain generated with span helpers, in this package or any other,")
+	g.P("// as its OTelEventName event carrying its code, setting the span status to")
+	g.P("// Error when it MarksSpanError. Any other error is recorded with")
+	g.P("// RecordError and fails the span. A nil err records nothing.")
+	g.P("func RecordToSpan(ctx ", g.QualifiedGoIdent(contextPackage.Ident("Context")), ", err error) {")
+	g.P("if err == nil {")
+	g.P("return")
+	g.P("}")
+	g.P("span := ", g.QualifiedGoIdent(otelTracePackage.Ident("SpanFromContext")), "(ctx)")
+	g.P("var e interface {")
+	g.P("error")
+	g.P("GetCode() int32")
+	g.P("OTelEventName() string")
+	g.P("MarksSpanError() bool")
+	g.P("}")
+	g.P("if !", g.QualifiedGoIdent(errorsPackage.Ident("As")), "(err, &e) {")
+	g.P("span.RecordError(err)")
+	g.P("span.SetStatus(", codes("Error"), ", err.Error())")
+	g.P("return")
+	g.P("}")
+	g.P("span.AddEvent(e.OTelEventName(), ", g.QualifiedGoIdent(otelTracePackage.Ident("WithAttributes")), "(")
+	g.P(attribute("Int64"), `("sphere.error.code", int64(e.GetCode())),`)
+	g.P(attribute("String"), `("sphere.error.message", err.Error()),`)
+	g.P("))")
+	g.P("if e.MarksSpanError() {")
+	g.P("span.SetStatus(", codes("Error"), ", e.Error())")
+	g.P("}")
+	g.P("}")
+	g.P()
+}
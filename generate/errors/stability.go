@@ -0,0 +1,162 @@
+package errors
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Stabilities of an error value, set with the stability parameter or the
+// stability field of a custom OptionsType. Experimental values are left out
+// of catalogs unless IncludeExperimental is set, so their codes can change
+// without breaking a baseline.
+const (
+	StabilityStable       = "STABLE"
+	StabilityBeta         = "BETA"
+	StabilityExperimental = "EXPERIMENTAL"
+)
+
+// ParseStability parses a stability parameter of the form 'name=STABILITY',
+// name being a fully-qualified enum or enum value and STABILITY one of
+// STABLE, BETA or EXPERIMENTAL.
+func ParseStability(s string) (string, string, error) {
+	name, stability, ok := strings.Cut(s, "=")
+	name, stability = strings.TrimSpace(name), strings.ToUpper(strings.TrimSpace(stability))
+	if !ok || name == "" || !validStability(stability) {
+		return "", "", fmt.Errorf("invalid stability %q, expected 'name=STABLE', 'name=BETA' or 'name=EXPERIMENTAL'", s)
+	}
+	return name, stability, nil
+}
+
+// validStability reports whether stability is STABLE, BETA or EXPERIMENTAL.
+func validStability(stability string) bool {
+	return stability == StabilityStable || stability == StabilityBeta || stability == StabilityExperimental
+}
+
+// stabilityHelpers reports whether the error enums get Stability methods:
+// when Stabilities lists a name, or OptionsType declares a stability field.
+func (c *Config) stabilityHelpers() bool {
+	return len(c.Stabilities) > 0 || c.custom != nil && c.custom.stability != nil
+}
+
+// stabilityOption returns the stability declared for v by the stability field
+// of a custom OptionsType, uppercased, or "" when it declares none. An enum
+// field is read by the name of its value.
+func (c *Config) stabilityOption(v *protogen.EnumValue) string {
+	if c.custom == nil || c.custom.value == nil || c.custom.stability == nil {
+		return ""
+	}
+	ext, ok := c.custom.extension(v.Desc.Options(), c.custom.value)
+	if !ok {
+		return ""
+	}
+	m, fd := ext.Message(), c.custom.stability
+	if !m.Has(fd) {
+		return ""
+	}
+	if fd.Kind() != protoreflect.EnumKind {
+		return strings.ToUpper(m.Get(fd).String())
+	}
+	if ev := fd.Enum().Values().ByNumber(m.Get(fd).Enum()); ev != nil {
+		return strings.ToUpper(string(ev.Name()))
+	}
+	return ""
+}
+
+// stability returns the stability of v, a value of the enum named enum: by
+// the stability field of a custom OptionsType, which wins, or else by
+// Stabilities naming v or, failing that, its enum. Values are stable by
+// default.
+func (c *Config) stability(enum string, v *protogen.EnumValue) string {
+	if stability := c.stabilityOption(v); stability != "" {
+		return stability
+	}
+	if stability, ok := c.Stabilities[string(v.Desc.FullName())]; ok {
+		return stability
+	}
+	if stability, ok := c.Stabilities[enum]; ok {
+		return stability
+	}
+	return StabilityStable
+}
+
+// experimentalCode returns the public code of an experimental value, code
+// wrapped in ExperimentalCodePrefix and ExperimentalCodeSuffix.
+func (c *Config) experimentalCode(code string) string {
+	return c.ExperimentalCodePrefix + code + c.ExperimentalCodeSuffix
+}
+
+// ValidateStabilities rejects stabilities read from the stability field of a
+// custom OptionsType other than STABLE, BETA and EXPERIMENTAL. All offending
+// values are reported together in a single error.
+func ValidateStabilities(files []*protogen.File, config *Config) error {
+	if config.custom == nil || config.custom.stability == nil {
+		return nil
+	}
+	var problems []Diagnostic
+	for _, f := range files {
+		for _, enum := range f.Enums {
+			if _, ok := config.defaultStatus(enum); !ok || !config.selects(enum) {
+				continue
+			}
+			for _, v := range enum.Values {
+				if stability := config.stabilityOption(v); stability != "" && !validStability(stability) {
+					problems = append(problems, valueDiagnostic(v, "stability %q of %s.%s (%s)", stability, enum.Desc.FullName(), v.Desc.Name(), sourceLocation(v)))
+				}
+			}
+		}
+	}
+	if len(problems) > 0 {
+		sortDiagnostics(problems)
+		return &Diagnostics{Summary: "invalid stabilities, expected STABLE, BETA or EXPERIMENTAL", Problems: problems}
+	}
+	return nil
+}
+
+// prereleaseVersion matches the trailing version of the proto package of a
+// service not yet stable, such as v1alpha or v2beta1.
+var prereleaseVersion = regexp.MustCompile(`^v[0-9]+(alpha|beta)[0-9]*$`)
+
+// StabilityWarnings returns a warning per method of a stable service of the
+// files marked for generation declaring with MethodErrorsOption that it may
+// return an experimental value, in method order. Services are stable unless
+// the last component of their proto package is a prerelease version such as
+// v1beta1.
+func StabilityWarnings(files []*protogen.File, config *Config) []string {
+	if config.MethodErrorsOption == "" {
+		return nil
+	}
+	experimental := map[string]bool{}
+	for _, f := range files {
+		for _, ew := range ErrorEnums(f, config) {
+			for _, info := range append(ew.Errors[:len(ew.Errors):len(ew.Errors)], ew.Aliases...) {
+				if info.Stability == StabilityExperimental {
+					experimental[valueFullName(ew.FullName, info.Value)] = true
+				}
+			}
+		}
+	}
+	if len(experimental) == 0 {
+		return nil
+	}
+	var warnings []string
+	names := methodErrorNames(files, config)
+	for _, f := range files {
+		if !f.Generate || prereleaseVersion.MatchString(string(f.Desc.Package().Name())) {
+			continue
+		}
+		for _, service := range f.Services {
+			for _, method := range service.Methods {
+				for _, value := range possibleErrors(config.declaredMethodErrors(method), names) {
+					if experimental[value] {
+						warnings = append(warnings, fmt.Sprintf("%s of stable service %s (%s) may return experimental error %s", method.Desc.Name(), service.Desc.FullName(), methodLocation(method), value))
+					}
+				}
+			}
+		}
+	}
+	return warnings
+}
@@ -0,0 +1,63 @@
+package errors
+
+import (
+	"strconv"
+
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// Import paths used by the generated status proto converters.
+const (
+	grpcErrorsPackage  = protogen.GoImportPath("github.com/go-sphere/protoc-gen-sphere-errors/grpcerrors")
+	statusProtoPackage = protogen.GoImportPath("google.golang.org/genproto/googleapis/rpc/status")
+)
+
+// generateStatusProto writes the package-level ToStatusProto and
+// FromStatusProto converters. FromStatusProto resolves codes with the
+// function written by generateErrorByCode.
+func generateStatusProto(g *protogen.GeneratedFile) {
+	statusType := g.QualifiedGoIdent(statusProtoPackage.Ident("Status"))
+	g.P("// ToStatusProto converts err into a google.rpc.Status message carrying its")
+	g.P("// code, metadata and typed details, for errors serialized outside of gRPC.")
+	g.P("// It returns nil for a nil error.")
+	g.P("func ToStatusProto(err error) *", statusType, " {")
+	g.P("return ", g.QualifiedGoIdent(grpcErrorsPackage.Ident("ToStatusProto")), "(err)")
+	g.P("}")
+	g.P()
+	g.P("// FromStatusProto rebuilds the error of this package s was converted from by")
+	g.P("// ToStatusProto, with its metadata and typed details. A status whose code no")
+	g.P("// value of this package has becomes a plain gRPC status error. It returns nil")
+	g.P("// for a nil or OK status.")
+	g.P("func FromStatusProto(s *", statusType, ") error {")
+	g.P("return ", g.QualifiedGoIdent(grpcErrorsPackage.Ident("FromStatusProto")), "(s, sphereErrorByCode)")
+	g.P("}")
+	g.P()
+}
+
+// generateErrorByCode writes sphereErrorByCode, the lookup of the error values
+// generated into the Go package of file by code, used by the converters of
+// the package.
+func generateErrorByCode(gen *protogen.Plugin, file *protogen.File, g *protogen.GeneratedFile, config *Config) {
+	g.P("// sphereErrorByCode returns the error value of this package whose code is code.")
+	g.P("func sphereErrorByCode(code int32) (error, bool) {")
+	g.P("switch code {")
+	// A code shared by two values resolves to the first, as registry
+	// lookups do.
+	seen := map[int32]bool{}
+	for _, f := range packageFiles(gen, file, config) {
+		for _, ew := range ErrorEnums(f, config) {
+			for _, info := range ew.Errors {
+				if info.Number == 0 || seen[info.Code] {
+					continue
+				}
+				seen[info.Code] = true
+				g.P("case ", strconv.Itoa(int(info.Code)), ":")
+				g.P("return ", ew.Name, "_", info.Value, ", true")
+			}
+		}
+	}
+	g.P("}")
+	g.P("return nil, false")
+	g.P("}")
+	g.P()
+}
@@ -0,0 +1,68 @@
+package errors
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// StatusRange assigns Status to the values of an error enum numbered within
+// Range that declare no status of their own.
+type StatusRange struct {
+	Range  CodeRange
+	Status int32
+}
+
+// ParseStatusRange parses a status_range parameter of the form
+// "proto.package.Enum:N-M=STATUS", or "proto.package.Enum:N=STATUS" for a
+// single number.
+func ParseStatusRange(s string) (string, StatusRange, error) {
+	enum, rule, ok := strings.Cut(s, ":")
+	enum = strings.TrimSpace(enum)
+	numbers, status, ok2 := strings.Cut(rule, "=")
+	if !ok || !ok2 || enum == "" {
+		return "", StatusRange{}, fmt.Errorf("invalid status range %q, expected 'proto.package.Enum:N-M=STATUS'", s)
+	}
+	r, err := ParseCodeRange(numbers)
+	if err != nil {
+		return "", StatusRange{}, fmt.Errorf("invalid status range %q: %w", s, err)
+	}
+	n, err := strconv.ParseInt(strings.TrimSpace(status), 10, 32)
+	if err != nil || !validHTTPStatus(int32(n)) {
+		return "", StatusRange{}, fmt.Errorf("invalid status range %q, expected an HTTP status from 100 to 599", s)
+	}
+	return enum, StatusRange{Range: r, Status: int32(n)}, nil
+}
+
+// ValidateStatusRanges rejects overlapping StatusRanges of one enum, which
+// would leave the status of the values numbered in both ambiguous.
+func ValidateStatusRanges(config *Config) error {
+	var problems []Diagnostic
+	for enum, ranges := range config.StatusRanges {
+		for i, a := range ranges {
+			for _, b := range ranges[i+1:] {
+				if a.Range.Start <= b.Range.End && b.Range.Start <= a.Range.End {
+					d := diagnosticf("%s: %s and %s overlap", enum, a.Range, b.Range)
+					d.Enum = enum
+					problems = append(problems, d)
+				}
+			}
+		}
+	}
+	if len(problems) > 0 {
+		sortDiagnostics(problems)
+		return &Diagnostics{Summary: "status_range", Problems: problems}
+	}
+	return nil
+}
+
+// rangeStatus returns the status of the value numbered number of the enum
+// named enum from its StatusRanges, falling back to defaultStatus.
+func (c *Config) rangeStatus(enum string, number, defaultStatus int32) int32 {
+	for _, r := range c.StatusRanges[enum] {
+		if r.Range.Contains(number) {
+			return r.Status
+		}
+	}
+	return defaultStatus
+}
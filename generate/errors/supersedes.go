@@ -0,0 +1,124 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// ParseSupersedes parses a supersedes parameter of the form
+// "new.package.VALUE=old.package.VALUE".
+func ParseSupersedes(s string) (string, string, error) {
+	value, superseded, ok := strings.Cut(s, "=")
+	value, superseded = strings.TrimSpace(value), strings.TrimSpace(superseded)
+	if !ok || value == "" || superseded == "" {
+		return "", "", fmt.Errorf("invalid supersedes %q, expected 'new.package.VALUE=old.package.VALUE'", s)
+	}
+	return value, superseded, nil
+}
+
+// errorValues returns the Go constants of the error enum values, aliases
+// included, of files in the package their errors are generated into, by
+// fully-qualified proto name.
+func errorValues(files []*protogen.File, config *Config) map[string]protogen.GoIdent {
+	values := map[string]protogen.GoIdent{}
+	for _, f := range files {
+		importPath := config.outputFor(f).importPath
+		for _, ew := range ErrorEnums(f, config) {
+			for _, info := range append(ew.Errors[:len(ew.Errors):len(ew.Errors)], ew.Aliases...) {
+				values[valueFullName(ew.FullName, info.Value)] = importPath.Ident(ew.Name + "_" + info.Value)
+			}
+		}
+	}
+	return values
+}
+
+// ValidateSupersedes rejects Supersedes naming a value that is not an error
+// enum value of files, or a value superseding itself.
+func ValidateSupersedes(files []*protogen.File, config *Config) error {
+	if len(config.Supersedes) == 0 {
+		return nil
+	}
+	values := errorValues(files, config)
+	var problems []Diagnostic
+	for value, superseded := range config.Supersedes {
+		for _, name := range []string{value, superseded} {
+			if _, ok := values[name]; !ok {
+				problems = append(problems, diagnosticf("%s (in %s=%s) is not an error enum value of this request", name, value, superseded))
+			}
+		}
+		if value == superseded {
+			problems = append(problems, diagnosticf("%s supersedes itself", value))
+		}
+	}
+	if len(problems) > 0 {
+		sortDiagnostics(problems)
+		return &Diagnostics{Summary: "supersedes", Problems: problems}
+	}
+	return nil
+}
+
+// generateSupersedes writes the package-level ToSuperseded and FromSuperseded
+// translating between the error values of this package and the values of
+// older API versions they supersede. It writes nothing when no value of the
+// package supersedes another.
+func generateSupersedes(gen *protogen.Plugin, file *protogen.File, g *protogen.GeneratedFile, config *Config) {
+	values := errorValues(gen.Files, config)
+	importPath := config.outputFor(file).importPath
+	// Pairs follow the declaration order of the superseding values.
+	var pairs [][2]protogen.GoIdent
+	for _, f := range packageFiles(gen, file, config) {
+		for _, ew := range ErrorEnums(f, config) {
+			for _, info := range append(ew.Errors[:len(ew.Errors):len(ew.Errors)], ew.Aliases...) {
+				superseded, ok := config.Supersedes[valueFullName(ew.FullName, info.Value)]
+				if !ok {
+					continue
+				}
+				old, ok := values[superseded]
+				if !ok {
+					continue
+				}
+				pairs = append(pairs, [2]protogen.GoIdent{importPath.Ident(ew.Name + "_" + info.Value), old})
+			}
+		}
+	}
+	if len(pairs) == 0 {
+		return
+	}
+	errorsIs := g.QualifiedGoIdent(errorsPackage.Ident("Is"))
+	g.P("// ToSuperseded translates err for clients of older API versions: when its")
+	g.P("// chain holds an error value of this package superseding a value of an older")
+	g.P("// version, it returns that older value joined with err and true. Gateways")
+	g.P("// serving older clients down-convert errors with it. Other errors are")
+	g.P("// returned unchanged with false.")
+	g.P("func ToSuperseded(err error) (error, bool) {")
+	g.P("switch {")
+	for _, p := range pairs {
+		g.P("case ", errorsIs, "(err, ", g.QualifiedGoIdent(p[0]), "):")
+		g.P("return ", g.QualifiedGoIdent(p[1]), ".Join(err), true")
+	}
+	g.P("}")
+	g.P("return err, false")
+	g.P("}")
+	g.P()
+	g.P("// FromSuperseded translates err from older API versions: when its chain holds")
+	g.P("// an error value superseded by a value of this package, it returns that value")
+	g.P("// joined with err and true. A value superseded by several translates to the")
+	g.P("// first declared. Other errors are returned unchanged with false.")
+	g.P("func FromSuperseded(err error) (error, bool) {")
+	g.P("switch {")
+	seen := map[protogen.GoIdent]bool{}
+	for _, p := range pairs {
+		if seen[p[1]] {
+			continue
+		}
+		seen[p[1]] = true
+		g.P("case ", errorsIs, "(err, ", g.QualifiedGoIdent(p[1]), "):")
+		g.P("return ", g.QualifiedGoIdent(p[0]), ".Join(err), true")
+	}
+	g.P("}")
+	g.P("return err, false")
+	g.P("}")
+	g.P()
+}
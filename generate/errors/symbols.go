@@ -0,0 +1,76 @@
+package errors
+
+import (
+	"fmt"
+
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/internal/template"
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// ValidateSymbols rejects error enums of the files marked for generation that
+// would declare the same package-level Go identifier in one Go package, e.g.
+// two UserError enums of different proto packages generated into a shared
+// output_package. Each enum is generated only with the file defining it, so
+// files importing an error enum never redeclare it; they use the Go package
+// of the defining file instead. All collisions are reported together in a
+// single error.
+func ValidateSymbols(files []*protogen.File, config *Config) error {
+	var problems []Diagnostic
+	seen := map[protogen.GoImportPath]map[string]string{}
+	for _, f := range files {
+		if !f.Generate {
+			continue
+		}
+		importPath := config.outputFor(f).importPath
+		if seen[importPath] == nil {
+			seen[importPath] = map[string]string{}
+		}
+		for _, ew := range ErrorEnums(f, config) {
+			for _, symbol := range packageSymbols(ew, config) {
+				where := fmt.Sprintf("%s (%s)", ew.FullName, f.Desc.Path())
+				if prev, ok := seen[importPath][symbol]; ok && prev != where {
+					d := fileDiagnostic(f, "%s is declared by both %s and %s in Go package %s", symbol, prev, where, importPath)
+					d.Enum = ew.FullName
+					problems = append(problems, d)
+					continue
+				}
+				seen[importPath][symbol] = where
+			}
+		}
+	}
+	if len(problems) > 0 {
+		return &Diagnostics{Summary: "conflicting generated identifiers", Problems: problems}
+	}
+	return nil
+}
+
+// packageSymbols returns the package-level identifiers named after ew or its
+// values. Identifiers derived from the enum name (the type, Parse<Enum>, the
+// metrics counter...) are represented by the enum name alone.
+func packageSymbols(ew *template.ErrorWrapper, config *Config) []string {
+	symbols := []string{ew.Name}
+	for _, info := range append(ew.Errors[:len(ew.Errors):len(ew.Errors)], ew.Aliases...) {
+		constructor := func(name string) string {
+			return template.ConstructorName(name, config.UnexportedConstructors)
+		}
+		if info.HasFormat() {
+			symbols = append(symbols, constructor("New"+info.GoName))
+		}
+		if len(info.Placeholders()) > 0 {
+			symbols = append(symbols, constructor("New"+info.GoName), constructor(info.GoName+"Params"))
+		}
+		if info.DetailType != "" {
+			symbols = append(symbols, constructor(info.GoName+"Error"))
+		}
+		if len(ew.Locales()) > 0 && info.Canonical == nil {
+			symbols = append(symbols, constructor(info.GoName+"ErrorWithLocale"))
+		}
+		if config.SentinelErrors {
+			symbols = append(symbols, "Err"+info.GoName, "Is"+info.GoName)
+		}
+		if (config.ErrorCodes || config.Exhaustive) && info.Canonical == nil {
+			symbols = append(symbols, "Code"+info.GoName)
+		}
+	}
+	return symbols
+}
@@ -8,6 +8,7 @@ package basic
 import (
 	errors "errors"
 	httpx "github.com/go-sphere/httpx"
+	http "net/http"
 )
 
 func (e UserError) Error() string {
@@ -47,17 +48,17 @@ func (e UserError) GetCode() int32 {
 func (e UserError) GetStatus() int32 {
 	switch e {
 	case UserError_USER_ERROR_UNSPECIFIED:
-		return 400
+		return http.StatusBadRequest
 	case UserError_USER_ERROR_INVALID_ID:
-		return 400
+		return http.StatusBadRequest
 	case UserError_USER_ERROR_NOT_FOUND:
-		return 404
+		return http.StatusNotFound
 	case UserError_USER_ERROR_PERMISSION_DENIED:
-		return 403
+		return http.StatusForbidden
 	case UserError_USER_ERROR_DEFAULTED:
-		return 400
+		return http.StatusBadRequest
 	default:
-		return 500
+		return http.StatusInternalServerError
 	}
 }
 
@@ -102,6 +103,12 @@ func (e UserError) JoinWithMessage(msg string, errs ...error) error {
 	)
 }
 
+// WithCause returns e wrapping cause, so errors.Is and errors.As reach both e
+// and the root cause through the constructed error's Unwrap.
+func (e UserError) WithCause(cause error) error {
+	return e.Join(cause)
+}
+
 func (e OrderError) Error() string {
 	switch e {
 	case OrderError_ORDER_ERROR_UNSPECIFIED:
@@ -127,11 +134,11 @@ func (e OrderError) GetCode() int32 {
 func (e OrderError) GetStatus() int32 {
 	switch e {
 	case OrderError_ORDER_ERROR_UNSPECIFIED:
-		return 500
+		return http.StatusInternalServerError
 	case OrderError_ORDER_ERROR_OUT_OF_STOCK:
-		return 400
+		return http.StatusBadRequest
 	default:
-		return 500
+		return http.StatusInternalServerError
 	}
 }
 
@@ -169,3 +176,9 @@ func (e OrderError) JoinWithMessage(msg string, errs ...error) error {
 		errors.Join(allErrs...),
 	)
 }
+
+// WithCause returns e wrapping cause, so errors.Is and errors.As reach both e
+// and the root cause through the constructed error's Unwrap.
+func (e OrderError) WithCause(cause error) error {
+	return e.Join(cause)
+}
@@ -8,6 +8,7 @@ package mixed
 import (
 	errors "errors"
 	httpx "github.com/go-sphere/httpx"
+	http "net/http"
 )
 
 func (e AuthError) Error() string {
@@ -35,11 +36,11 @@ func (e AuthError) GetCode() int32 {
 func (e AuthError) GetStatus() int32 {
 	switch e {
 	case AuthError_AUTH_ERROR_UNSPECIFIED:
-		return 401
+		return http.StatusUnauthorized
 	case AuthError_AUTH_ERROR_TOKEN_EXPIRED:
-		return 401
+		return http.StatusUnauthorized
 	default:
-		return 500
+		return http.StatusInternalServerError
 	}
 }
 
@@ -77,3 +78,9 @@ func (e AuthError) JoinWithMessage(msg string, errs ...error) error {
 		errors.Join(allErrs...),
 	)
 }
+
+// WithCause returns e wrapping cause, so errors.Is and errors.As reach both e
+// and the root cause through the constructed error's Unwrap.
+func (e AuthError) WithCause(cause error) error {
+	return e.Join(cause)
+}
@@ -0,0 +1,188 @@
+package errors
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// CodeRange is an inclusive range of error codes.
+type CodeRange struct {
+	Start, End int32
+}
+
+// Contains reports whether code lies within r.
+func (r CodeRange) Contains(code int32) bool {
+	return code >= r.Start && code <= r.End
+}
+
+func (r CodeRange) String() string {
+	if r.Start == r.End {
+		return strconv.Itoa(int(r.Start))
+	}
+	return fmt.Sprintf("%d-%d", r.Start, r.End)
+}
+
+// ParseCodeOffset parses a code_offset parameter, either "N" (applies to every
+// proto package) or "proto.package=N".
+func ParseCodeOffset(s string) (string, int32, error) {
+	pkg, n := "", s
+	if i := strings.LastIndex(s, "="); i >= 0 {
+		pkg, n = s[:i], s[i+1:]
+	}
+	offset, err := strconv.ParseInt(strings.TrimSpace(n), 10, 32)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid code offset %q: %w", s, err)
+	}
+	return strings.TrimSpace(pkg), int32(offset), nil
+}
+
+// ParseDomain parses a domain parameter, either "example.com" (applies to every
+// proto package) or "proto.package=example.com".
+func ParseDomain(s string) (string, string, error) {
+	pkg, domain := "", s
+	if i := strings.LastIndex(s, "="); i >= 0 {
+		pkg, domain = s[:i], s[i+1:]
+	}
+	domain = strings.TrimSpace(domain)
+	if domain == "" {
+		return "", "", fmt.Errorf("invalid domain %q, expected 'domain' or 'proto.package=domain'", s)
+	}
+	return strings.TrimSpace(pkg), domain, nil
+}
+
+// ParseCodePrefix parses a code_prefix parameter, either "AUTH-" (applies to
+// every proto package) or "proto.package=AUTH-".
+func ParseCodePrefix(s string) (string, string, error) {
+	pkg, prefix := "", s
+	if i := strings.LastIndex(s, "="); i >= 0 {
+		pkg, prefix = s[:i], s[i+1:]
+	}
+	prefix = strings.TrimSpace(prefix)
+	if prefix == "" {
+		return "", "", fmt.Errorf("invalid code prefix %q, expected 'prefix' or 'proto.package=prefix'", s)
+	}
+	return strings.TrimSpace(pkg), prefix, nil
+}
+
+// ParseDefaultMessage parses a default_message parameter of the form
+// "proto.package.Enum=message".
+func ParseDefaultMessage(s string) (string, string, error) {
+	enum, msg, ok := strings.Cut(s, "=")
+	enum = strings.TrimSpace(enum)
+	if !ok || enum == "" || msg == "" {
+		return "", "", fmt.Errorf("invalid default message %q, expected 'proto.package.Enum=message'", s)
+	}
+	return enum, msg, nil
+}
+
+// ParseLogMessage parses a log_message parameter of the form
+// "proto.package.VALUE=message".
+func ParseLogMessage(s string) (string, string, error) {
+	value, msg, ok := strings.Cut(s, "=")
+	value = strings.TrimSpace(value)
+	if !ok || value == "" || msg == "" {
+		return "", "", fmt.Errorf("invalid log message %q, expected 'proto.package.VALUE=message'", s)
+	}
+	return value, msg, nil
+}
+
+// ParseCodeRange parses "N" or "N-M" into a CodeRange.
+func ParseCodeRange(s string) (CodeRange, error) {
+	lo, hi, found := strings.Cut(s, "-")
+	start, err := strconv.ParseInt(strings.TrimSpace(lo), 10, 32)
+	if err != nil {
+		return CodeRange{}, fmt.Errorf("invalid code range %q: %w", s, err)
+	}
+	end := start
+	if found {
+		if end, err = strconv.ParseInt(strings.TrimSpace(hi), 10, 32); err != nil {
+			return CodeRange{}, fmt.Errorf("invalid code range %q: %w", s, err)
+		}
+	}
+	if end < start {
+		return CodeRange{}, fmt.Errorf("invalid code range %q: end before start", s)
+	}
+	return CodeRange{Start: int32(start), End: int32(end)}, nil
+}
+
+// ValidateCodes checks the error enums of files for codes that are shared by
+// two values (when config.UniqueCodes is set) or that fall inside one of
+// config.ReservedCodes. Zero values are skipped, since every proto3 enum
+// starts with one. All problems are reported together in a single error.
+func ValidateCodes(files []*protogen.File, config *Config) error {
+	if !config.UniqueCodes && len(config.ReservedCodes) == 0 {
+		return nil
+	}
+	var problems []Diagnostic
+	seen := map[int32]string{}
+	for _, f := range files {
+		for _, ew := range ErrorEnums(f, config) {
+			for _, info := range ew.Errors {
+				if info.Number == 0 {
+					continue
+				}
+				where := fmt.Sprintf("%s.%s (%s)", ew.FullName, info.Value, info.Source)
+				for _, r := range config.ReservedCodes {
+					if r.Contains(info.Code) {
+						problems = append(problems, infoDiagnostic(ew, info, "error code %d of %s is in reserved range %s", info.Code, where, r))
+					}
+				}
+				if !config.UniqueCodes {
+					continue
+				}
+				if prev, ok := seen[info.Code]; ok {
+					problems = append(problems, infoDiagnostic(ew, info, "duplicate error code %d: %s and %s", info.Code, prev, where))
+					continue
+				}
+				seen[info.Code] = where
+			}
+		}
+	}
+	if len(problems) > 0 {
+		return &Diagnostics{Summary: "invalid error codes", Problems: problems}
+	}
+	return nil
+}
+
+// ValidateOptions rejects, when config.Strict is set, non-zero error values
+// that carry no (sphere.errors.options) annotation or no message (neither
+// their own nor a configured default message), instead of
+// silently falling back to the default status and a generated reason. Aliases
+// are skipped, since they share the options of their canonical value. All
+// offending values are reported together in a single error.
+func ValidateOptions(files []*protogen.File, config *Config) error {
+	if !config.Strict {
+		return nil
+	}
+	var problems []Diagnostic
+	for _, f := range files {
+		for _, enum := range f.Enums {
+			if _, ok := config.defaultStatus(enum); !ok || !config.selects(enum) {
+				continue
+			}
+			seen := map[protoreflect.EnumNumber]bool{}
+			for _, v := range enum.Values {
+				number := v.Desc.Number()
+				if number == 0 || seen[number] {
+					continue
+				}
+				seen[number] = true
+				where := fmt.Sprintf("%s.%s (%s)", enum.Desc.FullName(), v.Desc.Name(), sourceLocation(v))
+				switch {
+				case !config.hasValueOptions(v):
+					problems = append(problems, valueDiagnostic(v, "%s has no %s", where, config.optionsName()))
+				case config.valueOptions(v).GetMessage() == "" && config.DefaultMessages[string(enum.Desc.FullName())] == "":
+					problems = append(problems, valueDiagnostic(v, "%s has no message", where))
+				}
+			}
+		}
+	}
+	if len(problems) > 0 {
+		return &Diagnostics{Summary: "strict: error values missing options", Problems: problems}
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package errors
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Import paths used by the generated protovalidate bridge.
+const (
+	protovalidatePackage = protogen.GoImportPath("buf.build/go/protovalidate")
+	protoPackage         = protogen.GoImportPath("google.golang.org/protobuf/proto")
+)
+
+// ParseValidationError parses a validation_error parameter of the form
+// "proto.package.VALUE", designating the error of every message, or
+// "proto.package.Message=proto.package.VALUE". The returned message is empty
+// for the former.
+func ParseValidationError(s string) (string, string, error) {
+	message, value, ok := strings.Cut(s, "=")
+	if !ok {
+		message, value = "", message
+	}
+	message, value = strings.TrimSpace(message), strings.TrimSpace(value)
+	if value == "" || (ok && message == "") {
+		return "", "", fmt.Errorf("invalid validation error %q, expected 'proto.package.VALUE' or 'proto.package.Message=proto.package.VALUE'", s)
+	}
+	return message, value, nil
+}
+
+// ValidateValidationErrors rejects ValidationErrors naming a value that is
+// not an error enum value of files, which would otherwise never be converted
+// to.
+func ValidateValidationErrors(files []*protogen.File, config *Config) error {
+	if len(config.ValidationErrors) == 0 {
+		return nil
+	}
+	values := errorValues(files, config)
+	var problems []Diagnostic
+	for message, value := range config.ValidationErrors {
+		if _, ok := values[value]; !ok {
+			if message == "" {
+				message = "every message"
+			}
+			problems = append(problems, diagnosticf("%s (validation error of %s) is not an error enum value of this request", value, message))
+		}
+	}
+	if len(problems) > 0 {
+		sortDiagnostics(problems)
+		return &Diagnostics{Summary: "validation_error", Problems: problems}
+	}
+	return nil
+}
+
+// valueFullName returns the fully-qualified proto name of the value named
+// value of the enum named enum. Enum values are scoped as siblings of their
+// enum.
+func valueFullName(enum, value string) string {
+	return string(protoreflect.FullName(enum).Parent().Append(protoreflect.Name(value)))
+}
+
+// validationTargets returns the Go constants of the validation errors
+// generated into the Go package of file, keyed like ValidationErrors.
+func validationTargets(gen *protogen.Plugin, file *protogen.File, config *Config) map[string]string {
+	values := errorValues(packageFiles(gen, file, config), config)
+	targets := map[string]string{}
+	for message, value := range config.ValidationErrors {
+		if ident, ok := values[value]; ok {
+			targets[message] = ident.GoName
+		}
+	}
+	return targets
+}
+
+// generateValidationBridge writes the package-level FromValidationError,
+// converting protovalidate violations into the validation errors of
+// ValidationErrors declared in this package. It writes nothing when none is.
+func generateValidationBridge(gen *protogen.Plugin, file *protogen.File, g *protogen.GeneratedFile, config *Config) {
+	targets := validationTargets(gen, file, config)
+	if len(targets) == 0 {
+		return
+	}
+	messages := make([]string, 0, len(targets))
+	for message := range targets {
+		if message != "" {
+			messages = append(messages, message)
+		}
+	}
+	sort.Strings(messages)
+	errdetails := func(name string) string {
+		return g.QualifiedGoIdent(errdetailsPackage.Ident(name))
+	}
+	g.P("// FromValidationError converts err, returned by protovalidate validating msg,")
+	g.P("// into the validation error designated for the type of msg, carrying a")
+	g.P("// google.rpc.BadRequest detail with a field violation per protovalidate")
+	g.P("// violation, so request validation and business errors share one error")
+	g.P("// surface. The original error stays in the chain. Errors other than a")
+	g.P("// *protovalidate.ValidationError, nil included, and messages without a")
+	g.P("// validation error are returned unchanged.")
+	g.P("func FromValidationError(msg ", g.QualifiedGoIdent(protoPackage.Ident("Message")), ", err error) error {")
+	g.P("var verr *", g.QualifiedGoIdent(protovalidatePackage.Ident("ValidationError")))
+	g.P("if !", g.QualifiedGoIdent(errorsPackage.Ident("As")), "(err, &verr) {")
+	g.P("return err")
+	g.P("}")
+	ret := "error"
+	if config.ConcreteReturn {
+		ret = "*Error"
+	}
+	g.P("var target interface{ Join(errs ...error) ", ret, " }")
+	g.P("switch msg.ProtoReflect().Descriptor().FullName() {")
+	for _, message := range messages {
+		g.P("case ", strconv.Quote(message), ":")
+		g.P("target = ", targets[message])
+	}
+	g.P("default:")
+	if c, ok := targets[""]; ok {
+		g.P("target = ", c)
+	} else {
+		g.P("return err")
+	}
+	g.P("}")
+	g.P("bad := &", errdetails("BadRequest"), "{}")
+	g.P("for _, v := range verr.Violations {")
+	g.P("bad.FieldViolations = append(bad.FieldViolations, &", errdetails("BadRequest_FieldViolation"), "{")
+	g.P("Field: ", g.QualifiedGoIdent(protovalidatePackage.Ident("FieldPathString")), "(v.Proto.GetField()),")
+	g.P("Description: v.Proto.GetMessage(),")
+	g.P("})")
+	g.P("}")
+	g.P("return ", g.QualifiedGoIdent(detailsPackage.Ident("Wrap")), "(target.Join(verr), bad)")
+	g.P("}")
+	g.P()
+}
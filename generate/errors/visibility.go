@@ -0,0 +1,160 @@
+package errors
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Visibilities of an error value, set with the visibility parameter or the
+// visibility field of a custom OptionsType. Internal errors never reach the
+// wire: the httperrors and grpcerrors encoders render them as a generic
+// internal error.
+const (
+	VisibilityPublic   = "public"
+	VisibilityInternal = "internal"
+)
+
+// InternalPackage is the name of the sub-package written under internal/ next
+// to the errors of each Go package declaring internal values, holding their
+// constructors.
+const InternalPackage = "internalerrors"
+
+// ParseVisibility parses a visibility parameter of the form 'name=internal'
+// or 'name=public', name being a fully-qualified enum or enum value.
+func ParseVisibility(s string) (string, string, error) {
+	name, visibility, ok := strings.Cut(s, "=")
+	name, visibility = strings.TrimSpace(name), strings.ToLower(strings.TrimSpace(visibility))
+	if !ok || name == "" || (visibility != VisibilityPublic && visibility != VisibilityInternal) {
+		return "", "", fmt.Errorf("invalid visibility %q, expected 'name=internal' or 'name=public'", s)
+	}
+	return name, visibility, nil
+}
+
+// visibilityHelpers reports whether the error enums get IsInternal methods:
+// when Visibilities lists a name, or OptionsType declares a visibility field.
+func (c *Config) visibilityHelpers() bool {
+	return len(c.Visibilities) > 0 || c.custom != nil && c.custom.visibility != nil
+}
+
+// visibilityOption returns the visibility declared for v by the visibility
+// field of a custom OptionsType, lowercased, or "" when it declares none. An
+// enum field is read by the name of its value.
+func (c *Config) visibilityOption(v *protogen.EnumValue) string {
+	if c.custom == nil || c.custom.value == nil || c.custom.visibility == nil {
+		return ""
+	}
+	ext, ok := c.custom.extension(v.Desc.Options(), c.custom.value)
+	if !ok {
+		return ""
+	}
+	m, fd := ext.Message(), c.custom.visibility
+	if !m.Has(fd) {
+		return ""
+	}
+	if fd.Kind() != protoreflect.EnumKind {
+		return strings.ToLower(m.Get(fd).String())
+	}
+	if ev := fd.Enum().Values().ByNumber(m.Get(fd).Enum()); ev != nil {
+		return strings.ToLower(string(ev.Name()))
+	}
+	return ""
+}
+
+// internal reports whether v, a value of the enum named enum, is internal: by
+// the visibility field of a custom OptionsType, which wins, or else by
+// Visibilities naming v or, failing that, its enum. Values are public by
+// default.
+func (c *Config) internal(enum string, v *protogen.EnumValue) bool {
+	if visibility := c.visibilityOption(v); visibility != "" {
+		return visibility == VisibilityInternal
+	}
+	if visibility, ok := c.Visibilities[string(v.Desc.FullName())]; ok {
+		return visibility == VisibilityInternal
+	}
+	return c.Visibilities[enum] == VisibilityInternal
+}
+
+// ValidateVisibilities rejects visibilities read from the visibility field of
+// a custom OptionsType other than INTERNAL and PUBLIC. All offending values
+// are reported together in a single error.
+func ValidateVisibilities(files []*protogen.File, config *Config) error {
+	if config.custom == nil || config.custom.visibility == nil {
+		return nil
+	}
+	var problems []Diagnostic
+	for _, f := range files {
+		for _, enum := range f.Enums {
+			if _, ok := config.defaultStatus(enum); !ok || !config.selects(enum) {
+				continue
+			}
+			for _, v := range enum.Values {
+				if visibility := config.visibilityOption(v); visibility != "" && visibility != VisibilityPublic && visibility != VisibilityInternal {
+					problems = append(problems, valueDiagnostic(v, "visibility %q of %s.%s (%s)", visibility, enum.Desc.FullName(), v.Desc.Name(), sourceLocation(v)))
+				}
+			}
+		}
+	}
+	if len(problems) > 0 {
+		sortDiagnostics(problems)
+		return &Diagnostics{Summary: "invalid visibilities, expected INTERNAL or PUBLIC", Problems: problems}
+	}
+	return nil
+}
+
+// generateInternalPackage writes internal/internalerrors/internalerrors.sphere.go
+// under the Go package of file when the errors of the package declare internal
+// values: a New<Name> constructor per internal value, so only code of the
+// module tree above internal/ builds them by name. It writes nothing when
+// every value is public.
+func generateInternalPackage(gen *protogen.Plugin, file *protogen.File, config *Config) {
+	out := config.outputFor(file)
+	files := packageFiles(gen, file, config)
+	var values []*internalValue
+	for _, f := range files {
+		for _, ew := range ErrorEnums(f, config) {
+			for _, info := range ew.NonZeroErrors() {
+				if info.Internal {
+					values = append(values, &internalValue{ident: info.Name + "_" + info.Value, goName: info.GoName})
+				}
+			}
+		}
+	}
+	if len(values) == 0 {
+		return
+	}
+	importPath := protogen.GoImportPath(path.Join(string(out.importPath), "internal", InternalPackage))
+	g := gen.NewGeneratedFile(path.Join(path.Dir(out.prefix), "internal", InternalPackage, InternalPackage+".sphere.go"), importPath)
+	g.P("// Code generated by ", config.generatedBy(), ". DO NOT EDIT.")
+	g.P("// versions:")
+	g.P("// - protoc             ", protocVersion(gen))
+	for _, f := range files {
+		g.P("// source: ", f.Desc.Path())
+	}
+	g.P()
+	if config.BuildTag != "" {
+		g.P("//go:build ", config.BuildTag)
+		g.P()
+	}
+	g.P("// Package ", InternalPackage, " constructs the internal errors of package ", out.packageName, ",")
+	g.P("// which httperrors and grpcerrors encode as a generic internal error.")
+	g.P("package ", InternalPackage)
+	g.P()
+	for _, v := range values {
+		g.P("// New", v.goName, " returns ", v.ident, " joined with")
+		g.P("// errs. It is internal: encoders never expose its code, reason or message.")
+		g.P("func New", v.goName, "(errs ...error) error {")
+		g.P("return ", g.QualifiedGoIdent(out.importPath.Ident(v.ident)), ".Join(errs...)")
+		g.P("}")
+		g.P()
+	}
+}
+
+// internalValue is an internal error value rendered by
+// generateInternalPackage.
+type internalValue struct {
+	ident, goName string
+}
@@ -0,0 +1,86 @@
+package errors
+
+import (
+	"fmt"
+
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/internal/template"
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// Policies for the zero value of error enums, selectable with ZeroValue.
+const (
+	// ZeroValueInclude generates the zero value like any other value, with
+	// the enum's default status.
+	ZeroValueInclude = "include"
+	// ZeroValueSkip leaves the zero value out, since it conventionally means
+	// no error: it gets no helpers, and the switch based methods answer it as
+	// they answer numbers the enum does not declare.
+	ZeroValueSkip = "skip"
+	// ZeroValueUnknown generates the zero value as the unknown error of the
+	// enum, with status 500 and the UNKNOWN_ERROR reason unless its options
+	// declare them.
+	ZeroValueUnknown = "unknown"
+	// ZeroValueFail skips the zero value as ZeroValueSkip does, and fails
+	// generation when a zero value declares error options it would lose.
+	ZeroValueFail = "fail"
+)
+
+// ValidateZeroValue reports whether policy is a supported ZeroValue. The
+// empty string selects ZeroValueInclude.
+func ValidateZeroValue(policy string) error {
+	switch policy {
+	case "", ZeroValueInclude, ZeroValueSkip, ZeroValueUnknown, ZeroValueFail:
+		return nil
+	}
+	return fmt.Errorf("invalid zero_value %q, expected %s, %s, %s or %s", policy, ZeroValueInclude, ZeroValueSkip, ZeroValueUnknown, ZeroValueFail)
+}
+
+// skipsZeroValue reports whether the zero value of error enums is left out.
+func (c *Config) skipsZeroValue() bool {
+	return c.ZeroValue == ZeroValueSkip || c.ZeroValue == ZeroValueFail
+}
+
+// unknownErrorInfo turns info, the zero value of its enum, into the enum's
+// unknown error under ZeroValueUnknown. What the options of v declare is
+// kept.
+func (c *Config) unknownErrorInfo(info *template.ErrorInfo, v *protogen.EnumValue) {
+	opt := c.valueOptions(v)
+	if opt.GetStatus() == 0 {
+		info.Status = 500
+		info.GRPCCode = "UNKNOWN"
+	}
+	if opt.GetReason() == "" {
+		info.Reason = info.Name + ":UNKNOWN_ERROR"
+	}
+	if info.Message == "" {
+		info.Message = "unknown error"
+	}
+}
+
+// ValidateEnumValues rejects negative values of error enums, whose codes
+// clients cannot tell from the unset code 0 or a transport failure, and under
+// ZeroValueFail zero values declaring error options. All offending values are
+// reported together in a single error.
+func ValidateEnumValues(files []*protogen.File, config *Config) error {
+	var problems []Diagnostic
+	for _, f := range files {
+		for _, enum := range f.Enums {
+			if _, ok := config.defaultStatus(enum); !ok || !config.selects(enum) {
+				continue
+			}
+			for _, v := range enum.Values {
+				switch number := v.Desc.Number(); {
+				case number < 0:
+					problems = append(problems, valueDiagnostic(v, "negative value %s.%s = %d (%s), error codes must be 0 or more", enum.Desc.FullName(), v.Desc.Name(), number, sourceLocation(v)))
+				case number == 0 && config.ZeroValue == ZeroValueFail && config.hasValueOptions(v):
+					problems = append(problems, valueDiagnostic(v, "zero value %s.%s (%s) declares error options, but zero_value=%s treats it as no error", enum.Desc.FullName(), v.Desc.Name(), sourceLocation(v), ZeroValueFail))
+				}
+			}
+		}
+	}
+	if len(problems) > 0 {
+		sortDiagnostics(problems)
+		return &Diagnostics{Summary: "invalid error enum values", Problems: problems}
+	}
+	return nil
+}
@@ -0,0 +1,217 @@
+// Package graph implements the error taxonomy graph output of
+// protoc-gen-sphere-errors. It writes one errors.graph.dot per request, a
+// Graphviz digraph drawing the error values of every generated file grouped
+// by proto package and category, with the supersedes relationships between
+// them, so architecture reviews can visualize the error landscape.
+package graph
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/catalog"
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/errors"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Supported graph formats.
+const (
+	FormatDOT = "dot"
+)
+
+// ValidateFormat rejects graph formats other than dot.
+func ValidateFormat(format string) error {
+	if format != FormatDOT {
+		return fmt.Errorf("invalid graph_out %q, expected %s", format, FormatDOT)
+	}
+	return nil
+}
+
+// Graph is the error taxonomy of a request.
+type Graph struct {
+	// Packages are the proto packages declaring error values, ordered by
+	// name.
+	Packages []*Package
+	// Edges are the supersedes relationships between values, ordered by
+	// superseding value.
+	Edges []Edge
+}
+
+// Package holds the error values of a proto package.
+type Package struct {
+	Name string
+	// Categories group the values of the package by category, ordered by
+	// name. Values without a category are grouped under "".
+	Categories []*Category
+}
+
+// Category holds the error values of a package sharing a category.
+type Category struct {
+	Name   string
+	Values []Value
+}
+
+// Value is an error value, a node of the graph.
+type Value struct {
+	// FullName is the fully-qualified proto name of the value, e.g.
+	// "shop.v1.USER_ERROR_NOT_FOUND".
+	FullName string
+	// Enum is the name of the enum declaring the value, e.g. "UserError".
+	Enum       string
+	Name       string
+	Code       int32
+	Status     int32
+	Deprecated bool
+}
+
+// Edge is a supersedes relationship: From replaces To, an error value of an
+// older API version. CrossService reports whether both values belong to
+// different services, proto packages differing once their version
+// suffixes are dropped, the errors then being translated between services
+// rather than between versions of one.
+type Edge struct {
+	From, To     string
+	CrossService bool
+}
+
+// Build collects the error values of files, resolved with config. Aliases
+// and zero values are left out.
+func Build(files []*protogen.File, config *errors.Config) *Graph {
+	byName := map[string]*Package{}
+	for _, f := range files {
+		pkg := string(f.Desc.Package())
+		for _, ew := range errors.ErrorEnums(f, config) {
+			p := byName[pkg]
+			if p == nil {
+				p = &Package{Name: pkg}
+				byName[pkg] = p
+			}
+			for _, info := range ew.Errors {
+				if info.Number == 0 {
+					continue
+				}
+				c := p.category(info.Category)
+				c.Values = append(c.Values, Value{
+					FullName:   string(protoreflect.FullName(ew.FullName).Parent().Append(protoreflect.Name(info.Value))),
+					Enum:       ew.Name,
+					Name:       info.Value,
+					Code:       info.Code,
+					Status:     info.Status,
+					Deprecated: info.Deprecated,
+				})
+			}
+		}
+	}
+	g := &Graph{}
+	for _, p := range byName {
+		slices.SortFunc(p.Categories, func(a, b *Category) int { return strings.Compare(a.Name, b.Name) })
+		g.Packages = append(g.Packages, p)
+	}
+	slices.SortFunc(g.Packages, func(a, b *Package) int { return strings.Compare(a.Name, b.Name) })
+	for from, to := range config.Supersedes {
+		g.Edges = append(g.Edges, Edge{
+			From:         from,
+			To:           to,
+			CrossService: service(from) != service(to),
+		})
+	}
+	slices.SortFunc(g.Edges, func(a, b Edge) int {
+		if c := strings.Compare(a.From, b.From); c != 0 {
+			return c
+		}
+		return strings.Compare(a.To, b.To)
+	})
+	return g
+}
+
+// category returns the category of p named name, adding it when missing.
+func (p *Package) category(name string) *Category {
+	for _, c := range p.Categories {
+		if c.Name == name {
+			return c
+		}
+	}
+	c := &Category{Name: name}
+	p.Categories = append(p.Categories, c)
+	return c
+}
+
+// versionSuffix matches a trailing API version package component, e.g. v1,
+// v2beta1 or v1alpha.
+var versionSuffix = regexp.MustCompile(`^v[0-9]+((alpha|beta)[0-9]*)?$`)
+
+// service returns the package of the error value named value without its
+// version suffix, e.g. "shop" for "shop.v1.USER_ERROR_NOT_FOUND".
+func service(value string) string {
+	pkg := protoreflect.FullName(value).Parent()
+	if versionSuffix.MatchString(string(pkg.Name())) {
+		pkg = pkg.Parent()
+	}
+	return string(pkg)
+}
+
+// Marshal encodes g in the given format.
+func Marshal(g *Graph, format string) ([]byte, error) {
+	if err := ValidateFormat(format); err != nil {
+		return nil, err
+	}
+	var b strings.Builder
+	b.WriteString("// Code generated by protoc-gen-sphere-errors. DO NOT EDIT.\n")
+	b.WriteString("digraph errors {\n")
+	b.WriteString("\trankdir=LR;\n")
+	b.WriteString("\tnode [shape=box, fontname=\"Helvetica\"];\n")
+	for _, p := range g.Packages {
+		fmt.Fprintf(&b, "\tsubgraph %s {\n", quote("cluster_"+p.Name))
+		fmt.Fprintf(&b, "\t\tlabel=%s;\n", quote(p.Name))
+		for _, c := range p.Categories {
+			indent := "\t\t"
+			if c.Name != "" {
+				fmt.Fprintf(&b, "\t\tsubgraph %s {\n", quote("cluster_"+p.Name+"/"+c.Name))
+				fmt.Fprintf(&b, "\t\t\tlabel=%s;\n", quote(c.Name))
+				b.WriteString("\t\t\tstyle=dashed;\n")
+				indent = "\t\t\t"
+			}
+			for _, v := range c.Values {
+				fmt.Fprintf(&b, "%s%s [label=%s", indent, quote(v.FullName), quote(fmt.Sprintf("%s.%s\n%d · %d", v.Enum, v.Name, v.Code, v.Status)))
+				if v.Deprecated {
+					b.WriteString(", style=dashed, fontcolor=gray")
+				}
+				b.WriteString("];\n")
+			}
+			if c.Name != "" {
+				b.WriteString("\t\t}\n")
+			}
+		}
+		b.WriteString("\t}\n")
+	}
+	for _, e := range g.Edges {
+		if e.CrossService {
+			fmt.Fprintf(&b, "\t%s -> %s [label=\"translates\", style=dashed, color=blue];\n", quote(e.From), quote(e.To))
+		} else {
+			fmt.Fprintf(&b, "\t%s -> %s [label=\"supersedes\"];\n", quote(e.From), quote(e.To))
+		}
+	}
+	b.WriteString("}\n")
+	return []byte(b.String()), nil
+}
+
+// quote returns s as a DOT string.
+func quote(s string) string {
+	return strconv.Quote(s)
+}
+
+// GenerateFile writes the graph of the files marked for generation to
+// errors.graph.dot at the root of the output.
+func GenerateFile(gen *protogen.Plugin, config *errors.Config, format string) error {
+	b, err := Marshal(Build(catalog.GeneratedFiles(gen), config), format)
+	if err != nil {
+		return err
+	}
+	g := gen.NewGeneratedFile("errors.graph."+format, "")
+	_, err = g.Write(b)
+	return err
+}
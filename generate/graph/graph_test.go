@@ -0,0 +1,91 @@
+package graph
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/errors"
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/internal/testutil"
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+func TestBuild(t *testing.T) {
+	plugin := testutil.PluginFromPB(t, "../errors/testdata/pb/basic_errors.pb", "basic_errors.proto")
+	config := &errors.Config{
+		Categories: map[string]string{"tests.basic.USER_ERROR_NOT_FOUND": "lookup"},
+		Supersedes: map[string]string{
+			"tests.basic.USER_ERROR_NOT_FOUND":  "tests.legacy.v1.USER_NOT_FOUND",
+			"tests.basic.v2.USER_ERROR_DELETED": "tests.basic.v1.USER_DELETED",
+		},
+	}
+	g := Build([]*protogen.File{testutil.FileToGenerate(t, plugin)}, config)
+	if len(g.Packages) != 1 || g.Packages[0].Name != "tests.basic" {
+		t.Fatalf("packages = %v, want tests.basic", g.Packages)
+	}
+	categories := g.Packages[0].Categories
+	if len(categories) != 2 || categories[0].Name != "" || categories[1].Name != "lookup" {
+		t.Fatalf("categories = %v, want \"\" and lookup", categories)
+	}
+	if v := categories[1].Values; len(v) != 1 || v[0].FullName != "tests.basic.USER_ERROR_NOT_FOUND" || v[0].Enum != "UserError" || v[0].Status != 404 {
+		t.Errorf("lookup values = %+v, want USER_ERROR_NOT_FOUND", v)
+	}
+	want := []Edge{
+		{From: "tests.basic.USER_ERROR_NOT_FOUND", To: "tests.legacy.v1.USER_NOT_FOUND", CrossService: true},
+		{From: "tests.basic.v2.USER_ERROR_DELETED", To: "tests.basic.v1.USER_DELETED"},
+	}
+	if len(g.Edges) != len(want) || g.Edges[0] != want[0] || g.Edges[1] != want[1] {
+		t.Errorf("edges = %+v, want %+v", g.Edges, want)
+	}
+}
+
+func TestService(t *testing.T) {
+	for value, want := range map[string]string{
+		"shop.v1.USER_ERROR_NOT_FOUND":      "shop",
+		"shop.v2beta1.USER_ERROR_NOT_FOUND": "shop",
+		"shop.USER_ERROR_NOT_FOUND":         "shop",
+		"shop.vip.USER_ERROR_NOT_FOUND":     "shop.vip",
+	} {
+		if got := service(value); got != want {
+			t.Errorf("service(%q) = %q, want %q", value, got, want)
+		}
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	plugin := testutil.PluginFromPB(t, "../errors/testdata/pb/basic_errors.pb", "basic_errors.proto")
+	config := &errors.Config{
+		Categories: map[string]string{"tests.basic.UserError": "auth"},
+		Supersedes: map[string]string{"tests.basic.USER_ERROR_NOT_FOUND": "tests.legacy.v1.USER_NOT_FOUND"},
+	}
+	dot, err := Marshal(Build([]*protogen.File{testutil.FileToGenerate(t, plugin)}, config), FormatDOT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"digraph errors {",
+		"\tsubgraph \"cluster_tests.basic\" {",
+		"\t\tsubgraph \"cluster_tests.basic/auth\" {",
+		"\t\t\t\"tests.basic.USER_ERROR_NOT_FOUND\" [label=\"UserError.USER_ERROR_NOT_FOUND\\n2 · 404\"];",
+		"\t\t\t\"tests.basic.USER_ERROR_DEFAULTED\" [label=\"UserError.USER_ERROR_DEFAULTED\\n4 · 400\", style=dashed, fontcolor=gray];",
+		"\t\t\"tests.basic.ORDER_ERROR_OUT_OF_STOCK\" [label=",
+		"\t\"tests.basic.USER_ERROR_NOT_FOUND\" -> \"tests.legacy.v1.USER_NOT_FOUND\" [label=\"translates\", style=dashed, color=blue];",
+	} {
+		if !strings.Contains(string(dot), want) {
+			t.Errorf("dot missing %q:\n%s", want, dot)
+		}
+	}
+	if _, err := Marshal(&Graph{}, "svg"); err == nil {
+		t.Error("expected an error for an invalid format")
+	}
+}
+
+func TestGenerateFile(t *testing.T) {
+	plugin := testutil.PluginFromPB(t, "../errors/testdata/pb/basic_errors.pb", "basic_errors.proto")
+	if err := GenerateFile(plugin, &errors.Config{}, FormatDOT); err != nil {
+		t.Fatal(err)
+	}
+	files := plugin.Response().File
+	if len(files) != 1 || files[0].GetName() != "errors.graph.dot" {
+		t.Fatalf("files = %v, want errors.graph.dot", files)
+	}
+}
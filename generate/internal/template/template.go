@@ -5,22 +5,157 @@ package template
 
 import (
 	_ "embed"
+	"go/token"
+	"io"
+	"regexp"
+	"sort"
 	"strings"
+	"sync"
 	"text/template"
 )
 
 //go:embed template.tmpl
 var errorsTemplate string
 
+//go:embed tests.tmpl
+var testsTemplate string
+
+//go:embed fuzz.tmpl
+var fuzzTemplate string
+
 // ErrorInfo describes a single enum value rendered as an error case.
 type ErrorInfo struct {
 	Name  string
 	Value string
+	// GoName is the CamelCase name used for per-value identifiers such as
+	// sentinels, e.g. "UserNotFound" for UserError_USER_ERROR_NOT_FOUND.
+	GoName string
+
+	Status int32
+	// StatusIdent is the qualified net/http constant of Status, such as
+	// http.StatusNotFound, or empty to render Status as a literal.
+	StatusIdent string
+	// Code is the error code: Number plus the configured code offset.
+	Code int32
+	// Number is the proto enum value number.
+	Number int32
+	// PublicCode is Code prefixed with the enum's CodePrefix, e.g.
+	// "AUTH-40401", or empty without a prefix.
+	PublicCode string
+	// CodeName is the symbolic name of Code rendered by the typed error
+	// codes, e.g. "USER_NOT_FOUND".
+	CodeName string
+	Reason   string
+	Message  string
+	// LogMessage is the internal message returned by Error() in place of the
+	// reason. It is never part of the encoded error.
+	LogMessage string
+	// LocalizedMessages are the messages of the value in other languages,
+	// keyed by language tag.
+	LocalizedMessages map[string]string
+
+	// Description is the leading comment of the enum value, without comment
+	// markers and the example it may end with.
+	Description string
+
+	// Deprecated mirrors the deprecated option of the enum value.
+	Deprecated bool
+
+	// Source is the proto file and line declaring the value, e.g.
+	// "shared/v1/errors.proto:12". The line is omitted when the request
+	// carries no source info. SourceFile and SourceLine are its parts, the
+	// line being 0 when omitted.
+	Source     string
+	SourceFile string
+	SourceLine int
+
+	// Severity is the log severity of the value: INFO, WARN, ERROR or
+	// CRITICAL. SeverityLevel is its qualified slog.Level expression, set only
+	// when logging helpers are generated.
+	Severity      string
+	SeverityLevel string
+	// LogSampleRate is the Go literal of the fraction of occurrences of the
+	// value worth logging, "1" for all.
+	LogSampleRate string
+
+	// CacheControl is the Cache-Control header suggested for responses
+	// carrying the value, empty for none.
+	CacheControl string
+
+	// HTTPHeaders are the response headers declared for HTTP responses
+	// carrying the value, sorted by name.
+	HTTPHeaders []HTTPHeader
+
+	// SLOExempt reports whether the value burns no error budget.
+	SLOExempt bool
+
+	// Internal reports whether the value is internal, never exposed by the
+	// encoders.
+	Internal bool
+
+	// Stability is STABLE, BETA or EXPERIMENTAL.
+	Stability string
+	// OTelEvent is the name of the OpenTelemetry span event recording the
+	// value, and SpanError whether it marks the span as failed.
+	OTelEvent string
+	SpanError bool
+
+	// Retryable reports whether a request failing with the value is safe to
+	// retry.
+	Retryable bool
+
+	// Origin is who causes the value: CLIENT, SERVER or UPSTREAM.
+	Origin string
 
-	Status  int32
-	Code    int32
-	Reason  string
-	Message string
+	// ClientCode is the code clients see in place of Code, shared by the
+	// values of one equivalence class, or 0 to keep Code.
+	ClientCode int32
+
+	// LegacyPattern is the regular expression matching the bodies of legacy
+	// upstream responses that denote the value, or empty.
+	LegacyPattern string
+
+	// Category is the coarse-grained category of the value, e.g.
+	// "validation", or empty.
+	Category string
+
+	// ProblemType and ProblemTitle are the RFC 9457 problem type URI and
+	// title of the value, set only when problem details are generated.
+	ProblemType  string
+	ProblemTitle string
+
+	// Example is an example error response body of the value, as compact
+	// JSON, or empty.
+	Example string
+
+	// HelpLink is the documentation URL of the value, e.g. a runbook or
+	// knowledge base article, or empty.
+	HelpLink string
+
+	// DetailType is the fully-qualified name of the detail message of the
+	// value, if any. DetailIdent is its qualified Go type; the
+	// <GoName>Error constructor is generated only when it is set.
+	DetailType  string
+	DetailIdent string
+
+	// Canonical is set on aliases (allow_alias values reusing an earlier
+	// number) and points at the first value declared with that number. An
+	// alias shares its canonical value's status, code, reason and message.
+	Canonical *ErrorInfo
+
+	// NewErrorsFunc is the qualified constructor building the errors of the
+	// value in place of the NewErrorsFunc of its enum, or empty.
+	NewErrorsFunc string
+
+	// RuntimeCodeIdent is the qualified code identifier of the runtime=connect
+	// adapter, e.g. "connect.CodeNotFound".
+	RuntimeCodeIdent string
+
+	// GRPCCode is the canonical gRPC code name (e.g. "NOT_FOUND") derived
+	// from Status. GRPCCodeIdent is its qualified Go identifier, set only
+	// when GRPCStatus helpers are generated.
+	GRPCCode      string
+	GRPCCodeIdent string
 }
 
 // HasReason reports whether an explicit reason string was provided.
@@ -28,24 +163,784 @@ func (i *ErrorInfo) HasReason() bool {
 	return i.Reason != ""
 }
 
+// DescriptionLines returns the lines of Description, for rendering it as a
+// comment.
+func (i *ErrorInfo) DescriptionLines() []string {
+	if i.Description == "" {
+		return nil
+	}
+	return strings.Split(i.Description, "\n")
+}
+
+// ErrorString returns the Error() text of the value: its log message, or its
+// reason when it has none.
+func (i *ErrorInfo) ErrorString() string {
+	if i.LogMessage != "" {
+		return i.LogMessage
+	}
+	return i.Reason
+}
+
+// formatVerb matches a printf verb, optionally with flags, width and
+// precision. Escaped percent signs (%%) are removed before matching.
+var formatVerb = regexp.MustCompile(`%[-+# 0]*(\[\d+\])?(\d+|\*)?(\.(\d+|\*))?[vTtbcdoOqxXUeEfFgGsp]`)
+
+// HasFormat reports whether the message contains printf verbs.
+func (i *ErrorInfo) HasFormat() bool {
+	return formatVerb.MatchString(strings.ReplaceAll(i.Message, "%%", ""))
+}
+
+// placeholderPattern matches a named placeholder of a message, a snake_case
+// name in braces such as {user_id}.
+var placeholderPattern = regexp.MustCompile(`\{([a-z][a-z0-9_]*)\}`)
+
+// Placeholder is a named placeholder of a message, as {user_id}.
+type Placeholder struct {
+	// Name is the placeholder name, e.g. "user_id".
+	Name string
+	// Field is the Go field of the params struct, e.g. "UserId".
+	Field string
+	// Param is the Go parameter of the New<GoName> constructor, e.g.
+	// "userId".
+	Param string
+}
+
+// Placeholders returns the distinct named placeholders of the message, in
+// order of first appearance.
+func (i *ErrorInfo) Placeholders() []Placeholder {
+	var out []Placeholder
+	seen := map[string]bool{}
+	for _, m := range placeholderPattern.FindAllStringSubmatch(i.Message, -1) {
+		if seen[m[1]] {
+			continue
+		}
+		seen[m[1]] = true
+		field := snakeToCamel(m[1])
+		param := strings.ToLower(field[:1]) + field[1:]
+		if token.IsKeyword(param) {
+			param += "_"
+		}
+		out = append(out, Placeholder{Name: m[1], Field: field, Param: param})
+	}
+	return out
+}
+
+// snakeToCamel converts a snake_case name to CamelCase: user_id becomes
+// UserId.
+func snakeToCamel(s string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(s, "_") {
+		if part != "" {
+			b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+		}
+	}
+	return b.String()
+}
+
 // ErrorWrapper is the template root: one error enum and its values, plus the
 // already-qualified identifiers the generated code calls into.
 type ErrorWrapper struct {
-	Name           string
-	Errors         []*ErrorInfo
+	Name string
+	// FullName is the fully-qualified proto name, e.g. "tests.basic.UserError".
+	FullName string
+	// Description is the leading comment of the enum, without comment markers.
+	Description string
+	// Errors are the canonical values, one per number. They drive the switch
+	// based methods.
+	Errors []*ErrorInfo
+	// DefaultStatusIdent is the qualified net/http constant GetStatus returns
+	// for unknown values, or empty to render 500 as a literal.
+	DefaultStatusIdent string
+	// Domain is the google.rpc.ErrorInfo domain of the enum. GetReason and
+	// GetDomain methods are generated only when it is set.
+	Domain string
+	// CodePrefix is the service identifier of the enum's public codes. The
+	// PublicCode method is generated only when it is set or a value has a
+	// PublicCode, see HasPublicCodes.
+	CodePrefix string
+	// Aliases are the values reusing the number of an earlier value. They only
+	// get per-value helpers, which refer to their own enum constant.
+	Aliases        []*ErrorInfo
 	NewErrorsFunc  string
 	ErrorsJoinFunc string
+
+	// SprintfFunc is the qualified fmt.Sprintf function, set when at least one
+	// message has printf verbs. Errorf and New<GoName> constructors are
+	// generated only when it is set.
+	SprintfFunc string
+
+	// Placeholder holds the identifiers of the <GoName>Params structs and
+	// New<GoName> constructors of messages with named placeholders, set when
+	// at least one message has them.
+	Placeholder *PlaceholderIdents
+
+	// ErrorsIsFunc is the qualified errors.Is function. Sentinel variables and
+	// Is<GoName> predicates are generated only when it is set.
+	ErrorsIsFunc string
+
+	// Kratos holds the kratos identifiers of the Error<GoName> constructors
+	// and Is<GoName> predicates of kratos protoc-gen-go-errors, generated
+	// only when it is set.
+	Kratos *KratosIdents
+
+	// Prebuilt generates the Err method returning an error constructed once
+	// per value at package initialization.
+	Prebuilt bool
+
+	// ConcreteReturn makes the constructors return the *Error type declared
+	// with the package helpers instead of error, so callers chain its With
+	// methods without a type assertion.
+	ConcreteReturn bool
+
+	// StackWrap is the qualified stack.Wrap function. The Join constructors
+	// return their error wrapped with it, recording the call stack, only when
+	// it is set.
+	StackWrap string
+
+	// SplitDeprecated moves the per-value helpers of deprecated values out of
+	// the main rendering into the "deprecated" template, which is emitted in a
+	// separate build-constrained file.
+	SplitDeprecated bool
+
+	// Registry holds the qualified registry identifiers. An init function
+	// registering the enum values is generated only when it is set.
+	Registry *RegistryIdents
+
+	// GRPC holds the qualified gRPC identifiers. GetGRPCCode and GRPCStatus
+	// methods are generated only when it is set.
+	GRPC *GRPCIdents
+
+	// Adapter holds the identifiers of the newError method adapting the Join
+	// helpers to a runtime other than the NewErrorsFunc. The method is
+	// generated only when it is set.
+	Adapter *AdapterIdents
+
+	// Codes holds the identifiers of the typed <Name>Code type. The type, its
+	// constants and the ErrorCode method are generated only when it is set.
+	Codes *CodeIdents
+
+	// Log holds the qualified log/slog identifiers. Severity and LogValue
+	// methods are generated only when it is set.
+	Log *LogIdents
+
+	// SampleFloat is the qualified math/rand/v2 Float64 identifier.
+	// LogSampleRate and ShouldLog methods are generated only when it is set.
+	SampleFloat string
+
+	// RetryHelpers generates the IsRetryable method.
+	RetryHelpers bool
+
+	// CacheHelpers generates the CacheControl method.
+	CacheHelpers bool
+
+	// HeaderHelpers generates the HTTPHeaders method.
+	HeaderHelpers bool
+
+	// SLOHelpers generates the SLOExempt method.
+	SLOHelpers bool
+	// VisibilityHelpers generates the IsInternal method.
+	VisibilityHelpers bool
+	// StabilityHelpers generates the Stability method.
+	StabilityHelpers bool
+	// SpanHelpers generates the OTelEventName and MarksSpanError methods.
+	SpanHelpers bool
+	// Exhaustive generates the <Name>Values, <Name>Codes and ForEach<Name>
+	// functions. It requires Registry and Codes.
+	Exhaustive bool
+	// AWSErrorTypes generates the AWSErrorType method.
+	AWSErrorTypes bool
+
+	// UnexportedConstructors unexports the package-level constructors of
+	// the values, see Constructor.
+	UnexportedConstructors bool
+
+	// OriginHelpers generates the Origin method.
+	OriginHelpers bool
+
+	// ClientCodeHelpers generates the ClientCode method.
+	ClientCodeHelpers bool
+
+	// CategoryHelpers generates the Category method.
+	CategoryHelpers bool
+
+	// HelpLinks generates the HelpLink method, and makes GRPCStatus attach
+	// a google.rpc.Help detail for values with a HelpLink.
+	HelpLinks bool
+
+	// Problem holds the identifiers of the Problem method, generated only
+	// when it is set.
+	Problem *ProblemIdents
+
+	// Binary holds the identifiers of the MarshalCBOR and MarshalMsgpack
+	// methods, generated only when it is set.
+	Binary *BinaryIdents
+
+	// MessageResolver makes GetMessage consult the package's resolveMessage
+	// hook.
+	MessageResolver bool
+
+	// Legacy holds the regexp identifiers of ClassifyLegacy<Name>, generated
+	// only when it is set.
+	Legacy *LegacyIdents
+
+	// JSONUnmarshal is the qualified encoding/json.Unmarshal function.
+	// Parse<Name> and <Name>FromHTTPResponse are generated only when it is
+	// set.
+	JSONUnmarshal string
+
+	// Metrics holds the Prometheus identifiers. A counter incremented by Join
+	// and JoinWithMessage is generated only when it is set.
+	Metrics *MetricsIdents
+
+	// Metadata holds the qualified metadata identifiers. WithMetadata and
+	// WithField methods are generated only when it is set.
+	Metadata *MetadataIdents
+
+	// Trace holds the qualified trace identifiers. WithTraceContext methods
+	// are generated only when it is set, together with Metadata.
+	Trace *TraceIdents
+
+	// DetailWrap is the qualified details.Wrap function, set when at least one
+	// value has a detail type.
+	DetailWrap string
+}
+
+// NonZeroErrors returns the canonical values other than the zero value, the
+// values denoting an error.
+func (e *ErrorWrapper) NonZeroErrors() []*ErrorInfo {
+	var out []*ErrorInfo
+	for _, info := range e.Errors {
+		if info.Number != 0 {
+			out = append(out, info)
+		}
+	}
+	return out
+}
+
+// HelperSet is the input of the "valueHelpers" template: the per-value helpers
+// (sentinels, predicates, formatted constructors) of Errors, rendered with the
+// identifiers of the embedded ErrorWrapper. Errors holds canonical values
+// followed by aliases.
+type HelperSet struct {
+	*ErrorWrapper
+	Errors []*ErrorInfo
+}
+
+// ReturnType returns the result type of the constructors of e.
+func (e *ErrorWrapper) ReturnType() string {
+	if e.ConcreteReturn {
+		return "*Error"
+	}
+	return "error"
+}
+
+// Helpers returns the values whose helpers belong in the main rendering: all
+// of them, or only the non-deprecated ones when SplitDeprecated is set.
+func (e *ErrorWrapper) Helpers() *HelperSet {
+	if !e.SplitDeprecated {
+		return &HelperSet{ErrorWrapper: e, Errors: append(e.Errors[:len(e.Errors):len(e.Errors)], e.Aliases...)}
+	}
+	return e.helperSet(false)
+}
+
+// DeprecatedHelpers returns the deprecated values rendered by the
+// "deprecated" template.
+func (e *ErrorWrapper) DeprecatedHelpers() *HelperSet {
+	return e.helperSet(true)
+}
+
+func (e *ErrorWrapper) helperSet(deprecated bool) *HelperSet {
+	set := &HelperSet{ErrorWrapper: e}
+	for _, info := range append(e.Errors[:len(e.Errors):len(e.Errors)], e.Aliases...) {
+		if info.Deprecated == deprecated {
+			set.Errors = append(set.Errors, info)
+		}
+	}
+	return set
+}
+
+// RegistryIdents are the already-qualified identifiers of the registry
+// runtime package.
+type RegistryIdents struct {
+	MustRegister   string
+	Descriptor     string
+	SourceLocation string
+	// Registrant is the Go import path of the generated package, naming it
+	// when another package registers the same enum.
+	Registrant string
+}
+
+// AdapterIdents are the already-qualified identifiers of a runtime adapter.
+// Runtime names the target ("kratos", "connect", "typed", or "httpx" for a
+// new_errors_func with a custom signature); the other fields are set as the
+// target needs them.
+type AdapterIdents struct {
+	Runtime     string
+	New         string
+	Itoa        string
+	CodeType    string
+	UnknownCode string
+	StatusError string
+
+	// Error, NewErrorDetail, ErrorInfo, Help and HelpLink are the connect
+	// error type and detail constructor, and the google.rpc detail types
+	// the connect adapter attaches. Error is the generic typederror.Error
+	// of the typed adapter.
+	Error          string
+	NewErrorDetail string
+	ErrorInfo      string
+	Help           string
+	HelpLink       string
+
+	// Args is the argument list New is called with by the httpx adapter,
+	// e.g. "ctx, code, message". Context and Background are the qualified
+	// context.Context and context.Background, set when Args passes a context.
+	Args       string
+	Context    string
+	Background string
+}
+
+// KratosIdents are the already-qualified identifiers of the kratos
+// compatibility helpers: kratos errors.New, errors.FromError and errors.Error,
+// and fmt.Sprintf.
+type KratosIdents struct {
+	New       string
+	FromError string
+	Error     string
+	Sprintf   string
+}
+
+// ProblemIdents are the already-qualified problem package identifiers the
+// Problem method refers to.
+type ProblemIdents struct {
+	Details string
+}
+
+// BinaryIdents are the already-qualified httperrors identifiers of the binary
+// encoding methods, and the encodings to generate.
+type BinaryIdents struct {
+	FromError string
+	CBOR      bool
+	Msgpack   bool
+}
+
+// LegacyIdents are the already-qualified regexp identifiers the legacy
+// response classifier refers to.
+type LegacyIdents struct {
+	MustCompile string
+	Regexp      string
+}
+
+// CodeIdents are the already-qualified strconv and fmt identifiers the typed
+// error codes refer to.
+type CodeIdents struct {
+	Itoa   string
+	Quote  string
+	Errorf string
+}
+
+// LogIdents are the already-qualified log/slog identifiers the Severity and
+// LogValue methods refer to.
+type LogIdents struct {
+	Level      string
+	ErrorLevel string
+	Value      string
+	GroupValue string
+	Int        string
+	String     string
+}
+
+// MetricsIdents are the already-qualified Prometheus client identifiers of
+// the generated counter, and Var, the name of the package-level variable
+// holding it.
+type MetricsIdents struct {
+	Var                    string
+	CounterVec             string
+	NewCounterVec          string
+	CounterOpts            string
+	Register               string
+	AlreadyRegisteredError string
+	Itoa                   string
+}
+
+// PlaceholderIdents are the already-qualified fmt and strings identifiers of
+// the helpers filling named placeholders.
+type PlaceholderIdents struct {
+	Sprint      string
+	Errorf      string
+	NewReplacer string
+	Join        string
 }
 
-// Execute renders the error-helper methods for the wrapped enum.
+// MetadataIdents are the already-qualified identifiers of the metadata
+// runtime package.
+type MetadataIdents struct {
+	Wrap      string
+	WithField string
+	Chain     string
+	Error     string
+}
+
+// TraceIdents are the already-qualified context and OpenTelemetry trace
+// identifiers of the generated WithTraceContext methods.
+type TraceIdents struct {
+	Context                string
+	SpanContextFromContext string
+}
+
+// GRPCIdents are the already-qualified grpc/codes and grpc/status identifiers
+// the generated GRPCStatus helpers refer to.
+type GRPCIdents struct {
+	CodeType    string
+	UnknownCode string
+	StatusType  string
+	NewStatus   string
+	ErrorInfo   string
+	// Help and HelpLink are the google.rpc.Help types the GRPCStatus
+	// method attaches the HelpLink of a value with.
+	Help     string
+	HelpLink string
+}
+
+// HasRetryable reports whether any wrapped error is retryable.
+func (e *ErrorWrapper) HasRetryable() bool {
+	for _, info := range e.Errors {
+		if info.Retryable {
+			return true
+		}
+	}
+	return false
+}
+
+// Constructor returns the name of a package-level constructor of the value
+// named goName, prefix, goName and suffix joined, e.g. NewQuotaExceeded, with
+// a lower-case initial under UnexportedConstructors.
+func (e *ErrorWrapper) Constructor(prefix, goName, suffix string) string {
+	return ConstructorName(prefix+goName+suffix, e.UnexportedConstructors)
+}
+
+// ConstructorName returns the constructor name, unexported when unexported.
+func ConstructorName(name string, unexported bool) string {
+	if !unexported || name == "" {
+		return name
+	}
+	return strings.ToLower(name[:1]) + name[1:]
+}
+
+// HasExamples reports whether any value of the enum has an Example.
+func (e *ErrorWrapper) HasExamples() bool {
+	for _, info := range e.Errors {
+		if info.Example != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// HasPublicCodes reports whether the PublicCode method is generated: when
+// the enum has a CodePrefix, or a value has a PublicCode, as experimental
+// values do with an experimental code affix.
+func (e *ErrorWrapper) HasPublicCodes() bool {
+	if e.CodePrefix != "" {
+		return true
+	}
+	for _, info := range e.Errors {
+		if info.PublicCode != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Locales returns the sorted language tags of the localized messages of the
+// enum values, those of its message bundle.
+func (e *ErrorWrapper) Locales() []string {
+	seen := map[string]bool{}
+	var locales []string
+	for _, info := range e.Errors {
+		for lang := range info.LocalizedMessages {
+			if !seen[lang] {
+				seen[lang] = true
+				locales = append(locales, lang)
+			}
+		}
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// HasClientCodes reports whether any value of the enum has a ClientCode.
+func (e *ErrorWrapper) HasClientCodes() bool {
+	for _, info := range e.Errors {
+		if info.ClientCode != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// HasInternal reports whether any wrapped error is internal.
+func (e *ErrorWrapper) HasInternal() bool {
+	for _, info := range e.Errors {
+		if info.Internal {
+			return true
+		}
+	}
+	return false
+}
+
+// HTTPHeader is a response header of an error value. Value may hold {key}
+// placeholders filled from the metadata of the error.
+type HTTPHeader struct {
+	Name  string
+	Value string
+}
+
+// HasHTTPHeaders reports whether any wrapped error declares response
+// headers.
+func (e *ErrorWrapper) HasHTTPHeaders() bool {
+	for _, info := range e.Errors {
+		if len(info.HTTPHeaders) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// HasNewErrorsFuncs reports whether any wrapped error overrides the
+// constructor of the enum.
+func (e *ErrorWrapper) HasNewErrorsFuncs() bool {
+	for _, info := range e.Errors {
+		if info.NewErrorsFunc != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// HasSLOExempt reports whether any wrapped error is exempt from the SLO.
+func (e *ErrorWrapper) HasSLOExempt() bool {
+	for _, info := range e.Errors {
+		if info.SLOExempt {
+			return true
+		}
+	}
+	return false
+}
+
+// HasCacheControls reports whether any wrapped error suggests a
+// Cache-Control header.
+func (e *ErrorWrapper) HasCacheControls() bool {
+	for _, info := range e.Errors {
+		if info.CacheControl != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// HasLogSampleRates reports whether any wrapped error is logged at a rate
+// below 1.
+func (e *ErrorWrapper) HasLogSampleRates() bool {
+	for _, info := range e.Errors {
+		if info.LogSampleRate != "1" {
+			return true
+		}
+	}
+	return false
+}
+
+// HasLogMessages reports whether any wrapped error has a log message. Error()
+// then no longer returns the reason, and the generated code reads it from
+// GetReason instead.
+func (e *ErrorWrapper) HasLogMessages() bool {
+	for _, info := range e.Errors {
+		if info.LogMessage != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// HasFormat reports whether any wrapped error has a formatted message.
+func (e *ErrorWrapper) HasFormat() bool {
+	for _, info := range e.Errors {
+		if info.HasFormat() {
+			return true
+		}
+	}
+	return false
+}
+
+// HasPlaceholders reports whether any wrapped message has named placeholders.
+func (e *ErrorWrapper) HasPlaceholders() bool {
+	for _, info := range e.Errors {
+		if len(info.Placeholders()) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Execute renders the error-helper methods for the wrapped enum using the
+// built-in template.
 func (e *ErrorWrapper) Execute() (string, error) {
-	tmpl, err := template.New("errors").Parse(errorsTemplate)
+	var buf strings.Builder
+	if err := e.Render(&buf); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// Render writes the error-helper methods for the wrapped enum to w using the
+// built-in template, without holding the output in memory. Generators pass
+// the protogen.GeneratedFile itself.
+func (e *ErrorWrapper) Render(w io.Writer) error {
+	return e.RenderText(w, errorsTemplate)
+}
+
+// ExecuteDeprecated renders the helpers of the deprecated values using the
+// "deprecated" template defined by text, or by the built-in template when text
+// is empty.
+func (e *ErrorWrapper) ExecuteDeprecated(text string) (string, error) {
+	if text == "" {
+		text = errorsTemplate
+	}
+	tmpl, err := parse("errors", text)
 	if err != nil {
 		return "", err
 	}
 	var buf strings.Builder
-	if err := tmpl.Execute(&buf, e); err != nil {
+	if err := tmpl.ExecuteTemplate(&buf, "deprecated", e); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// ExecuteText renders the wrapped enum with text, a text/template source that
+// replaces the built-in template. The template receives the ErrorWrapper as
+// its root value.
+func (e *ErrorWrapper) ExecuteText(text string) (string, error) {
+	var buf strings.Builder
+	if err := e.RenderText(&buf, text); err != nil {
 		return "", err
 	}
 	return buf.String(), nil
 }
+
+// RenderText writes the wrapped enum rendered with text to w, as ExecuteText
+// returns it. On error w may hold partial output.
+func (e *ErrorWrapper) RenderText(w io.Writer, text string) error {
+	tmpl, err := parse("errors", text)
+	if err != nil {
+		return err
+	}
+	return tmpl.Execute(w, e)
+}
+
+// TestSet is the root of the tests template: one error enum and the qualified
+// testing.T, errors.Is and errors.As identifiers the generated test refers
+// to.
+type TestSet struct {
+	*ErrorWrapper
+	T        string
+	ErrorsIs string
+	ErrorsAs string
+	// Parse reports whether the Parse<Name> helper is generated, which the
+	// round trip of every value then goes through.
+	Parse bool
+	// B and AllocsPerRun are the qualified testing.B and testing.AllocsPerRun
+	// identifiers, used by the allocation test and benchmarks of Prebuilt
+	// enums.
+	B            string
+	AllocsPerRun string
+	// Race holds the identifiers of the concurrency test of enums with
+	// Metadata helpers. The test is generated only when it is set.
+	Race *RaceIdents
+}
+
+// RaceIdents are the already-qualified identifiers of the generated test
+// asserting concurrent With calls on a shared error leave it unchanged.
+type RaceIdents struct {
+	WaitGroup string
+	WithField string
+	From      string
+	Itoa      string
+}
+
+// ExecuteTests renders a test asserting the code, status, reason and message
+// of every value of the wrapped enum and that the error Join builds of it
+// parses back to it, plus for Prebuilt enums a test asserting Err does not
+// allocate and benchmarks of Err and Join, and with race a test asserting
+// concurrent With calls leave the shared base error unchanged.
+func (e *ErrorWrapper) ExecuteTests(testingT, errorsIs, errorsAs string, parseHelpers bool, testingB, allocsPerRun string, race *RaceIdents) (string, error) {
+	var buf strings.Builder
+	if err := e.RenderTests(&buf, testingT, errorsIs, errorsAs, parseHelpers, testingB, allocsPerRun, race); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// RenderTests writes the tests returned by ExecuteTests to w.
+func (e *ErrorWrapper) RenderTests(w io.Writer, testingT, errorsIs, errorsAs string, parseHelpers bool, testingB, allocsPerRun string, race *RaceIdents) error {
+	tmpl, err := parse("tests", testsTemplate)
+	if err != nil {
+		return err
+	}
+	return tmpl.Execute(w, &TestSet{ErrorWrapper: e, T: testingT, ErrorsIs: errorsIs, ErrorsAs: errorsAs, Parse: parseHelpers, B: testingB, AllocsPerRun: allocsPerRun, Race: race})
+}
+
+// FuzzSet is the root of the fuzz template: one error enum and the qualified
+// testing.T, testing.F and strconv.Itoa identifiers the generated tests and
+// fuzz targets refer to.
+type FuzzSet struct {
+	*ErrorWrapper
+	T    string
+	F    string
+	Itoa string
+}
+
+// ExecuteFuzz renders a test asserting every value of the wrapped enum parses
+// back from its code and from an error response, and fuzz targets asserting
+// Parse<Name> and <Name>FromHTTPResponse never return a value not matching
+// their input.
+func (e *ErrorWrapper) ExecuteFuzz(testingT, testingF, itoa string) (string, error) {
+	var buf strings.Builder
+	if err := e.RenderFuzz(&buf, testingT, testingF, itoa); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// RenderFuzz writes the tests and fuzz targets returned by ExecuteFuzz to w.
+func (e *ErrorWrapper) RenderFuzz(w io.Writer, testingT, testingF, itoa string) error {
+	tmpl, err := parse("fuzz", fuzzTemplate)
+	if err != nil {
+		return err
+	}
+	return tmpl.Execute(w, &FuzzSet{ErrorWrapper: e, T: testingT, F: testingF, Itoa: itoa})
+}
+
+// templateKey identifies a parsed template in the parsed cache.
+type templateKey struct {
+	name, text string
+}
+
+// parsed caches the templates parsed by parse. A parsed template may be
+// executed concurrently, and every enum of a request shares the same few
+// templates, so each one is parsed once.
+var parsed sync.Map
+
+// parse returns text parsed as a template named name.
+func parse(name, text string) (*template.Template, error) {
+	key := templateKey{name, text}
+	if tmpl, ok := parsed.Load(key); ok {
+		return tmpl.(*template.Template), nil
+	}
+	tmpl, err := template.New(name).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	actual, _ := parsed.LoadOrStore(key, tmpl)
+	return actual.(*template.Template), nil
+}
@@ -4,6 +4,7 @@ package testutil
 
 import (
 	"os"
+	"slices"
 	"testing"
 
 	"google.golang.org/protobuf/compiler/protogen"
@@ -30,6 +31,25 @@ func LoadDescriptorSet(t *testing.T, path string) *descriptorpb.FileDescriptorSe
 	return &set
 }
 
+// SetLeadingComment replaces the leading comment of the element at path, a
+// SourceCodeInfo path such as [5, 0, 2, 1] for the second value of the first
+// enum, of the file named file in set.
+func SetLeadingComment(t *testing.T, set *descriptorpb.FileDescriptorSet, file string, path []int32, comment string) {
+	t.Helper()
+	for _, f := range set.File {
+		if f.GetName() != file {
+			continue
+		}
+		for _, loc := range f.GetSourceCodeInfo().GetLocation() {
+			if slices.Equal(loc.Path, path) {
+				loc.LeadingComments = proto.String(comment)
+				return
+			}
+		}
+	}
+	t.Fatalf("no source location %v in %q", path, file)
+}
+
 // MustCreatePlugin builds a real *protogen.Plugin from a descriptor set. The set
 // must include every dependency (compile with --include_imports); fileToGenerate
 // is the proto path (relative to the proto_path) that should be generated.
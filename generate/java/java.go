@@ -0,0 +1,170 @@
+// Package java implements the Java output of protoc-gen-sphere-errors. It
+// mirrors every error enum as a Java enum carrying the code, HTTP status,
+// reason and default message of each value, plus an exception class carrying
+// it, so partner SDKs handle the same errors as the backend.
+package java
+
+import (
+	_ "embed"
+	"fmt"
+	"path"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/errors"
+	errtemplate "github.com/go-sphere/protoc-gen-sphere-errors/generate/internal/template"
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+//go:embed java.tmpl
+var javaTemplate string
+
+// fileData is the template root for one generated .java file.
+type fileData struct {
+	Source  string
+	Package string
+	Class   string
+	Enums   []*errtemplate.ErrorWrapper
+}
+
+// GenerateFile generates the <Class>.java file for file, next to its other
+// outputs, resolving the errors with config. Class is the CamelCased base
+// name of the proto file followed by Errors, e.g. BasicErrorsErrors for
+// basic_errors.proto, so it never collides with the outer class of
+// protoc-gen-java, and the Java package is the java_package option or, when
+// unset, the proto package. It returns a nil GeneratedFile (and nil error)
+// when file declares no error enums.
+func GenerateFile(gen *protogen.Plugin, file *protogen.File, config *errors.Config) (*protogen.GeneratedFile, error) {
+	enums := errors.ErrorEnums(file, config)
+	if len(enums) == 0 {
+		return nil, nil
+	}
+	tmpl, err := template.New("java").Funcs(template.FuncMap{
+		"quote":        quote,
+		"constantName": constantName,
+		"doc":          doc,
+	}).Parse(javaTemplate)
+	if err != nil {
+		return nil, err
+	}
+	pkg := file.Proto.GetOptions().GetJavaPackage()
+	if pkg == "" {
+		pkg = string(file.Desc.Package())
+	}
+	class := className(file.Desc.Path())
+	var buf strings.Builder
+	if err := tmpl.Execute(&buf, &fileData{Source: file.Desc.Path(), Package: packageName(pkg), Class: class, Enums: enums}); err != nil {
+		return nil, err
+	}
+	g := gen.NewGeneratedFile(path.Join(path.Dir(file.GeneratedFilenamePrefix), class+".java"), "")
+	g.P(buf.String())
+	return g, nil
+}
+
+// className returns the name of the class generated for the proto file
+// source: the CamelCased base name followed by Errors.
+func className(source string) string {
+	base := strings.TrimSuffix(path.Base(source), ".proto")
+	var b strings.Builder
+	upper := true
+	for _, r := range base {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	name := b.String()
+	if name == "" || unicode.IsDigit(rune(name[0])) {
+		name = "_" + name
+	}
+	return name + "Errors"
+}
+
+// keywords are the Java reserved words, which cannot be package name
+// segments and get an underscore appended, as protoc-gen-java does.
+var keywords = map[string]bool{
+	"abstract": true, "assert": true, "boolean": true, "break": true, "byte": true,
+	"case": true, "catch": true, "char": true, "class": true, "const": true,
+	"continue": true, "default": true, "do": true, "double": true, "else": true,
+	"enum": true, "extends": true, "final": true, "finally": true, "float": true,
+	"for": true, "goto": true, "if": true, "implements": true, "import": true,
+	"instanceof": true, "int": true, "interface": true, "long": true, "native": true,
+	"new": true, "package": true, "private": true, "protected": true, "public": true,
+	"return": true, "short": true, "static": true, "strictfp": true, "super": true,
+	"switch": true, "synchronized": true, "this": true, "throw": true, "throws": true,
+	"transient": true, "try": true, "void": true, "volatile": true, "while": true,
+	"true": true, "false": true, "null": true,
+}
+
+// packageName escapes the reserved segments of the dotted package pkg.
+func packageName(pkg string) string {
+	parts := strings.Split(pkg, ".")
+	for i, p := range parts {
+		if keywords[p] {
+			parts[i] = p + "_"
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+// constantName returns the Java enum constant name of the value valueName of
+// enumName: UserError.USER_ERROR_NOT_FOUND becomes NOT_FOUND.
+func constantName(enumName, valueName string) string {
+	name := errors.TrimValuePrefix(enumName, valueName)
+	if name == "" || unicode.IsDigit(rune(name[0])) {
+		return "_" + name
+	}
+	return name
+}
+
+// doc renders text as a Javadoc comment indented by indent and terminated by
+// a newline. A backslash before u is doubled, since javac reads unicode
+// escapes even in comments. It returns "" for empty text.
+func doc(indent, text string) string {
+	if text == "" {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString(indent + "/**\n")
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.ReplaceAll(strings.ReplaceAll(line, "*/", "* /"), `\u`, `\\u`)
+		b.WriteString(strings.TrimRight(indent+" * "+line, " ") + "\n")
+	}
+	b.WriteString(indent + " */\n")
+	return b.String()
+}
+
+// quote renders s as a Java string literal. Control characters use octal
+// escapes, as a unicode escape of a line break would end the literal.
+func quote(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&b, `\%03o`, r)
+				continue
+			}
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
@@ -0,0 +1,105 @@
+package java
+
+import (
+	"flag"
+	"os"
+	"testing"
+
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/errors"
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/internal/testutil"
+)
+
+var updateGolden = flag.Bool("update-golden", false, "update golden files instead of comparing")
+
+func TestGenerateFile_Golden(t *testing.T) {
+	for _, name := range []string{"basic_errors", "aliased_errors"} {
+		t.Run(name, func(t *testing.T) {
+			plugin := testutil.PluginFromPB(t, "../errors/testdata/pb/"+name+".pb", name+".proto")
+			genFile, err := GenerateFile(plugin, testutil.FileToGenerate(t, plugin), &errors.Config{})
+			if err != nil {
+				t.Fatalf("GenerateFile failed: %v", err)
+			}
+			if genFile == nil {
+				t.Fatal("expected generated file, got nil")
+			}
+			content, err := genFile.Content()
+			if err != nil {
+				t.Fatalf("GeneratedFile.Content() failed: %v", err)
+			}
+
+			goldenFile := "testdata/golden/" + name + ".java"
+			if *updateGolden {
+				if err := os.MkdirAll("testdata/golden", 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(goldenFile, content, 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			want, err := os.ReadFile(goldenFile)
+			if err != nil {
+				t.Fatalf("read golden file (run `make update-golden` to create it): %v", err)
+			}
+			if string(want) != string(content) {
+				t.Errorf("generated content mismatch for %s:\n%s", goldenFile, content)
+			}
+		})
+	}
+}
+
+func TestGenerateFile_NoErrors(t *testing.T) {
+	plugin := testutil.PluginFromPB(t, "../errors/testdata/pb/no_errors.pb", "no_errors.proto")
+	genFile, err := GenerateFile(plugin, testutil.FileToGenerate(t, plugin), &errors.Config{})
+	if err != nil {
+		t.Fatalf("GenerateFile failed: %v", err)
+	}
+	if genFile != nil {
+		t.Error("expected nil for file without error enums, got non-nil")
+	}
+}
+
+func TestClassName(t *testing.T) {
+	tests := []struct{ source, want string }{
+		{"basic_errors.proto", "BasicErrorsErrors"},
+		{"shop/v1/cart-errors.proto", "CartErrorsErrors"},
+		{"v1.proto", "V1Errors"},
+		{"1st.proto", "_1stErrors"},
+	}
+	for _, tt := range tests {
+		if got := className(tt.source); got != tt.want {
+			t.Errorf("className(%q) = %s, want %s", tt.source, got, tt.want)
+		}
+	}
+}
+
+func TestConstantName(t *testing.T) {
+	tests := []struct{ enum, value, want string }{
+		{"UserError", "USER_ERROR_NOT_FOUND", "NOT_FOUND"},
+		{"HTTPError", "HTTP_ERROR_404", "_404"},
+		{"AuthError", "TOKEN_EXPIRED", "TOKEN_EXPIRED"},
+	}
+	for _, tt := range tests {
+		if got := constantName(tt.enum, tt.value); got != tt.want {
+			t.Errorf("constantName(%q, %q) = %s, want %s", tt.enum, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestPackageName(t *testing.T) {
+	if got, want := packageName("com.example.int.v1"), "com.example.int_.v1"; got != want {
+		t.Errorf("packageName() = %s, want %s", got, want)
+	}
+}
+
+func TestQuote(t *testing.T) {
+	if got, want := quote("say \"hi\"\n\x01 \\u0041"), `"say \"hi\"\n\001 \\u0041"`; got != want {
+		t.Errorf("quote() = %s, want %s", got, want)
+	}
+}
+
+func TestDoc(t *testing.T) {
+	if got, want := doc("", "see C:\\users */"), "/**\n * see C:\\\\users * /\n */\n"; got != want {
+		t.Errorf("doc() = %q, want %q", got, want)
+	}
+}
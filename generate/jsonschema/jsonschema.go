@@ -0,0 +1,139 @@
+// Package jsonschema implements the JSON Schema output of
+// protoc-gen-sphere-errors. It writes one errors.schema.json per proto
+// package describing the error response body written by httperrors: the code
+// is constrained to the codes of the package, and each error value pins the
+// reason going with its code, so consumer-driven contract tests (Pact,
+// schemathesis) can validate error responses automatically.
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"slices"
+
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/catalog"
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/errors"
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// Supported JSON Schema dialects.
+const (
+	FormatDraft2020 = "2020-12"
+	FormatDraft07   = "draft-07"
+)
+
+// dialects are the $schema URIs of the supported dialects.
+var dialects = map[string]string{
+	FormatDraft2020: "https://json-schema.org/draft/2020-12/schema",
+	FormatDraft07:   "http://json-schema.org/draft-07/schema#",
+}
+
+// ValidateFormat rejects JSON Schema dialects other than 2020-12 and
+// draft-07.
+func ValidateFormat(format string) error {
+	if _, ok := dialects[format]; !ok {
+		return fmt.Errorf("invalid json_schema_out %q, expected %s or %s", format, FormatDraft2020, FormatDraft07)
+	}
+	return nil
+}
+
+// internalMessage is the message of the body httperrors writes for errors
+// that are not generated errors, or are internal.
+const internalMessage = "internal error"
+
+// Schema is the subset of a JSON Schema used by the error body. Its keywords
+// mean the same in both supported dialects.
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	ID                   string             `json:"$id,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Const                any                `json:"const,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+}
+
+// Build returns the schema of the error responses of the errors of c in the
+// dialect format. Besides the error values, the body of the internal error,
+// with code 0, is valid. A value with a client code may be answered with
+// either code, as clients of an Encoder with ClientCodes see the latter.
+func Build(c *catalog.Catalog, format string) *Schema {
+	codes := []int32{0}
+	branches := []*Schema{{
+		Title: "internal error",
+		Properties: map[string]*Schema{
+			"code":    {Const: int32(0)},
+			"message": {Const: internalMessage},
+		},
+	}}
+	for _, e := range c.Errors {
+		code := &Schema{Const: e.Code}
+		codes = append(codes, e.Code)
+		if e.ClientCode != 0 && e.ClientCode != e.Code {
+			code = &Schema{Enum: []any{e.Code, e.ClientCode}}
+			codes = append(codes, e.ClientCode)
+		}
+		branches = append(branches, &Schema{
+			Title:       e.Enum + "." + e.Value,
+			Description: e.Description,
+			Properties: map[string]*Schema{
+				"code":   code,
+				"reason": {Const: e.Reason},
+			},
+		})
+	}
+	slices.Sort(codes)
+	codes = slices.Compact(codes)
+	enum := make([]any, len(codes))
+	for i, code := range codes {
+		enum[i] = code
+	}
+	return &Schema{
+		Schema:   dialects[format],
+		ID:       c.Package + ".errors.schema.json",
+		Title:    c.Package + " error response",
+		Type:     "object",
+		Required: []string{"code", "message"},
+		Properties: map[string]*Schema{
+			"code":    {Type: "integer", Description: "Numeric error code.", Enum: enum},
+			"reason":  {Type: "string", Description: "Machine-readable error reason."},
+			"message": {Type: "string", Description: "Human-readable error message."},
+			"details": {
+				Type:                 "object",
+				Description:          "String metadata attached to the error.",
+				AdditionalProperties: &Schema{Type: "string"},
+			},
+		},
+		AnyOf: branches,
+	}
+}
+
+// Marshal encodes s as indented JSON.
+func Marshal(s *Schema) ([]byte, error) {
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+// GenerateFiles writes one error response schema per proto package among the
+// files marked for generation, next to the package's first generated file.
+func GenerateFiles(gen *protogen.Plugin, config *errors.Config, format string) error {
+	for _, c := range catalog.Build(catalog.GeneratedFiles(gen), config) {
+		b, err := Marshal(Build(c, format))
+		if err != nil {
+			return err
+		}
+		g := gen.NewGeneratedFile(path.Join(c.Dir, "errors.schema.json"), "")
+		if _, err := g.Write(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
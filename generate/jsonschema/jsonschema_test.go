@@ -0,0 +1,86 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/catalog"
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/errors"
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/internal/testutil"
+)
+
+func TestBuild(t *testing.T) {
+	c := &catalog.Catalog{
+		Package: "tests.basic",
+		Errors: []*catalog.Entry{
+			{Enum: "UserError", Value: "USER_ERROR_NOT_FOUND", Code: 2, Status: 404, Reason: "user not found", Description: "The user does not exist."},
+			{Enum: "UserError", Value: "USER_ERROR_GONE", Code: 5, ClientCode: 2, Status: 410, Reason: "user gone"},
+			{Enum: "OrderError", Value: "ORDER_ERROR_NOT_FOUND", Code: 2, Status: 404, Reason: "order not found"},
+		},
+	}
+	s := Build(c, FormatDraft07)
+	if s.Schema != "http://json-schema.org/draft-07/schema#" || s.ID != "tests.basic.errors.schema.json" {
+		t.Errorf("$schema, $id = %q, %q", s.Schema, s.ID)
+	}
+	if want := []any{int32(0), int32(2), int32(5)}; !reflect.DeepEqual(s.Properties["code"].Enum, want) {
+		t.Errorf("code enum = %v, want %v", s.Properties["code"].Enum, want)
+	}
+	if !reflect.DeepEqual(s.Required, []string{"code", "message"}) {
+		t.Errorf("required = %v", s.Required)
+	}
+	if len(s.AnyOf) != 4 {
+		t.Fatalf("len(anyOf) = %d, want the internal error and 3 values", len(s.AnyOf))
+	}
+	if internal := s.AnyOf[0]; internal.Properties["code"].Const != int32(0) || internal.Properties["message"].Const != "internal error" {
+		t.Errorf("internal error branch = %+v", internal.Properties)
+	}
+	notFound := s.AnyOf[1]
+	if notFound.Title != "UserError.USER_ERROR_NOT_FOUND" || notFound.Description != "The user does not exist." ||
+		notFound.Properties["code"].Const != int32(2) || notFound.Properties["reason"].Const != "user not found" {
+		t.Errorf("value branch = %+v", notFound)
+	}
+	if gone := s.AnyOf[2].Properties["code"]; gone.Const != nil || !reflect.DeepEqual(gone.Enum, []any{int32(5), int32(2)}) {
+		t.Errorf("code of a value with a client code = %+v, want either code", gone)
+	}
+	if s := Build(c, FormatDraft2020); s.Schema != "https://json-schema.org/draft/2020-12/schema" {
+		t.Errorf("$schema = %q", s.Schema)
+	}
+}
+
+func TestGenerateFiles(t *testing.T) {
+	plugin := testutil.PluginFromPB(t, "../errors/testdata/pb/basic_errors.pb", "basic_errors.proto")
+	if err := GenerateFiles(plugin, &errors.Config{}, FormatDraft2020); err != nil {
+		t.Fatalf("GenerateFiles failed: %v", err)
+	}
+	resp := plugin.Response()
+	if len(resp.File) != 1 {
+		t.Fatalf("len(File) = %d, want 1", len(resp.File))
+	}
+	f := resp.File[0]
+	if !strings.HasSuffix(f.GetName(), "testdata/basic/errors.schema.json") {
+		t.Errorf("file name = %q", f.GetName())
+	}
+	var doc map[string]any
+	if err := json.Unmarshal([]byte(f.GetContent()), &doc); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if doc["$schema"] != "https://json-schema.org/draft/2020-12/schema" || doc["title"] != "tests.basic error response" {
+		t.Errorf("schema = %v", doc)
+	}
+	if anyOf, _ := doc["anyOf"].([]any); len(anyOf) < 2 {
+		t.Errorf("anyOf = %v, want a branch per error value", doc["anyOf"])
+	}
+}
+
+func TestValidateFormat(t *testing.T) {
+	for _, format := range []string{FormatDraft2020, FormatDraft07} {
+		if err := ValidateFormat(format); err != nil {
+			t.Errorf("ValidateFormat(%q) = %v", format, err)
+		}
+	}
+	if err := ValidateFormat("draft-04"); err == nil {
+		t.Error("expected error for unsupported dialect")
+	}
+}
@@ -0,0 +1,136 @@
+// Package kotlin implements the Kotlin output of protoc-gen-sphere-errors. It
+// mirrors every error enum as a sealed class of Exception with one data object
+// per value, carrying the code, HTTP status, reason and default message, so
+// Android clients handle the same errors as the backend.
+package kotlin
+
+import (
+	_ "embed"
+	"fmt"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/errors"
+	errtemplate "github.com/go-sphere/protoc-gen-sphere-errors/generate/internal/template"
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+//go:embed kotlin.tmpl
+var kotlinTemplate string
+
+// fileData is the template root for one generated .kt file.
+type fileData struct {
+	Source  string
+	Package string
+	Enums   []*errtemplate.ErrorWrapper
+}
+
+// GenerateFile generates the <prefix>.errors.kt file for file, resolving the
+// errors with config. The Kotlin package is the java_package option or, when
+// unset, the proto package. It returns a nil GeneratedFile (and nil error)
+// when file declares no error enums.
+func GenerateFile(gen *protogen.Plugin, file *protogen.File, config *errors.Config) (*protogen.GeneratedFile, error) {
+	enums := errors.ErrorEnums(file, config)
+	if len(enums) == 0 {
+		return nil, nil
+	}
+	tmpl, err := template.New("kotlin").Funcs(template.FuncMap{
+		"quote":      quote,
+		"objectName": objectName,
+		"doc":        doc,
+	}).Parse(kotlinTemplate)
+	if err != nil {
+		return nil, err
+	}
+	pkg := file.Proto.GetOptions().GetJavaPackage()
+	if pkg == "" {
+		pkg = string(file.Desc.Package())
+	}
+	var buf strings.Builder
+	if err := tmpl.Execute(&buf, &fileData{Source: file.Desc.Path(), Package: packageName(pkg), Enums: enums}); err != nil {
+		return nil, err
+	}
+	g := gen.NewGeneratedFile(file.GeneratedFilenamePrefix+".errors.kt", "")
+	g.P(buf.String())
+	return g, nil
+}
+
+// keywords are the Kotlin hard keywords, which must be escaped with backticks
+// to be used as package name segments.
+var keywords = map[string]bool{
+	"as": true, "break": true, "class": true, "continue": true, "do": true,
+	"else": true, "false": true, "for": true, "fun": true, "if": true, "in": true,
+	"interface": true, "is": true, "null": true, "object": true, "package": true,
+	"return": true, "super": true, "this": true, "throw": true, "true": true,
+	"try": true, "typealias": true, "typeof": true, "val": true, "var": true,
+	"when": true, "while": true,
+}
+
+// packageName escapes the keyword segments of the dotted package pkg.
+func packageName(pkg string) string {
+	parts := strings.Split(pkg, ".")
+	for i, p := range parts {
+		if keywords[p] {
+			parts[i] = "`" + p + "`"
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+// objectName returns the UpperCamelCase object name of the value valueName of
+// enumName: UserError.USER_ERROR_NOT_FOUND becomes NotFound.
+func objectName(enumName, valueName string) string {
+	name := errors.ValueName(enumName, valueName)
+	if name == "" || unicode.IsDigit(rune(name[0])) {
+		return "_" + name
+	}
+	return name
+}
+
+// doc renders text as a KDoc comment indented by indent and terminated by a
+// newline. It returns "" for empty text.
+func doc(indent, text string) string {
+	if text == "" {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString(indent + "/**\n")
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.ReplaceAll(line, "*/", "* /")
+		b.WriteString(strings.TrimRight(indent+" * "+line, " ") + "\n")
+	}
+	b.WriteString(indent + " */\n")
+	return b.String()
+}
+
+// quote renders s as a Kotlin string literal. The dollar sign is escaped so
+// messages never start a string template.
+func quote(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '$':
+			b.WriteString(`\$`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&b, `\u%04x`, r)
+				continue
+			}
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
@@ -0,0 +1,64 @@
+// Package markdown implements the Markdown documentation output of
+// protoc-gen-sphere-errors. It writes one <prefix>.errors.md per proto file
+// with a table per error enum: code, name, HTTP status, message and the
+// description taken from the value's leading comment, followed by the example
+// error response bodies of its values.
+package markdown
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"strings"
+	"text/template"
+
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/errors"
+	errtemplate "github.com/go-sphere/protoc-gen-sphere-errors/generate/internal/template"
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+//go:embed markdown.tmpl
+var mdTemplate string
+
+// fileData is the template root for one generated .md file.
+type fileData struct {
+	Source string
+	Enums  []*errtemplate.ErrorWrapper
+}
+
+// GenerateFile generates the <prefix>.errors.md file for file, resolving the
+// errors with config. It returns a nil GeneratedFile (and nil error) when file
+// declares no error enums.
+func GenerateFile(gen *protogen.Plugin, file *protogen.File, config *errors.Config) (*protogen.GeneratedFile, error) {
+	enums := errors.ErrorEnums(file, config)
+	if len(enums) == 0 {
+		return nil, nil
+	}
+	tmpl, err := template.New("markdown").Funcs(template.FuncMap{"cell": cell, "indentJSON": indentJSON}).Parse(mdTemplate)
+	if err != nil {
+		return nil, err
+	}
+	var buf strings.Builder
+	if err := tmpl.Execute(&buf, &fileData{Source: file.Desc.Path(), Enums: enums}); err != nil {
+		return nil, err
+	}
+	g := gen.NewGeneratedFile(file.GeneratedFilenamePrefix+".errors.md", "")
+	g.P(buf.String())
+	return g, nil
+}
+
+// cell escapes s for use inside a Markdown table cell: pipes are escaped and
+// line breaks become <br>.
+func cell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", "<br>")
+}
+
+// indentJSON returns the compact JSON s indented by two spaces.
+func indentJSON(s string) string {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, []byte(s), "", "  "); err != nil {
+		return s
+	}
+	return buf.String()
+}
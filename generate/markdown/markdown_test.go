@@ -0,0 +1,88 @@
+package markdown
+
+import (
+	"flag"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/errors"
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/internal/testutil"
+)
+
+var updateGolden = flag.Bool("update-golden", false, "update golden files instead of comparing")
+
+func TestGenerateFile_Golden(t *testing.T) {
+	plugin := testutil.PluginFromPB(t, "../errors/testdata/pb/basic_errors.pb", "basic_errors.proto")
+	genFile, err := GenerateFile(plugin, testutil.FileToGenerate(t, plugin), &errors.Config{})
+	if err != nil {
+		t.Fatalf("GenerateFile failed: %v", err)
+	}
+	if genFile == nil {
+		t.Fatal("expected generated file, got nil")
+	}
+	content, err := genFile.Content()
+	if err != nil {
+		t.Fatalf("GeneratedFile.Content() failed: %v", err)
+	}
+
+	const goldenFile = "testdata/golden/basic_errors.errors.md"
+	if *updateGolden {
+		if err := os.MkdirAll("testdata/golden", 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(goldenFile, content, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(goldenFile)
+	if err != nil {
+		t.Fatalf("read golden file (run `make update-golden` to create it): %v", err)
+	}
+	if string(want) != string(content) {
+		t.Errorf("generated content mismatch for %s:\n%s", goldenFile, content)
+	}
+}
+
+func TestGenerateFile_Examples(t *testing.T) {
+	set := testutil.LoadDescriptorSet(t, "../errors/testdata/pb/basic_errors.pb")
+	testutil.SetLeadingComment(t, set, "basic_errors.proto", []int32{5, 0, 2, 2}, " Returned when no user matches the requested ID.\n\n Example: {\"code\": 2, \"reason\": \"user not found\",\n   \"message\": \"user 42 does not exist\"}\n")
+	plugin := testutil.MustCreatePlugin(t, set, "basic_errors.proto")
+	genFile, err := GenerateFile(plugin, testutil.FileToGenerate(t, plugin), &errors.Config{})
+	if err != nil {
+		t.Fatalf("GenerateFile failed: %v", err)
+	}
+	content, err := genFile.Content()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"| 2 | `USER_ERROR_NOT_FOUND` | 404 | user does not exist | Returned when no user matches the requested ID. |",
+		"### UserError examples\n\n`USER_ERROR_NOT_FOUND` (404):\n\n```json\n{\n  \"code\": 2,\n  \"reason\": \"user not found\",\n  \"message\": \"user 42 does not exist\"\n}\n```\n",
+	} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("markdown missing %q:\n%s", want, content)
+		}
+	}
+	if strings.Contains(string(content), "OrderError examples") {
+		t.Error("OrderError has no examples but got a section")
+	}
+}
+
+func TestGenerateFile_NoErrors(t *testing.T) {
+	plugin := testutil.PluginFromPB(t, "../errors/testdata/pb/no_errors.pb", "no_errors.proto")
+	genFile, err := GenerateFile(plugin, testutil.FileToGenerate(t, plugin), &errors.Config{})
+	if err != nil {
+		t.Fatalf("GenerateFile failed: %v", err)
+	}
+	if genFile != nil {
+		t.Error("expected nil for file without error enums, got non-nil")
+	}
+}
+
+func TestCell(t *testing.T) {
+	if got, want := cell("a | b\nc"), `a \| b<br>c`; got != want {
+		t.Errorf("cell() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,194 @@
+// Package openapi implements the OpenAPI output of protoc-gen-sphere-errors.
+// It writes one errors.openapi.json (or .yaml) per proto package holding an
+// OpenAPI 3 components object: a shared Error schema and one reusable response
+// per HTTP status used by the package's errors, with an example per error:
+// the example body declared for it, or else one built from its code, reason
+// and message.
+// The document is meant to be merged into the spec produced by
+// protoc-gen-sphere.
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"strconv"
+
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/catalog"
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/errors"
+	"google.golang.org/protobuf/compiler/protogen"
+	"gopkg.in/yaml.v3"
+)
+
+// errorSchemaRef points at the shared error body schema.
+const errorSchemaRef = "#/components/schemas/Error"
+
+// Document is the subset of an OpenAPI 3 document emitted by the plugin.
+type Document struct {
+	OpenAPI    string     `json:"openapi" yaml:"openapi"`
+	Info       Info       `json:"info" yaml:"info"`
+	Paths      struct{}   `json:"paths" yaml:"paths"`
+	Components Components `json:"components" yaml:"components"`
+}
+
+// Info is the OpenAPI info object.
+type Info struct {
+	Title   string `json:"title" yaml:"title"`
+	Version string `json:"version" yaml:"version"`
+}
+
+// Components holds the reusable schemas and responses. MethodResponses, an
+// x-method-responses extension, holds per full gRPC method name the responses
+// object of the errors of the package the method declares it may return,
+// keyed by HTTP status, to be copied into the operation of the method.
+type Components struct {
+	Schemas         map[string]*Schema                `json:"schemas" yaml:"schemas"`
+	Responses       map[string]*Response              `json:"responses" yaml:"responses"`
+	MethodResponses map[string]map[string]ResponseRef `json:"x-method-responses,omitempty" yaml:"x-method-responses,omitempty"`
+}
+
+// ResponseRef is an OpenAPI reference to a reusable response.
+type ResponseRef struct {
+	Ref string `json:"$ref" yaml:"$ref"`
+}
+
+// Schema is the subset of the OpenAPI schema object used by the error body.
+type Schema struct {
+	Ref         string             `json:"$ref,omitempty" yaml:"$ref,omitempty"`
+	Type        string             `json:"type,omitempty" yaml:"type,omitempty"`
+	Format      string             `json:"format,omitempty" yaml:"format,omitempty"`
+	Description string             `json:"description,omitempty" yaml:"description,omitempty"`
+	Required    []string           `json:"required,omitempty" yaml:"required,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty" yaml:"properties,omitempty"`
+}
+
+// Response is an OpenAPI response object.
+type Response struct {
+	Description string                `json:"description" yaml:"description"`
+	Content     map[string]*MediaType `json:"content" yaml:"content"`
+}
+
+// MediaType is an OpenAPI media type object.
+type MediaType struct {
+	Schema   *Schema             `json:"schema" yaml:"schema"`
+	Examples map[string]*Example `json:"examples,omitempty" yaml:"examples,omitempty"`
+}
+
+// Example is an OpenAPI example object. Value is an ErrorBody, or the
+// example body declared for the error.
+type Example struct {
+	Summary string `json:"summary,omitempty" yaml:"summary,omitempty"`
+	Value   any    `json:"value" yaml:"value"`
+}
+
+// ErrorBody is the error response body described by the Error schema.
+type ErrorBody struct {
+	Code    int32  `json:"code" yaml:"code"`
+	Reason  string `json:"reason" yaml:"reason"`
+	Message string `json:"message" yaml:"message"`
+}
+
+// ResponseName returns the components.responses key used for an HTTP status,
+// e.g. "Error404".
+func ResponseName(status int32) string {
+	return "Error" + strconv.Itoa(int(status))
+}
+
+// Build returns the OpenAPI document for the errors of c.
+func Build(c *catalog.Catalog) *Document {
+	doc := &Document{
+		OpenAPI: "3.0.3",
+		Info:    Info{Title: c.Package + " errors", Version: "1.0.0"},
+		Components: Components{
+			Schemas: map[string]*Schema{
+				"Error": {
+					Type:     "object",
+					Required: []string{"code", "message"},
+					Properties: map[string]*Schema{
+						"code":    {Type: "integer", Format: "int32", Description: "Numeric error code."},
+						"reason":  {Type: "string", Description: "Machine-readable error reason."},
+						"message": {Type: "string", Description: "Human-readable error message."},
+					},
+				},
+			},
+			Responses: map[string]*Response{},
+		},
+	}
+	for _, e := range c.Errors {
+		name := ResponseName(e.Status)
+		resp, ok := doc.Components.Responses[name]
+		if !ok {
+			description := http.StatusText(int(e.Status))
+			if description == "" {
+				description = "HTTP " + strconv.Itoa(int(e.Status))
+			}
+			resp = &Response{
+				Description: description,
+				Content: map[string]*MediaType{
+					"application/json": {
+						Schema:   &Schema{Ref: errorSchemaRef},
+						Examples: map[string]*Example{},
+					},
+				},
+			}
+			doc.Components.Responses[name] = resp
+		}
+		// The generated Join helper falls back to the reason when no message
+		// is declared, so the example body does the same.
+		message := e.Message
+		if message == "" {
+			message = e.Reason
+		}
+		var value any = ErrorBody{Code: e.Code, Reason: e.Reason, Message: message}
+		if e.Example != nil {
+			value = e.Example
+		}
+		resp.Content["application/json"].Examples[e.Enum+"."+e.Value] = &Example{
+			Summary: message,
+			Value:   value,
+		}
+		for _, method := range e.Methods {
+			if doc.Components.MethodResponses == nil {
+				doc.Components.MethodResponses = map[string]map[string]ResponseRef{}
+			}
+			if doc.Components.MethodResponses[method] == nil {
+				doc.Components.MethodResponses[method] = map[string]ResponseRef{}
+			}
+			doc.Components.MethodResponses[method][strconv.Itoa(int(e.Status))] = ResponseRef{Ref: "#/components/responses/" + name}
+		}
+	}
+	return doc
+}
+
+// Marshal encodes doc in the given format.
+func Marshal(doc *Document, format string) ([]byte, error) {
+	switch format {
+	case catalog.FormatJSON:
+		b, err := json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(b, '\n'), nil
+	case catalog.FormatYAML:
+		return yaml.Marshal(doc)
+	default:
+		return nil, fmt.Errorf("invalid openapi format %q, expected %s or %s", format, catalog.FormatJSON, catalog.FormatYAML)
+	}
+}
+
+// GenerateFiles writes one OpenAPI components document per proto package among
+// the files marked for generation, next to the package's first generated file.
+func GenerateFiles(gen *protogen.Plugin, config *errors.Config, format string) error {
+	for _, c := range catalog.Build(catalog.GeneratedFiles(gen), config) {
+		b, err := Marshal(Build(c), format)
+		if err != nil {
+			return err
+		}
+		g := gen.NewGeneratedFile(path.Join(c.Dir, "errors.openapi."+format), "")
+		if _, err := g.Write(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/catalog"
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/errors"
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/internal/testutil"
+)
+
+func TestBuild(t *testing.T) {
+	c := &catalog.Catalog{
+		Package: "tests.basic",
+		Errors: []*catalog.Entry{
+			{Enum: "UserError", Value: "USER_ERROR_NOT_FOUND", Code: 2, Status: 404, Reason: "user not found", Message: "user does not exist", Methods: []string{"/tests.basic.UserService/GetUser"}},
+			{Enum: "UserError", Value: "USER_ERROR_GONE", Code: 5, Status: 404, Reason: "user gone"},
+			{Enum: "UserError", Value: "USER_ERROR_INVALID_ID", Code: 1, Status: 400, Reason: "invalid user id", Example: map[string]any{"code": 1, "message": "user ID abc is invalid"}},
+		},
+	}
+	doc := Build(c)
+
+	if n := len(doc.Components.Responses); n != 2 {
+		t.Fatalf("len(Responses) = %d, want 2", n)
+	}
+	notFound := doc.Components.Responses["Error404"]
+	if notFound == nil {
+		t.Fatal("missing Error404 response")
+	}
+	if notFound.Description != "Not Found" {
+		t.Errorf("Description = %q, want Not Found", notFound.Description)
+	}
+	media := notFound.Content["application/json"]
+	if media.Schema.Ref != errorSchemaRef {
+		t.Errorf("schema ref = %q", media.Schema.Ref)
+	}
+	if len(media.Examples) != 2 {
+		t.Fatalf("len(Examples) = %d, want 2", len(media.Examples))
+	}
+	gone, _ := media.Examples["UserError.USER_ERROR_GONE"].Value.(ErrorBody)
+	if gone.Message != "user gone" {
+		t.Errorf("example message = %q, want the reason as fallback", gone.Message)
+	}
+	invalid := doc.Components.Responses["Error400"].Content["application/json"].Examples["UserError.USER_ERROR_INVALID_ID"]
+	if body, _ := invalid.Value.(map[string]any); body["message"] != "user ID abc is invalid" {
+		t.Errorf("example value = %v, want the declared example", invalid.Value)
+	}
+	methods := map[string]map[string]ResponseRef{"/tests.basic.UserService/GetUser": {"404": {Ref: "#/components/responses/Error404"}}}
+	if !reflect.DeepEqual(doc.Components.MethodResponses, methods) {
+		t.Errorf("MethodResponses = %v, want %v", doc.Components.MethodResponses, methods)
+	}
+}
+
+func TestGenerateFiles(t *testing.T) {
+	plugin := testutil.PluginFromPB(t, "../errors/testdata/pb/basic_errors.pb", "basic_errors.proto")
+	if err := GenerateFiles(plugin, &errors.Config{}, catalog.FormatJSON); err != nil {
+		t.Fatalf("GenerateFiles failed: %v", err)
+	}
+	resp := plugin.Response()
+	if len(resp.File) != 1 {
+		t.Fatalf("len(File) = %d, want 1", len(resp.File))
+	}
+	f := resp.File[0]
+	if !strings.HasSuffix(f.GetName(), "testdata/basic/errors.openapi.json") {
+		t.Errorf("file name = %q", f.GetName())
+	}
+	var doc Document
+	if err := json.Unmarshal([]byte(f.GetContent()), &doc); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	for _, name := range []string{"Error400", "Error403", "Error404", "Error500"} {
+		if doc.Components.Responses[name] == nil {
+			t.Errorf("missing response %s", name)
+		}
+	}
+}
+
+func TestMarshal_InvalidFormat(t *testing.T) {
+	if _, err := Marshal(&Document{}, "toml"); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+}
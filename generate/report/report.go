@@ -0,0 +1,196 @@
+// Package report implements the governance report output of
+// protoc-gen-sphere-errors. It writes one errors.report.json (or .md) per
+// request summarizing the error values of every generated file: counts per
+// HTTP status and category, the biggest enums, and the reserved code ranges
+// no value uses, so API reviews can track error sprawl per service.
+package report
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/catalog"
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/errors"
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// Supported report formats.
+const (
+	FormatJSON     = "json"
+	FormatMarkdown = "markdown"
+)
+
+// biggestEnums is the number of enums listed in BiggestEnums.
+const biggestEnums = 10
+
+//go:embed report.tmpl
+var mdTemplate string
+
+// Report summarizes the error values of a request. Counts cover the non-zero
+// values and skip aliases, which share the code of an earlier value.
+type Report struct {
+	Enums      int `json:"enums"`
+	Values     int `json:"values"`
+	Deprecated int `json:"deprecated"`
+	// ByStatus counts the values per HTTP status, ordered by status.
+	ByStatus []StatusCount `json:"by_status"`
+	// ByCategory counts the values per category, ordered by name. Values
+	// without a category are counted under "".
+	ByCategory []CategoryCount `json:"by_category"`
+	// BiggestEnums are the enums with the most values, largest first.
+	BiggestEnums []EnumSize `json:"biggest_enums"`
+	// UnusedReserved are the code ranges no value may use: the reserved
+	// numbers of the enums, shifted by their code offset, and the
+	// reserved_codes ranges.
+	UnusedReserved []ReservedRange `json:"unused_reserved"`
+}
+
+// StatusCount is the number of values with an HTTP status.
+type StatusCount struct {
+	Status int32 `json:"status"`
+	Count  int   `json:"count"`
+}
+
+// CategoryCount is the number of values of a category.
+type CategoryCount struct {
+	Category string `json:"category"`
+	Count    int    `json:"count"`
+}
+
+// EnumSize describes the size of an error enum and the range its codes span.
+type EnumSize struct {
+	Enum    string `json:"enum"`
+	Values  int    `json:"values"`
+	MinCode int32  `json:"min_code"`
+	MaxCode int32  `json:"max_code"`
+}
+
+// ReservedRange is a reserved code range. Enum is the fully-qualified name of
+// the enum reserving it, or empty for a reserved_codes range.
+type ReservedRange struct {
+	Enum  string `json:"enum,omitempty"`
+	Start int32  `json:"start"`
+	End   int32  `json:"end"`
+}
+
+// Build summarizes the error enums of files, resolved with config.
+func Build(files []*protogen.File, config *errors.Config) *Report {
+	r := &Report{}
+	statuses := map[int32]int{}
+	categories := map[string]int{}
+	for _, f := range files {
+		for _, ew := range errors.ErrorEnums(f, config) {
+			r.Enums++
+			size := EnumSize{Enum: ew.FullName}
+			for _, info := range ew.Errors {
+				if info.Number == 0 {
+					continue
+				}
+				r.Values++
+				if info.Deprecated {
+					r.Deprecated++
+				}
+				statuses[info.Status]++
+				categories[info.Category]++
+				if size.Values == 0 || info.Code < size.MinCode {
+					size.MinCode = info.Code
+				}
+				if size.Values == 0 || info.Code > size.MaxCode {
+					size.MaxCode = info.Code
+				}
+				size.Values++
+			}
+			r.BiggestEnums = append(r.BiggestEnums, size)
+			// Every enum declares at least one value, so the offset
+			// shared by its codes follows from the first.
+			offset := ew.Errors[0].Code - ew.Errors[0].Number
+			for _, enum := range f.Enums {
+				if string(enum.Desc.FullName()) != ew.FullName {
+					continue
+				}
+				ranges := enum.Desc.ReservedRanges()
+				for i := 0; i < ranges.Len(); i++ {
+					rr := ranges.Get(i)
+					r.UnusedReserved = append(r.UnusedReserved, ReservedRange{
+						Enum:  ew.FullName,
+						Start: shift(int32(rr[0]), offset),
+						End:   shift(int32(rr[1]), offset),
+					})
+				}
+			}
+		}
+	}
+	for _, cr := range config.ReservedCodes {
+		r.UnusedReserved = append(r.UnusedReserved, ReservedRange{Start: cr.Start, End: cr.End})
+	}
+	for status, n := range statuses {
+		r.ByStatus = append(r.ByStatus, StatusCount{Status: status, Count: n})
+	}
+	slices.SortFunc(r.ByStatus, func(a, b StatusCount) int { return int(a.Status - b.Status) })
+	for category, n := range categories {
+		r.ByCategory = append(r.ByCategory, CategoryCount{Category: category, Count: n})
+	}
+	slices.SortFunc(r.ByCategory, func(a, b CategoryCount) int { return strings.Compare(a.Category, b.Category) })
+	slices.SortStableFunc(r.BiggestEnums, func(a, b EnumSize) int {
+		if a.Values != b.Values {
+			return b.Values - a.Values
+		}
+		return strings.Compare(a.Enum, b.Enum)
+	})
+	if len(r.BiggestEnums) > biggestEnums {
+		r.BiggestEnums = r.BiggestEnums[:biggestEnums]
+	}
+	return r
+}
+
+// shift returns number plus offset, clamped to the int32 range so that
+// ranges reserved up to max stay ordered.
+func shift(number, offset int32) int32 {
+	return int32(max(math.MinInt32, min(math.MaxInt32, int64(number)+int64(offset))))
+}
+
+// Marshal encodes r in the given format.
+func Marshal(r *Report, format string) ([]byte, error) {
+	switch format {
+	case FormatJSON:
+		b, err := json.MarshalIndent(r, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(b, '\n'), nil
+	case FormatMarkdown:
+		tmpl, err := template.New("report").Parse(mdTemplate)
+		if err != nil {
+			return nil, err
+		}
+		var buf strings.Builder
+		if err := tmpl.Execute(&buf, r); err != nil {
+			return nil, err
+		}
+		return []byte(buf.String()), nil
+	default:
+		return nil, fmt.Errorf("invalid report format %q, expected %s or %s", format, FormatJSON, FormatMarkdown)
+	}
+}
+
+// GenerateFile writes the report of the files marked for generation to
+// errors.report.json, or errors.report.md for the markdown format, at the
+// root of the output.
+func GenerateFile(gen *protogen.Plugin, config *errors.Config, format string) error {
+	b, err := Marshal(Build(catalog.GeneratedFiles(gen), config), format)
+	if err != nil {
+		return err
+	}
+	ext := "json"
+	if format == FormatMarkdown {
+		ext = "md"
+	}
+	g := gen.NewGeneratedFile("errors.report."+ext, "")
+	_, err = g.Write(b)
+	return err
+}
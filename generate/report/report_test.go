@@ -0,0 +1,89 @@
+package report
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/errors"
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/internal/testutil"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// basicPlugin returns a plugin for basic_errors.proto whose UserError reserves
+// the numbers 8 to 9.
+func basicPlugin(t *testing.T) *protogen.Plugin {
+	set := testutil.LoadDescriptorSet(t, "../errors/testdata/pb/basic_errors.pb")
+	for _, f := range set.File {
+		if f.GetName() != "basic_errors.proto" {
+			continue
+		}
+		for _, e := range f.EnumType {
+			if e.GetName() == "UserError" {
+				e.ReservedRange = append(e.ReservedRange, &descriptorpb.EnumDescriptorProto_EnumReservedRange{
+					Start: proto.Int32(8),
+					End:   proto.Int32(9),
+				})
+			}
+		}
+	}
+	return testutil.MustCreatePlugin(t, set, "basic_errors.proto")
+}
+
+func TestBuild(t *testing.T) {
+	plugin := basicPlugin(t)
+	config := &errors.Config{
+		CodeOffsets:   map[string]int32{"": 100},
+		Categories:    map[string]string{"tests.basic.UserError": "auth"},
+		ReservedCodes: []errors.CodeRange{{Start: 900, End: 999}},
+	}
+	r := Build([]*protogen.File{testutil.FileToGenerate(t, plugin)}, config)
+	got, err := json.Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"enums":2,"values":5,"deprecated":1,` +
+		`"by_status":[{"status":400,"count":3},{"status":403,"count":1},{"status":404,"count":1}],` +
+		`"by_category":[{"category":"","count":1},{"category":"auth","count":4}],` +
+		`"biggest_enums":[{"enum":"tests.basic.UserError","values":4,"min_code":101,"max_code":104},{"enum":"tests.basic.OrderError","values":1,"min_code":101,"max_code":101}],` +
+		`"unused_reserved":[{"enum":"tests.basic.UserError","start":108,"end":109},{"start":900,"end":999}]}`
+	if string(got) != want {
+		t.Errorf("Build() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	plugin := basicPlugin(t)
+	r := Build([]*protogen.File{testutil.FileToGenerate(t, plugin)}, &errors.Config{})
+	md, err := Marshal(r, FormatMarkdown)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"5 error values in 2 enums, 1 deprecated.",
+		"| 404 | 1 |",
+		"| (none) | 5 |",
+		"| `tests.basic.UserError` | 4 | 1–4 |",
+		"| 8–9 | `tests.basic.UserError` |",
+	} {
+		if !strings.Contains(string(md), want) {
+			t.Errorf("markdown missing %q:\n%s", want, md)
+		}
+	}
+	if _, err := Marshal(r, "xml"); err == nil {
+		t.Error("expected an error for an invalid format")
+	}
+}
+
+func TestGenerateFile(t *testing.T) {
+	plugin := basicPlugin(t)
+	if err := GenerateFile(plugin, &errors.Config{}, FormatJSON); err != nil {
+		t.Fatal(err)
+	}
+	files := plugin.Response().File
+	if len(files) != 1 || files[0].GetName() != "errors.report.json" {
+		t.Fatalf("files = %v, want errors.report.json", files)
+	}
+}
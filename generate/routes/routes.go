@@ -0,0 +1,216 @@
+// Package routes implements the route errors output of
+// protoc-gen-sphere-errors. It writes one errors.routes.json per request,
+// mapping every HTTP route of the generated files, as annotated with
+// google.api.http for the routing code of protoc-gen-sphere, to the errors its
+// method declares with method_errors_option. Routers load it with the
+// routeerrors runtime package to check at startup, in debug builds, that the
+// routes they serve are the annotated ones and that their handlers only
+// return declared errors.
+package routes
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/catalog"
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/errors"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// Supported route errors formats.
+const (
+	FormatJSON = "json"
+)
+
+// ValidateFormat rejects route errors formats other than json.
+func ValidateFormat(format string) error {
+	if format != FormatJSON {
+		return fmt.Errorf("invalid routes_out %q, expected %s", format, FormatJSON)
+	}
+	return nil
+}
+
+// httpOption is the method option annotating the HTTP routes of a method.
+const httpOption = "google.api.http"
+
+// Manifest is the route errors of a request.
+type Manifest struct {
+	// Routes are ordered by path, then by HTTP method.
+	Routes []Route `json:"routes"`
+}
+
+// Route is an HTTP route of a method and the errors the method declares.
+type Route struct {
+	// Method is the HTTP method, e.g. "GET", or the kind of a custom
+	// pattern.
+	Method string `json:"method"`
+	// Path is the path template, e.g. "/v1/users/{id}".
+	Path string `json:"path"`
+	// Operation is the full gRPC name of the method, e.g.
+	// "/shop.v1.UserService/GetUser".
+	Operation string `json:"operation"`
+	// Errors are the errors the method declares, in declaration order;
+	// empty when it declares none.
+	Errors []Error `json:"errors"`
+}
+
+// Error is an error value declared by the method of a route.
+type Error struct {
+	// Name is the fully-qualified proto name of the value, e.g.
+	// "shop.v1.USER_ERROR_NOT_FOUND".
+	Name   string `json:"name"`
+	Code   int32  `json:"code"`
+	Status int32  `json:"status"`
+}
+
+// Build collects the routes of the methods of files, annotated with
+// google.api.http, with the errors they declare by the MethodErrorsOption of
+// config, the errors being resolved among all, every file of the request.
+// Additional bindings are routes of their own.
+func Build(files, all []*protogen.File, config *errors.Config) (*Manifest, error) {
+	rules, err := newRuleReader(all)
+	if err != nil {
+		return nil, err
+	}
+	declared := errors.MethodErrors(all, config)
+	values := map[string]Error{}
+	for _, f := range all {
+		for _, ew := range errors.ErrorEnums(f, config) {
+			for _, info := range ew.Errors {
+				name := string(protoreflect.FullName(ew.FullName).Parent().Append(protoreflect.Name(info.Value)))
+				values[name] = Error{Name: name, Code: info.Code, Status: info.Status}
+			}
+		}
+	}
+	m := &Manifest{Routes: []Route{}}
+	for _, f := range files {
+		for _, service := range f.Services {
+			for _, method := range service.Methods {
+				operation := "/" + string(service.Desc.FullName()) + "/" + string(method.Desc.Name())
+				errs := []Error{}
+				for _, name := range declared[operation] {
+					errs = append(errs, values[name])
+				}
+				for _, b := range rules.bindings(method) {
+					m.Routes = append(m.Routes, Route{Method: b.method, Path: b.path, Operation: operation, Errors: errs})
+				}
+			}
+		}
+	}
+	slices.SortStableFunc(m.Routes, func(a, b Route) int {
+		return cmp.Or(strings.Compare(a.Path, b.Path), strings.Compare(a.Method, b.Method))
+	})
+	return m, nil
+}
+
+// binding is an HTTP method and path template of a google.api.HttpRule.
+type binding struct {
+	method, path string
+}
+
+// ruleReader reads the google.api.http option of methods.
+type ruleReader struct {
+	types *protoregistry.Types
+	xt    protoreflect.ExtensionType
+}
+
+// newRuleReader returns the reader of the google.api.http option declared
+// among files. It reads no route when the option is not declared, the
+// request then having no annotated method.
+func newRuleReader(files []*protogen.File) (*ruleReader, error) {
+	r := &ruleReader{types: &protoregistry.Types{}}
+	for _, f := range files {
+		xs := f.Desc.Extensions()
+		for i := 0; i < xs.Len(); i++ {
+			if xd := xs.Get(i); xd.FullName() == httpOption {
+				if xd.ContainingMessage().FullName() != "google.protobuf.MethodOptions" || xd.Kind() != protoreflect.MessageKind || xd.IsList() {
+					return nil, fmt.Errorf("%s must be a google.api.HttpRule extension of google.protobuf.MethodOptions", httpOption)
+				}
+				r.xt = dynamicpb.NewExtensionType(xd)
+				return r, r.types.RegisterExtension(r.xt)
+			}
+		}
+	}
+	return r, nil
+}
+
+// bindings returns the routes of method: the pattern of its HttpRule
+// followed by those of its additional bindings.
+func (r *ruleReader) bindings(method *protogen.Method) []binding {
+	if r.xt == nil {
+		return nil
+	}
+	opts := method.Desc.Options()
+	b, err := proto.Marshal(opts)
+	if err != nil {
+		return nil
+	}
+	m := opts.ProtoReflect().Type().New().Interface()
+	if err := (proto.UnmarshalOptions{Resolver: r.types}).Unmarshal(b, m); err != nil {
+		return nil
+	}
+	xd := r.xt.TypeDescriptor()
+	if !m.ProtoReflect().Has(xd) {
+		return nil
+	}
+	return appendBindings(nil, m.ProtoReflect().Get(xd).Message())
+}
+
+// appendBindings appends the routes of the HttpRule rule to out.
+func appendBindings(out []binding, rule protoreflect.Message) []binding {
+	fields := rule.Descriptor().Fields()
+	for _, verb := range []string{"get", "put", "post", "delete", "patch"} {
+		if fd := fields.ByName(protoreflect.Name(verb)); fd != nil && rule.Has(fd) {
+			out = append(out, binding{method: strings.ToUpper(verb), path: rule.Get(fd).String()})
+		}
+	}
+	if fd := fields.ByName("custom"); fd != nil && rule.Has(fd) {
+		custom := rule.Get(fd).Message()
+		cf := custom.Descriptor().Fields()
+		if kind, path := cf.ByName("kind"), cf.ByName("path"); kind != nil && path != nil {
+			out = append(out, binding{method: custom.Get(kind).String(), path: custom.Get(path).String()})
+		}
+	}
+	if fd := fields.ByName("additional_bindings"); fd != nil && fd.IsList() {
+		list := rule.Get(fd).List()
+		for i := 0; i < list.Len(); i++ {
+			out = appendBindings(out, list.Get(i).Message())
+		}
+	}
+	return out
+}
+
+// Marshal renders m in format.
+func Marshal(m *Manifest, format string) ([]byte, error) {
+	if err := ValidateFormat(format); err != nil {
+		return nil, err
+	}
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+// GenerateFile writes errors.routes.<format> with the routes of the files
+// marked for generation.
+func GenerateFile(gen *protogen.Plugin, config *errors.Config, format string) error {
+	m, err := Build(catalog.GeneratedFiles(gen), gen.Files, config)
+	if err != nil {
+		return err
+	}
+	b, err := Marshal(m, format)
+	if err != nil {
+		return err
+	}
+	g := gen.NewGeneratedFile("errors.routes."+format, "")
+	_, err = g.Write(b)
+	return err
+}
@@ -0,0 +1,178 @@
+package routes
+
+import (
+	"strings"
+	"testing"
+
+	sphereerrors "github.com/go-sphere/errors/sphere/errors"
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/catalog"
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/errors"
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/internal/testutil"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// annotationsProto declares the google.api.http option and the part of
+// google.api.HttpRule the routes are read from.
+func annotationsProto() *descriptorpb.FileDescriptorProto {
+	field := func(name string, number int32, typ descriptorpb.FieldDescriptorProto_Type, typeName string, label descriptorpb.FieldDescriptorProto_Label) *descriptorpb.FieldDescriptorProto {
+		f := &descriptorpb.FieldDescriptorProto{Name: proto.String(name), Number: proto.Int32(number), Type: typ.Enum(), Label: label.Enum()}
+		if typeName != "" {
+			f.TypeName = proto.String(typeName)
+		}
+		return f
+	}
+	str, msg := descriptorpb.FieldDescriptorProto_TYPE_STRING, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE
+	optional, repeated := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL, descriptorpb.FieldDescriptorProto_LABEL_REPEATED
+	return &descriptorpb.FileDescriptorProto{
+		Name:       proto.String("google/api/annotations.proto"),
+		Package:    proto.String("google.api"),
+		Dependency: []string{"google/protobuf/descriptor.proto"},
+		Options:    &descriptorpb.FileOptions{GoPackage: proto.String("google.golang.org/genproto/googleapis/api/annotations")},
+		Syntax:     proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{
+			{Name: proto.String("HttpRule"), Field: []*descriptorpb.FieldDescriptorProto{
+				field("get", 2, str, "", optional),
+				field("put", 3, str, "", optional),
+				field("post", 4, str, "", optional),
+				field("delete", 5, str, "", optional),
+				field("patch", 6, str, "", optional),
+				field("custom", 8, msg, ".google.api.CustomHttpPattern", optional),
+				field("additional_bindings", 11, msg, ".google.api.HttpRule", repeated),
+			}},
+			{Name: proto.String("CustomHttpPattern"), Field: []*descriptorpb.FieldDescriptorProto{
+				field("kind", 1, str, "", optional),
+				field("path", 2, str, "", optional),
+			}},
+		},
+		Extension: []*descriptorpb.FieldDescriptorProto{{
+			Name:     proto.String("http"),
+			Number:   proto.Int32(72295728),
+			Label:    optional.Enum(),
+			Type:     msg.Enum(),
+			TypeName: proto.String(".google.api.HttpRule"),
+			Extendee: proto.String(".google.protobuf.MethodOptions"),
+		}},
+	}
+}
+
+// methodOptions returns the options of a method routed by rule, a wire
+// encoded HttpRule, declaring the errors names.
+func methodOptions(rule []byte, names ...string) *descriptorpb.MethodOptions {
+	b := protowire.AppendBytes(protowire.AppendTag(nil, 72295728, protowire.BytesType), rule)
+	for _, name := range names {
+		b = protowire.AppendString(protowire.AppendTag(b, 50105, protowire.BytesType), name)
+	}
+	opts := &descriptorpb.MethodOptions{}
+	opts.ProtoReflect().SetUnknown(b)
+	return opts
+}
+
+// rule returns the wire encoding of an HttpRule field holding path.
+func rule(field protowire.Number, path string) []byte {
+	return protowire.AppendString(protowire.AppendTag(nil, field, protowire.BytesType), path)
+}
+
+func shopProto() *descriptorpb.FileDescriptorProto {
+	enumOpts := &descriptorpb.EnumOptions{}
+	proto.SetExtension(enumOpts, sphereerrors.E_DefaultStatus, int32(400))
+	method := func(name string, opts *descriptorpb.MethodOptions) *descriptorpb.MethodDescriptorProto {
+		return &descriptorpb.MethodDescriptorProto{Name: proto.String(name), InputType: proto.String(".tests.shop.Request"), OutputType: proto.String(".tests.shop.Request"), Options: opts}
+	}
+	custom := protowire.AppendBytes(protowire.AppendTag(nil, 8, protowire.BytesType), append(rule(1, "HEAD"), rule(2, "/v1/carts/{id}")...))
+	return &descriptorpb.FileDescriptorProto{
+		Name:        proto.String("shop.proto"),
+		Package:     proto.String("tests.shop"),
+		Dependency:  []string{"google/protobuf/descriptor.proto", "google/api/annotations.proto"},
+		Options:     &descriptorpb.FileOptions{GoPackage: proto.String("github.com/example/shop")},
+		MessageType: []*descriptorpb.DescriptorProto{{Name: proto.String("Request")}},
+		Extension: []*descriptorpb.FieldDescriptorProto{{
+			Name:     proto.String("errors"),
+			Number:   proto.Int32(50105),
+			Label:    descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum(),
+			Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+			Extendee: proto.String(".google.protobuf.MethodOptions"),
+		}},
+		EnumType: []*descriptorpb.EnumDescriptorProto{{
+			Name:    proto.String("CartError"),
+			Options: enumOpts,
+			Value: []*descriptorpb.EnumValueDescriptorProto{
+				{Name: proto.String("CART_ERROR_UNSPECIFIED"), Number: proto.Int32(0)},
+				{Name: proto.String("CART_ERROR_EMPTY"), Number: proto.Int32(1)},
+				{Name: proto.String("CART_ERROR_FULL"), Number: proto.Int32(2)},
+			},
+		}},
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name: proto.String("CartService"),
+			Method: []*descriptorpb.MethodDescriptorProto{
+				method("Checkout", methodOptions(append(rule(4, "/v1/carts/{id}:checkout"),
+					protowire.AppendBytes(protowire.AppendTag(nil, 11, protowire.BytesType), rule(4, "/v1/checkout"))...), "tests.shop.CART_ERROR_EMPTY")),
+				method("AddItem", methodOptions(rule(4, "/v1/carts/{id}/items"), "tests.shop.CartError")),
+				method("GetCart", methodOptions(append(rule(2, "/v1/carts/{id}"), custom...))),
+				method("Internal", nil),
+			},
+		}},
+	}
+}
+
+func TestBuild(t *testing.T) {
+	set := &descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{
+		protodesc.ToFileDescriptorProto(descriptorpb.File_google_protobuf_descriptor_proto),
+		annotationsProto(),
+		shopProto(),
+	}}
+	plugin := testutil.MustCreatePlugin(t, set, "shop.proto")
+	config := &errors.Config{MethodErrorsOption: "tests.shop.errors"}
+	if err := errors.ResolveOptions(plugin.Files, config); err != nil {
+		t.Fatal(err)
+	}
+	m, err := Build(catalog.GeneratedFiles(plugin), plugin.Files, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := Marshal(m, FormatJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, r := range m.Routes {
+		var names []string
+		for _, e := range r.Errors {
+			names = append(names, e.Name)
+		}
+		got = append(got, r.Method+" "+r.Path+" "+r.Operation+" ["+strings.Join(names, " ")+"]")
+	}
+	want := []string{
+		"GET /v1/carts/{id} /tests.shop.CartService/GetCart []",
+		"HEAD /v1/carts/{id} /tests.shop.CartService/GetCart []",
+		"POST /v1/carts/{id}/items /tests.shop.CartService/AddItem [tests.shop.CART_ERROR_EMPTY tests.shop.CART_ERROR_FULL]",
+		"POST /v1/carts/{id}:checkout /tests.shop.CartService/Checkout [tests.shop.CART_ERROR_EMPTY]",
+		"POST /v1/checkout /tests.shop.CartService/Checkout [tests.shop.CART_ERROR_EMPTY]",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("routes =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	for _, want := range []string{`"errors": []`, `"name": "tests.shop.CART_ERROR_EMPTY",
+          "code": 1,
+          "status": 400`} {
+		if !strings.Contains(string(b), want) {
+			t.Errorf("json missing %q:\n%s", want, b)
+		}
+	}
+}
+
+func TestBuild_NoAnnotations(t *testing.T) {
+	plugin := testutil.PluginFromPB(t, "../errors/testdata/pb/basic_errors.pb", "basic_errors.proto")
+	m, err := Build(catalog.GeneratedFiles(plugin), plugin.Files, &errors.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Routes) != 0 {
+		t.Errorf("routes = %+v, want none", m.Routes)
+	}
+	if _, err := Marshal(m, "yaml"); err == nil {
+		t.Error("expected an error for an invalid format")
+	}
+}
@@ -0,0 +1,137 @@
+// Package sql implements the SQL output of protoc-gen-sphere-errors. It writes
+// one errors.catalog.sql per proto package: a migration creating the
+// error_catalog table if needed and upserting one row per error, so admin
+// systems that store the catalog in a database stay in sync with the protos.
+package sql
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/catalog"
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/errors"
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// Supported SQL dialects.
+const (
+	DialectPostgres = "postgres"
+	DialectMySQL    = "mysql"
+	DialectSQLite   = "sqlite"
+)
+
+// Table is the name of the table the migration writes to.
+const Table = "error_catalog"
+
+// dialect holds the statements that differ between databases.
+type dialect struct {
+	createTable string
+	upsert      string
+	// backslashEscapes reports whether backslashes escape characters in
+	// string literals, as in MySQL by default.
+	backslashEscapes bool
+}
+
+var dialects = map[string]dialect{
+	DialectPostgres: {
+		createTable: `CREATE TABLE IF NOT EXISTS ` + Table + ` (
+    code integer NOT NULL,
+    name text PRIMARY KEY,
+    status integer NOT NULL,
+    reason text NOT NULL,
+    message text NOT NULL
+);
+`,
+		upsert: "ON CONFLICT (name) DO UPDATE SET code = EXCLUDED.code, status = EXCLUDED.status, reason = EXCLUDED.reason, message = EXCLUDED.message",
+	},
+	DialectMySQL: {
+		createTable: `CREATE TABLE IF NOT EXISTS ` + Table + ` (
+    code INT NOT NULL,
+    name VARCHAR(255) NOT NULL PRIMARY KEY,
+    status INT NOT NULL,
+    reason VARCHAR(255) NOT NULL,
+    message TEXT NOT NULL
+);
+`,
+		upsert:           "ON DUPLICATE KEY UPDATE code = VALUES(code), status = VALUES(status), reason = VALUES(reason), message = VALUES(message)",
+		backslashEscapes: true,
+	},
+	DialectSQLite: {
+		createTable: `CREATE TABLE IF NOT EXISTS ` + Table + ` (
+    code INTEGER NOT NULL,
+    name TEXT NOT NULL PRIMARY KEY,
+    status INTEGER NOT NULL,
+    reason TEXT NOT NULL,
+    message TEXT NOT NULL
+);
+`,
+		upsert: "ON CONFLICT (name) DO UPDATE SET code = excluded.code, status = excluded.status, reason = excluded.reason, message = excluded.message",
+	},
+}
+
+// ValidateDialect reports whether name is a supported SQL dialect.
+func ValidateDialect(name string) error {
+	if _, ok := dialects[name]; !ok {
+		return fmt.Errorf("invalid sql_out %q, expected %s, %s or %s", name, DialectPostgres, DialectMySQL, DialectSQLite)
+	}
+	return nil
+}
+
+// Build returns the migration of c in the given dialect. Each error is
+// upserted by its own statement, keyed by the name column, the
+// fully-qualified value name, e.g. "shared.v1.UserError.USER_ERROR_NOT_FOUND",
+// so values of different enums sharing a code keep rows of their own.
+func Build(c *catalog.Catalog, name string) ([]byte, error) {
+	d, ok := dialects[name]
+	if !ok {
+		return nil, ValidateDialect(name)
+	}
+	var b bytes.Buffer
+	b.WriteString("-- Code generated by protoc-gen-sphere-errors. DO NOT EDIT.\n")
+	fmt.Fprintf(&b, "-- package: %s\n\n", c.Package)
+	b.WriteString(d.createTable)
+	for _, e := range c.Errors {
+		// The generated Join helper falls back to the reason when no message
+		// is declared, so the stored message does the same.
+		message := e.Message
+		if message == "" {
+			message = e.Reason
+		}
+		fmt.Fprintf(&b, "\nINSERT INTO %s (code, name, status, reason, message)\nVALUES (%d, %s, %d, %s, %s)\n%s;\n",
+			Table,
+			e.Code,
+			d.quote(c.Package+"."+e.Enum+"."+e.Value),
+			e.Status,
+			d.quote(e.Reason),
+			d.quote(message),
+			d.upsert,
+		)
+	}
+	return b.Bytes(), nil
+}
+
+// quote returns s as a string literal of d.
+func (d dialect) quote(s string) string {
+	if d.backslashEscapes {
+		s = strings.ReplaceAll(s, `\`, `\\`)
+	}
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// GenerateFiles writes one migration per proto package among the files marked
+// for generation, next to the package's first generated file.
+func GenerateFiles(gen *protogen.Plugin, config *errors.Config, name string) error {
+	for _, c := range catalog.Build(catalog.GeneratedFiles(gen), config) {
+		b, err := Build(c, name)
+		if err != nil {
+			return err
+		}
+		g := gen.NewGeneratedFile(path.Join(c.Dir, "errors.catalog.sql"), "")
+		if _, err := g.Write(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package sql
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/catalog"
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/errors"
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/internal/testutil"
+)
+
+var testCatalog = &catalog.Catalog{
+	Package: "tests.basic",
+	Errors: []*catalog.Entry{
+		{Enum: "UserError", Value: "USER_ERROR_NOT_FOUND", Code: 2, Status: 404, Reason: "user not found", Message: "user's row does not exist"},
+		{Enum: "UserError", Value: "USER_ERROR_GONE", Code: 5, Status: 410, Reason: `user\gone`},
+	},
+}
+
+func TestBuild(t *testing.T) {
+	b, err := Build(testCatalog, DialectPostgres)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(b)
+	for _, want := range []string{
+		"CREATE TABLE IF NOT EXISTS error_catalog (",
+		"VALUES (2, 'tests.basic.UserError.USER_ERROR_NOT_FOUND', 404, 'user not found', 'user''s row does not exist')\nON CONFLICT (name) DO UPDATE SET code = EXCLUDED.code",
+		`VALUES (5, 'tests.basic.UserError.USER_ERROR_GONE', 410, 'user\gone', 'user\gone')`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("migration does not contain %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestBuild_SharedCode(t *testing.T) {
+	c := &catalog.Catalog{
+		Package: "tests.basic",
+		Errors: []*catalog.Entry{
+			{Enum: "UserError", Value: "USER_ERROR_NOT_FOUND", Code: 2, Status: 404, Reason: "user not found"},
+			{Enum: "OrderError", Value: "ORDER_ERROR_NOT_FOUND", Code: 2, Status: 404, Reason: "order not found"},
+		},
+	}
+	for name := range dialects {
+		b, err := Build(c, name)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		out := string(b)
+		if strings.Contains(out, "ON CONFLICT (code)") || !strings.Contains(out, "PRIMARY KEY,\n    status") {
+			t.Errorf("%s migration is not keyed by name:\n%s", name, out)
+		}
+		for _, want := range []string{"'tests.basic.UserError.USER_ERROR_NOT_FOUND'", "'tests.basic.OrderError.ORDER_ERROR_NOT_FOUND'"} {
+			if !strings.Contains(out, want) {
+				t.Errorf("%s migration does not upsert %s:\n%s", name, want, out)
+			}
+		}
+	}
+}
+
+func TestBuild_Dialects(t *testing.T) {
+	tests := []struct {
+		dialect string
+		want    []string
+	}{
+		{DialectMySQL, []string{"ON DUPLICATE KEY UPDATE code = VALUES(code)", `'user\\gone'`}},
+		{DialectSQLite, []string{"ON CONFLICT (name) DO UPDATE SET code = excluded.code", `'user\gone'`}},
+	}
+	for _, tt := range tests {
+		b, err := Build(testCatalog, tt.dialect)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.dialect, err)
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(string(b), want) {
+				t.Errorf("%s migration does not contain %q:\n%s", tt.dialect, want, b)
+			}
+		}
+	}
+	if _, err := Build(testCatalog, "oracle"); err == nil {
+		t.Error("expected error for unsupported dialect")
+	}
+}
+
+func TestGenerateFiles(t *testing.T) {
+	plugin := testutil.PluginFromPB(t, "../errors/testdata/pb/basic_errors.pb", "basic_errors.proto")
+	if err := GenerateFiles(plugin, &errors.Config{}, DialectSQLite); err != nil {
+		t.Fatalf("GenerateFiles failed: %v", err)
+	}
+	resp := plugin.Response()
+	if len(resp.File) != 1 {
+		t.Fatalf("len(File) = %d, want 1", len(resp.File))
+	}
+	f := resp.File[0]
+	if !strings.HasSuffix(f.GetName(), "testdata/basic/errors.catalog.sql") {
+		t.Errorf("file name = %q", f.GetName())
+	}
+	if n := strings.Count(f.GetContent(), "INSERT INTO error_catalog"); n != 7 {
+		t.Errorf("got %d INSERT statements, want 7", n)
+	}
+}
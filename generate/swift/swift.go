@@ -0,0 +1,122 @@
+// Package swift implements the Swift output of protoc-gen-sphere-errors. It
+// mirrors every error enum as a Swift enum conforming to Error, carrying the
+// code, HTTP status, reason and default message of each case, so iOS clients
+// switch on the same errors as the backend.
+package swift
+
+import (
+	_ "embed"
+	"fmt"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/errors"
+	errtemplate "github.com/go-sphere/protoc-gen-sphere-errors/generate/internal/template"
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+//go:embed swift.tmpl
+var swiftTemplate string
+
+// fileData is the template root for one generated .swift file.
+type fileData struct {
+	Source string
+	Enums  []*errtemplate.ErrorWrapper
+}
+
+// GenerateFile generates the <prefix>.errors.swift file for file, resolving
+// the errors with config. It returns a nil GeneratedFile (and nil error) when
+// file declares no error enums.
+func GenerateFile(gen *protogen.Plugin, file *protogen.File, config *errors.Config) (*protogen.GeneratedFile, error) {
+	enums := errors.ErrorEnums(file, config)
+	if len(enums) == 0 {
+		return nil, nil
+	}
+	tmpl, err := template.New("swift").Funcs(template.FuncMap{
+		"quote":    quote,
+		"caseName": caseName,
+		"doc":      doc,
+	}).Parse(swiftTemplate)
+	if err != nil {
+		return nil, err
+	}
+	var buf strings.Builder
+	if err := tmpl.Execute(&buf, &fileData{Source: file.Desc.Path(), Enums: enums}); err != nil {
+		return nil, err
+	}
+	g := gen.NewGeneratedFile(file.GeneratedFilenamePrefix+".errors.swift", "")
+	g.P(buf.String())
+	return g, nil
+}
+
+// keywords are the Swift keywords that must be escaped with backticks to be
+// used as case names.
+var keywords = map[string]bool{
+	"as": true, "associatedtype": true, "break": true, "case": true, "catch": true,
+	"class": true, "continue": true, "default": true, "defer": true, "deinit": true,
+	"do": true, "else": true, "enum": true, "extension": true, "fallthrough": true,
+	"false": true, "fileprivate": true, "for": true, "func": true, "guard": true,
+	"if": true, "import": true, "in": true, "init": true, "inout": true,
+	"internal": true, "is": true, "let": true, "nil": true, "open": true,
+	"operator": true, "private": true, "protocol": true, "public": true,
+	"repeat": true, "rethrows": true, "return": true, "self": true, "static": true,
+	"struct": true, "subscript": true, "super": true, "switch": true, "throw": true,
+	"throws": true, "true": true, "try": true, "typealias": true, "var": true,
+	"where": true, "while": true,
+}
+
+// caseName returns the lowerCamelCase Swift case name of the value valueName
+// of enumName: UserError.USER_ERROR_NOT_FOUND becomes notFound.
+func caseName(enumName, valueName string) string {
+	name := errors.ValueName(enumName, valueName)
+	if name == "" || unicode.IsDigit(rune(name[0])) {
+		name = "_" + name
+	}
+	name = strings.ToLower(name[:1]) + name[1:]
+	if keywords[name] {
+		return "`" + name + "`"
+	}
+	return name
+}
+
+// doc renders text as /// documentation comment lines indented by indent,
+// each terminated by a newline. It returns "" for empty text.
+func doc(indent, text string) string {
+	if text == "" {
+		return ""
+	}
+	var b strings.Builder
+	for _, line := range strings.Split(text, "\n") {
+		b.WriteString(strings.TrimRight(indent+"/// "+line, " ") + "\n")
+	}
+	return b.String()
+}
+
+// quote renders s as a Swift string literal.
+func quote(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&b, `\u{%x}`, r)
+				continue
+			}
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
@@ -0,0 +1,79 @@
+package swift
+
+import (
+	"flag"
+	"os"
+	"testing"
+
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/errors"
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/internal/testutil"
+)
+
+var updateGolden = flag.Bool("update-golden", false, "update golden files instead of comparing")
+
+func TestGenerateFile_Golden(t *testing.T) {
+	for _, name := range []string{"basic_errors", "aliased_errors"} {
+		t.Run(name, func(t *testing.T) {
+			plugin := testutil.PluginFromPB(t, "../errors/testdata/pb/"+name+".pb", name+".proto")
+			genFile, err := GenerateFile(plugin, testutil.FileToGenerate(t, plugin), &errors.Config{})
+			if err != nil {
+				t.Fatalf("GenerateFile failed: %v", err)
+			}
+			if genFile == nil {
+				t.Fatal("expected generated file, got nil")
+			}
+			content, err := genFile.Content()
+			if err != nil {
+				t.Fatalf("GeneratedFile.Content() failed: %v", err)
+			}
+
+			goldenFile := "testdata/golden/" + name + ".errors.swift"
+			if *updateGolden {
+				if err := os.MkdirAll("testdata/golden", 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(goldenFile, content, 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			want, err := os.ReadFile(goldenFile)
+			if err != nil {
+				t.Fatalf("read golden file (run `make update-golden` to create it): %v", err)
+			}
+			if string(want) != string(content) {
+				t.Errorf("generated content mismatch for %s:\n%s", goldenFile, content)
+			}
+		})
+	}
+}
+
+func TestGenerateFile_NoErrors(t *testing.T) {
+	plugin := testutil.PluginFromPB(t, "../errors/testdata/pb/no_errors.pb", "no_errors.proto")
+	genFile, err := GenerateFile(plugin, testutil.FileToGenerate(t, plugin), &errors.Config{})
+	if err != nil {
+		t.Fatalf("GenerateFile failed: %v", err)
+	}
+	if genFile != nil {
+		t.Error("expected nil for file without error enums, got non-nil")
+	}
+}
+
+func TestCaseName(t *testing.T) {
+	tests := []struct{ enum, value, want string }{
+		{"UserError", "USER_ERROR_NOT_FOUND", "notFound"},
+		{"UserError", "USER_ERROR_DEFAULT", "`default`"},
+		{"HTTPError", "HTTP_ERROR_404", "_404"},
+	}
+	for _, tt := range tests {
+		if got := caseName(tt.enum, tt.value); got != tt.want {
+			t.Errorf("caseName(%q, %q) = %s, want %s", tt.enum, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestQuote(t *testing.T) {
+	if got, want := quote("say \"hi\"\\\n\x01"), `"say \"hi\"\\\n\u{1}"`; got != want {
+		t.Errorf("quote() = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,53 @@
+// Package typescript implements the TypeScript output of
+// protoc-gen-sphere-errors. It mirrors every Go error enum as a TypeScript enum
+// plus a definitions table carrying the code, HTTP status, reason and default
+// message, so frontends can switch on the same identifiers as the backend.
+package typescript
+
+import (
+	_ "embed"
+	"encoding/json"
+	"strings"
+	"text/template"
+
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/errors"
+	errtemplate "github.com/go-sphere/protoc-gen-sphere-errors/generate/internal/template"
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+//go:embed typescript.tmpl
+var tsTemplate string
+
+// fileData is the template root for one generated .ts file.
+type fileData struct {
+	Source string
+	Enums  []*errtemplate.ErrorWrapper
+}
+
+// GenerateFile generates the <prefix>.errors.ts file for file, resolving the
+// errors with config. It returns a nil GeneratedFile (and nil error) when file
+// declares no error enums.
+func GenerateFile(gen *protogen.Plugin, file *protogen.File, config *errors.Config) (*protogen.GeneratedFile, error) {
+	enums := errors.ErrorEnums(file, config)
+	if len(enums) == 0 {
+		return nil, nil
+	}
+	tmpl, err := template.New("typescript").Funcs(template.FuncMap{"quote": quote}).Parse(tsTemplate)
+	if err != nil {
+		return nil, err
+	}
+	var buf strings.Builder
+	if err := tmpl.Execute(&buf, &fileData{Source: file.Desc.Path(), Enums: enums}); err != nil {
+		return nil, err
+	}
+	g := gen.NewGeneratedFile(file.GeneratedFilenamePrefix+".errors.ts", "")
+	g.P(buf.String())
+	return g, nil
+}
+
+// quote renders s as a TypeScript string literal. JSON string syntax is a
+// subset of TypeScript's, so encoding/json gives correct escaping.
+func quote(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
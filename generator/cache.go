@@ -0,0 +1,151 @@
+package generator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+// fileCache stores the files generated for each proto file in a directory,
+// keyed by a hash of everything the output of that file depends on, so an
+// unchanged file replays its stored output instead of being generated again.
+type fileCache struct {
+	dir string
+	// salt is hashed into every key: the plugin build, the request parameter
+	// and the configuration, which affect the output of every file.
+	salt []byte
+	// samePackage reports whether the files a and b generate into the same Go
+	// package, whose package-level helpers are derived from all its files.
+	samePackage func(a, b *protogen.File) bool
+}
+
+// newFileCache returns the cache in cfg.CacheDir for the request of gen, or
+// nil when it is empty.
+func newFileCache(gen *protogen.Plugin, cfg Config) (*fileCache, error) {
+	if cfg.CacheDir == "" {
+		return nil, nil
+	}
+	if err := os.MkdirAll(cfg.CacheDir, 0o755); err != nil {
+		return nil, err
+	}
+	h := sha256.New()
+	io.WriteString(h, Version+"\x00"+gen.Request.GetParameter()+"\x00")
+	if exe, err := os.Executable(); err == nil {
+		if err := hashFile(h, exe); err != nil {
+			return nil, err
+		}
+	}
+	b, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+	h.Write(b)
+	c := &fileCache{
+		dir:  cfg.CacheDir,
+		salt: h.Sum(nil),
+		samePackage: func(a, b *protogen.File) bool {
+			return a.GoImportPath == b.GoImportPath
+		},
+	}
+	if cfg.Errors.OutputPackage != "" || len(cfg.Errors.OutputPackages) > 0 {
+		c.samePackage = func(a, b *protogen.File) bool { return true }
+	}
+	return c, nil
+}
+
+// hashFile writes the content of the file name to h.
+func hashFile(h hash.Hash, name string) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(h, f)
+	return err
+}
+
+// key returns the cache key of f: a hash of the salt, of f and its transitive
+// imports, and of the other generated files of its Go package.
+func (c *fileCache) key(gen *protogen.Plugin, f *protogen.File) (string, error) {
+	h := sha256.New()
+	h.Write(c.salt)
+	seen := map[string]bool{}
+	var add func(file *protogen.File) error
+	add = func(file *protogen.File) error {
+		if seen[file.Desc.Path()] {
+			return nil
+		}
+		seen[file.Desc.Path()] = true
+		b, err := proto.MarshalOptions{Deterministic: true}.Marshal(file.Proto)
+		if err != nil {
+			return err
+		}
+		io.WriteString(h, file.Desc.Path()+"\x00")
+		h.Write(b)
+		imports := file.Desc.Imports()
+		for i := range imports.Len() {
+			if dep, ok := gen.FilesByPath[imports.Get(i).Path()]; ok {
+				if err := add(dep); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	if err := add(f); err != nil {
+		return "", err
+	}
+	for _, peer := range gen.Files {
+		if peer.Generate && c.samePackage(f, peer) {
+			if err := add(peer); err != nil {
+				return "", err
+			}
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// load returns the files stored under key. A missing or unreadable entry is a
+// miss.
+func (c *fileCache) load(key string) (*pluginpb.CodeGeneratorResponse, bool) {
+	b, err := os.ReadFile(filepath.Join(c.dir, key))
+	if err != nil {
+		return nil, false
+	}
+	resp := &pluginpb.CodeGeneratorResponse{}
+	if err := proto.Unmarshal(b, resp); err != nil {
+		return nil, false
+	}
+	return resp, true
+}
+
+// store stores the files of resp under key. It writes a temporary file and
+// renames it, so concurrent runs never read a partial entry.
+func (c *fileCache) store(key string, resp *pluginpb.CodeGeneratorResponse) error {
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(&pluginpb.CodeGeneratorResponse{File: resp.File})
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(c.dir, key+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(c.dir, key))
+}
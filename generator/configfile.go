@@ -0,0 +1,105 @@
+package generator
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// packageParams are the parameters a config file may set per proto package,
+// mapped to the parameter value applying a file value to package pkg.
+var packageParams = map[string]func(pkg, value string) string{
+	"code_offset":    qualifyPackage,
+	"code_prefix":    qualifyPackage,
+	"domain":         qualifyPackage,
+	"output_package": qualifyPackage,
+	"runtime":        qualifyPackage,
+	"exclude_enums": func(pkg, pattern string) string {
+		return pkg + "." + pattern
+	},
+}
+
+// qualifyPackage returns the "proto.package=value" form of a parameter
+// accepting one.
+func qualifyPackage(pkg, value string) string {
+	return pkg + "=" + value
+}
+
+// loadConfigFile applies the parameters of the YAML config file name, as if
+// they were given in its place in the request parameter: parameters that
+// follow override it. Top-level keys are parameter names, with a list for a
+// repeatable parameter, and the packages key maps proto packages to the
+// parameters overridden for them, e.g.
+//
+//	sentinel_errors: true
+//	exclude_enums: [internal.*]
+//	packages:
+//	  shared.v1:
+//	    output_package: example.com/api/apierrors
+//	    code_offset: 10000
+//	    runtime: kratos
+//	    exclude_enums: [LegacyError]
+func (p *Params) loadConfigFile(name string) error {
+	b, err := os.ReadFile(name)
+	if err != nil {
+		return fmt.Errorf("read config: %w", err)
+	}
+	var doc map[string]any
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return fmt.Errorf("config %s: %w", name, err)
+	}
+	packages, ok := doc["packages"].(map[string]any)
+	if v := doc["packages"]; v != nil && !ok {
+		return fmt.Errorf("config %s: packages must map proto packages to parameters", name)
+	}
+	for _, key := range slices.Sorted(maps.Keys(doc)) {
+		if key == "packages" {
+			continue
+		}
+		if key == "config" {
+			return fmt.Errorf("config %s: config cannot be set in a config file", name)
+		}
+		if err := p.setValues(key, doc[key], func(v string) string { return v }); err != nil {
+			return fmt.Errorf("config %s: %w", name, err)
+		}
+	}
+	for _, pkg := range slices.Sorted(maps.Keys(packages)) {
+		overrides, ok := packages[pkg].(map[string]any)
+		if !ok {
+			return fmt.Errorf("config %s: package %s must map parameters to values", name, pkg)
+		}
+		for _, key := range slices.Sorted(maps.Keys(overrides)) {
+			qualify, ok := packageParams[key]
+			if !ok {
+				return fmt.Errorf("config %s: parameter %q cannot be set per package, expected one of %s", name, key, strings.Join(slices.Sorted(maps.Keys(packageParams)), ", "))
+			}
+			if err := p.setValues(key, overrides[key], func(v string) string { return qualify(pkg, v) }); err != nil {
+				return fmt.Errorf("config %s: package %s: %w", name, pkg, err)
+			}
+		}
+	}
+	return nil
+}
+
+// setValues sets the parameter key to value, a scalar or a list of scalars
+// setting it once per element, each rendered by format.
+func (p *Params) setValues(key string, value any, format func(string) string) error {
+	values, ok := value.([]any)
+	if !ok {
+		values = []any{value}
+	}
+	for _, v := range values {
+		switch v.(type) {
+		case nil, []any, map[string]any:
+			return fmt.Errorf("parameter %q: expected a scalar or a list of scalars", key)
+		}
+		if err := p.Set(key, format(fmt.Sprint(v))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package generator
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/errors"
+)
+
+// Formats of the error reported in the CodeGeneratorResponse when generation
+// fails, selected by the error_format parameter.
+const (
+	ErrorFormatText = "text"
+	ErrorFormatJSON = "json"
+)
+
+// ValidateErrorFormat reports whether format is a supported error format.
+func ValidateErrorFormat(format string) error {
+	if format != ErrorFormatText && format != ErrorFormatJSON {
+		return fmt.Errorf("invalid error_format %q, expected text or json", format)
+	}
+	return nil
+}
+
+// diagnosticsReport is the JSON form of a failed generation: one entry per
+// failure, such as a failed check or the failed generation of one file.
+type diagnosticsReport struct {
+	Errors []*errors.Diagnostics `json:"errors"`
+}
+
+// FormatError returns err, the failure of a generation request, as reported
+// in the error field of the CodeGeneratorResponse: unchanged with
+// ErrorFormatText, and with ErrorFormatJSON as an object listing every
+// failure joined in err with the diagnostics of its problems, so CI and buf
+// plugins can surface them per file, enum and value. Failures that are no
+// errors.Diagnostics are reported by their text alone.
+func FormatError(err error, format string) error {
+	if err == nil || format != ErrorFormatJSON {
+		return err
+	}
+	report := diagnosticsReport{Errors: failures(err)}
+	data, jerr := json.Marshal(report)
+	if jerr != nil {
+		return err
+	}
+	return stderrors.New(string(data))
+}
+
+// failures returns the failures joined in err, as diagnostics.
+func failures(err error) []*errors.Diagnostics {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		var list []*errors.Diagnostics
+		for _, e := range joined.Unwrap() {
+			list = append(list, failures(e)...)
+		}
+		return list
+	}
+	var d *errors.Diagnostics
+	if stderrors.As(err, &d) && d.Error() == err.Error() {
+		if d.Problems == nil {
+			return []*errors.Diagnostics{{Summary: d.Summary, Problems: []errors.Diagnostic{}}}
+		}
+		return []*errors.Diagnostics{d}
+	}
+	return []*errors.Diagnostics{{Summary: err.Error(), Problems: []errors.Diagnostic{}}}
+}
+
+// lineDiagnostics returns the failure summarized by summary whose problems
+// are the lines, each read as the message of one diagnostic.
+func lineDiagnostics(summary string, lines []string) error {
+	d := &errors.Diagnostics{Summary: summary}
+	for _, line := range lines {
+		d.Problems = append(d.Problems, errors.Diagnostic{Message: line})
+	}
+	return d
+}
@@ -0,0 +1,173 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines around the changes of a hunk.
+const diffContext = 3
+
+// edit is a line of an edit script: kept (' '), deleted ('-') or inserted
+// ('+'), at the positions a and b of the old and new lines it applies at.
+type edit struct {
+	kind byte
+	line string
+	a, b int
+}
+
+// unifiedDiff returns the unified diff turning old, the content of the file
+// name on disk, into new, or "" when they are equal. exists is false when the
+// file is missing, old then being empty.
+func unifiedDiff(name, old, new string, exists bool) string {
+	if exists && old == new {
+		return ""
+	}
+	edits := diffLines(splitLines(old), splitLines(new))
+	var out strings.Builder
+	if exists {
+		fmt.Fprintf(&out, "--- a/%s\n", name)
+	} else {
+		out.WriteString("--- /dev/null\n")
+	}
+	fmt.Fprintf(&out, "+++ b/%s\n", name)
+	for i := 0; i < len(edits); {
+		if edits[i].kind == ' ' {
+			i++
+			continue
+		}
+		// Extend the hunk over the changes separated by at most twice
+		// the context.
+		end := i + 1
+		for j := end; j < len(edits) && j-end < 2*diffContext; j++ {
+			if edits[j].kind != ' ' {
+				end = j + 1
+			}
+		}
+		start, stop := max(0, i-diffContext), min(len(edits), end+diffContext)
+		var oldLines, newLines int
+		for _, e := range edits[start:stop] {
+			if e.kind != '+' {
+				oldLines++
+			}
+			if e.kind != '-' {
+				newLines++
+			}
+		}
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n", hunkRange(edits[start].a, oldLines), hunkRange(edits[start].b, newLines))
+		for _, e := range edits[start:stop] {
+			out.WriteByte(e.kind)
+			out.WriteString(e.line)
+			if !strings.HasSuffix(e.line, "\n") {
+				out.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+		i = stop
+	}
+	return out.String()
+}
+
+// hunkRange formats the range of a hunk starting at the 0-based line start
+// and spanning n lines.
+func hunkRange(start, n int) string {
+	if n == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if n == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, n)
+}
+
+// splitLines splits s into lines, each keeping its line terminator.
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffLines returns the shortest edit script turning a into b, computed with
+// the Myers algorithm.
+func diffLines(a, b []string) []edit {
+	n, m := len(a), len(b)
+	if n == 0 || m == 0 {
+		var edits []edit
+		for i, line := range a {
+			edits = append(edits, edit{kind: '-', line: line, a: i})
+		}
+		for i, line := range b {
+			edits = append(edits, edit{kind: '+', line: line, b: i})
+		}
+		return edits
+	}
+	// v holds, per diagonal k shifted by offset, the furthest x reached;
+	// trace keeps the diagonals -d-1 to d+1 of v as they were before step d.
+	offset := n + m + 1
+	v := make([]int, 2*offset+1)
+	var trace [][]int
+	for d := 0; d <= n+m; d++ {
+		trace = append(trace, append([]int(nil), v[offset-d-1:offset+d+2]...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrack(trace, a, b)
+			}
+		}
+	}
+	return nil
+}
+
+// backtrack walks trace back from the end of a and b into the edit script
+// reaching it.
+func backtrack(trace [][]int, a, b []string) []edit {
+	x, y := len(a), len(b)
+	var edits []edit
+	for d := len(trace) - 1; d >= 0; d-- {
+		// at returns the furthest x of diagonal k before step d.
+		at := func(k int) int { return trace[d][k+d+1] }
+		k := x - y
+		prev := k - 1
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prev = k + 1
+		}
+		prevX := 0
+		if d > 0 {
+			prevX = at(prev)
+		}
+		prevY := prevX - prev
+		for x > prevX && y > prevY {
+			x, y = x-1, y-1
+			edits = append(edits, edit{kind: ' ', line: a[x], a: x, b: y})
+		}
+		if d == 0 {
+			break
+		}
+		if x == prevX {
+			y--
+			edits = append(edits, edit{kind: '+', line: b[y], a: x, b: y})
+		} else {
+			x--
+			edits = append(edits, edit{kind: '-', line: a[x], a: x, b: y})
+		}
+	}
+	for i, j := 0, len(edits)-1; i < j; i, j = i+1, j-1 {
+		edits[i], edits[j] = edits[j], edits[i]
+	}
+	return edits
+}
@@ -0,0 +1,484 @@
+// Package generator is the generation core of protoc-gen-sphere-errors, for
+// embedding the plugin in a code generation orchestrator or testing it in
+// process. Generate answers a whole plugin request, Run generates into a
+// protogen plugin created by the caller, and Params parses the plugin
+// parameter into a Config.
+package generator
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/catalog"
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/csharp"
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/errors"
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/graph"
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/java"
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/jsonschema"
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/kotlin"
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/markdown"
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/openapi"
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/report"
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/routes"
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/sql"
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/swift"
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/typescript"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+// Version is the version of the plugin.
+const Version = "0.0.1"
+
+// Config configures a generation request: the generated errors and every
+// output written alongside them. The zero value of each field but Errors
+// leaves its output out; NewParams().Config() returns the configuration of a
+// request without parameters.
+type Config struct {
+	// Errors configures the generated errors, shared by every output. It
+	// must not be nil, and is not modified by generation.
+	Errors *errors.Config
+	// Langs are the per-file outputs: go, ts, swift, kotlin, java and csharp.
+	// Empty generates go only.
+	Langs []string
+	// DocOut writes per-file error documentation when markdown.
+	DocOut string
+	// CatalogOut writes a per-package error catalog when json or yaml.
+	CatalogOut string
+	// OpenAPIOut writes per-package OpenAPI error components when json or
+	// yaml.
+	OpenAPIOut string
+	// JSONSchemaOut writes a per-package JSON Schema of the error response
+	// body when 2020-12 or draft-07.
+	JSONSchemaOut string
+	// SQLOut writes a per-package error_catalog upsert migration when
+	// postgres, mysql or sqlite.
+	SQLOut string
+	// ReportOut writes a governance report of the request when json or
+	// markdown.
+	ReportOut string
+	// GraphOut writes a Graphviz graph of the error taxonomy of the request
+	// when dot.
+	GraphOut string
+	// RoutesOut writes the errors declared by the method of every HTTP route
+	// of the request when json. It requires Errors.MethodErrorsOption.
+	RoutesOut string
+	// LookupCmd, when set, is the directory an errlookup command is written
+	// into. It requires Errors.Registry.
+	LookupCmd string
+	// DI writes dependency injection glue when wire or fx, into the Go
+	// package DIPackage, as 'path' or 'path;name'. It requires
+	// Errors.Registry.
+	DI        string
+	DIPackage string
+	// Baselines are previously published error catalogs the generated errors
+	// are checked against for incompatible changes, which fail generation
+	// unless BaselineWarnOnly.
+	Baselines        []string
+	BaselineWarnOnly bool
+	// ChangelogOut writes a per-package changelog of the errors against
+	// Baselines when markdown or json. It requires Baselines.
+	ChangelogOut string
+	// Lint checks the error enums and fails on findings instead of
+	// generating code.
+	Lint bool
+	// Workers is the number of files generated concurrently, 0 for
+	// GOMAXPROCS and 1 for sequential generation.
+	Workers int
+	// CacheDir, when set, is the directory caching the generated files of
+	// each proto file.
+	CacheDir string
+	// WriteIfChanged, when set, is the output directory of the plugin: the
+	// generated files byte-identical to those already in it are left out of
+	// the response, so that protoc does not rewrite them.
+	WriteIfChanged string
+	// DiffDir, when set, is the output directory of the plugin the generated
+	// files are compared with instead of being written: generation fails
+	// with their unified diff when any differs.
+	DiffDir string
+	// Warnings receives baseline and stability warnings, os.Stderr when nil.
+	Warnings io.Writer `json:"-"`
+}
+
+// validate rejects invalid and conflicting settings of c.
+func (c Config) validate() error {
+	if c.Errors == nil {
+		return fmt.Errorf("generator: Config.Errors is nil")
+	}
+	for _, l := range c.Langs {
+		switch l {
+		case "go", "ts", "swift", "kotlin", "java", "csharp":
+		default:
+			return fmt.Errorf("invalid lang %q, expected go, ts, swift, kotlin, java or csharp", l)
+		}
+	}
+	if c.DocOut != "" && c.DocOut != "markdown" {
+		return fmt.Errorf("invalid doc_out %q, expected markdown", c.DocOut)
+	}
+	if c.SQLOut != "" {
+		if err := sql.ValidateDialect(c.SQLOut); err != nil {
+			return err
+		}
+	}
+	if c.GraphOut != "" {
+		if err := graph.ValidateFormat(c.GraphOut); err != nil {
+			return err
+		}
+	}
+	if c.RoutesOut != "" {
+		if err := routes.ValidateFormat(c.RoutesOut); err != nil {
+			return err
+		}
+		if c.Errors.MethodErrorsOption == "" {
+			return fmt.Errorf("routes_out requires method_errors_option")
+		}
+	}
+	if c.JSONSchemaOut != "" {
+		if err := jsonschema.ValidateFormat(c.JSONSchemaOut); err != nil {
+			return err
+		}
+	}
+	if c.ChangelogOut != "" {
+		if err := catalog.ValidateChangelogFormat(c.ChangelogOut); err != nil {
+			return err
+		}
+		if len(c.Baselines) == 0 {
+			return fmt.Errorf("changelog_out requires baseline")
+		}
+	}
+	if c.DiffDir != "" && c.WriteIfChanged != "" {
+		return fmt.Errorf("diff cannot be combined with write_if_changed, as it writes no files")
+	}
+	if c.Workers < 0 {
+		return fmt.Errorf("invalid workers %d, expected 0 or more", c.Workers)
+	}
+	if c.Errors.Prebuilt && c.Errors.MessageResolver {
+		return fmt.Errorf("prebuilt cannot be combined with message_resolver, whose messages may change after the errors are built")
+	}
+	if c.Errors.KratosCompat && c.Errors.SentinelErrors {
+		return fmt.Errorf("kratos_compat cannot be combined with sentinel_errors, whose Is predicates share the names")
+	}
+	if c.LookupCmd != "" && !c.Errors.Registry {
+		return fmt.Errorf("lookup_cmd requires registry=true")
+	}
+	if err := errors.ValidateDI(c.DI); err != nil {
+		return err
+	}
+	if c.DI != "" && !c.Errors.Registry {
+		return fmt.Errorf("di requires registry=true")
+	}
+	if c.DI != "" && c.DIPackage == "" {
+		return fmt.Errorf("di requires di_package")
+	}
+	return nil
+}
+
+// lang reports whether the per-file output l is requested.
+func (c Config) lang(l string) bool {
+	if len(c.Langs) == 0 {
+		return l == "go"
+	}
+	return slices.Contains(c.Langs, l)
+}
+
+// Generate answers the plugin request req, configured by cfg. The request
+// parameter may only hold the parameters of protogen itself, such as
+// paths=source_relative and M mappings; the plugin parameters are given by
+// cfg. Generation failures are returned as the error rather than set on the
+// response.
+func Generate(req *pluginpb.CodeGeneratorRequest, cfg Config) (*pluginpb.CodeGeneratorResponse, error) {
+	gen, err := protogen.Options{
+		ParamFunc: func(name, _ string) error {
+			return fmt.Errorf("unknown parameter %q, plugin parameters are set in the generator.Config", name)
+		},
+	}.New(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := Run(gen, cfg); err != nil {
+		return nil, err
+	}
+	resp := gen.Response()
+	if resp.Error != nil {
+		return nil, fmt.Errorf("%s", resp.GetError())
+	}
+	return resp, nil
+}
+
+// Run generates every output requested by cfg into gen.
+func Run(gen *protogen.Plugin, cfg Config) error {
+	switch {
+	case cfg.DiffDir != "":
+		return runDiff(gen, cfg)
+	case cfg.WriteIfChanged != "":
+		return runIfChanged(gen, cfg)
+	}
+	return run(gen, cfg)
+}
+
+// run generates every output requested by cfg into gen.
+func run(gen *protogen.Plugin, cfg Config) error {
+	errors.SetSupportedFeatures(gen)
+	if err := cfg.validate(); err != nil {
+		return err
+	}
+	// Generation resolves options and Go names into the errors config, so
+	// it works on a copy shared by no other request.
+	config := new(errors.Config)
+	*config = *cfg.Errors
+	config.Module = moduleParam(gen.Request.GetParameter())
+	if err := errors.ResolveOptions(gen.Files, config); err != nil {
+		return err
+	}
+	if err := errors.GateFiles(gen.Files, config); err != nil {
+		return err
+	}
+	if err := errors.ValidateOutputPaths(gen.Files, config); err != nil {
+		return err
+	}
+	if err := errors.ResolveFallbackErrors(gen.Files, config); err != nil {
+		return err
+	}
+	if err := errors.ValidateEnumValues(gen.Files, config); err != nil {
+		return err
+	}
+	if err := errors.ValidateCodes(gen.Files, config); err != nil {
+		return err
+	}
+	if err := errors.ValidateMergedPackages(gen.Files, config); err != nil {
+		return err
+	}
+	if err := errors.ValidateOptions(gen.Files, config); err != nil {
+		return err
+	}
+	if err := errors.ValidateStatuses(gen.Files, config); err != nil {
+		return err
+	}
+	if err := errors.ValidateGRPCCodes(gen.Files, config); err != nil {
+		return err
+	}
+	if err := errors.ValidateClientCodes(gen.Files, config); err != nil {
+		return err
+	}
+	if err := errors.ValidateStabilities(gen.Files, config); err != nil {
+		return err
+	}
+	if err := errors.ValidateLegacyPatterns(gen.Files, config); err != nil {
+		return err
+	}
+	if err := errors.ValidateVisibilities(gen.Files, config); err != nil {
+		return err
+	}
+	if err := errors.ValidatePlaceholders(gen.Files, config); err != nil {
+		return err
+	}
+	if err := errors.ValidateValueNewErrorsFuncs(gen.Files, config); err != nil {
+		return err
+	}
+	if err := errors.ValidateExamples(gen.Files, config); err != nil {
+		return err
+	}
+	if err := errors.ValidateMethodErrors(gen.Files, config); err != nil {
+		return err
+	}
+	if err := errors.ValidateValidationErrors(gen.Files, config); err != nil {
+		return err
+	}
+	if err := errors.ValidateMessageErrors(gen.Files, config); err != nil {
+		return err
+	}
+	if err := errors.ValidateRecoverErrors(gen.Files, config); err != nil {
+		return err
+	}
+	if err := errors.ValidateFallbackErrors(gen.Files, config); err != nil {
+		return err
+	}
+	if err := errors.ValidateMessages(gen.Files, config); err != nil {
+		return err
+	}
+	if err := errors.ValidateLocalizedMessages(gen.Files, config); err != nil {
+		return err
+	}
+	if err := errors.ValidateSupersedes(gen.Files, config); err != nil {
+		return err
+	}
+	errors.AssignGoNames(gen.Files, config)
+	if err := errors.ValidateSymbols(gen.Files, config); err != nil {
+		return err
+	}
+	baselines, err := loadBaselines(cfg.Baselines)
+	if err != nil {
+		return err
+	}
+	if err := checkBaselines(gen, config, baselines, cfg); err != nil {
+		return err
+	}
+	for _, warning := range errors.StabilityWarnings(gen.Files, config) {
+		fmt.Fprintln(cfg.warnings(), "protoc-gen-sphere-errors: warning:", warning)
+	}
+	if cfg.Lint {
+		var files []*protogen.File
+		for _, f := range gen.Files {
+			if f.Generate {
+				files = append(files, f)
+			}
+		}
+		if findings := errors.Lint(files, config); len(findings) > 0 {
+			return lineDiagnostics("lint", findings)
+		}
+		return nil
+	}
+	cache, err := newFileCache(gen, cfg)
+	if err != nil {
+		return err
+	}
+	if err := generateFiles(gen, cfg.Workers, cache, func(gen *protogen.Plugin, f *protogen.File) error {
+		return generateFile(gen, f, config, cfg)
+	}); err != nil {
+		return err
+	}
+	if cfg.CatalogOut != "" {
+		if err := catalog.GenerateFiles(gen, config, cfg.CatalogOut); err != nil {
+			return err
+		}
+	}
+	if cfg.ChangelogOut != "" {
+		if err := catalog.GenerateChangelogs(gen, config, baselines, cfg.ChangelogOut); err != nil {
+			return err
+		}
+	}
+	if cfg.OpenAPIOut != "" {
+		if err := openapi.GenerateFiles(gen, config, cfg.OpenAPIOut); err != nil {
+			return err
+		}
+	}
+	if cfg.JSONSchemaOut != "" {
+		if err := jsonschema.GenerateFiles(gen, config, cfg.JSONSchemaOut); err != nil {
+			return err
+		}
+	}
+	if cfg.SQLOut != "" {
+		if err := sql.GenerateFiles(gen, config, cfg.SQLOut); err != nil {
+			return err
+		}
+	}
+	if cfg.ReportOut != "" {
+		if err := report.GenerateFile(gen, config, cfg.ReportOut); err != nil {
+			return err
+		}
+	}
+	if cfg.GraphOut != "" {
+		if err := graph.GenerateFile(gen, config, cfg.GraphOut); err != nil {
+			return err
+		}
+	}
+	if cfg.RoutesOut != "" {
+		if err := routes.GenerateFile(gen, config, cfg.RoutesOut); err != nil {
+			return err
+		}
+	}
+	if cfg.LookupCmd != "" {
+		errors.GenerateLookupCommand(gen, config, cfg.LookupCmd)
+	}
+	if cfg.DI != "" {
+		importPath, name, _ := strings.Cut(cfg.DIPackage, ";")
+		errors.GenerateDI(gen, config, cfg.DI, protogen.GoImportPath(importPath), protogen.GoPackageName(name))
+	}
+	return nil
+}
+
+// generateFile generates every requested per-file output of f.
+func generateFile(gen *protogen.Plugin, f *protogen.File, config *errors.Config, cfg Config) error {
+	if cfg.lang("go") {
+		if _, err := errors.GenerateFile(gen, f, config); err != nil {
+			return err
+		}
+	}
+	if cfg.lang("ts") {
+		if _, err := typescript.GenerateFile(gen, f, config); err != nil {
+			return err
+		}
+	}
+	if cfg.lang("swift") {
+		if _, err := swift.GenerateFile(gen, f, config); err != nil {
+			return err
+		}
+	}
+	if cfg.lang("kotlin") {
+		if _, err := kotlin.GenerateFile(gen, f, config); err != nil {
+			return err
+		}
+	}
+	if cfg.lang("java") {
+		if _, err := java.GenerateFile(gen, f, config); err != nil {
+			return err
+		}
+	}
+	if cfg.lang("csharp") {
+		if _, err := csharp.GenerateFile(gen, f, config); err != nil {
+			return err
+		}
+	}
+	if cfg.DocOut == "markdown" {
+		if _, err := markdown.GenerateFile(gen, f, config); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadBaselines reads the baseline catalogs names.
+func loadBaselines(names []string) ([]*catalog.Catalog, error) {
+	var baselines []*catalog.Catalog
+	for _, name := range names {
+		c, err := catalog.Load(name)
+		if err != nil {
+			return nil, err
+		}
+		baselines = append(baselines, c)
+	}
+	return baselines, nil
+}
+
+// warnings returns the writer of the warnings of generation: c.Warnings, or
+// os.Stderr when nil.
+func (c Config) warnings() io.Writer {
+	if c.Warnings == nil {
+		return os.Stderr
+	}
+	return c.Warnings
+}
+
+// checkBaselines compares the generated errors against baselines.
+// Incompatible changes fail generation, or are written to cfg.Warnings with
+// BaselineWarnOnly.
+func checkBaselines(gen *protogen.Plugin, config *errors.Config, baselines []*catalog.Catalog, cfg Config) error {
+	if len(baselines) == 0 {
+		return nil
+	}
+	problems := catalog.CheckBaselines(gen, config, baselines)
+	if len(problems) == 0 {
+		return nil
+	}
+	if cfg.BaselineWarnOnly {
+		for _, problem := range problems {
+			fmt.Fprintln(cfg.warnings(), "protoc-gen-sphere-errors: warning:", problem)
+		}
+		return nil
+	}
+	return lineDiagnostics("incompatible error changes against baseline", problems)
+}
+
+// moduleParam returns the module= parameter of parameter, the plugin
+// parameter of a request, which protogen consumes before the plugin sees it.
+func moduleParam(parameter string) string {
+	for _, param := range strings.Split(parameter, ",") {
+		if value, ok := strings.CutPrefix(param, "module="); ok {
+			return value
+		}
+	}
+	return ""
+}
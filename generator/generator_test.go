@@ -0,0 +1,578 @@
+package generator
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+// generate runs the plugin on basic_errors.proto with the request parameter
+// parameter, the way a single protoc or buf invocation would.
+func generate(t *testing.T, parameter string) *pluginpb.CodeGeneratorResponse {
+	t.Helper()
+	return generateProtos(t, parameter, "basic_errors")
+}
+
+// generateProtos runs the plugin on the named test protos at once with the
+// request parameter parameter.
+func generateProtos(t *testing.T, parameter string, names ...string) *pluginpb.CodeGeneratorResponse {
+	t.Helper()
+	req := request(t, parameter, names...)
+	p := NewParams()
+	gen, err := protogen.Options{ParamFunc: p.Set}.New(req)
+	if err != nil {
+		return &pluginpb.CodeGeneratorResponse{Error: proto.String(err.Error())}
+	}
+	cfg, err := p.Config()
+	if err == nil {
+		err = Run(gen, cfg)
+	}
+	if err != nil {
+		gen.Error(err)
+	}
+	return gen.Response()
+}
+
+// request returns the plugin request generating the named test protos with
+// the request parameter parameter.
+func request(t *testing.T, parameter string, names ...string) *pluginpb.CodeGeneratorRequest {
+	t.Helper()
+	req := &pluginpb.CodeGeneratorRequest{Parameter: proto.String(parameter)}
+	seen := map[string]bool{}
+	for _, name := range names {
+		data, err := os.ReadFile("../generate/errors/testdata/pb/" + name + ".pb")
+		if err != nil {
+			t.Fatal(err)
+		}
+		var set descriptorpb.FileDescriptorSet
+		if err := proto.Unmarshal(data, &set); err != nil {
+			t.Fatal(err)
+		}
+		for _, f := range set.File {
+			if !seen[f.GetName()] {
+				seen[f.GetName()] = true
+				req.ProtoFile = append(req.ProtoFile, f)
+			}
+		}
+		req.FileToGenerate = append(req.FileToGenerate, name+".proto")
+	}
+	return req
+}
+
+func TestGenerate(t *testing.T) {
+	cfg, err := NewParams().Config()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Langs = []string{"go", "ts"}
+	cfg.Errors.GRPCStatus = true
+	resp, err := Generate(request(t, "paths=source_relative", "basic_errors"), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := generate(t, "paths=source_relative,lang=go,lang=ts,grpc_status=true")
+	if !proto.Equal(resp, want) {
+		t.Error("Generate differs from the plugin run with the equivalent parameter")
+	}
+	again, err := Generate(request(t, "paths=source_relative", "basic_errors"), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(resp, again) {
+		t.Error("a second Generate with the same Config differs from the first")
+	}
+
+	if _, err := Generate(request(t, "grpc_status=true", "basic_errors"), cfg); err == nil || !strings.Contains(err.Error(), "grpc_status") {
+		t.Errorf("error = %v, want the plugin parameter rejected", err)
+	}
+	if _, err := Generate(request(t, "", "basic_errors"), Config{}); err == nil {
+		t.Error("Generate without an errors config succeeded")
+	}
+	cfg.Langs = []string{"rust"}
+	if _, err := Generate(request(t, "", "basic_errors"), cfg); err == nil {
+		t.Error("Generate with an invalid lang succeeded")
+	}
+}
+
+func TestRun_Deterministic(t *testing.T) {
+	first := generate(t, "lang=go,lang=ts,catalog_out=json,openapi_out=yaml,sql_out=postgres,doc_out=markdown,report_out=markdown,graph_out=dot")
+	second := generate(t, "lang=go,lang=ts,catalog_out=json,openapi_out=yaml,sql_out=postgres,doc_out=markdown,report_out=markdown,graph_out=dot")
+	if first.GetError() != "" {
+		t.Fatal(first.GetError())
+	}
+	if !proto.Equal(first, second) {
+		t.Error("two runs with the same request produced different responses")
+	}
+}
+
+func TestRun_Workers(t *testing.T) {
+	protos := []string{"basic_errors", "formatted_errors", "aliased_errors", "mixed_enums", "proto2_errors"}
+	parameter := "lang=go,lang=ts,lang=swift,lang=kotlin,lang=java,lang=csharp,doc_out=markdown,gen_tests=true,catalog_out=json"
+	sequential := generateProtos(t, parameter+",workers=1", protos...)
+	if sequential.GetError() != "" {
+		t.Fatal(sequential.GetError())
+	}
+	for _, workers := range []string{"0", "2", "8"} {
+		concurrent := generateProtos(t, parameter+",workers="+workers, protos...)
+		if !proto.Equal(sequential, concurrent) {
+			t.Errorf("workers=%s: response differs from a sequential run", workers)
+		}
+	}
+}
+
+func TestRun_WorkersModule(t *testing.T) {
+	protos := []string{"basic_errors", "formatted_errors", "aliased_errors"}
+	for _, parameter := range []string{"module=github.com/go-sphere/protoc-gen-sphere-errors", "annotate_code=true", "paths=source_relative,annotate_code=true"} {
+		sequential := generateProtos(t, parameter+",gen_tests=true,workers=1", protos...)
+		if sequential.GetError() != "" {
+			t.Fatal(sequential.GetError())
+		}
+		concurrent := generateProtos(t, parameter+",gen_tests=true,workers=4", protos...)
+		if !proto.Equal(sequential, concurrent) {
+			t.Errorf("%s: concurrent response differs from a sequential run", parameter)
+		}
+		if len(concurrent.GetFile()) == 0 {
+			t.Fatalf("%s: no files generated", parameter)
+		}
+		for _, f := range concurrent.GetFile() {
+			if strings.HasPrefix(parameter, "module=") && strings.HasPrefix(f.GetName(), "github.com/") {
+				t.Errorf("%s: %s keeps the module prefix", parameter, f.GetName())
+			}
+		}
+		if strings.Contains(parameter, "annotate_code") && !slices.ContainsFunc(concurrent.GetFile(), func(f *pluginpb.CodeGeneratorResponse_File) bool {
+			return strings.HasSuffix(f.GetName(), ".go.meta")
+		}) {
+			t.Errorf("%s: no annotations generated", parameter)
+		}
+	}
+}
+
+func TestRun_WorkersJoinErrors(t *testing.T) {
+	// Both files fail on a detail_type naming a message that does not exist.
+	parameter := "detail_type=tests.basic.USER_ERROR_NOT_FOUND=tests.Missing,detail_type=tests.formatted.QUOTA_ERROR_EXCEEDED=tests.Missing"
+	for _, workers := range []string{"1", "4"} {
+		resp := generateProtos(t, parameter+",workers="+workers, "basic_errors", "formatted_errors")
+		if n := strings.Count(resp.GetError(), "tests.Missing"); n != 2 {
+			t.Errorf("workers=%s: error %q, want the errors of both files", workers, resp.GetError())
+		}
+	}
+}
+
+func TestRun_CacheDir(t *testing.T) {
+	protos := []string{"basic_errors", "formatted_errors"}
+	dir := t.TempDir()
+	parameter := "lang=go,lang=ts,cache_dir=" + dir
+	uncached := generateProtos(t, "lang=go,lang=ts", protos...)
+	first := generateProtos(t, parameter, protos...)
+	if first.GetError() != "" {
+		t.Fatal(first.GetError())
+	}
+	if !proto.Equal(uncached, first) {
+		t.Error("a run filling the cache differs from an uncached run")
+	}
+	if second := generateProtos(t, parameter, protos...); !proto.Equal(first, second) {
+		t.Error("a run replaying the cache differs from the run filling it")
+	}
+
+	// Tamper with the cached output to observe which files are replayed.
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != len(protos) {
+		t.Fatalf("cache holds %d entries, want %d", len(entries), len(protos))
+	}
+	for _, e := range entries {
+		name := filepath.Join(dir, e.Name())
+		data, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var resp pluginpb.CodeGeneratorResponse
+		if err := proto.Unmarshal(data, &resp); err != nil {
+			t.Fatal(err)
+		}
+		for _, f := range resp.File {
+			f.Content = proto.String("// replayed\n")
+		}
+		if data, err = proto.Marshal(&resp); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(name, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, f := range generateProtos(t, parameter, protos...).File {
+		if f.GetContent() != "// replayed\n" {
+			t.Errorf("%s was generated again instead of replayed", f.GetName())
+		}
+	}
+	for _, f := range generateProtos(t, parameter+",grpc_status=true", protos...).File {
+		if f.GetContent() == "// replayed\n" {
+			t.Errorf("%s was replayed for another parameter", f.GetName())
+		}
+	}
+}
+
+func TestRun_WriteIfChanged(t *testing.T) {
+	protos := []string{"basic_errors", "formatted_errors"}
+	dir := t.TempDir()
+	parameter := "lang=go,lang=ts,write_if_changed=" + dir
+	full := generateProtos(t, "lang=go,lang=ts", protos...)
+	if first := generateProtos(t, parameter, protos...); !proto.Equal(full, first) {
+		t.Error("a run into an empty directory differs from a plain run")
+	}
+	for _, f := range full.File {
+		name := filepath.Join(dir, f.GetName())
+		if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(name, []byte(f.GetContent()), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if resp := generateProtos(t, parameter, protos...); resp.GetError() != "" || len(resp.File) != 0 {
+		t.Errorf("a run over unchanged files generated %d files (error %q), want none", len(resp.File), resp.GetError())
+	}
+
+	changed := full.File[0].GetName()
+	if err := os.WriteFile(filepath.Join(dir, changed), []byte("// stale\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	resp := generateProtos(t, parameter, protos...)
+	if len(resp.File) != 1 || resp.File[0].GetName() != changed || resp.File[0].GetContent() != full.File[0].GetContent() {
+		t.Errorf("a run over a stale %s generated %d files, want only it", changed, len(resp.File))
+	}
+	if resp.GetSupportedFeatures() != full.GetSupportedFeatures() {
+		t.Errorf("supported features = %d, want %d", resp.GetSupportedFeatures(), full.GetSupportedFeatures())
+	}
+}
+
+func TestRun_Diff(t *testing.T) {
+	dir := t.TempDir()
+	parameter := "lang=go,diff=" + dir
+	full := generateProtos(t, "lang=go", "basic_errors")
+	resp := generateProtos(t, parameter, "basic_errors")
+	name := full.File[0].GetName()
+	if len(resp.File) != 0 || !strings.Contains(resp.GetError(), "--- /dev/null\n+++ b/"+name+"\n@@ -0,0 +1,") {
+		t.Errorf("a diff against an empty directory = %d files, error %q, want the new file", len(resp.File), resp.GetError())
+	}
+
+	stale := strings.Replace(full.File[0].GetContent(), "package basic", "package stale", 1)
+	if err := os.MkdirAll(filepath.Join(dir, filepath.Dir(name)), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(stale), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	resp = generateProtos(t, parameter, "basic_errors")
+	if !strings.Contains(resp.GetError(), "--- a/"+name+"\n+++ b/"+name+"\n") || !strings.Contains(resp.GetError(), "\n-package stale\n+package basic\n") {
+		t.Errorf("a diff against a stale file = %q, want the changed package clause", resp.GetError())
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(full.File[0].GetContent()), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if resp := generateProtos(t, parameter, "basic_errors"); resp.GetError() != "" || len(resp.File) != 0 {
+		t.Errorf("a diff against up-to-date files = %d files, error %q, want neither", len(resp.File), resp.GetError())
+	}
+	if resp := generateProtos(t, parameter+",write_if_changed="+dir, "basic_errors"); !strings.Contains(resp.GetError(), "diff cannot be combined with write_if_changed") {
+		t.Errorf("error = %q, want diff rejected with write_if_changed", resp.GetError())
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	for _, tt := range []struct {
+		old, new string
+		exists   bool
+		want     string
+	}{
+		{"a\nb\n", "a\nb\n", true, ""},
+		{"", "a\n", false, "--- /dev/null\n+++ b/f\n@@ -0,0 +1 @@\n+a\n"},
+		{"a\n", "", true, "--- a/f\n+++ b/f\n@@ -1 +0,0 @@\n-a\n"},
+		{
+			"1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n14\n15\n",
+			"1\n2\nthree\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n15\n",
+			true,
+			"--- a/f\n+++ b/f\n" +
+				"@@ -1,6 +1,6 @@\n 1\n 2\n-3\n+three\n 4\n 5\n 6\n" +
+				"@@ -11,5 +11,4 @@\n 11\n 12\n 13\n-14\n 15\n",
+		},
+		{"a\nb", "a\nc", true, "--- a/f\n+++ b/f\n@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+c\n\\ No newline at end of file\n"},
+	} {
+		if got := unifiedDiff("f", tt.old, tt.new, tt.exists); got != tt.want {
+			t.Errorf("unifiedDiff(%q, %q) =\n%s\nwant\n%s", tt.old, tt.new, got, tt.want)
+		}
+	}
+}
+
+func TestRun_Lint(t *testing.T) {
+	resp := generate(t, "lint=true")
+	if !strings.Contains(resp.GetError(), `basic_errors.proto:16: message of tests.basic.UserError.USER_ERROR_INVALID_ID "invalid user ID format" does not start with an upper-case letter`) {
+		t.Errorf("error = %q, want the lint findings", resp.GetError())
+	}
+	if len(resp.File) != 0 {
+		t.Errorf("lint generated %d files, want none", len(resp.File))
+	}
+	if resp := generate(t, "lint=true,include_enums=tests.none.*"); resp.GetError() != "" {
+		t.Errorf("lint without error enums failed: %s", resp.GetError())
+	}
+}
+
+func TestRun_NoStateBetweenRequests(t *testing.T) {
+	if resp := generate(t, "grpc_status=true,lang=ts"); resp.GetError() != "" {
+		t.Fatal(resp.GetError())
+	}
+	resp := generate(t, "")
+	if resp.GetError() != "" {
+		t.Fatal(resp.GetError())
+	}
+	if len(resp.File) != 1 || !strings.HasSuffix(resp.File[0].GetName(), ".errors.pb.go") {
+		t.Fatalf("files = %v, want only the Go errors file", resp.File)
+	}
+	if strings.Contains(resp.File[0].GetContent(), "GRPCStatus") {
+		t.Error("grpc_status of an earlier request leaked into a later one")
+	}
+}
+
+func TestRun_InvalidParameter(t *testing.T) {
+	for _, parameter := range []string{"no_such_param=1", "message_error=tests.basic.USER_ERROR_NOT_FOUND", "message_error=tests.basic.NoSuchMessage=tests.basic.USER_ERROR_NOT_FOUND", "message_i18n=tests.basic.USER_ERROR_NOT_FOUND=x", "message_i18n=tests.basic.NO_SUCH_VALUE:zh=x", "binary_format=protobuf", "messages_file=testdata/no_such_messages.yaml", "otel_event=tests.basic.UserError", "span_error=tests.basic.UserError=maybe", "routes_out=yaml", "routes_out=json", "go_package_dir=example.com/api=../api", "error_format=xml", "legacy_pattern=tests.basic.USER_ERROR_NOT_FOUND=(no such", "stability=tests.basic.UserError=ALPHA", "json_schema_out=draft-04", "client_code=shop.v1.OrderError=0", "graph_out=svg", "http_header=tests.basic.UserError=Retry-After", "value_new_errors_func=tests.basic.UserError", "http_envelope=azure", "visibility=tests.basic.UserError=secret", "cache_ttl=tests.basic.UserError=10ms", "zero_value=drop", "minimal_runtime=true,runtime=stdlib", "minimal_runtime=true,new_errors_func=example.com/errs;New", "locale_fallback=zh", "error_text=json", "grpc_code=tests.basic.USER_ERROR_DEFAULTED=TEAPOT", "changelog_out=markdown", "changelog_out=html,baseline=errors.catalog.json", "lang=rust", "runtime=kratos,new_errors_func=a;B", "lookup_cmd=cmd/errlookup", "new_errors_func=a;B;ctx+reason", "sql_out=oracle", "workers=-1", "registry=true,di=fx", "registry=true,di=dig,di_package=example.com/errdi", "di=fx,di_package=example.com/errdi", "status_range=tests.basic.UserError:1-9=400,status_range=tests.basic.UserError:5-20=404", "kratos_compat=true,sentinel_errors=true", "doc_url_base=https://kb.example.com/{id}"} {
+		if resp := generate(t, parameter); resp.GetError() == "" {
+			t.Errorf("%s: expected an error", parameter)
+		}
+	}
+}
+
+func TestFormatError(t *testing.T) {
+	req := request(t, "reserved_codes=2-3,error_format=json", "basic_errors")
+	p := NewParams()
+	gen, err := protogen.Options{ParamFunc: p.Collect}.New(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := p.Config()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = FormatError(Run(gen, cfg), p.ErrorFormat())
+	var report diagnosticsReport
+	if err == nil || json.Unmarshal([]byte(err.Error()), &report) != nil {
+		t.Fatalf("FormatError() = %v, want a JSON report", err)
+	}
+	if len(report.Errors) != 1 || report.Errors[0].Summary != "invalid error codes" || len(report.Errors[0].Problems) != 2 {
+		t.Fatalf("report = %+v, want the two reserved codes", report)
+	}
+	if d := report.Errors[0].Problems[0]; d.File != "basic_errors.proto" || d.Line == 0 || d.Enum != "tests.basic.UserError" || d.Value != "USER_ERROR_NOT_FOUND" {
+		t.Errorf("diagnostic = %+v, want USER_ERROR_NOT_FOUND located in basic_errors.proto", d)
+	}
+
+	plain := FormatError(stderrors.Join(stderrors.New("a failed"), stderrors.New("b failed")), ErrorFormatJSON)
+	if want := `{"errors":[{"error":"a failed","diagnostics":[]},{"error":"b failed","diagnostics":[]}]}`; plain.Error() != want {
+		t.Errorf("FormatError(joined) = %s, want %s", plain, want)
+	}
+	if text := stderrors.New("failed"); FormatError(text, ErrorFormatText) != text {
+		t.Error("FormatError(text) changed the error")
+	}
+}
+
+func TestParams_Collect(t *testing.T) {
+	p := NewParams()
+	for _, kv := range [][2]string{{"no_such_param", "1"}, {"error_format", "json"}} {
+		if err := p.Collect(kv[0], kv[1]); err != nil {
+			t.Fatalf("Collect(%s) = %v", kv[0], err)
+		}
+	}
+	if _, err := p.Config(); err == nil || !strings.Contains(err.Error(), `unknown parameter "no_such_param"`) {
+		t.Errorf("Config() = %v, want the unknown parameter", err)
+	}
+	if p.ErrorFormat() != ErrorFormatJSON {
+		t.Errorf("ErrorFormat() = %q, want json despite the earlier invalid parameter", p.ErrorFormat())
+	}
+}
+
+func TestRun_OutputPaths(t *testing.T) {
+	const module = "github.com/go-sphere/protoc-gen-sphere-errors"
+	const basic = module + "/generate/errors/testdata/basic"
+	for _, tt := range []struct {
+		parameter string
+		want      []string
+	}{
+		{"", []string{basic + "/basic_errors.errors.pb.go"}},
+		{"paths=source_relative", []string{"basic_errors.errors.pb.go"}},
+		{"paths=source_relative,go_package_dir=" + basic + "=api/basic", []string{"api/basic/basic_errors.errors.pb.go"}},
+		{"module=" + module, []string{"generate/errors/testdata/basic/basic_errors.errors.pb.go"}},
+		{"module=" + module + ",go_package_dir=" + basic + "=api/basic", []string{"api/basic/basic_errors.errors.pb.go"}},
+		{"module=" + module + ",registry=true,lookup_cmd=cmd/errlookup", []string{"generate/errors/testdata/basic/basic_errors.errors.pb.go", "cmd/errlookup/main.go"}},
+		{"module=example.com/api,go_package_dir=" + basic + "=basic", []string{"basic/basic_errors.errors.pb.go"}},
+	} {
+		resp := generate(t, tt.parameter)
+		if resp.GetError() != "" {
+			t.Errorf("%s: %s", tt.parameter, resp.GetError())
+			continue
+		}
+		var got []string
+		for _, f := range resp.File {
+			got = append(got, f.GetName())
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: files = %v, want %v", tt.parameter, got, tt.want)
+		}
+	}
+
+	resp := generate(t, "module=example.com/api")
+	if want := "generated into Go package " + basic + " outside module example.com/api; place it with go_package_dir"; !strings.Contains(resp.GetError(), want) {
+		t.Errorf("error = %q, want it to contain %q", resp.GetError(), want)
+	}
+}
+
+func TestRun_LookupCmd(t *testing.T) {
+	resp := generate(t, "registry=true,lookup_cmd=cmd/errlookup")
+	if resp.GetError() != "" {
+		t.Fatal(resp.GetError())
+	}
+	var main string
+	for _, f := range resp.File {
+		if f.GetName() == "cmd/errlookup/main.go" {
+			main = f.GetContent()
+		}
+	}
+	for _, want := range []string{
+		"package main",
+		`_ "github.com/go-sphere/protoc-gen-sphere-errors/generate/errors/testdata/basic"`,
+		"os.Exit(errlookup.Run(os.Args[1:], os.Stdout))",
+	} {
+		if !strings.Contains(main, want) {
+			t.Errorf("cmd/errlookup/main.go does not contain %q:\n%s", want, main)
+		}
+	}
+}
+
+func TestRun_ConfigFile(t *testing.T) {
+	config := filepath.Join(t.TempDir(), "errors.gen.yaml")
+	if err := os.WriteFile(config, []byte(`
+runtime: stdlib
+sentinel_errors: true
+exclude_enums: [tests.basic.OrderError]
+packages:
+  tests.formatted:
+    output_package: example.com/api/quotaerrors
+    code_offset: 10000
+    runtime: kratos
+`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	resp := generateProtos(t, "config="+config+",runtime=httpx", "basic_errors", "formatted_errors")
+	if resp.GetError() != "" {
+		t.Fatal(resp.GetError())
+	}
+	files := map[string]string{}
+	for _, f := range resp.File {
+		files[f.GetName()] = f.GetContent()
+	}
+	basic := files["github.com/go-sphere/protoc-gen-sphere-errors/generate/errors/testdata/basic/basic_errors.errors.pb.go"]
+	quota := files["example.com/api/quotaerrors/formatted_errors.errors.pb.go"]
+	if basic == "" || quota == "" {
+		t.Fatalf("missing outputs, got %v", slices.Collect(maps.Keys(files)))
+	}
+	for _, tt := range []struct {
+		content, want string
+		present       bool
+	}{
+		{basic, "func IsUserInvalidId(err error) bool", true}, // sentinel_errors
+		{basic, "type OrderError", false},                     // exclude_enums
+		{basic, "httpx.NewError(", true},                      // runtime overridden after config
+		{quota, "package quotaerrors", true},
+		{quota, "return 10001", true},
+		{quota, "func (e QuotaError) newError(", true}, // the kratos adapter
+	} {
+		if strings.Contains(tt.content, tt.want) != tt.present {
+			t.Errorf("output containing %q = %v, want %v", tt.want, !tt.present, tt.present)
+		}
+	}
+
+	for _, bad := range []string{
+		"packages:\n  tests.basic:\n    sentinel_errors: true\n",
+		"config: other.yaml\n",
+		"no_such_param: 1\n",
+		"exclude_enums: {a: b}\n",
+	} {
+		if err := os.WriteFile(config, []byte(bad), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if resp := generate(t, "config="+config); resp.GetError() == "" {
+			t.Errorf("%q: expected an error", bad)
+		}
+	}
+}
+
+func TestRun_MessagesFile(t *testing.T) {
+	messages := filepath.Join(t.TempDir(), "messages.yaml")
+	if err := os.WriteFile(messages, []byte("tests.basic.USER_ERROR_NOT_FOUND: We could not find that account.\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	resp := generateProtos(t, "messages_file="+messages, "basic_errors")
+	if resp.GetError() != "" {
+		t.Fatal(resp.GetError())
+	}
+	content := resp.File[0].GetContent()
+	if !strings.Contains(content, `return "We could not find that account."`) || strings.Contains(content, `return "user does not exist"`) {
+		t.Errorf("messages_file did not override the proto message:\n%s", content)
+	}
+
+	if err := os.WriteFile(messages, []byte("tests.basic.USER_ERROR_GONE: Gone.\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	resp = generateProtos(t, "messages_file="+messages, "basic_errors")
+	if !strings.Contains(resp.GetError(), "tests.basic.USER_ERROR_GONE is not an error enum value of this request") {
+		t.Errorf("error = %q, want the unknown value reported", resp.GetError())
+	}
+}
+
+func TestRun_DI(t *testing.T) {
+	tests := []struct {
+		parameter string
+		want      []string
+	}{
+		{"registry=true,di=fx,di_package=example.com/app/errorsfx", []string{
+			"package errorsfx",
+			`_ "github.com/go-sphere/protoc-gen-sphere-errors/generate/errors/testdata/basic"`,
+			"func NewRegistry() registry.Registry {",
+			`var Module = fx.Module("sphere-errors", fx.Provide(NewRegistry, NewEncoder))`,
+		}},
+		{"registry=true,di=wire,di_package=example.com/app/di;errorswire", []string{
+			"package errorswire",
+			"func NewEncoder() httperrors.Encoder {",
+			"var ProviderSet = wire.NewSet(NewRegistry, NewEncoder)",
+		}},
+		{"registry=true,di=wire,di_package=example.com/app/errorswire,http_envelope=google", []string{
+			"return httperrors.Encoder{Envelope: httperrors.EnvelopeGoogle}",
+		}},
+	}
+	for _, tt := range tests {
+		resp := generate(t, tt.parameter)
+		if resp.GetError() != "" {
+			t.Fatal(resp.GetError())
+		}
+		var glue string
+		for _, f := range resp.File {
+			if strings.HasSuffix(f.GetName(), "/errors_di.go") {
+				glue = f.GetContent()
+			}
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(glue, want) {
+				t.Errorf("%s: errors_di.go does not contain %q:\n%s", tt.parameter, want, glue)
+			}
+		}
+	}
+}
@@ -0,0 +1,884 @@
+package generator
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/errors"
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+const (
+	defaultErrorsPackage = "github.com/go-sphere/httpx"
+)
+
+// Params holds the plugin parameters of a single generation request. Each
+// request parses into a fresh Params, so nothing carries over between
+// invocations of a long-lived plugin process such as a Buf remote plugin.
+type Params struct {
+	flags *flag.FlagSet
+
+	newErrorsFunc *string
+	nameStyle     *string
+	errorText     *string
+	zeroValue     *string
+	rawStatus     *bool
+	templateFile  *string
+	messagesFile  *string
+	grpcStatus    *bool
+	sentinels     *bool
+	kratosCompat  *bool
+	minimal       *bool
+	registry      *bool
+	errorCodes    *bool
+	exhaustive    *bool
+	metrics       *string
+	logHelpers    *bool
+	retryHelpers  *bool
+	originHelpers *bool
+	msgResolver   *bool
+	statusProto   *bool
+	propagation   *bool
+	msgHeaders    *bool
+	joinErrors    *bool
+	otelSpan      *bool
+	withStack     *bool
+	prebuilt      *bool
+	errorFuncs    *bool
+	unexportCtors *bool
+	includeExp    *bool
+	expCodePrefix *string
+	expCodeSuffix *string
+	concreteRet   *bool
+	embedCatalog  *bool
+	catalogSvc    *bool
+	anyDetails    *bool
+	gateway       *bool
+	httpFramework *string
+	httpEnvelope  *string
+	problemJSON   *bool
+	problemType   *string
+	docURLBase    *string
+	parseHelpers  *bool
+	metadata      *bool
+	traceContext  *bool
+	failOnDepr    *bool
+	strict        *bool
+	genTests      *bool
+	genFuzz       *bool
+	errorTest     *bool
+	uniqueCodes   *bool
+	packageSuffix *string
+	aggregate     *bool
+	mergePackages *bool
+	catalogOut    *string
+	openapiOut    *string
+	jsonSchemaOut *string
+	sqlOut        *string
+	reportOut     *string
+	graphOut      *string
+	routesOut     *string
+	lookupCmd     *string
+	di            *string
+	diPackage     *string
+	docOut        *string
+	baselineWarn  *bool
+	changelogOut  *string
+	workers       *int
+	lint          *bool
+	cacheDir      *string
+	ifChanged     *string
+	diffDir       *string
+	optionsType   *string
+	defaultStatus *string
+	methodErrors  *string
+	genOption     *string
+	fallbackOpt   *string
+	genPolicy     *string
+	fileSuffix    *string
+	buildTag      *string
+	generatedBy   *string
+	errorFormat   *string
+
+	// langs collects the repeatable lang parameter. protoc splits plugin
+	// parameters on commas, so several outputs are requested as lang=go,lang=ts.
+	langs           stringList
+	reservedCodes   stringList
+	runtimes        stringList
+	outputPackages  stringList
+	goPackageDirs   stringList
+	configFiles     stringList
+	codeOffsets     stringList
+	severities      stringList
+	sampleRates     stringList
+	cacheTTLs       stringList
+	httpHeaders     stringList
+	sloExempt       stringList
+	visibilities    stringList
+	otelEvents      stringList
+	spanErrors      stringList
+	stabilities     stringList
+	valueNewErrors  stringList
+	retryableValues stringList
+	domains         stringList
+	codePrefixes    stringList
+	defaultMessages stringList
+	statusRanges    stringList
+	logMessages     stringList
+	i18nMessages    stringList
+	grpcCodes       stringList
+	localeFallbacks stringList
+	docURLs         stringList
+	includeEnums    stringList
+	excludeEnums    stringList
+	excludeFiles    stringList
+	baselines       stringList
+	detailTypes     stringList
+	validationErrs  stringList
+	messageErrs     stringList
+	supersedes      stringList
+	recoverErrors   stringList
+	fallbackErrors  stringList
+	binaryFormats   stringList
+	origins         stringList
+	clientCodes     stringList
+	legacyPatterns  stringList
+	categories      stringList
+	optionFields    stringList
+
+	// setErr is the first error of a parameter given to Collect.
+	setErr error
+}
+
+// NewParams returns the parameter set of one generation request, with every
+// parameter at its default.
+func NewParams() *Params {
+	fs := flag.NewFlagSet("protoc-gen-sphere-errors", flag.ContinueOnError)
+	p := &Params{
+		flags:         fs,
+		newErrorsFunc: fs.String("new_errors_func", defaultErrorsPackage+";NewError", "new errors func as path;ident, by default func(status, code int32, message string, err error) error; append ;args such as ;ctx+code+message for another signature"),
+		optionsType:   fs.String("options_type", "", "message read as the error options of enum values in place of (sphere.errors.options), e.g. mycorp.errors.v1.ErrorOptions"),
+		methodErrors:  fs.String("method_errors_option", "", "repeated string method option listing the error enums and values an RPC may return, e.g. mycorp.api.v1.errors"),
+		defaultStatus: fs.String("default_status_option", "", "integer enum option read in place of (sphere.errors.default_status), e.g. mycorp.errors.v1.http_status"),
+		genOption:     fs.String("generate_option", "", "bool file option gating the generation of each file, by default sphere.errors.generate"),
+		fallbackOpt:   fs.String("fallback_option", "", "string file option naming the fallback error value of the Go package of each file, by default sphere.errors.fallback_error"),
+		genPolicy:     fs.String("generate_policy", "", "generation of files not setting the generate option: all (default) or opt_in"),
+		fileSuffix:    fs.String("file_suffix", "", "suffix of the generated Go files replacing .errors.pb.go, e.g. _errors.go"),
+		buildTag:      fs.String("build_tag", "", "//go:build expression added to every generated Go file, e.g. !tinygo"),
+		errorFormat:   fs.String("error_format", ErrorFormatText, "format of the error reported when generation fails: text, or json for machine-readable diagnostics"),
+		generatedBy:   fs.String("generated_by", "", "generator named in the \"Code generated by\" header, by default protoc-gen-sphere-errors"),
+		zeroValue:     fs.String("zero_value", "", "policy for the zero value of error enums: include (default), skip, unknown or fail"),
+		errorText:     fs.String("error_text", "", "text returned by Error() of the error values: reason (default) or code_message, formatted when generating"),
+		nameStyle:     fs.String("name_style", "", "style of the Go identifiers generated per error value: enum_prefixed (default), value_only or camel"),
+		rawStatus:     fs.Bool("raw_status", false, "render HTTP statuses as int32 literals instead of net/http constants such as http.StatusNotFound"),
+		templateFile:  fs.String("template_file", "", "path to a Go text/template file overriding the built-in error template"),
+		messagesFile:  fs.String("messages_file", "", "path to a YAML file of messages keyed by fully-qualified enum value, overriding the proto messages"),
+		grpcStatus:    fs.Bool("grpc_status", false, "generate GetGRPCCode and GRPCStatus methods derived from the HTTP status"),
+		sentinels:     fs.Bool("sentinel_errors", false, "generate Err<Name> sentinel variables and Is<Name>(err) predicates per enum value"),
+		minimal:       fs.Bool("minimal_runtime", false, "make the generated helpers build a package-local error type instead of calling new_errors_func, importing nothing outside the standard library"),
+		kratosCompat:  fs.Bool("kratos_compat", false, "generate the kratos Error<Name>(format, args...) constructors and Is<Name>(err) predicates per enum value"),
+		registry:      fs.Bool("registry", false, "register every error enum with the registry runtime package from init"),
+		errorCodes:    fs.Bool("error_codes", false, "generate a typed <Enum>Code type rendering codes by symbolic name"),
+		exhaustive:    fs.Bool("exhaustive", false, "generate <Enum>Values, <Enum>Codes and ForEach<Enum> iterating every error of an enum; implies registry and error_codes"),
+		metrics:       fs.String("metrics", "", "count created errors: prometheus"),
+		logHelpers:    fs.Bool("log_helpers", false, "generate Severity and slog LogValue methods"),
+		retryHelpers:  fs.Bool("retry_helpers", false, "generate IsRetryable methods, true for 408, 429, 502, 503, 504 and values listed by retryable"),
+		originHelpers: fs.Bool("origin_helpers", false, "generate Origin methods: CLIENT, SERVER or UPSTREAM, defaulting by HTTP status"),
+		problemJSON:   fs.Bool("problem_json", false, "generate Problem methods returning RFC 9457 problem details"),
+		problemType:   fs.String("problem_type", "", "problem type URI template with {package}, {enum}, {value} and {code}, implies problem_json"),
+		docURLBase:    fs.String("doc_url_base", "", "documentation URL template of every error value with {package}, {enum}, {value} and {code}, generating HelpLink methods"),
+		msgResolver:   fs.Bool("message_resolver", false, "generate a package-level SetMessageResolver hook consulted by GetMessage"),
+		statusProto:   fs.Bool("status_proto", false, "generate package-level ToStatusProto and FromStatusProto converting errors to and from google.rpc.Status"),
+		propagation:   fs.Bool("propagation", false, "generate package-level PropagationHeader and FromPropagationHeader re-raising errors across service boundaries"),
+		msgHeaders:    fs.Bool("message_headers", false, "generate package-level InjectMessageHeaders and FromMessageHeaders carrying errors in NATS or Kafka message headers"),
+		joinErrors:    fs.Bool("join_errors", false, "generate a package-level JoinErrors aggregating errors, such as per-item batch failures, into one"),
+		gateway:       fs.Bool("gateway", false, "generate a package-level GatewayErrorHandler writing errors behind grpc-gateway with the httperrors envelope"),
+		httpFramework: fs.String("http_framework", "", "generate a package-level error adapter writing errors with the httperrors envelope for gin, echo or chi"),
+		httpEnvelope:  fs.String("http_envelope", "", "write errors from the generated gateway handler, framework adapter and DI encoder with the sphere (default), google or aws envelope"),
+		concreteRet:   fs.Bool("concrete_return", false, "make the constructors return a package-level *Error type with chainable WithMetadata, WithField and WithDetails methods instead of error"),
+		unexportCtors: fs.Bool("unexported_constructors", false, "unexport the package-level constructors of error values, wrapped by a hand-written facade"),
+		includeExp:    fs.Bool("include_experimental", false, "keep experimental error values in catalogs and the outputs built from them"),
+		expCodePrefix: fs.String("experimental_code_prefix", "", "prefix of the public codes of experimental error values, e.g. X-"),
+		expCodeSuffix: fs.String("experimental_code_suffix", "", "suffix of the public codes of experimental error values, e.g. -beta"),
+		errorFuncs:    fs.Bool("error_funcs", false, "generate package-level Code, HTTPStatus and Message functions reading any error chain"),
+		anyDetails:    fs.Bool("any_details", false, "generate a package-level DetailTypes registry with Details and DecodeDetails packing typed details as google.protobuf.Any"),
+		embedCatalog:  fs.Bool("embed_catalog", false, "embed a JSON catalog of each package's errors and generate a Catalog accessor"),
+		catalogSvc:    fs.Bool("catalog_service", false, "generate a CatalogService serving the embedded catalog as a sphere.errors.catalog.v1.ErrorCatalogService; implies embed_catalog"),
+		prebuilt:      fs.Bool("prebuilt", false, "generate Err methods returning errors constructed once per value, allocation-free on hot paths"),
+		withStack:     fs.Bool("with_stack", false, "record the call stack in every constructed error, read back with stack.From or printed with %+v"),
+		parseHelpers:  fs.Bool("parse_helpers", false, "generate Parse<Enum> and <Enum>FromHTTPResponse functions rebuilding errors from codes and responses"),
+		metadata:      fs.Bool("metadata", false, "generate WithMetadata and WithField methods attaching details via the metadata runtime package"),
+		traceContext:  fs.Bool("trace_context", false, "generate WithTraceContext methods attaching OpenTelemetry trace and span IDs; implies metadata"),
+		otelSpan:      fs.Bool("otel_span", false, "generate OTelEventName and MarksSpanError methods and a package-level RecordToSpan recording errors on OpenTelemetry spans"),
+		failOnDepr:    fs.Bool("fail_on_deprecated_use", false, "move helpers of deprecated values into a file excluded by the sphere_errors_strict build tag"),
+		strict:        fs.Bool("strict", false, "fail when a non-zero error value has no (sphere.errors.options) or no message"),
+		genTests:      fs.Bool("gen_tests", false, "also write a _test.go file asserting the mapping of every error value"),
+		errorTest:     fs.Bool("errortest", false, "also write an errortest sub-package per Go package with assertions and gomock/testify matchers for the errors"),
+		genFuzz:       fs.Bool("gen_fuzz", false, "also write a _fuzz_test.go file with parse round-trip tests, fuzz targets and a code uniqueness test; implies parse_helpers"),
+		uniqueCodes:   fs.Bool("unique_codes", false, "fail when two error values across all files share a code"),
+		packageSuffix: fs.String("package_suffix", "", "generate the Go errors into this sub-package of the message types' package"),
+		mergePackages: fs.Bool("merge_package_errors", false, "treat the error enums of each proto package as one set with unique codes, ErrorByCode and Errors, generated into one file; implies aggregate"),
+		aggregate:     fs.Bool("aggregate", false, "generate the Go errors of every proto file of a Go package into one errors.sphere.go"),
+		catalogOut:    fs.String("catalog_out", "", "also write a per-package error catalog: json or yaml"),
+		openapiOut:    fs.String("openapi_out", "", "also write per-package OpenAPI error components: json or yaml"),
+		jsonSchemaOut: fs.String("json_schema_out", "", "also write a per-package JSON Schema of the error response body: 2020-12 or draft-07"),
+		sqlOut:        fs.String("sql_out", "", "also write a per-package error_catalog upsert migration: postgres, mysql or sqlite"),
+		lookupCmd:     fs.String("lookup_cmd", "", "also write an errlookup command into this directory, requires registry=true"),
+		di:            fs.String("di", "", "also write dependency injection glue providing the registry and encoder: wire or fx, requires registry=true and di_package"),
+		diPackage:     fs.String("di_package", "", "Go package the di glue is written into, as 'path' or 'path;name'"),
+		reportOut:     fs.String("report_out", "", "also write a governance report of the request: json or markdown"),
+		graphOut:      fs.String("graph_out", "", "also write a Graphviz graph of the error taxonomy of the request: dot"),
+		routesOut:     fs.String("routes_out", "", "also write the errors declared by the method of every HTTP route of the request: json"),
+		docOut:        fs.String("doc_out", "", "also write per-file error documentation: markdown"),
+		changelogOut:  fs.String("changelog_out", "", "also write a per-package changelog of the errors against baseline: markdown or json"),
+		baselineWarn:  fs.Bool("baseline_warn_only", false, "report incompatible changes against baseline as warnings instead of failing"),
+		cacheDir:      fs.String("cache_dir", "", "directory caching the generated files of each proto file, replayed while the file and its dependencies are unchanged"),
+		ifChanged:     fs.String("write_if_changed", "", "output directory of the plugin, leaving out of the response the generated files identical to those already in it so that protoc does not rewrite them"),
+		diffDir:       fs.String("diff", "", "output directory of the plugin to compare the generated files with instead of writing them, failing with a unified diff when they differ"),
+		lint:          fs.Bool("lint", false, "check the error enums for common problems and fail on findings instead of generating code"),
+		workers:       fs.Int("workers", 0, "number of files generated concurrently, 0 for GOMAXPROCS and 1 for sequential generation"),
+	}
+	fs.Var(&p.langs, "lang", "output to generate, repeatable: go (default), ts, swift, kotlin, java, csharp")
+	fs.Var(&p.reservedCodes, "reserved_codes", "error code range N or N-M no value may use, repeatable")
+	fs.Var(&p.severities, "severity", "log severity of an enum or enum value, as full.Name=INFO|WARN|ERROR|CRITICAL, repeatable")
+	fs.Var(&p.sampleRates, "log_sample_rate", "fraction of occurrences of an enum or enum value worth logging, as full.Name=RATE from 0 to 1, repeatable")
+	fs.Var(&p.httpHeaders, "http_header", "response header of HTTP errors carrying an enum or enum value, as full.Name=Header-Name:value with {key} placeholders filled from the error metadata, generating HTTPHeaders methods, repeatable")
+	fs.Var(&p.cacheTTLs, "cache_ttl", "how long HTTP responses carrying an enum or enum value may be cached, as full.Name=DURATION such as 60s, generating CacheControl methods, repeatable")
+	fs.Var(&p.origins, "origin", "origin of an enum or enum value, as full.Name=CLIENT|SERVER|UPSTREAM, repeatable")
+	fs.Var(&p.clientCodes, "client_code", "code clients see for the errors of an enum or value, grouping internal codes, as proto.package.VALUE=CODE, repeatable")
+	fs.Var(&p.legacyPatterns, "legacy_pattern", "regular expression matching the bodies of legacy upstream responses denoting an enum value, as proto.package.VALUE=REGEX, generating ClassifyLegacy functions, repeatable")
+	fs.Var(&p.categories, "category", "category of an enum or enum value, as proto.package.Name=category, repeatable")
+	fs.Var(&p.valueNewErrors, "value_new_errors_func", "constructor of a fully-qualified enum or enum value in place of new_errors_func, as name=path;ident called with the arguments of new_errors_func, repeatable")
+	fs.Var(&p.stabilities, "stability", "stability of a fully-qualified enum or enum value, as name=STABLE, name=BETA or name=EXPERIMENTAL, generating Stability methods, repeatable")
+	fs.Var(&p.visibilities, "visibility", "visibility of a fully-qualified enum or enum value, as name=internal or name=public, generating IsInternal methods, repeatable")
+	fs.Var(&p.otelEvents, "otel_event", "OpenTelemetry span event name of a fully-qualified enum or enum value, as name=event, generating RecordToSpan, repeatable")
+	fs.Var(&p.spanErrors, "span_error", "whether a fully-qualified enum or enum value marks its span as Error, as name=true or name=false, generating RecordToSpan, repeatable")
+	fs.Var(&p.sloExempt, "slo_exempt", "fully-qualified enum or enum value whose errors burn no error budget, generating SLOExempt methods and IsSLOExempt, repeatable")
+	fs.Var(&p.retryableValues, "retryable", "fully-qualified enum value that is retryable regardless of its status, repeatable")
+	fs.Var(&p.domains, "domain", "google.rpc.ErrorInfo domain, as domain or proto.package=domain, repeatable")
+	fs.Var(&p.codePrefixes, "code_prefix", "prefix of the codes returned by PublicCode, as prefix or proto.package=prefix, repeatable")
+	fs.Var(&p.defaultMessages, "default_message", "message of values without one, as proto.package.Enum=message, repeatable")
+	fs.Var(&p.statusRanges, "status_range", "status of values without one by number, as proto.package.Enum:N-M=STATUS, repeatable")
+	fs.Var(&p.localeFallbacks, "locale_fallback", "language fallback chain of the generated ResolveMessage, as zh-HK>zh>en, repeatable")
+	fs.Var(&p.grpcCodes, "grpc_code", "gRPC code of a value, deriving its HTTP status when it declares none, as proto.package.VALUE=NOT_FOUND, repeatable")
+	fs.Var(&p.i18nMessages, "message_i18n", "message of a value in another language, as proto.package.VALUE:lang=message, generating message bundles and WithLocale, repeatable")
+	fs.Var(&p.logMessages, "log_message", "internal message returned by Error(), as proto.package.VALUE=message, repeatable")
+	fs.Var(&p.docURLs, "doc_url", "documentation URL of an enum value, as proto.package.VALUE=URL, repeatable")
+	fs.Var(&p.includeEnums, "include_enums", "only generate error enums whose full name matches this glob, repeatable")
+	fs.Var(&p.excludeEnums, "exclude_enums", "skip error enums whose full name matches this glob, repeatable")
+	fs.Var(&p.excludeFiles, "exclude_files", "skip the enums of proto files whose path matches this glob, repeatable")
+	fs.Var(&p.baselines, "baseline", "previously published error catalog (.json or .yaml) to check for incompatible changes, repeatable")
+	fs.Var(&p.detailTypes, "detail_type", "typed detail message of an enum value, as proto.package.VALUE=proto.package.Message, repeatable")
+	fs.Var(&p.messageErrs, "message_error", "error value of the typed error carrying a message, as proto.package.Message=proto.package.VALUE, repeatable")
+	fs.Var(&p.validationErrs, "validation_error", "error value protovalidate violations convert to, as proto.package.VALUE or proto.package.Message=proto.package.VALUE, repeatable")
+	fs.Var(&p.supersedes, "supersedes", "error value of an older API version an error value replaces, as new.package.VALUE=old.package.VALUE, repeatable")
+	fs.Var(&p.binaryFormats, "binary_format", "binary encoding of the httperrors body generated per error enum, cbor or msgpack, repeatable")
+	fs.Var(&p.recoverErrors, "recover_error", "error value panics recovered in its Go package convert to, as proto.package.VALUE, repeatable")
+	fs.Var(&p.fallbackErrors, "fallback_error", "error value AsSphereError wraps the foreign errors of its Go package into, as proto.package.VALUE, repeatable")
+	fs.Var(&p.optionFields, "option_field", "field of options_type holding the status, reason, message or grpc_code, as status=name, repeatable")
+	fs.Var(&p.configFiles, "config", "YAML file of parameters, with per proto package overrides under packages, applied in its place")
+	fs.Var(&p.runtimes, "runtime", "error runtime the generated helpers construct errors for: httpx (default), stdlib, kratos or connect, as runtime or proto.package=runtime, repeatable")
+	fs.Var(&p.goPackageDirs, "go_package_dir", "directory, relative to the output directory, the Go files of a Go package are written to, as import/path=dir, repeatable")
+	fs.Var(&p.outputPackages, "output_package", "generate the Go errors into this package instead of alongside the message types, as 'path;name' or 'proto.package=path;name', repeatable")
+	fs.Var(&p.codeOffsets, "code_offset", "offset added to every error code, as N or proto.package=N, repeatable")
+	return p
+}
+
+// Set sets the plugin parameter name to value. It is the protogen ParamFunc,
+// called once per name=value pair of the request parameter.
+func (p *Params) Set(name, value string) error {
+	if p.flags.Lookup(name) == nil {
+		return fmt.Errorf("unknown parameter %q", name)
+	}
+	if err := p.flags.Set(name, value); err != nil {
+		return err
+	}
+	if name == "config" {
+		return p.loadConfigFile(value)
+	}
+	return nil
+}
+
+// Collect sets the plugin parameter name to value like Set, but keeps the
+// first error for Config to return instead of failing, so that invalid
+// parameters are reported through the CodeGeneratorResponse, in the
+// error_format of the request, like any other generation failure.
+func (p *Params) Collect(name, value string) error {
+	if err := p.Set(name, value); err != nil && p.setErr == nil {
+		p.setErr = err
+	}
+	return nil
+}
+
+// ErrorFormat returns the error_format parameter, the format FormatError
+// reports failures of the request in.
+func (p *Params) ErrorFormat() string {
+	return *p.errorFormat
+}
+
+// isSet reports whether the plugin parameter name was given explicitly.
+func (p *Params) isSet(name string) bool {
+	set := false
+	p.flags.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// Config returns the generation configuration of the parsed plugin
+// parameters.
+func (p *Params) Config() (Config, error) {
+	if p.setErr != nil {
+		return Config{}, p.setErr
+	}
+	if err := ValidateErrorFormat(*p.errorFormat); err != nil {
+		return Config{}, err
+	}
+	config, err := p.errorsConfig()
+	if err != nil {
+		return Config{}, err
+	}
+	return Config{
+		Errors:           config,
+		Langs:            p.langs,
+		DocOut:           *p.docOut,
+		CatalogOut:       *p.catalogOut,
+		OpenAPIOut:       *p.openapiOut,
+		JSONSchemaOut:    *p.jsonSchemaOut,
+		SQLOut:           *p.sqlOut,
+		ReportOut:        *p.reportOut,
+		GraphOut:         *p.graphOut,
+		RoutesOut:        *p.routesOut,
+		LookupCmd:        *p.lookupCmd,
+		DI:               *p.di,
+		DIPackage:        *p.diPackage,
+		Baselines:        p.baselines,
+		BaselineWarnOnly: *p.baselineWarn,
+		ChangelogOut:     *p.changelogOut,
+		Lint:             *p.lint,
+		Workers:          *p.workers,
+		CacheDir:         *p.cacheDir,
+		WriteIfChanged:   *p.ifChanged,
+		DiffDir:          *p.diffDir,
+	}, nil
+}
+
+// stringList is a flag.Value accumulating every occurrence of a parameter.
+type stringList []string
+
+func (s *stringList) String() string { return strings.Join(*s, ",") }
+
+func (s *stringList) Set(v string) error {
+	*s = append(*s, v)
+	return nil
+}
+
+// errorsConfig assembles the errors configuration from the parsed plugin
+// parameters.
+func (p *Params) errorsConfig() (*errors.Config, error) {
+	errPkg := strings.Split(*p.newErrorsFunc, ";")
+	if len(errPkg) != 2 && len(errPkg) != 3 {
+		return nil, fmt.Errorf("invalid new_errors_func format, expected 'path;ident' or 'path;ident;args'")
+	}
+	var newErrorsArgs []string
+	if len(errPkg) == 3 {
+		newErrorsArgs = strings.Split(errPkg[2], "+")
+		if err := errors.ValidateNewErrorsArgs(newErrorsArgs); err != nil {
+			return nil, err
+		}
+	}
+	var runtime string
+	var runtimes map[string]string
+	for _, s := range p.runtimes {
+		pkg, r, err := errors.ParseRuntime(s)
+		if err != nil {
+			return nil, err
+		}
+		if pkg == "" {
+			runtime = r
+			continue
+		}
+		if runtimes == nil {
+			runtimes = map[string]string{}
+		}
+		runtimes[pkg] = r
+	}
+	if err := errors.ValidateMetrics(*p.metrics); err != nil {
+		return nil, err
+	}
+	if err := errors.ValidateZeroValue(*p.zeroValue); err != nil {
+		return nil, err
+	}
+	if err := errors.ValidateErrorText(*p.errorText); err != nil {
+		return nil, err
+	}
+	if err := errors.ValidateNameStyle(*p.nameStyle); err != nil {
+		return nil, err
+	}
+	if err := errors.ValidateHTTPEnvelope(*p.httpEnvelope); err != nil {
+		return nil, err
+	}
+	if err := errors.ValidateHTTPFramework(*p.httpFramework); err != nil {
+		return nil, err
+	}
+	if err := errors.ValidateGeneratePolicy(*p.genPolicy); err != nil {
+		return nil, err
+	}
+	if err := errors.ValidateFileSuffix(*p.fileSuffix); err != nil {
+		return nil, err
+	}
+	if err := errors.ValidateBuildTag(*p.buildTag); err != nil {
+		return nil, err
+	}
+	if err := errors.ValidateGeneratedBy(*p.generatedBy); err != nil {
+		return nil, err
+	}
+	if runtime != "" && runtime != errors.RuntimeHTTPX && p.isSet("new_errors_func") {
+		return nil, fmt.Errorf("new_errors_func only applies to runtime=httpx, got runtime=%s", runtime)
+	}
+	if *p.minimal {
+		if p.isSet("new_errors_func") {
+			return nil, fmt.Errorf("minimal_runtime cannot be combined with new_errors_func, whose constructor it replaces")
+		}
+		for _, s := range p.runtimes {
+			if _, r, _ := errors.ParseRuntime(s); r != errors.RuntimeHTTPX {
+				return nil, fmt.Errorf("minimal_runtime cannot be combined with runtime=%s", s)
+			}
+		}
+	}
+	config := &errors.Config{
+		NewErrorsFunc: protogen.GoIdent{
+			GoName:       errPkg[1],
+			GoImportPath: protogen.GoImportPath(errPkg[0]),
+		},
+		NewErrorsArgs:          newErrorsArgs,
+		Runtime:                runtime,
+		Runtimes:               runtimes,
+		MinimalRuntime:         *p.minimal,
+		GRPCStatus:             *p.grpcStatus,
+		SentinelErrors:         *p.sentinels,
+		KratosCompat:           *p.kratosCompat,
+		Registry:               *p.registry,
+		ErrorCodes:             *p.errorCodes,
+		Exhaustive:             *p.exhaustive,
+		Metrics:                *p.metrics,
+		LogHelpers:             *p.logHelpers,
+		RetryHelpers:           *p.retryHelpers,
+		OriginHelpers:          *p.originHelpers,
+		MessageResolver:        *p.msgResolver,
+		StatusProto:            *p.statusProto,
+		Propagation:            *p.propagation,
+		MessageHeaders:         *p.msgHeaders,
+		JoinErrors:             *p.joinErrors,
+		WithStack:              *p.withStack,
+		Prebuilt:               *p.prebuilt,
+		ErrorFuncs:             *p.errorFuncs,
+		ConcreteReturn:         *p.concreteRet,
+		UnexportedConstructors: *p.unexportCtors,
+		EmbedCatalog:           *p.embedCatalog || *p.catalogSvc,
+		CatalogService:         *p.catalogSvc,
+		AnyDetails:             *p.anyDetails,
+		Gateway:                *p.gateway,
+		HTTPFramework:          *p.httpFramework,
+		HTTPEnvelope:           *p.httpEnvelope,
+		BinaryFormats:          p.binaryFormats,
+		ProblemJSON:            *p.problemJSON || *p.problemType != "",
+		ProblemType:            *p.problemType,
+		DocURLBase:             *p.docURLBase,
+		ParseHelpers:           *p.parseHelpers,
+		Metadata:               *p.metadata,
+		TraceContext:           *p.traceContext,
+		SpanHelpers:            *p.otelSpan,
+		FailOnDeprecatedUse:    *p.failOnDepr,
+		Strict:                 *p.strict,
+		GenTests:               *p.genTests,
+		GenFuzz:                *p.genFuzz,
+		ErrorTest:              *p.errorTest,
+		UniqueCodes:            *p.uniqueCodes,
+		PackageSuffix:          *p.packageSuffix,
+		NameStyle:              *p.nameStyle,
+		ErrorText:              *p.errorText,
+		ZeroValue:              *p.zeroValue,
+		OptionsType:            *p.optionsType,
+		DefaultStatusOption:    *p.defaultStatus,
+		MethodErrorsOption:     *p.methodErrors,
+		GenerateOption:         *p.genOption,
+		FallbackOption:         *p.fallbackOpt,
+		GeneratePolicy:         *p.genPolicy,
+		FileSuffix:             *p.fileSuffix,
+		BuildTag:               *p.buildTag,
+		GeneratedBy:            *p.generatedBy,
+		RawStatus:              *p.rawStatus,
+		Aggregate:              *p.aggregate || *p.mergePackages,
+		MergePackageErrors:     *p.mergePackages,
+		IncludeEnums:           p.includeEnums,
+		ExcludeEnums:           p.excludeEnums,
+		ExcludeFiles:           p.excludeFiles,
+		IncludeExperimental:    *p.includeExp,
+		ExperimentalCodePrefix: *p.expCodePrefix,
+		ExperimentalCodeSuffix: *p.expCodeSuffix,
+		RecoverErrors:          p.recoverErrors,
+		FallbackErrors:         p.fallbackErrors,
+	}
+	if err := errors.ValidateProblemType(config.ProblemType); err != nil {
+		return nil, err
+	}
+	if err := errors.ValidateDocURLBase(config.DocURLBase); err != nil {
+		return nil, err
+	}
+	if err := errors.ValidateBinaryFormats(config.BinaryFormats); err != nil {
+		return nil, err
+	}
+	if err := errors.ValidatePatterns("include_enums", config.IncludeEnums); err != nil {
+		return nil, err
+	}
+	if err := errors.ValidatePatterns("exclude_enums", config.ExcludeEnums); err != nil {
+		return nil, err
+	}
+	if err := errors.ValidatePatterns("exclude_files", config.ExcludeFiles); err != nil {
+		return nil, err
+	}
+	for _, s := range p.outputPackages {
+		pkg, target, err := errors.ParseOutputPackage(s)
+		if err != nil {
+			return nil, err
+		}
+		if pkg == "" {
+			config.OutputPackage, config.OutputPackageName = target.ImportPath, target.Name
+			continue
+		}
+		if config.OutputPackages == nil {
+			config.OutputPackages = map[string]errors.GoPackage{}
+		}
+		config.OutputPackages[pkg] = target
+	}
+	for _, s := range p.goPackageDirs {
+		importPath, dir, err := errors.ParseGoPackageDir(s)
+		if err != nil {
+			return nil, err
+		}
+		if config.GoPackageDirs == nil {
+			config.GoPackageDirs = map[protogen.GoImportPath]string{}
+		}
+		config.GoPackageDirs[importPath] = dir
+	}
+	if *p.templateFile != "" {
+		b, err := os.ReadFile(*p.templateFile)
+		if err != nil {
+			return nil, fmt.Errorf("read template_file: %w", err)
+		}
+		config.Template = string(b)
+	}
+	if *p.messagesFile != "" {
+		messages, err := errors.LoadMessagesFile(*p.messagesFile)
+		if err != nil {
+			return nil, err
+		}
+		config.Messages = messages
+	}
+	for _, s := range p.reservedCodes {
+		r, err := errors.ParseCodeRange(s)
+		if err != nil {
+			return nil, err
+		}
+		config.ReservedCodes = append(config.ReservedCodes, r)
+	}
+	for _, s := range p.codeOffsets {
+		pkg, offset, err := errors.ParseCodeOffset(s)
+		if err != nil {
+			return nil, err
+		}
+		if config.CodeOffsets == nil {
+			config.CodeOffsets = map[string]int32{}
+		}
+		config.CodeOffsets[pkg] = offset
+	}
+	for _, s := range p.severities {
+		name, level, err := errors.ParseSeverity(s)
+		if err != nil {
+			return nil, err
+		}
+		if config.Severities == nil {
+			config.Severities = map[string]string{}
+		}
+		config.Severities[name] = level
+	}
+	for _, s := range p.sampleRates {
+		name, rate, err := errors.ParseLogSampleRate(s)
+		if err != nil {
+			return nil, err
+		}
+		if config.LogSampleRates == nil {
+			config.LogSampleRates = map[string]float64{}
+		}
+		config.LogSampleRates[name] = rate
+	}
+	for _, s := range p.cacheTTLs {
+		name, ttl, err := errors.ParseCacheTTL(s)
+		if err != nil {
+			return nil, err
+		}
+		if config.CacheTTLs == nil {
+			config.CacheTTLs = map[string]time.Duration{}
+		}
+		config.CacheTTLs[name] = ttl
+	}
+	for _, s := range p.httpHeaders {
+		name, header, value, err := errors.ParseHTTPHeader(s)
+		if err != nil {
+			return nil, err
+		}
+		if config.HTTPHeaders == nil {
+			config.HTTPHeaders = map[string]map[string]string{}
+		}
+		if config.HTTPHeaders[name] == nil {
+			config.HTTPHeaders[name] = map[string]string{}
+		}
+		config.HTTPHeaders[name][header] = value
+	}
+	for _, s := range p.origins {
+		name, origin, err := errors.ParseOrigin(s)
+		if err != nil {
+			return nil, err
+		}
+		if config.Origins == nil {
+			config.Origins = map[string]string{}
+		}
+		config.Origins[name] = origin
+	}
+	for _, s := range p.clientCodes {
+		name, code, err := errors.ParseClientCode(s)
+		if err != nil {
+			return nil, err
+		}
+		if config.ClientCodes == nil {
+			config.ClientCodes = map[string]int32{}
+		}
+		config.ClientCodes[name] = code
+	}
+	for _, s := range p.legacyPatterns {
+		value, pattern, err := errors.ParseLegacyPattern(s)
+		if err != nil {
+			return nil, err
+		}
+		if config.LegacyPatterns == nil {
+			config.LegacyPatterns = map[string]string{}
+		}
+		config.LegacyPatterns[value] = pattern
+	}
+	for _, s := range p.categories {
+		name, category, err := errors.ParseCategory(s)
+		if err != nil {
+			return nil, err
+		}
+		if config.Categories == nil {
+			config.Categories = map[string]string{}
+		}
+		config.Categories[name] = category
+	}
+	for _, s := range p.retryableValues {
+		if config.RetryableValues == nil {
+			config.RetryableValues = map[string]bool{}
+		}
+		config.RetryableValues[s] = true
+	}
+	for _, s := range p.sloExempt {
+		if config.SLOExemptValues == nil {
+			config.SLOExemptValues = map[string]bool{}
+		}
+		config.SLOExemptValues[s] = true
+	}
+	for _, s := range p.visibilities {
+		name, visibility, err := errors.ParseVisibility(s)
+		if err != nil {
+			return nil, err
+		}
+		if config.Visibilities == nil {
+			config.Visibilities = map[string]string{}
+		}
+		config.Visibilities[name] = visibility
+	}
+	for _, s := range p.otelEvents {
+		name, event, err := errors.ParseOTelEvent(s)
+		if err != nil {
+			return nil, err
+		}
+		if config.OTelEvents == nil {
+			config.OTelEvents = map[string]string{}
+		}
+		config.OTelEvents[name] = event
+	}
+	for _, s := range p.spanErrors {
+		name, marks, err := errors.ParseSpanError(s)
+		if err != nil {
+			return nil, err
+		}
+		if config.SpanErrors == nil {
+			config.SpanErrors = map[string]bool{}
+		}
+		config.SpanErrors[name] = marks
+	}
+	for _, s := range p.stabilities {
+		name, stability, err := errors.ParseStability(s)
+		if err != nil {
+			return nil, err
+		}
+		if config.Stabilities == nil {
+			config.Stabilities = map[string]string{}
+		}
+		config.Stabilities[name] = stability
+	}
+	for _, s := range p.valueNewErrors {
+		name, ident, err := errors.ParseValueNewErrorsFunc(s)
+		if err != nil {
+			return nil, err
+		}
+		if config.ValueNewErrorsFuncs == nil {
+			config.ValueNewErrorsFuncs = map[string]protogen.GoIdent{}
+		}
+		config.ValueNewErrorsFuncs[name] = ident
+	}
+	for _, s := range p.optionFields {
+		field, name, err := errors.ParseOptionField(s)
+		if err != nil {
+			return nil, err
+		}
+		if config.OptionFields == nil {
+			config.OptionFields = map[string]string{}
+		}
+		config.OptionFields[field] = name
+	}
+	for _, s := range p.domains {
+		pkg, domain, err := errors.ParseDomain(s)
+		if err != nil {
+			return nil, err
+		}
+		if config.Domains == nil {
+			config.Domains = map[string]string{}
+		}
+		config.Domains[pkg] = domain
+	}
+	for _, s := range p.codePrefixes {
+		pkg, prefix, err := errors.ParseCodePrefix(s)
+		if err != nil {
+			return nil, err
+		}
+		if config.CodePrefixes == nil {
+			config.CodePrefixes = map[string]string{}
+		}
+		config.CodePrefixes[pkg] = prefix
+	}
+	for _, s := range p.defaultMessages {
+		enum, msg, err := errors.ParseDefaultMessage(s)
+		if err != nil {
+			return nil, err
+		}
+		if config.DefaultMessages == nil {
+			config.DefaultMessages = map[string]string{}
+		}
+		config.DefaultMessages[enum] = msg
+	}
+	for _, s := range p.statusRanges {
+		enum, r, err := errors.ParseStatusRange(s)
+		if err != nil {
+			return nil, err
+		}
+		if config.StatusRanges == nil {
+			config.StatusRanges = map[string][]errors.StatusRange{}
+		}
+		config.StatusRanges[enum] = append(config.StatusRanges[enum], r)
+	}
+	if err := errors.ValidateStatusRanges(config); err != nil {
+		return nil, err
+	}
+	for _, s := range p.localeFallbacks {
+		tags, err := errors.ParseLocaleFallback(s)
+		if err != nil {
+			return nil, err
+		}
+		config.AddLocaleFallback(tags)
+	}
+	for _, s := range p.grpcCodes {
+		value, code, err := errors.ParseGRPCCode(s)
+		if err != nil {
+			return nil, err
+		}
+		if config.GRPCCodes == nil {
+			config.GRPCCodes = map[string]string{}
+		}
+		config.GRPCCodes[value] = code
+	}
+	for _, s := range p.i18nMessages {
+		value, lang, msg, err := errors.ParseLocalizedMessage(s)
+		if err != nil {
+			return nil, err
+		}
+		config.AddLocalizedMessage(value, lang, msg)
+	}
+	for _, s := range p.logMessages {
+		value, msg, err := errors.ParseLogMessage(s)
+		if err != nil {
+			return nil, err
+		}
+		if config.LogMessages == nil {
+			config.LogMessages = map[string]string{}
+		}
+		config.LogMessages[value] = msg
+	}
+	for _, s := range p.docURLs {
+		value, url, err := errors.ParseDocURL(s)
+		if err != nil {
+			return nil, err
+		}
+		if config.DocURLs == nil {
+			config.DocURLs = map[string]string{}
+		}
+		config.DocURLs[value] = url
+	}
+	for _, s := range p.detailTypes {
+		value, message, err := errors.ParseDetailType(s)
+		if err != nil {
+			return nil, err
+		}
+		if config.DetailTypes == nil {
+			config.DetailTypes = map[string]string{}
+		}
+		config.DetailTypes[value] = message
+	}
+	for _, s := range p.messageErrs {
+		message, value, err := errors.ParseMessageError(s)
+		if err != nil {
+			return nil, err
+		}
+		if config.MessageErrors == nil {
+			config.MessageErrors = map[string]string{}
+		}
+		config.MessageErrors[message] = value
+	}
+	for _, s := range p.validationErrs {
+		message, value, err := errors.ParseValidationError(s)
+		if err != nil {
+			return nil, err
+		}
+		if config.ValidationErrors == nil {
+			config.ValidationErrors = map[string]string{}
+		}
+		config.ValidationErrors[message] = value
+	}
+	for _, s := range p.supersedes {
+		value, superseded, err := errors.ParseSupersedes(s)
+		if err != nil {
+			return nil, err
+		}
+		if config.Supersedes == nil {
+			config.Supersedes = map[string]string{}
+		}
+		config.Supersedes[value] = superseded
+	}
+	return config, nil
+}
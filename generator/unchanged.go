@@ -0,0 +1,83 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/errors"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+// runIfChanged generates the outputs requested by cfg on a plugin of its own
+// and copies into gen only the files that differ from those already in
+// cfg.WriteIfChanged. The files left out keep their content and modification
+// time, since protoc only writes the files of the response.
+func runIfChanged(gen *protogen.Plugin, cfg Config) error {
+	resp, err := generateAside(gen, cfg)
+	if err != nil {
+		return err
+	}
+	for _, rf := range resp.GetFile() {
+		if unchanged(filepath.Join(cfg.WriteIfChanged, filepath.FromSlash(rf.GetName())), rf.GetContent()) {
+			continue
+		}
+		g := gen.NewGeneratedFile(rf.GetName(), "")
+		if _, err := g.Write([]byte(rf.GetContent())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runDiff generates the outputs requested by cfg on a plugin of its own and
+// compares them with the files already in cfg.DiffDir, leaving gen without
+// files. It fails with the unified diff of the files that differ or are
+// missing.
+func runDiff(gen *protogen.Plugin, cfg Config) error {
+	resp, err := generateAside(gen, cfg)
+	if err != nil {
+		return err
+	}
+	var diffs strings.Builder
+	for _, rf := range resp.GetFile() {
+		data, err := os.ReadFile(filepath.Join(cfg.DiffDir, filepath.FromSlash(rf.GetName())))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		diffs.WriteString(unifiedDiff(rf.GetName(), string(data), rf.GetContent(), err == nil))
+	}
+	if diffs.Len() > 0 {
+		return fmt.Errorf("generated files differ from %s:\n%s", cfg.DiffDir, strings.TrimSuffix(diffs.String(), "\n"))
+	}
+	return nil
+}
+
+// generateAside generates the outputs requested by cfg on a plugin sharing
+// the request of gen and returns its files, already formatted. gen only
+// receives the supported features.
+func generateAside(gen *protogen.Plugin, cfg Config) (*pluginpb.CodeGeneratorResponse, error) {
+	errors.SetSupportedFeatures(gen)
+	shadow := &protogen.Plugin{
+		Request:     gen.Request,
+		Files:       gen.Files,
+		FilesByPath: gen.FilesByPath,
+	}
+	if err := run(shadow, cfg); err != nil {
+		return nil, err
+	}
+	resp := shadow.Response()
+	if resp.Error != nil {
+		return nil, fmt.Errorf("%s", resp.GetError())
+	}
+	return resp, nil
+}
+
+// unchanged reports whether the file name exists and holds content.
+func unchanged(name, content string) bool {
+	data, err := os.ReadFile(name)
+	return err == nil && bytes.Equal(data, []byte(content))
+}
@@ -0,0 +1,144 @@
+package generator
+
+import (
+	stderrors "errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+// generateFiles calls generate for every file of gen marked for generation,
+// on up to workers files at a time, and returns the errors of all files
+// joined in file order. A workers of 0 uses GOMAXPROCS workers. With a
+// non-nil cache, files whose key is cached replay their stored output, and the
+// output of the others is stored.
+//
+// protogen.Plugin is not safe for concurrent use, so with more than one
+// worker each file generates into a plugin of its own, a copy of the
+// shadowPlugin of gen. Its formatted output is then copied into gen in file
+// order, which keeps the response identical to a sequential run.
+func generateFiles(gen *protogen.Plugin, workers int, cache *fileCache, generate func(*protogen.Plugin, *protogen.File) error) error {
+	var files []*protogen.File
+	for _, f := range gen.Files {
+		if f.Generate {
+			files = append(files, f)
+		}
+	}
+	if workers == 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	errs := make([]error, len(files))
+	if cache == nil && (workers == 1 || len(files) < 2) {
+		for i, f := range files {
+			errs[i] = generate(gen, f)
+		}
+		return stderrors.Join(errs...)
+	}
+
+	base, err := shadowPlugin(gen)
+	if err != nil {
+		return err
+	}
+	responses := make([]*pluginpb.CodeGeneratorResponse, len(files))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range min(workers, len(files)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				responses[i], errs[i] = generateCached(base, files[i], cache, generate)
+			}
+		}()
+	}
+	for i := range files {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	for _, resp := range responses {
+		for _, rf := range resp.GetFile() {
+			g := gen.NewGeneratedFile(rf.GetName(), "")
+			if _, err := g.Write([]byte(rf.GetContent())); err != nil {
+				return err
+			}
+		}
+	}
+	return stderrors.Join(errs...)
+}
+
+// generateCached returns the files generated for f on a copy of base,
+// replayed from cache when it holds them.
+func generateCached(base *protogen.Plugin, f *protogen.File, cache *fileCache, generate func(*protogen.Plugin, *protogen.File) error) (*pluginpb.CodeGeneratorResponse, error) {
+	if cache == nil {
+		return generateShadow(base, f, generate)
+	}
+	key, err := cache.key(base, f)
+	if err != nil {
+		return nil, err
+	}
+	if resp, ok := cache.load(key); ok {
+		return resp, nil
+	}
+	resp, err := generateShadow(base, f, generate)
+	if err != nil {
+		return nil, err
+	}
+	return resp, cache.store(key, resp)
+}
+
+// shadowPlugin returns the plugin the workers of generateFiles copy: the
+// request of gen parsed by protogen, so the copies honour its paths, M
+// mappings and features like gen does, but without its module and
+// annotate_code parameters, which gen applies once when the output of the
+// copies is written into it. Its files are those of gen, which the generate
+// callbacks and the resolved configuration refer to.
+func shadowPlugin(gen *protogen.Plugin) (*protogen.Plugin, error) {
+	var params []string
+	for _, param := range strings.Split(gen.Request.GetParameter(), ",") {
+		if name, _, _ := strings.Cut(param, "="); name != "module" && name != "annotate_code" {
+			params = append(params, param)
+		}
+	}
+	req := &pluginpb.CodeGeneratorRequest{
+		FileToGenerate:        gen.Request.GetFileToGenerate(),
+		Parameter:             proto.String(strings.Join(params, ",")),
+		ProtoFile:             gen.Request.GetProtoFile(),
+		SourceFileDescriptors: gen.Request.GetSourceFileDescriptors(),
+		CompilerVersion:       gen.Request.GetCompilerVersion(),
+	}
+	base, err := protogen.Options{
+		// The plugin parameters were parsed when gen was created.
+		ParamFunc: func(string, string) error { return nil },
+	}.New(req)
+	if err != nil {
+		return nil, err
+	}
+	base.Request = gen.Request
+	base.Files, base.FilesByPath = gen.Files, gen.FilesByPath
+	base.SupportedFeatures = gen.SupportedFeatures
+	base.SupportedEditionsMinimum = gen.SupportedEditionsMinimum
+	base.SupportedEditionsMaximum = gen.SupportedEditionsMaximum
+	return base, nil
+}
+
+// generateShadow calls generate for f on a copy of base and returns the files
+// it generated, already formatted.
+func generateShadow(base *protogen.Plugin, f *protogen.File, generate func(*protogen.Plugin, *protogen.File) error) (*pluginpb.CodeGeneratorResponse, error) {
+	shadow := new(protogen.Plugin)
+	*shadow = *base
+	if err := generate(shadow, f); err != nil {
+		return nil, err
+	}
+	resp := shadow.Response()
+	if resp.Error != nil {
+		return nil, fmt.Errorf("%s", resp.GetError())
+	}
+	return resp, nil
+}
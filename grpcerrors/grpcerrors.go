@@ -0,0 +1,278 @@
+// Package grpcerrors converts generated sphere errors into gRPC statuses. Its
+// server interceptors replace the glue every service otherwise writes by hand:
+// an error whose chain holds a generated error enum value becomes a status
+// with the enum's gRPC code and message, plus an ErrorInfo detail carrying the
+// reason, code and any metadata attached with the metadata package, a
+// RetryInfo detail when the error is retryable, a Help detail when it has a
+// documentation URL, and the typed details attached with the details package.
+package grpcerrors
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	typeddetails "github.com/go-sphere/protoc-gen-sphere-errors/details"
+	"github.com/go-sphere/protoc-gen-sphere-errors/metadata"
+	"github.com/go-sphere/protoc-gen-sphere-errors/retry"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+// sphereError is the method set shared by every generated error enum.
+type sphereError interface {
+	error
+	GetStatus() int32
+	GetCode() int32
+	GetMessage() string
+}
+
+// reasoner is implemented by error enums whose Error() is not their reason,
+// those generated with log messages.
+type reasoner interface {
+	GetReason() string
+}
+
+// reasonOf returns the machine-readable reason of se, that of its value.
+func reasonOf(se sphereError) string {
+	v := valueOf(se)
+	if r, ok := v.(reasoner); ok {
+		return r.GetReason()
+	}
+	return v.Error()
+}
+
+// valueOf returns the generated error enum value in the chain of se: the
+// first error with the sphereError methods that wraps no other error. The
+// constructors wrap the value in the error of their runtime, which has the
+// sphereError methods too, carrying any custom message, but none of the
+// optional methods of the value, such as GetGRPCCode or GetDomain, so those
+// are looked up on the value. It returns se when no error of the chain is
+// one.
+func valueOf(se sphereError) sphereError {
+	if v, ok := findValue(se); ok {
+		return v
+	}
+	return se
+}
+
+// findValue returns the first error of the chain of err, depth first as
+// errors.As walks it, with the sphereError methods that wraps no error.
+func findValue(err error) (sphereError, bool) {
+	for err != nil {
+		switch u := err.(type) {
+		case interface{ Unwrap() error }:
+			err = u.Unwrap()
+		case interface{ Unwrap() []error }:
+			for _, e := range u.Unwrap() {
+				if v, ok := findValue(e); ok {
+					return v, true
+				}
+			}
+			return nil, false
+		default:
+			se, ok := err.(sphereError)
+			return se, ok
+		}
+	}
+	return nil, false
+}
+
+// grpcCoder is implemented by error enums generated with grpc_status=true.
+type grpcCoder interface {
+	GetGRPCCode() codes.Code
+}
+
+// domainer is implemented by error enums generated with a domain parameter.
+type domainer interface {
+	GetDomain() string
+}
+
+// internaler is implemented by error enums generated with visibility.
+type internaler interface {
+	IsInternal() bool
+}
+
+// helpLinker is implemented by error enums generated with documentation URLs.
+type helpLinker interface {
+	HelpLink() string
+}
+
+// Option configures the conversion.
+type Option func(*options)
+
+type options struct {
+	domain   string
+	fallback func(error) *status.Status
+	trailers bool
+}
+
+// WithDomain sets the Domain of the ErrorInfo detail, typically the service
+// name, for errors that do not declare their own through GetDomain. It is
+// empty by default.
+func WithDomain(domain string) Option {
+	return func(o *options) { o.domain = domain }
+}
+
+// WithFallback sets the conversion of errors that are neither generated
+// errors nor gRPC statuses, and of generated errors that are internal. By
+// default they become codes.Internal with the message "internal error", so no
+// internal detail leaks to clients.
+func WithFallback(fallback func(error) *status.Status) Option {
+	return func(o *options) { o.fallback = fallback }
+}
+
+// WithTrailers makes the server interceptors also set the Trailer of the
+// error, so grpc-web clients read the code, reason and message of the HTTP
+// envelope without decoding the binary status details. ToStatus ignores it.
+func WithTrailers() Option {
+	return func(o *options) { o.trailers = true }
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{
+		fallback: func(error) *status.Status {
+			return status.New(codes.Internal, "internal error")
+		},
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// ToStatus converts err into a gRPC status. It returns nil for a nil error.
+func ToStatus(err error, opts ...Option) *status.Status {
+	return newOptions(opts).toStatus(err)
+}
+
+func (o *options) toStatus(err error) *status.Status {
+	if err == nil {
+		return nil
+	}
+	var se sphereError
+	if !errors.As(err, &se) {
+		if s, ok := status.FromError(err); ok {
+			return s
+		}
+		return o.fallback(err)
+	}
+	value := valueOf(se)
+	if i, ok := value.(internaler); ok && i.IsInternal() {
+		return o.fallback(err)
+	}
+	code := CodeFromHTTP(se.GetStatus())
+	if c, ok := value.(grpcCoder); ok {
+		code = c.GetGRPCCode()
+	}
+	msg := se.GetMessage()
+	if msg == "" {
+		msg = reasonOf(se)
+	}
+	md := metadata.From(err)
+	if md == nil {
+		md = map[string]string{}
+	}
+	md["code"] = strconv.Itoa(int(se.GetCode()))
+	domain := o.domain
+	if d, ok := value.(domainer); ok && d.GetDomain() != "" {
+		domain = d.GetDomain()
+	}
+	details := []protoadapt.MessageV1{&errdetails.ErrorInfo{
+		Reason:   reasonOf(se),
+		Domain:   domain,
+		Metadata: md,
+	}}
+	if retry.IsRetryable(err) {
+		details = append(details, &errdetails.RetryInfo{})
+	}
+	if h, ok := value.(helpLinker); ok && h.HelpLink() != "" {
+		details = append(details, &errdetails.Help{
+			Links: []*errdetails.Help_Link{{Description: msg, Url: h.HelpLink()}},
+		})
+	}
+	for _, d := range typeddetails.From(err) {
+		details = append(details, protoadapt.MessageV1Of(d))
+	}
+	s := status.New(code, msg)
+	if ds, dErr := s.WithDetails(details...); dErr == nil {
+		s = ds
+	}
+	return s
+}
+
+// UnaryServerInterceptor returns an interceptor converting handler errors
+// with ToStatus.
+func UnaryServerInterceptor(opts ...Option) grpc.UnaryServerInterceptor {
+	o := newOptions(opts)
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		resp, err := handler(ctx, req)
+		if err != nil {
+			if o.trailers {
+				_ = grpc.SetTrailer(ctx, Trailer(err))
+			}
+			return resp, o.toStatus(err).Err()
+		}
+		return resp, nil
+	}
+}
+
+// StreamServerInterceptor returns an interceptor converting handler errors
+// with ToStatus.
+func StreamServerInterceptor(opts ...Option) grpc.StreamServerInterceptor {
+	o := newOptions(opts)
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := handler(srv, ss); err != nil {
+			if o.trailers {
+				ss.SetTrailer(Trailer(err))
+			}
+			return o.toStatus(err).Err()
+		}
+		return nil
+	}
+}
+
+// CodeFromHTTP maps an HTTP status to the gRPC code used when an error enum
+// was generated without grpc_status=true. It matches the mapping of the
+// generated GetGRPCCode methods.
+func CodeFromHTTP(httpStatus int32) codes.Code {
+	switch httpStatus {
+	case 200:
+		return codes.OK
+	case 400:
+		return codes.InvalidArgument
+	case 401:
+		return codes.Unauthenticated
+	case 403:
+		return codes.PermissionDenied
+	case 404:
+		return codes.NotFound
+	case 408, 504:
+		return codes.DeadlineExceeded
+	case 409:
+		return codes.AlreadyExists
+	case 412:
+		return codes.FailedPrecondition
+	case 416:
+		return codes.OutOfRange
+	case 429:
+		return codes.ResourceExhausted
+	case 499:
+		return codes.Canceled
+	case 501:
+		return codes.Unimplemented
+	case 502, 503:
+		return codes.Unavailable
+	}
+	switch {
+	case httpStatus >= 400 && httpStatus < 500:
+		return codes.FailedPrecondition
+	case httpStatus >= 500 && httpStatus < 600:
+		return codes.Internal
+	default:
+		return codes.Unknown
+	}
+}
@@ -0,0 +1,240 @@
+package grpcerrors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	typeddetails "github.com/go-sphere/protoc-gen-sphere-errors/details"
+	"github.com/go-sphere/protoc-gen-sphere-errors/metadata"
+	"github.com/go-sphere/protoc-gen-sphere-errors/statuserror"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// testError mimics a generated error enum.
+type testError int32
+
+const (
+	testErrorNotFound testError = 2
+	testErrorNoMsg    testError = 3
+)
+
+func (e testError) Error() string {
+	if e == testErrorNotFound {
+		return "user not found"
+	}
+	return "TestError:NO_MSG"
+}
+func (e testError) GetStatus() int32 { return 404 }
+func (e testError) GetCode() int32   { return int32(e) }
+func (e testError) GetMessage() string {
+	if e == testErrorNotFound {
+		return "user does not exist"
+	}
+	return ""
+}
+
+// joinError mimics the generated Join under runtime=stdlib: e joined with
+// errs under a statuserror.Error carrying msg.
+func joinError(e sphereError, msg string, errs ...error) error {
+	return statuserror.New(e.GetStatus(), e.GetCode(), msg, errors.Join(append([]error{e}, errs...)...))
+}
+
+// grpcTestError mimics an enum generated with grpc_status=true.
+type grpcTestError struct{ testError }
+
+func (grpcTestError) GetGRPCCode() codes.Code { return codes.Aborted }
+
+// domainTestError mimics an enum generated with a domain parameter.
+type domainTestError struct{ testError }
+
+func (domainTestError) GetDomain() string { return "users.example.com" }
+
+func TestToStatus(t *testing.T) {
+	err := metadata.WithField(fmt.Errorf("lookup: %w", testErrorNotFound), "user_id", "u1")
+	s := ToStatus(err, WithDomain("users"))
+	if s.Code() != codes.NotFound || s.Message() != "user does not exist" {
+		t.Fatalf("ToStatus = %v %q", s.Code(), s.Message())
+	}
+	details := s.Details()
+	if len(details) != 1 {
+		t.Fatalf("len(Details) = %d, want 1", len(details))
+	}
+	info, ok := details[0].(*errdetails.ErrorInfo)
+	if !ok {
+		t.Fatalf("detail is %T, want *errdetails.ErrorInfo", details[0])
+	}
+	if info.Reason != "user not found" || info.Domain != "users" {
+		t.Errorf("ErrorInfo = %v", info)
+	}
+	if info.Metadata["code"] != "2" || info.Metadata["user_id"] != "u1" {
+		t.Errorf("ErrorInfo.Metadata = %v", info.Metadata)
+	}
+}
+
+func TestToStatus_MessageFallback(t *testing.T) {
+	if s := ToStatus(testErrorNoMsg); s.Message() != "TestError:NO_MSG" {
+		t.Errorf("Message = %q, want the reason", s.Message())
+	}
+}
+
+func TestToStatus_GRPCCode(t *testing.T) {
+	if s := ToStatus(grpcTestError{testErrorNotFound}); s.Code() != codes.Aborted {
+		t.Errorf("Code = %v, want Aborted from GetGRPCCode", s.Code())
+	}
+}
+
+func TestToStatus_Domain(t *testing.T) {
+	s := ToStatus(domainTestError{testErrorNotFound}, WithDomain("fallback"))
+	if info := s.Details()[0].(*errdetails.ErrorInfo); info.Domain != "users.example.com" {
+		t.Errorf("Domain = %q, want the error's own domain", info.Domain)
+	}
+}
+
+// retryTestError mimics an enum generated with retry_helpers=true.
+type retryTestError struct{ testError }
+
+func (retryTestError) IsRetryable() bool { return true }
+
+func TestToStatus_RetryInfo(t *testing.T) {
+	if n := len(ToStatus(testErrorNotFound).Details()); n != 1 {
+		t.Errorf("len(Details) = %d, want only ErrorInfo", n)
+	}
+	details := ToStatus(retryTestError{testErrorNotFound}).Details()
+	if len(details) != 2 {
+		t.Fatalf("len(Details) = %d, want 2", len(details))
+	}
+	if _, ok := details[1].(*errdetails.RetryInfo); !ok {
+		t.Errorf("detail is %T, want *errdetails.RetryInfo", details[1])
+	}
+}
+
+// helpTestError mimics an enum generated with documentation URLs.
+type helpTestError struct{ testError }
+
+func (helpTestError) HelpLink() string { return "https://kb.example.com/errors/2" }
+
+func TestToStatus_Help(t *testing.T) {
+	details := ToStatus(helpTestError{testErrorNotFound}).Details()
+	if len(details) != 2 {
+		t.Fatalf("len(Details) = %d, want 2", len(details))
+	}
+	help, ok := details[1].(*errdetails.Help)
+	if !ok || len(help.GetLinks()) != 1 || help.GetLinks()[0].GetUrl() != "https://kb.example.com/errors/2" {
+		t.Fatalf("detail = %v, want the Help link", details[1])
+	}
+	if d := help.GetLinks()[0].GetDescription(); d != "user does not exist" {
+		t.Errorf("Description = %q, want the message", d)
+	}
+}
+
+// internalTestError mimics an enum generated with visibility, whose values
+// are internal.
+type internalTestError struct{ testError }
+
+func (internalTestError) IsInternal() bool { return true }
+
+func TestToStatus_Internal(t *testing.T) {
+	s := ToStatus(fmt.Errorf("wrap: %w", internalTestError{testErrorNotFound}))
+	if s.Code() != codes.Internal || s.Message() != "internal error" || len(s.Details()) != 0 {
+		t.Errorf("ToStatus(internal) = %v, want the fallback", s.Proto())
+	}
+	if md := Trailer(internalTestError{testErrorNotFound}); md != nil {
+		t.Errorf("Trailer(internal) = %v, want none", md)
+	}
+	joined := joinError(internalTestError{testErrorNotFound}, "db password wrong")
+	if s := ToStatus(joined); s.Code() != codes.Internal || s.Message() != "internal error" || len(s.Details()) != 0 {
+		t.Errorf("ToStatus(joined internal) = %v, want the fallback", s.Proto())
+	}
+	if md := Trailer(joined); md != nil {
+		t.Errorf("Trailer(joined internal) = %v, want none", md)
+	}
+}
+
+// fullTestError mimics an enum generated with grpc_status=true, a domain
+// parameter and documentation URLs.
+type fullTestError struct{ testError }
+
+func (fullTestError) GetGRPCCode() codes.Code { return codes.Aborted }
+func (fullTestError) GetDomain() string       { return "users.example.com" }
+func (fullTestError) HelpLink() string        { return "https://kb.example.com/errors/2" }
+
+func TestToStatus_Joined(t *testing.T) {
+	s := ToStatus(joinError(fullTestError{testErrorNotFound}, "user u1 does not exist", grpcTestError{testErrorNoMsg}))
+	if s.Code() != codes.Aborted || s.Message() != "user u1 does not exist" {
+		t.Fatalf("ToStatus = %v %q, want the value's code and the custom message", s.Code(), s.Message())
+	}
+	details := s.Details()
+	if len(details) != 2 {
+		t.Fatalf("len(Details) = %d, want 2", len(details))
+	}
+	if info := details[0].(*errdetails.ErrorInfo); info.Reason != "user not found" || info.Domain != "users.example.com" {
+		t.Errorf("ErrorInfo = %v, want the value's reason and domain", info)
+	}
+	if help, ok := details[1].(*errdetails.Help); !ok || help.GetLinks()[0].GetUrl() != "https://kb.example.com/errors/2" {
+		t.Errorf("detail = %v, want the value's Help link", details[1])
+	}
+}
+
+func TestToStatus_TypedDetails(t *testing.T) {
+	violation := &errdetails.QuotaFailure{Violations: []*errdetails.QuotaFailure_Violation{{Subject: "user:1"}}}
+	details := ToStatus(typeddetails.Wrap(fmt.Errorf("wrap: %w", testErrorNotFound), violation)).Details()
+	if len(details) != 2 {
+		t.Fatalf("len(Details) = %d, want 2", len(details))
+	}
+	got, ok := details[1].(*errdetails.QuotaFailure)
+	if !ok || got.GetViolations()[0].GetSubject() != "user:1" {
+		t.Errorf("detail = %v, want the QuotaFailure", details[1])
+	}
+}
+
+func TestToStatus_NonSphereErrors(t *testing.T) {
+	if ToStatus(nil) != nil {
+		t.Error("ToStatus(nil) should be nil")
+	}
+	if s := ToStatus(status.Error(codes.Unavailable, "down")); s.Code() != codes.Unavailable {
+		t.Errorf("status errors should pass through, got %v", s.Code())
+	}
+	if s := ToStatus(errors.New("db password wrong")); s.Code() != codes.Internal || s.Message() != "internal error" {
+		t.Errorf("default fallback = %v %q", s.Code(), s.Message())
+	}
+	fallback := WithFallback(func(err error) *status.Status { return status.New(codes.Unknown, err.Error()) })
+	if s := ToStatus(errors.New("boom"), fallback); s.Code() != codes.Unknown || s.Message() != "boom" {
+		t.Errorf("custom fallback = %v %q", s.Code(), s.Message())
+	}
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	intercept := UnaryServerInterceptor()
+	_, err := intercept(context.Background(), nil, nil, func(context.Context, any) (any, error) {
+		return nil, testErrorNotFound
+	})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("status.Code = %v, want NotFound", status.Code(err))
+	}
+	resp, err := intercept(context.Background(), nil, nil, func(context.Context, any) (any, error) {
+		return "ok", nil
+	})
+	if resp != "ok" || err != nil {
+		t.Errorf("intercept = %v, %v", resp, err)
+	}
+}
+
+func TestCodeFromHTTP(t *testing.T) {
+	tests := map[int32]codes.Code{
+		200: codes.OK,
+		404: codes.NotFound,
+		418: codes.FailedPrecondition,
+		503: codes.Unavailable,
+		599: codes.Internal,
+		302: codes.Unknown,
+	}
+	for httpStatus, want := range tests {
+		if got := CodeFromHTTP(httpStatus); got != want {
+			t.Errorf("CodeFromHTTP(%d) = %v, want %v", httpStatus, got, want)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package grpcerrors
+
+import (
+	"errors"
+	"maps"
+	"strconv"
+
+	typeddetails "github.com/go-sphere/protoc-gen-sphere-errors/details"
+	"github.com/go-sphere/protoc-gen-sphere-errors/metadata"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// ToStatusProto converts err into a google.rpc.Status message, as ToStatus
+// does, for errors serialized outside of gRPC, e.g. onto a queue. It returns
+// nil for a nil error.
+func ToStatusProto(err error, opts ...Option) *spb.Status {
+	if err == nil {
+		return nil
+	}
+	return ToStatus(err, opts...).Proto()
+}
+
+// FromStatusProto rebuilds the error s was converted from by ToStatusProto.
+// lookup resolves the code of the ErrorInfo detail to a generated error value,
+// as the generated FromStatusProto functions do for their package. The
+// ErrorInfo metadata is attached again with the metadata package and the
+// typed details with the details package. A status without a known code
+// becomes a plain gRPC status error. It returns nil for a nil or OK status.
+func FromStatusProto(s *spb.Status, lookup func(code int32) (error, bool)) error {
+	if s == nil || codes.Code(s.GetCode()) == codes.OK {
+		return nil
+	}
+	var info *errdetails.ErrorInfo
+	var typed []proto.Message
+	for _, a := range s.GetDetails() {
+		d, err := a.UnmarshalNew()
+		if err != nil {
+			continue
+		}
+		switch d := d.(type) {
+		case *errdetails.ErrorInfo:
+			if info == nil {
+				info = d
+			}
+		case *errdetails.RetryInfo, *errdetails.Help:
+		default:
+			typed = append(typed, d)
+		}
+	}
+	code, convErr := strconv.ParseInt(info.GetMetadata()["code"], 10, 32)
+	if convErr != nil {
+		return status.ErrorProto(s)
+	}
+	err, ok := lookup(int32(code))
+	if !ok {
+		return status.ErrorProto(s)
+	}
+	md := maps.Clone(info.GetMetadata())
+	delete(md, "code")
+	if len(md) > 0 {
+		err = metadata.Wrap(err, md)
+	}
+	if len(typed) > 0 {
+		err = typeddetails.Wrap(err, typed...)
+	}
+	return err
+}
+
+// Resolve returns the generated error err stands for: err itself when its
+// chain holds a generated error, as returned by handlers called in process,
+// or the error FromStatusProto rebuilds with lookup from the gRPC status of
+// err, as returned by gRPC clients. It reports false when neither applies,
+// e.g. for a status without a known code.
+func Resolve(err error, lookup func(code int32) (error, bool)) (error, bool) {
+	var se sphereError
+	if errors.As(err, &se) {
+		return err, true
+	}
+	s, ok := status.FromError(err)
+	if !ok {
+		return nil, false
+	}
+	resolved := FromStatusProto(s.Proto(), lookup)
+	if !errors.As(resolved, &se) {
+		return nil, false
+	}
+	return resolved, true
+}
@@ -0,0 +1,90 @@
+package grpcerrors
+
+import (
+	"errors"
+	"testing"
+
+	typeddetails "github.com/go-sphere/protoc-gen-sphere-errors/details"
+	"github.com/go-sphere/protoc-gen-sphere-errors/metadata"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// lookupTestError resolves the codes of testError.
+func lookupTestError(code int32) (error, bool) {
+	if code == int32(testErrorNotFound) {
+		return testErrorNotFound, true
+	}
+	return nil, false
+}
+
+func TestStatusProto_RoundTrip(t *testing.T) {
+	violation := &errdetails.QuotaFailure{Violations: []*errdetails.QuotaFailure_Violation{{Subject: "user:1"}}}
+	err := typeddetails.Wrap(metadata.WithField(testErrorNotFound, "user_id", "u1"), violation)
+	s := ToStatusProto(err)
+	data, mErr := proto.Marshal(s)
+	if mErr != nil {
+		t.Fatal(mErr)
+	}
+	decoded := &spb.Status{}
+	if uErr := proto.Unmarshal(data, decoded); uErr != nil {
+		t.Fatal(uErr)
+	}
+
+	got := FromStatusProto(decoded, lookupTestError)
+	if !errors.Is(got, testErrorNotFound) {
+		t.Fatalf("FromStatusProto = %v, want testErrorNotFound", got)
+	}
+	if md := metadata.From(got); len(md) != 1 || md["user_id"] != "u1" {
+		t.Errorf("metadata = %v, want only user_id", md)
+	}
+	details := typeddetails.From(got)
+	if len(details) != 1 || !proto.Equal(details[0], violation) {
+		t.Errorf("details = %v, want the QuotaFailure", details)
+	}
+	// Compare the details unpacked: the map of the ErrorInfo marshals in no
+	// fixed order, so the packed bytes differ between conversions.
+	again := status.FromProto(ToStatusProto(got))
+	want := status.FromProto(s)
+	if again.Code() != want.Code() || again.Message() != want.Message() || len(again.Details()) != len(want.Details()) {
+		t.Fatalf("second conversion = %v, want %v", again.Proto(), s)
+	}
+	for i, d := range again.Details() {
+		if !proto.Equal(d.(proto.Message), want.Details()[i].(proto.Message)) {
+			t.Errorf("second conversion detail %d = %v, want %v", i, d, want.Details()[i])
+		}
+	}
+}
+
+func TestFromStatusProto_Unknown(t *testing.T) {
+	if FromStatusProto(nil, lookupTestError) != nil || FromStatusProto(status.New(codes.OK, "").Proto(), lookupTestError) != nil {
+		t.Error("nil and OK statuses should convert to nil")
+	}
+	s := status.New(codes.Unavailable, "down").Proto()
+	if err := FromStatusProto(s, lookupTestError); status.Code(err) != codes.Unavailable {
+		t.Errorf("status without ErrorInfo = %v, want the gRPC status error", err)
+	}
+	s = ToStatusProto(testErrorNoMsg)
+	if err := FromStatusProto(s, lookupTestError); status.Code(err) != codes.NotFound || errors.Is(err, testErrorNoMsg) {
+		t.Errorf("unknown code = %v, want the gRPC status error", err)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	wrapped := metadata.WithField(testErrorNotFound, "user_id", "u1")
+	if got, ok := Resolve(wrapped, lookupTestError); !ok || got != wrapped {
+		t.Errorf("Resolve(in-process error) = %v, %t, want it unchanged", got, ok)
+	}
+	got, ok := Resolve(ToStatus(wrapped).Err(), lookupTestError)
+	if !ok || !errors.Is(got, testErrorNotFound) || metadata.From(got)["user_id"] != "u1" {
+		t.Errorf("Resolve(status error) = %v, %t, want testErrorNotFound with its metadata", got, ok)
+	}
+	for _, err := range []error{nil, errors.New("boom"), status.Error(codes.Unavailable, "down")} {
+		if got, ok := Resolve(err, lookupTestError); ok {
+			t.Errorf("Resolve(%v) = %v, want false", err, got)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package grpcerrors
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	grpcmetadata "google.golang.org/grpc/metadata"
+)
+
+// Keys of the trailer returned by Trailer. They carry the fields of the
+// httperrors JSON envelope, so grpc-web and HTTP clients of one proto see the
+// same error.
+const (
+	CodeTrailer    = "sphere-code"
+	ReasonTrailer  = "sphere-reason"
+	MessageTrailer = "sphere-message"
+)
+
+// Trailer returns the code, reason and message of the generated error in
+// err's chain as gRPC trailer metadata. The reason and message are
+// percent-encoded as grpc-message is, since trailer values must be printable
+// ASCII; DecodeTrailer reverses it. It returns nil when err holds no
+// generated error or an internal one.
+func Trailer(err error) grpcmetadata.MD {
+	var se sphereError
+	if !errors.As(err, &se) {
+		return nil
+	}
+	if i, ok := valueOf(se).(internaler); ok && i.IsInternal() {
+		return nil
+	}
+	msg := se.GetMessage()
+	if msg == "" {
+		msg = reasonOf(se)
+	}
+	return grpcmetadata.Pairs(
+		CodeTrailer, strconv.Itoa(int(se.GetCode())),
+		ReasonTrailer, encodeTrailer(reasonOf(se)),
+		MessageTrailer, encodeTrailer(msg),
+	)
+}
+
+// DecodeTrailer returns the code, reason and message set by Trailer in md. It
+// reports false when md carries no valid code.
+func DecodeTrailer(md grpcmetadata.MD) (code int32, reason, message string, ok bool) {
+	values := md.Get(CodeTrailer)
+	if len(values) == 0 {
+		return 0, "", "", false
+	}
+	c, err := strconv.ParseInt(values[0], 10, 32)
+	if err != nil {
+		return 0, "", "", false
+	}
+	first := func(key string) string {
+		if v := md.Get(key); len(v) > 0 {
+			return decodeTrailer(v[0])
+		}
+		return ""
+	}
+	return int32(c), first(ReasonTrailer), first(MessageTrailer), true
+}
+
+// encodeTrailer percent-encodes the bytes of s outside printable ASCII and
+// the percent sign itself, as gRPC encodes grpc-message.
+func encodeTrailer(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c < ' ' || c > '~' || c == '%' {
+			fmt.Fprintf(&b, "%%%02X", c)
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// decodeTrailer reverses encodeTrailer, keeping malformed escapes as they
+// are.
+func decodeTrailer(s string) string {
+	if !strings.Contains(s, "%") {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '%' && i+2 < len(s) {
+			if v, err := strconv.ParseUint(s[i+1:i+3], 16, 8); err == nil {
+				b.WriteByte(byte(v))
+				i += 2
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
@@ -0,0 +1,70 @@
+package grpcerrors
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"google.golang.org/grpc"
+	grpcmetadata "google.golang.org/grpc/metadata"
+)
+
+// trailerStream records the trailer set through grpc.SetTrailer.
+type trailerStream struct {
+	trailer grpcmetadata.MD
+}
+
+func (s *trailerStream) Method() string                      { return "/test.Service/Method" }
+func (s *trailerStream) SetHeader(grpcmetadata.MD) error     { return nil }
+func (s *trailerStream) SendHeader(grpcmetadata.MD) error    { return nil }
+func (s *trailerStream) SetTrailer(md grpcmetadata.MD) error { s.trailer = md; return nil }
+
+func TestTrailer(t *testing.T) {
+	md := Trailer(testErrorNotFound)
+	code, reason, message, ok := DecodeTrailer(md)
+	if !ok || code != 2 || reason != "user not found" || message != "user does not exist" {
+		t.Errorf("DecodeTrailer = %d, %q, %q, %t", code, reason, message, ok)
+	}
+	if Trailer(errors.New("boom")) != nil {
+		t.Error("Trailer of a plain error should be nil")
+	}
+	if _, _, _, ok := DecodeTrailer(grpcmetadata.MD{}); ok {
+		t.Error("DecodeTrailer of an empty trailer should fail")
+	}
+}
+
+func TestTrailer_Encoding(t *testing.T) {
+	for _, s := range []string{"plain", "100% sure", "утилизация", "line\nbreak", "bad %zz"} {
+		encoded := encodeTrailer(s)
+		for i := 0; i < len(encoded); i++ {
+			if encoded[i] < ' ' || encoded[i] > '~' {
+				t.Errorf("encodeTrailer(%q) = %q, not printable ASCII", s, encoded)
+				break
+			}
+		}
+		if got := decodeTrailer(encoded); got != s {
+			t.Errorf("decodeTrailer(encodeTrailer(%q)) = %q", s, got)
+		}
+	}
+}
+
+func TestUnaryServerInterceptor_Trailers(t *testing.T) {
+	stream := &trailerStream{}
+	ctx := grpc.NewContextWithServerTransportStream(context.Background(), stream)
+	intercept := UnaryServerInterceptor(WithTrailers())
+	_, _ = intercept(ctx, nil, nil, func(context.Context, any) (any, error) {
+		return nil, testErrorNotFound
+	})
+	if code, _, _, ok := DecodeTrailer(stream.trailer); !ok || code != 2 {
+		t.Errorf("trailer = %v, want code 2", stream.trailer)
+	}
+
+	stream = &trailerStream{}
+	ctx = grpc.NewContextWithServerTransportStream(context.Background(), stream)
+	_, _ = UnaryServerInterceptor()(ctx, nil, nil, func(context.Context, any) (any, error) {
+		return nil, testErrorNotFound
+	})
+	if stream.trailer != nil {
+		t.Errorf("trailer = %v, want none without WithTrailers", stream.trailer)
+	}
+}
@@ -0,0 +1,176 @@
+package httperrors
+
+import (
+	"encoding/binary"
+	"sort"
+)
+
+// MarshalCBOR implements cbor.Marshaler, encoding b as a CBOR (RFC 8949) map
+// with the keys and omissions of its JSON form, for clients that don't speak
+// JSON. The details are sorted by key, so equal bodies encode identically.
+func (b Body) MarshalCBOR() ([]byte, error) {
+	return appendCBORBody(nil, b), nil
+}
+
+// MarshalMsgpack implements msgpack.Marshaler, encoding b as a MessagePack
+// map with the keys and omissions of its JSON form. The details are sorted
+// by key, so equal bodies encode identically.
+func (b Body) MarshalMsgpack() ([]byte, error) {
+	return appendMsgpackBody(nil, b), nil
+}
+
+// sortedKeys returns the keys of m in increasing order.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// bodyFields returns the number of fields of the JSON form of b.
+func bodyFields(b Body) int {
+	n := 2 // code and message
+	if b.Reason != "" {
+		n++
+	}
+	if len(b.Details) > 0 {
+		n++
+	}
+	if len(b.Errors) > 0 {
+		n++
+	}
+	return n
+}
+
+// CBOR major types.
+const (
+	cborUint   = 0
+	cborNegint = 1
+	cborText   = 3
+	cborArray  = 4
+	cborMap    = 5
+)
+
+// appendCBORHead appends the head of a CBOR data item of type major with the
+// argument n, in its shortest form.
+func appendCBORHead(dst []byte, major byte, n uint64) []byte {
+	switch {
+	case n < 24:
+		return append(dst, major<<5|byte(n))
+	case n <= 0xff:
+		return append(dst, major<<5|24, byte(n))
+	case n <= 0xffff:
+		return binary.BigEndian.AppendUint16(append(dst, major<<5|25), uint16(n))
+	case n <= 0xffffffff:
+		return binary.BigEndian.AppendUint32(append(dst, major<<5|26), uint32(n))
+	default:
+		return binary.BigEndian.AppendUint64(append(dst, major<<5|27), n)
+	}
+}
+
+func appendCBORText(dst []byte, s string) []byte {
+	return append(appendCBORHead(dst, cborText, uint64(len(s))), s...)
+}
+
+func appendCBORInt(dst []byte, v int32) []byte {
+	if v < 0 {
+		return appendCBORHead(dst, cborNegint, uint64(-1-int64(v)))
+	}
+	return appendCBORHead(dst, cborUint, uint64(v))
+}
+
+func appendCBORBody(dst []byte, b Body) []byte {
+	dst = appendCBORHead(dst, cborMap, uint64(bodyFields(b)))
+	dst = appendCBORInt(appendCBORText(dst, "code"), b.Code)
+	if b.Reason != "" {
+		dst = appendCBORText(appendCBORText(dst, "reason"), b.Reason)
+	}
+	dst = appendCBORText(appendCBORText(dst, "message"), b.Message)
+	if len(b.Details) > 0 {
+		dst = appendCBORHead(appendCBORText(dst, "details"), cborMap, uint64(len(b.Details)))
+		for _, k := range sortedKeys(b.Details) {
+			dst = appendCBORText(appendCBORText(dst, k), b.Details[k])
+		}
+	}
+	if len(b.Errors) > 0 {
+		dst = appendCBORHead(appendCBORText(dst, "errors"), cborArray, uint64(len(b.Errors)))
+		for _, member := range b.Errors {
+			dst = appendCBORBody(dst, member)
+		}
+	}
+	return dst
+}
+
+// appendMsgpackHead appends the header of a MessagePack map, array or string
+// of n entries or bytes, in its shortest format: fix is the tag of its fix
+// format, holding up to fixMax, and tag16 the tag of its 16-bit format, the
+// 32-bit one following it. str8 is the tag of the 8-bit format of strings, 0
+// for maps and arrays, which have none.
+func appendMsgpackHead(dst []byte, fix byte, fixMax int, str8, tag16 byte, n int) []byte {
+	switch {
+	case n <= fixMax:
+		return append(dst, fix|byte(n))
+	case str8 != 0 && n <= 0xff:
+		return append(dst, str8, byte(n))
+	case n <= 0xffff:
+		return binary.BigEndian.AppendUint16(append(dst, tag16), uint16(n))
+	default:
+		return binary.BigEndian.AppendUint32(append(dst, tag16+1), uint32(n))
+	}
+}
+
+func appendMsgpackMap(dst []byte, n int) []byte {
+	return appendMsgpackHead(dst, 0x80, 15, 0, 0xde, n)
+}
+
+func appendMsgpackArray(dst []byte, n int) []byte {
+	return appendMsgpackHead(dst, 0x90, 15, 0, 0xdc, n)
+}
+
+func appendMsgpackString(dst []byte, s string) []byte {
+	return append(appendMsgpackHead(dst, 0xa0, 31, 0xd9, 0xda, len(s)), s...)
+}
+
+// appendMsgpackInt appends v in its shortest MessagePack integer format.
+func appendMsgpackInt(dst []byte, v int32) []byte {
+	switch {
+	case v >= -32 && v <= 127:
+		return append(dst, byte(v))
+	case v > 0 && v <= 0xff:
+		return append(dst, 0xcc, byte(v))
+	case v > 0 && v <= 0xffff:
+		return binary.BigEndian.AppendUint16(append(dst, 0xcd), uint16(v))
+	case v > 0:
+		return binary.BigEndian.AppendUint32(append(dst, 0xce), uint32(v))
+	case v >= -128:
+		return append(dst, 0xd0, byte(v))
+	case v >= -32768:
+		return binary.BigEndian.AppendUint16(append(dst, 0xd1), uint16(v))
+	default:
+		return binary.BigEndian.AppendUint32(append(dst, 0xd2), uint32(v))
+	}
+}
+
+func appendMsgpackBody(dst []byte, b Body) []byte {
+	dst = appendMsgpackMap(dst, bodyFields(b))
+	dst = appendMsgpackInt(appendMsgpackString(dst, "code"), b.Code)
+	if b.Reason != "" {
+		dst = appendMsgpackString(appendMsgpackString(dst, "reason"), b.Reason)
+	}
+	dst = appendMsgpackString(appendMsgpackString(dst, "message"), b.Message)
+	if len(b.Details) > 0 {
+		dst = appendMsgpackMap(appendMsgpackString(dst, "details"), len(b.Details))
+		for _, k := range sortedKeys(b.Details) {
+			dst = appendMsgpackString(appendMsgpackString(dst, k), b.Details[k])
+		}
+	}
+	if len(b.Errors) > 0 {
+		dst = appendMsgpackArray(appendMsgpackString(dst, "errors"), len(b.Errors))
+		for _, member := range b.Errors {
+			dst = appendMsgpackBody(dst, member)
+		}
+	}
+	return dst
+}
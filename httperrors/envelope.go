@@ -0,0 +1,232 @@
+package httperrors
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"google.golang.org/grpc/codes"
+)
+
+// Envelope is the JSON shape of the error responses written by an Encoder,
+// so services fronted by an API gateway answer in its idiomatic format.
+type Envelope string
+
+// Envelopes supported by Encoder. The zero value is EnvelopeSphere.
+const (
+	// EnvelopeSphere writes Body, the envelope of Encode.
+	EnvelopeSphere Envelope = "sphere"
+	// EnvelopeGoogle writes GoogleBody, the error envelope of Google APIs:
+	//
+	//	{"error": {"code": 404, "message": "user does not exist", "status": "NOT_FOUND",
+	//	 "details": [{"@type": "type.googleapis.com/google.rpc.ErrorInfo", ...}]}}
+	EnvelopeGoogle Envelope = "google"
+	// EnvelopeAWS writes AWSBody, the error envelope of AWS JSON protocols,
+	// with the type also in the X-Amzn-ErrorType header:
+	//
+	//	{"__type": "UserNotFoundException", "message": "user does not exist"}
+	EnvelopeAWS Envelope = "aws"
+)
+
+// GoogleBody is the error envelope of Google APIs, written with
+// EnvelopeGoogle.
+type GoogleBody struct {
+	Error GoogleStatus `json:"error"`
+}
+
+// GoogleStatus is the google.rpc.Status of a GoogleBody.
+type GoogleStatus struct {
+	// Code is the HTTP status.
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	// Status is the canonical gRPC code name, e.g. "NOT_FOUND".
+	Status  string            `json:"status"`
+	Details []GoogleErrorInfo `json:"details,omitempty"`
+}
+
+// GoogleErrorInfo is the google.rpc.ErrorInfo detail of a GoogleStatus,
+// carrying the reason, the domain and the metadata of the error with its code
+// under "code", as grpcerrors attaches it.
+type GoogleErrorInfo struct {
+	Type     string            `json:"@type"`
+	Reason   string            `json:"reason"`
+	Domain   string            `json:"domain,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// googleErrorInfoType is the type URL of GoogleErrorInfo.
+const googleErrorInfoType = "type.googleapis.com/google.rpc.ErrorInfo"
+
+// AWSBody is the error envelope of AWS JSON protocols, written with
+// EnvelopeAWS.
+type AWSBody struct {
+	Type    string `json:"__type"`
+	Message string `json:"message"`
+}
+
+// awsInternalType is the AWSBody type of errors rendered as internal errors,
+// the common error of AWS services.
+const awsInternalType = "InternalFailure"
+
+// grpcCoder is implemented by error enums generated with grpc_status=true.
+type grpcCoder interface {
+	GetGRPCCode() codes.Code
+}
+
+// domainer is implemented by error enums generated with a domain parameter.
+type domainer interface {
+	GetDomain() string
+}
+
+// awsTyper is implemented by error enums generated with http_envelope=aws.
+type awsTyper interface {
+	AWSErrorType() string
+}
+
+// googleStatusNames are the canonical names of the gRPC codes.
+var googleStatusNames = [...]string{
+	codes.OK:                 "OK",
+	codes.Canceled:           "CANCELLED",
+	codes.Unknown:            "UNKNOWN",
+	codes.InvalidArgument:    "INVALID_ARGUMENT",
+	codes.DeadlineExceeded:   "DEADLINE_EXCEEDED",
+	codes.NotFound:           "NOT_FOUND",
+	codes.AlreadyExists:      "ALREADY_EXISTS",
+	codes.PermissionDenied:   "PERMISSION_DENIED",
+	codes.ResourceExhausted:  "RESOURCE_EXHAUSTED",
+	codes.FailedPrecondition: "FAILED_PRECONDITION",
+	codes.Aborted:            "ABORTED",
+	codes.OutOfRange:         "OUT_OF_RANGE",
+	codes.Unimplemented:      "UNIMPLEMENTED",
+	codes.Internal:           "INTERNAL",
+	codes.Unavailable:        "UNAVAILABLE",
+	codes.DataLoss:           "DATA_LOSS",
+	codes.Unauthenticated:    "UNAUTHENTICATED",
+}
+
+// codeFromHTTP maps an HTTP status to a gRPC code as grpcerrors.CodeFromHTTP
+// does, for errors without a GetGRPCCode method.
+func codeFromHTTP(status int) codes.Code {
+	switch status {
+	case 200:
+		return codes.OK
+	case 400:
+		return codes.InvalidArgument
+	case 401:
+		return codes.Unauthenticated
+	case 403:
+		return codes.PermissionDenied
+	case 404:
+		return codes.NotFound
+	case 408, 504:
+		return codes.DeadlineExceeded
+	case 409:
+		return codes.AlreadyExists
+	case 412:
+		return codes.FailedPrecondition
+	case 416:
+		return codes.OutOfRange
+	case 429:
+		return codes.ResourceExhausted
+	case 499:
+		return codes.Canceled
+	case 501:
+		return codes.Unimplemented
+	case 502, 503:
+		return codes.Unavailable
+	}
+	switch {
+	case status >= 400 && status < 500:
+		return codes.FailedPrecondition
+	case status >= 500 && status < 600:
+		return codes.Internal
+	default:
+		return codes.Unknown
+	}
+}
+
+// googleStatusName returns the canonical name of code, "UNKNOWN" for codes
+// outside the canonical ones.
+func googleStatusName(code codes.Code) string {
+	if int(code) < len(googleStatusNames) {
+		return googleStatusNames[code]
+	}
+	return "UNKNOWN"
+}
+
+// googleBody returns the GoogleBody of err, answered with status and body as
+// FromError renders it.
+func googleBody(status int, body Body, err error) GoogleBody {
+	se, ok := publicError(err)
+	if !ok {
+		return GoogleBody{Error: GoogleStatus{Code: status, Message: body.Message, Status: googleStatusName(codes.Internal)}}
+	}
+	value := valueOf(se)
+	code := codeFromHTTP(status)
+	if c, ok := value.(grpcCoder); ok {
+		code = c.GetGRPCCode()
+	}
+	info := GoogleErrorInfo{Type: googleErrorInfoType, Reason: body.Reason, Metadata: map[string]string{}}
+	if d, ok := value.(domainer); ok {
+		info.Domain = d.GetDomain()
+	}
+	for k, v := range body.Details {
+		info.Metadata[k] = v
+	}
+	info.Metadata["code"] = strconv.Itoa(int(body.Code))
+	return GoogleBody{Error: GoogleStatus{
+		Code:    status,
+		Message: body.Message,
+		Status:  googleStatusName(code),
+		Details: []GoogleErrorInfo{info},
+	}}
+}
+
+// awsBody returns the AWSBody of err, answered with body as FromError
+// renders it. The type is the AWSErrorType of the error or else its reason
+// in CamelCase followed by "Exception".
+func awsBody(body Body, err error) AWSBody {
+	se, ok := publicError(err)
+	if !ok {
+		return AWSBody{Type: awsInternalType, Message: body.Message}
+	}
+	if t, ok := valueOf(se).(awsTyper); ok && t.AWSErrorType() != "" {
+		return AWSBody{Type: t.AWSErrorType(), Message: body.Message}
+	}
+	return AWSBody{Type: awsTypeFromReason(body.Reason), Message: body.Message}
+}
+
+// awsTypeFromReason returns reason as an AWS error type: its words in
+// CamelCase followed by "Exception", e.g. UserNotFoundException for "user not
+// found". A reason without letters or digits gives InternalFailure.
+func awsTypeFromReason(reason string) string {
+	var b strings.Builder
+	for _, word := range strings.FieldsFunc(reason, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		rs := []rune(word)
+		b.WriteRune(unicode.ToUpper(rs[0]))
+		b.WriteString(string(rs[1:]))
+	}
+	if b.Len() == 0 {
+		return awsInternalType
+	}
+	return b.String() + "Exception"
+}
+
+// render returns the response body of err in the envelope of e, answered
+// with status and body as FromError renders it, and sets the headers of the
+// envelope on w.
+func (e Envelope) render(w http.ResponseWriter, status int, body Body, err error) any {
+	switch e {
+	case EnvelopeGoogle:
+		return googleBody(status, body, err)
+	case EnvelopeAWS:
+		b := awsBody(body, err)
+		w.Header().Set("X-Amzn-ErrorType", b.Type)
+		return b
+	default:
+		return body
+	}
+}
@@ -0,0 +1,70 @@
+package httperrors
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/go-sphere/protoc-gen-sphere-errors/metadata"
+)
+
+// headerer is implemented by error enums generated with http_header.
+type headerer interface {
+	HTTPHeaders() map[string]string
+}
+
+// SetHeaders sets on w the response headers suggested by the first generated
+// error in the chain of err: the Cache-Control of errors generated with
+// cache_ttl and the headers declared with http_header, whose {key}
+// placeholders are filled from the metadata of err. A header naming a key
+// missing from the metadata is left unset rather than sent half-filled.
+// Internal errors set no header. Encode and Encoder call it; handlers writing
+// their own error responses, such as with the problem package, may too.
+func SetHeaders(w http.ResponseWriter, err error) {
+	setCacheControl(w, err)
+	se, ok := publicError(err)
+	if !ok {
+		return
+	}
+	h, ok := valueOf(se).(headerer)
+	if !ok {
+		return
+	}
+	headers := h.HTTPHeaders()
+	if len(headers) == 0 {
+		return
+	}
+	md := metadata.From(err)
+	for name, value := range headers {
+		if v, ok := expandHeader(value, md); ok {
+			w.Header().Set(name, v)
+		}
+	}
+}
+
+// expandHeader replaces the {key} placeholders of value with the entries of
+// md, reporting false when md lacks one. Unterminated braces are kept as is.
+func expandHeader(value string, md map[string]string) (string, bool) {
+	if !strings.Contains(value, "{") {
+		return value, true
+	}
+	var b strings.Builder
+	for {
+		start := strings.IndexByte(value, '{')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(value[start:], '}')
+		if end < 0 {
+			break
+		}
+		v, ok := md[value[start+1:start+end]]
+		if !ok {
+			return "", false
+		}
+		b.WriteString(value[:start])
+		b.WriteString(v)
+		value = value[start+end+1:]
+	}
+	b.WriteString(value)
+	return b.String(), true
+}
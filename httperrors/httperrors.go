@@ -0,0 +1,359 @@
+// Package httperrors renders generated sphere errors as JSON HTTP responses,
+// giving services a single error rendering path instead of per-handler
+// handling. The body matches the Error schema of the openapi_out output:
+//
+//	{"code": 40401, "reason": "user not found", "message": "user does not exist"}
+//
+// plus a "details" object holding any metadata attached with the metadata
+// package.
+package httperrors
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-sphere/protoc-gen-sphere-errors/locale"
+	"github.com/go-sphere/protoc-gen-sphere-errors/metadata"
+)
+
+// sphereError is the method set shared by every generated error enum.
+type sphereError interface {
+	error
+	GetStatus() int32
+	GetCode() int32
+	GetMessage() string
+}
+
+// reasoner is implemented by error enums whose Error() is not their reason,
+// those generated with log messages.
+type reasoner interface {
+	GetReason() string
+}
+
+// reasonOf returns the machine-readable reason of se, that of its value.
+func reasonOf(se sphereError) string {
+	v := valueOf(se)
+	if r, ok := v.(reasoner); ok {
+		return r.GetReason()
+	}
+	return v.Error()
+}
+
+// valueOf returns the generated error enum value in the chain of se: the
+// first error with the sphereError methods that wraps no other error. The
+// constructors wrap the value in the error of their runtime, such as a
+// *statuserror.Error, which has the sphereError methods too, carrying any
+// custom message, but none of the optional methods of the value, so those
+// are looked up on the value. It returns se when no error of the chain is
+// one.
+func valueOf(se sphereError) sphereError {
+	if v, ok := findValue(se); ok {
+		return v
+	}
+	return se
+}
+
+// findValue returns the first error of the chain of err, depth first as
+// errors.As walks it, with the sphereError methods that wraps no error.
+func findValue(err error) (sphereError, bool) {
+	for err != nil {
+		switch u := err.(type) {
+		case interface{ Unwrap() error }:
+			err = u.Unwrap()
+		case interface{ Unwrap() []error }:
+			for _, e := range u.Unwrap() {
+				if v, ok := findValue(e); ok {
+					return v, true
+				}
+			}
+			return nil, false
+		default:
+			se, ok := err.(sphereError)
+			return se, ok
+		}
+	}
+	return nil, false
+}
+
+// internaler is implemented by error enums generated with visibility.
+type internaler interface {
+	IsInternal() bool
+}
+
+// publicError returns the first generated error in the chain of err, and
+// false when there is none or it is internal, so it is rendered as a generic
+// internal error.
+func publicError(err error) (sphereError, bool) {
+	var se sphereError
+	if !errors.As(err, &se) {
+		return nil, false
+	}
+	if i, ok := valueOf(se).(internaler); ok && i.IsInternal() {
+		return nil, false
+	}
+	return se, true
+}
+
+// cacheController is implemented by error enums generated with cache_ttl.
+type cacheController interface {
+	CacheControl() string
+}
+
+// setCacheControl sets the Cache-Control header of w to the one suggested
+// by the value of the first generated error in the chain of err, when it
+// suggests one.
+func setCacheControl(w http.ResponseWriter, err error) {
+	se, ok := publicError(err)
+	if !ok {
+		return
+	}
+	if c, ok := valueOf(se).(cacheController); ok {
+		if v := c.CacheControl(); v != "" {
+			w.Header().Set("Cache-Control", v)
+		}
+	}
+}
+
+// Body is the JSON error envelope written by Encode.
+type Body struct {
+	Code    int32             `json:"code"`
+	Reason  string            `json:"reason,omitempty"`
+	Message string            `json:"message"`
+	Details map[string]string `json:"details,omitempty"`
+	// Errors is the body of every member of an aggregate error, such as one
+	// returned by a generated JoinErrors, in order. Only EnvelopeSphere
+	// writes it.
+	Errors []Body `json:"errors,omitempty"`
+}
+
+// internalError is the body of errors whose chain holds no generated error
+// or an internal one, so no internal detail leaks to clients.
+var internalError = Body{Message: "internal error"}
+
+// Handles reports whether the chain of err holds a generated error enum value,
+// which FromError renders with its own status rather than as a 500. Framework
+// adapters leave other errors to the framework's own handling.
+func Handles(err error) bool {
+	var se sphereError
+	return errors.As(err, &se)
+}
+
+// FromError returns the HTTP status and body for err. Errors whose chain holds
+// a generated error enum value use its status, code, reason and message;
+// any other error, and errors generated as internal with visibility, are
+// reported as a 500 internal error. An aggregate error, such as one returned
+// by a generated JoinErrors, is reported with the body of each member under
+// Errors.
+func FromError(err error) (int, Body) {
+	if members, ok := membersOf(err); ok {
+		return aggregateBody(members, FromError)
+	}
+	se, ok := publicError(err)
+	if !ok {
+		return http.StatusInternalServerError, internalError
+	}
+	msg := se.GetMessage()
+	if msg == "" {
+		msg = reasonOf(se)
+	}
+	return int(se.GetStatus()), Body{
+		Code:    se.GetCode(),
+		Reason:  reasonOf(se),
+		Message: msg,
+		Details: metadata.From(err),
+	}
+}
+
+// aggregate is implemented by aggregate errors, such as the
+// *multierror.Error returned by a generated JoinErrors.
+type aggregate interface {
+	Errors() []error
+}
+
+// membersOf returns the members of the first aggregate error in the chain of
+// err, and false when there is none, when a generated error comes first, so
+// a generated error caused by an aggregate keeps its own status and body, or
+// when the aggregate has no members.
+func membersOf(err error) ([]error, bool) {
+	for err != nil {
+		if a, ok := err.(aggregate); ok {
+			members := a.Errors()
+			return members, len(members) > 0
+		}
+		if _, ok := err.(sphereError); ok {
+			return nil, false
+		}
+		switch u := err.(type) {
+		case interface{ Unwrap() error }:
+			err = u.Unwrap()
+		case interface{ Unwrap() []error }:
+			for _, e := range u.Unwrap() {
+				if members, ok := membersOf(e); ok {
+					return members, true
+				}
+			}
+			return nil, false
+		default:
+			return nil, false
+		}
+	}
+	return nil, false
+}
+
+// aggregateBody returns the HTTP status and body of an aggregate of members,
+// each rendered by one. The body lists the member bodies under Errors, with
+// the code and reason they share if any. The status is the one the members
+// share, 400 when they are all client errors, and 500 otherwise. Without
+// members it is the internal error.
+func aggregateBody(members []error, one func(error) (int, Body)) (int, Body) {
+	if len(members) == 0 {
+		return http.StatusInternalServerError, internalError
+	}
+	body := Body{Message: fmt.Sprintf("%d errors", len(members)), Errors: make([]Body, len(members))}
+	status := 0
+	for i, member := range members {
+		s, b := one(member)
+		body.Errors[i] = b
+		switch {
+		case i == 0:
+			status, body.Code, body.Reason = s, b.Code, b.Reason
+		case s != status && s >= 400 && s < 500 && status >= 400 && status < 500:
+			status = http.StatusBadRequest
+		case s != status:
+			status = http.StatusInternalServerError
+		}
+		if b.Code != body.Code || b.Reason != body.Reason {
+			body.Code, body.Reason = 0, ""
+		}
+	}
+	return status, body
+}
+
+// Encode writes err to w as a JSON error response, with the headers set by
+// SetHeaders. It does nothing for a nil error.
+func Encode(w http.ResponseWriter, err error) {
+	if err == nil {
+		return
+	}
+	status, body := FromError(err)
+	SetHeaders(w, err)
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// HandlerFunc is an HTTP handler returning an error.
+type HandlerFunc func(w http.ResponseWriter, r *http.Request) error
+
+// ServeHTTP calls f and renders its error with Encode.
+func (f HandlerFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	Encode(w, f(w, r))
+}
+
+// Encoder renders errors as Encode does, for dependency injection: containers
+// such as wire and fx provide it to handlers. Its zero value is ready to use.
+type Encoder struct {
+	// Localize, when set, returns the message of code in the first of
+	// langs, most preferred first, it has one for, or "" to keep the
+	// message of the error, such as the ResolveMessage function generated
+	// with locale_fallback. EncodeRequest and FromRequest call it with the
+	// Accept-Language of the request.
+	Localize func(code int32, langs []string) string
+	// Envelope is the shape of the responses written by Encode and
+	// EncodeRequest, Body when empty. FromError and FromRequest always
+	// return a Body.
+	Envelope Envelope
+	// ClientCodes, when set, replaces the code of errors generated with
+	// client_code by their ClientCode, so clients only see the small set of
+	// codes of their equivalence classes.
+	ClientCodes bool
+	// LogCode, when set, is called with every error whose code ClientCodes
+	// replaces, its precise internal code and the client code, to log what
+	// clients no longer see.
+	LogCode func(err error, code, clientCode int32)
+}
+
+// clientCoder is implemented by error enums generated with client_code.
+type clientCoder interface {
+	ClientCode() int32
+}
+
+// clientBody returns body, the body of err, with the client code of err in
+// place of its code when ClientCodes is set.
+func (e Encoder) clientBody(body Body, err error) Body {
+	if !e.ClientCodes {
+		return body
+	}
+	se, ok := publicError(err)
+	if !ok {
+		return body
+	}
+	c, ok := valueOf(se).(clientCoder)
+	if !ok {
+		return body
+	}
+	if code := c.ClientCode(); code != body.Code {
+		if e.LogCode != nil {
+			e.LogCode(err, body.Code, code)
+		}
+		body.Code = code
+	}
+	return body
+}
+
+// Encode writes err to w as Encode does, in the shape of Envelope.
+func (e Encoder) Encode(w http.ResponseWriter, err error) {
+	if err == nil {
+		return
+	}
+	status, body := e.FromError(err)
+	e.write(w, status, body, err)
+}
+
+// FromError returns the HTTP status and body for err as FromError does, with
+// the client code of err with ClientCodes.
+func (e Encoder) FromError(err error) (int, Body) {
+	if members, ok := membersOf(err); ok {
+		return aggregateBody(members, e.FromError)
+	}
+	status, body := FromError(err)
+	return status, e.clientBody(body, err)
+}
+
+// FromRequest returns the HTTP status and body for err as FromError does,
+// with the message localized for the Accept-Language of r by Localize. The
+// message is looked up by the internal code, before ClientCodes replaces it.
+func (e Encoder) FromRequest(r *http.Request, err error) (int, Body) {
+	if members, ok := membersOf(err); ok {
+		return aggregateBody(members, func(err error) (int, Body) { return e.FromRequest(r, err) })
+	}
+	status, body := FromError(err)
+	if e.Localize != nil && body.Code != 0 {
+		if msg := e.Localize(body.Code, locale.ParseAcceptLanguage(r.Header.Get("Accept-Language"))); msg != "" {
+			body.Message = msg
+		}
+	}
+	return status, e.clientBody(body, err)
+}
+
+// EncodeRequest writes err to w as Encode does, with the message localized
+// for the Accept-Language of r by Localize. It does nothing for a nil error.
+func (e Encoder) EncodeRequest(w http.ResponseWriter, r *http.Request, err error) {
+	if err == nil {
+		return
+	}
+	status, body := e.FromRequest(r, err)
+	e.write(w, status, body, err)
+}
+
+// write writes the response for err, answered with status and body, to w in
+// the shape of Envelope.
+func (e Encoder) write(w http.ResponseWriter, status int, body Body, err error) {
+	SetHeaders(w, err)
+	out := e.Envelope.render(w, status, body, err)
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(out)
+}
@@ -0,0 +1,608 @@
+package httperrors
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-sphere/protoc-gen-sphere-errors/grpcerrors"
+	"github.com/go-sphere/protoc-gen-sphere-errors/metadata"
+	"github.com/go-sphere/protoc-gen-sphere-errors/multierror"
+	"github.com/go-sphere/protoc-gen-sphere-errors/statuserror"
+	"google.golang.org/grpc/codes"
+)
+
+// testError mimics a generated error enum.
+type testError int32
+
+const (
+	testErrorNotFound testError = 40401
+	testErrorNoMsg    testError = 40402
+)
+
+func (e testError) Error() string {
+	if e == testErrorNotFound {
+		return "user not found"
+	}
+	return "TestError:NO_MSG"
+}
+func (e testError) GetStatus() int32 { return 404 }
+func (e testError) GetCode() int32   { return int32(e) }
+func (e testError) GetMessage() string {
+	if e == testErrorNotFound {
+		return "user does not exist"
+	}
+	return ""
+}
+
+// logError mimics a generated error enum declaring a log message.
+type logError int32
+
+func (logError) Error() string      { return "shard 3 lost the user row" }
+func (logError) GetReason() string  { return "user not found" }
+func (logError) GetStatus() int32   { return 404 }
+func (e logError) GetCode() int32   { return int32(e) }
+func (logError) GetMessage() string { return "" }
+
+// joinError mimics the generated Join under runtime=stdlib: e joined with
+// errs, wrapped in a *statuserror.Error carrying its status, code and the
+// message msg.
+func joinError(e sphereError, msg string, errs ...error) error {
+	return statuserror.New(e.GetStatus(), e.GetCode(), msg, errors.Join(append([]error{e}, errs...)...))
+}
+
+// minimalError mimics the sphereStatusError the generated Join returns under
+// minimal_runtime=true.
+type minimalError struct {
+	status  int32
+	code    int32
+	message string
+	err     error
+}
+
+func (e *minimalError) Error() string      { return e.message }
+func (e *minimalError) Unwrap() error      { return e.err }
+func (e *minimalError) GetStatus() int32   { return e.status }
+func (e *minimalError) GetCode() int32     { return e.code }
+func (e *minimalError) GetMessage() string { return e.message }
+
+func TestFromError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		want       Body
+	}{
+		{
+			name:       "generated error",
+			err:        testErrorNotFound,
+			wantStatus: 404,
+			want:       Body{Code: 40401, Reason: "user not found", Message: "user does not exist"},
+		},
+		{
+			name:       "message falls back to reason",
+			err:        testErrorNoMsg,
+			wantStatus: 404,
+			want:       Body{Code: 40402, Reason: "TestError:NO_MSG", Message: "TestError:NO_MSG"},
+		},
+		{
+			name:       "log message is not exposed",
+			err:        logError(40403),
+			wantStatus: 404,
+			want:       Body{Code: 40403, Reason: "user not found", Message: "user not found"},
+		},
+		{
+			name:       "wrapped generated error",
+			err:        fmt.Errorf("lookup: %w", testErrorNotFound),
+			wantStatus: 404,
+			want:       Body{Code: 40401, Reason: "user not found", Message: "user does not exist"},
+		},
+		{
+			name:       "joined generated error",
+			err:        joinError(testErrorNotFound, testErrorNotFound.GetMessage(), errors.New("no rows")),
+			wantStatus: 404,
+			want:       Body{Code: 40401, Reason: "user not found", Message: "user does not exist"},
+		},
+		{
+			name:       "joined with a custom message",
+			err:        fmt.Errorf("lookup: %w", joinError(testErrorNotFound, "user u1 does not exist")),
+			wantStatus: 404,
+			want:       Body{Code: 40401, Reason: "user not found", Message: "user u1 does not exist"},
+		},
+		{
+			name:       "joined log message error with a generated cause",
+			err:        joinError(logError(40403), "", testErrorNotFound),
+			wantStatus: 404,
+			want:       Body{Code: 40403, Reason: "user not found", Message: "user not found"},
+		},
+		{
+			name:       "other error",
+			err:        errors.New("db password wrong"),
+			wantStatus: 500,
+			want:       internalError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := FromError(tt.err)
+			if status != tt.wantStatus || body.Code != tt.want.Code || body.Reason != tt.want.Reason || body.Message != tt.want.Message {
+				t.Errorf("FromError = %d %+v, want %d %+v", status, body, tt.wantStatus, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandles(t *testing.T) {
+	if !Handles(fmt.Errorf("lookup: %w", testErrorNotFound)) {
+		t.Error("Handles should see a wrapped generated error")
+	}
+	if Handles(errors.New("db password wrong")) || Handles(nil) {
+		t.Error("Handles should reject errors without a generated error")
+	}
+}
+
+func TestHandlerFunc(t *testing.T) {
+	h := HandlerFunc(func(http.ResponseWriter, *http.Request) error {
+		return metadata.WithField(fmt.Errorf("lookup: %w", testErrorNotFound), "user_id", "u1")
+	})
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rec.Code != 404 {
+		t.Errorf("status = %d, want 404", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json; charset=utf-8" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	var body Body
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if body.Code != 40401 || body.Details["user_id"] != "u1" {
+		t.Errorf("body = %+v", body)
+	}
+}
+
+func TestHandlerFunc_NoError(t *testing.T) {
+	h := HandlerFunc(func(w http.ResponseWriter, _ *http.Request) error {
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	})
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusNoContent || rec.Body.Len() != 0 {
+		t.Errorf("got %d %q", rec.Code, rec.Body.String())
+	}
+}
+
+func TestEncoder(t *testing.T) {
+	var enc Encoder
+	rec := httptest.NewRecorder()
+	enc.Encode(rec, testErrorNotFound)
+	if rec.Code != 404 {
+		t.Errorf("status = %d, want 404", rec.Code)
+	}
+	if status, body := enc.FromError(errors.New("db down")); status != 500 || body.Message != "internal error" {
+		t.Errorf("FromError = %d, %+v", status, body)
+	}
+}
+
+func TestEncoder_Localize(t *testing.T) {
+	enc := Encoder{Localize: func(code int32, langs []string) string {
+		if code == int32(testErrorNotFound) && len(langs) > 0 && langs[0] == "zh-HK" {
+			return "用戶不存在"
+		}
+		return ""
+	}}
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Language", "en;q=0.5, zh-HK")
+	rec := httptest.NewRecorder()
+	enc.EncodeRequest(rec, req, fmt.Errorf("lookup: %w", testErrorNotFound))
+	var body Body
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != 404 || body.Message != "用戶不存在" {
+		t.Errorf("EncodeRequest = %d, %+v, want the zh-HK message", rec.Code, body)
+	}
+
+	req.Header.Set("Accept-Language", "fr")
+	if _, body := enc.FromRequest(req, testErrorNotFound); body.Message != "user does not exist" {
+		t.Errorf("FromRequest message = %q, want the declared message without a translation", body.Message)
+	}
+	if _, body := enc.FromRequest(req, errors.New("db down")); body.Message != "internal error" {
+		t.Errorf("FromRequest message = %q, want internal error", body.Message)
+	}
+}
+
+// classifiedError mimics a generated error enum generated with client_code,
+// grouping testErrorNotFound under client code 404.
+type classifiedError struct{ testError }
+
+func (e classifiedError) ClientCode() int32 {
+	if e.testError == testErrorNotFound {
+		return 404
+	}
+	return e.GetCode()
+}
+
+func TestEncoder_ClientCodes(t *testing.T) {
+	var logged []int32
+	enc := Encoder{
+		ClientCodes: true,
+		LogCode:     func(err error, code, clientCode int32) { logged = append(logged, code, clientCode) },
+		Localize: func(code int32, langs []string) string {
+			if code == int32(testErrorNotFound) {
+				return "用戶不存在"
+			}
+			return ""
+		},
+	}
+	rec := httptest.NewRecorder()
+	enc.Encode(rec, fmt.Errorf("lookup: %w", classifiedError{testErrorNotFound}))
+	var body Body
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Code != 404 || len(logged) != 2 || logged[0] != int32(testErrorNotFound) || logged[1] != 404 {
+		t.Errorf("code = %d, logged %v, want client code 404 and the internal code logged", body.Code, logged)
+	}
+	_, body = enc.FromRequest(httptest.NewRequest(http.MethodGet, "/", nil), classifiedError{testErrorNotFound})
+	if body.Code != 404 || body.Message != "用戶不存在" {
+		t.Errorf("FromRequest = %+v, want the client code and the message of the internal code", body)
+	}
+	if _, body := enc.FromError(joinError(classifiedError{testErrorNotFound}, "user u1 does not exist")); body.Code != 404 {
+		t.Errorf("code of a joined error = %d, want client code 404", body.Code)
+	}
+	logged = nil
+	for _, err := range []error{classifiedError{testErrorNoMsg}, testErrorNotFound} {
+		if _, body := enc.FromError(err); body.Code != err.(interface{ GetCode() int32 }).GetCode() {
+			t.Errorf("code of %v = %d, want its own code", err, body.Code)
+		}
+	}
+	if len(logged) != 0 {
+		t.Errorf("logged %v for errors keeping their code", logged)
+	}
+	if _, body := (Encoder{}).FromError(classifiedError{testErrorNotFound}); body.Code != int32(testErrorNotFound) {
+		t.Errorf("code without ClientCodes = %d, want the internal code", body.Code)
+	}
+}
+
+// cachedError mimics a generated error enum generated with cache_ttl.
+type cachedError int32
+
+func (cachedError) Error() string      { return "user not found" }
+func (cachedError) GetStatus() int32   { return 404 }
+func (e cachedError) GetCode() int32   { return int32(e) }
+func (cachedError) GetMessage() string { return "" }
+func (e cachedError) CacheControl() string {
+	if e == 1 {
+		return "public, max-age=60"
+	}
+	return ""
+}
+
+func TestEncode_CacheControl(t *testing.T) {
+	rec := httptest.NewRecorder()
+	Encode(rec, fmt.Errorf("lookup: %w", cachedError(1)))
+	if cc := rec.Header().Get("Cache-Control"); cc != "public, max-age=60" {
+		t.Errorf("Cache-Control = %q, want public, max-age=60", cc)
+	}
+	rec = httptest.NewRecorder()
+	Encode(rec, joinError(cachedError(1), "user u1 not found", cachedError(2)))
+	if cc := rec.Header().Get("Cache-Control"); cc != "public, max-age=60" {
+		t.Errorf("Cache-Control of a joined error = %q, want public, max-age=60", cc)
+	}
+	for _, err := range []error{cachedError(2), testErrorNotFound, errors.New("db down")} {
+		rec := httptest.NewRecorder()
+		Encoder{}.EncodeRequest(rec, httptest.NewRequest(http.MethodGet, "/", nil), err)
+		if cc := rec.Header().Get("Cache-Control"); cc != "" {
+			t.Errorf("Cache-Control of %v = %q, want none", err, cc)
+		}
+	}
+}
+
+// internalTestError mimics an enum generated with visibility, whose
+// testErrorNoMsg is internal.
+type internalTestError struct{ testError }
+
+func (e internalTestError) IsInternal() bool { return e.testError == testErrorNoMsg }
+
+func TestFromError_Internal(t *testing.T) {
+	status, body := FromError(fmt.Errorf("lookup: %w", internalTestError{testErrorNoMsg}))
+	if status != http.StatusInternalServerError || body.Code != 0 || body.Message != "internal error" {
+		t.Errorf("FromError(internal) = %d, %+v, want 500 and the internal error", status, body)
+	}
+	if status, body := FromError(internalTestError{testErrorNotFound}); status != 404 || body.Code != 40401 {
+		t.Errorf("FromError(public) = %d, %+v, want the error itself", status, body)
+	}
+	status, body = FromError(joinError(internalTestError{testErrorNoMsg}, "db password wrong"))
+	if status != http.StatusInternalServerError || body.Code != 0 || body.Message != "internal error" {
+		t.Errorf("FromError(joined internal) = %d, %+v, want 500 and the internal error", status, body)
+	}
+	minimal := &minimalError{404, 40402, "db password wrong", errors.Join(internalTestError{testErrorNoMsg})}
+	if status, body := FromError(minimal); status != http.StatusInternalServerError || body.Message != "internal error" {
+		t.Errorf("FromError(minimal runtime internal) = %d, %+v, want 500 and the internal error", status, body)
+	}
+}
+
+// typedError mimics an enum generated with grpc_status=true, a domain and
+// http_envelope=aws.
+type typedError struct{ testError }
+
+func (typedError) GetGRPCCode() codes.Code { return codes.NotFound }
+func (typedError) GetDomain() string       { return "users.example.com" }
+func (typedError) AWSErrorType() string    { return "UserNotFoundException" }
+
+func TestEncoder_EnvelopeGoogle(t *testing.T) {
+	enc := Encoder{Envelope: EnvelopeGoogle}
+	rec := httptest.NewRecorder()
+	enc.Encode(rec, metadata.WithField(typedError{testErrorNotFound}, "user_id", "42"))
+	var body GoogleBody
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	want := GoogleStatus{Code: 404, Message: "user does not exist", Status: "NOT_FOUND"}
+	if rec.Code != 404 || body.Error.Code != want.Code || body.Error.Message != want.Message || body.Error.Status != want.Status {
+		t.Errorf("Encode = %d, %+v, want 404 and %+v", rec.Code, body.Error, want)
+	}
+	if len(body.Error.Details) != 1 {
+		t.Fatalf("details = %+v, want one ErrorInfo", body.Error.Details)
+	}
+	info := body.Error.Details[0]
+	if info.Type != googleErrorInfoType || info.Reason != "user not found" || info.Domain != "users.example.com" ||
+		info.Metadata["user_id"] != "42" || info.Metadata["code"] != "40401" {
+		t.Errorf("ErrorInfo = %+v", info)
+	}
+	joined := joinError(typedError{testErrorNotFound}, "user 42 does not exist")
+	if status, body := FromError(joined); googleBody(status, body, joined).Error.Status != "NOT_FOUND" ||
+		googleBody(status, body, joined).Error.Details[0].Domain != "users.example.com" {
+		t.Errorf("google body of a joined error = %+v, want the value's status and domain", googleBody(status, body, joined).Error)
+	}
+
+	// Without GetGRPCCode the status follows the HTTP status; internal and
+	// foreign errors carry no details.
+	if _, body := FromError(testErrorNoMsg); googleBody(404, body, testErrorNoMsg).Error.Status != "NOT_FOUND" {
+		t.Errorf("status of an error without GetGRPCCode, want NOT_FOUND")
+	}
+	for _, err := range []error{errors.New("db down"), internalTestError{testErrorNoMsg}} {
+		status, body := FromError(err)
+		if got := googleBody(status, body, err).Error; got.Code != 500 || got.Status != "INTERNAL" || got.Message != "internal error" || got.Details != nil {
+			t.Errorf("google body of %v = %+v, want the internal error", err, got)
+		}
+	}
+}
+
+func TestEncoder_EnvelopeAWS(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	tests := []struct {
+		err        error
+		wantStatus int
+		want       AWSBody
+	}{
+		{typedError{testErrorNotFound}, 404, AWSBody{Type: "UserNotFoundException", Message: "user does not exist"}},
+		{testErrorNotFound, 404, AWSBody{Type: "UserNotFoundException", Message: "user does not exist"}},
+		{errors.New("db down"), 500, AWSBody{Type: "InternalFailure", Message: "internal error"}},
+		{internalTestError{testErrorNoMsg}, 500, AWSBody{Type: "InternalFailure", Message: "internal error"}},
+		{joinError(typedError{testErrorNoMsg}, "user 42 does not exist"), 404, AWSBody{Type: "UserNotFoundException", Message: "user 42 does not exist"}},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		Encoder{Envelope: EnvelopeAWS}.EncodeRequest(rec, req, tt.err)
+		var body AWSBody
+		if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		if rec.Code != tt.wantStatus || body != tt.want || rec.Header().Get("X-Amzn-ErrorType") != tt.want.Type {
+			t.Errorf("EncodeRequest(%v) = %d, %+v, %q, want %d, %+v", tt.err, rec.Code, body, rec.Header().Get("X-Amzn-ErrorType"), tt.wantStatus, tt.want)
+		}
+	}
+}
+
+func TestAWSTypeFromReason(t *testing.T) {
+	for reason, want := range map[string]string{
+		"user not found":   "UserNotFoundException",
+		"USER_NOT_FOUND":   "USERNOTFOUNDException",
+		"quota-exceeded 2": "QuotaExceeded2Exception",
+		"":                 "InternalFailure",
+	} {
+		if got := awsTypeFromReason(reason); got != want {
+			t.Errorf("awsTypeFromReason(%q) = %q, want %q", reason, got, want)
+		}
+	}
+}
+
+func TestCodeFromHTTP(t *testing.T) {
+	for status := 100; status < 700; status++ {
+		if got, want := codeFromHTTP(status), grpcerrors.CodeFromHTTP(int32(status)); got != want {
+			t.Errorf("codeFromHTTP(%d) = %v, want %v as grpcerrors", status, got, want)
+		}
+	}
+}
+
+// headerError mimics an enum generated with http_header, whose internal
+// value is testErrorNoMsg.
+type headerError struct{ internalTestError }
+
+func (headerError) HTTPHeaders() map[string]string {
+	return map[string]string{
+		"Retry-After":      "{retry_after}",
+		"Www-Authenticate": `Bearer realm="api", scope="{scope}"`,
+		"X-Static":         "on {weekdays",
+	}
+}
+
+func TestEncode_HTTPHeaders(t *testing.T) {
+	rec := httptest.NewRecorder()
+	Encode(rec, metadata.WithField(fmt.Errorf("login: %w", headerError{internalTestError{testErrorNotFound}}), "scope", "users:read"))
+	if got := rec.Header().Get("Www-Authenticate"); got != `Bearer realm="api", scope="users:read"` {
+		t.Errorf("WWW-Authenticate = %q, want the scope filled from the metadata", got)
+	}
+	if got := rec.Header().Get("X-Static"); got != "on {weekdays" {
+		t.Errorf("X-Static = %q, want the unterminated brace kept", got)
+	}
+	if _, ok := rec.Header()["Retry-After"]; ok {
+		t.Error("Retry-After set without its retry_after metadata")
+	}
+
+	rec = httptest.NewRecorder()
+	Encoder{Envelope: EnvelopeAWS}.Encode(rec, metadata.WithField(headerError{internalTestError{testErrorNotFound}}, "retry_after", "30"))
+	if got := rec.Header().Get("Retry-After"); got != "30" {
+		t.Errorf("Retry-After = %q, want 30", got)
+	}
+
+	rec = httptest.NewRecorder()
+	SetHeaders(rec, metadata.WithField(joinError(headerError{internalTestError{testErrorNotFound}}, "login required"), "retry_after", "30"))
+	if got := rec.Header().Get("Retry-After"); got != "30" {
+		t.Errorf("Retry-After of a joined error = %q, want 30", got)
+	}
+
+	rec = httptest.NewRecorder()
+	SetHeaders(rec, metadata.WithField(headerError{internalTestError{testErrorNoMsg}}, "retry_after", "30"))
+	if len(rec.Header()) != 0 {
+		t.Errorf("headers of an internal error = %v, want none", rec.Header())
+	}
+}
+
+func TestFromError_Aggregate(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		want       Body
+	}{
+		{
+			name:       "same error",
+			err:        multierror.Join(testErrorNotFound, metadata.Chain(testErrorNotFound).WithField("item", "2")),
+			wantStatus: 404,
+			want: Body{Code: 40401, Reason: "user not found", Message: "2 errors", Errors: []Body{
+				{Code: 40401, Reason: "user not found", Message: "user does not exist"},
+				{Code: 40401, Reason: "user not found", Message: "user does not exist", Details: map[string]string{"item": "2"}},
+			}},
+		},
+		{
+			name:       "client errors",
+			err:        fmt.Errorf("batch: %w", multierror.Join(testErrorNotFound, conflictError{})),
+			wantStatus: 400,
+			want: Body{Message: "2 errors", Errors: []Body{
+				{Code: 40401, Reason: "user not found", Message: "user does not exist"},
+				{Code: 40901, Reason: "out of stock", Message: "out of stock"},
+			}},
+		},
+		{
+			name:       "internal error",
+			err:        multierror.Join(testErrorNotFound, errors.New("boom")),
+			wantStatus: 500,
+			want: Body{Message: "2 errors", Errors: []Body{
+				{Code: 40401, Reason: "user not found", Message: "user does not exist"},
+				internalError,
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := FromError(tt.err)
+			if status != tt.wantStatus {
+				t.Errorf("status = %d, want %d", status, tt.wantStatus)
+			}
+			if fmt.Sprint(body) != fmt.Sprint(tt.want) {
+				t.Errorf("body = %+v, want %+v", body, tt.want)
+			}
+		})
+	}
+}
+
+// causedError mimics a generated error value wrapping a cause.
+type causedError struct {
+	testError
+	cause error
+}
+
+func (e causedError) Unwrap() error { return e.cause }
+
+// emptyAggregate is a foreign aggregate error with no members.
+type emptyAggregate struct{}
+
+func (emptyAggregate) Error() string   { return "no errors" }
+func (emptyAggregate) Errors() []error { return nil }
+
+func TestFromError_AggregateCause(t *testing.T) {
+	err := fmt.Errorf("import: %w", causedError{testErrorNotFound, multierror.Join(conflictError{}, conflictError{})})
+	status, body := FromError(err)
+	if status != 404 || body.Code != 40401 || len(body.Errors) != 0 {
+		t.Errorf("FromError = %d, %+v, want the generated error wrapping the aggregate", status, body)
+	}
+	if status, body := (Encoder{}).FromError(err); status != 404 || len(body.Errors) != 0 {
+		t.Errorf("Encoder.FromError = %d, %+v, want the generated error wrapping the aggregate", status, body)
+	}
+}
+
+func TestFromError_EmptyAggregate(t *testing.T) {
+	for _, err := range []error{&multierror.Error{}, fmt.Errorf("batch: %w", emptyAggregate{})} {
+		if status, body := FromError(err); status != http.StatusInternalServerError || body.Message != "internal error" {
+			t.Errorf("FromError(%T) = %d, %+v, want the internal error", err, status, body)
+		}
+		w := httptest.NewRecorder()
+		Encode(w, err)
+		if w.Code != http.StatusInternalServerError {
+			t.Errorf("Encode(%T) status = %d, want 500", err, w.Code)
+		}
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		if status, _ := (Encoder{}).FromRequest(r, err); status != http.StatusInternalServerError {
+			t.Errorf("Encoder.FromRequest(%T) status = %d, want 500", err, status)
+		}
+	}
+}
+
+// conflictError mimics a generated error enum with a 409 status.
+type conflictError struct{}
+
+func (conflictError) Error() string      { return "out of stock" }
+func (conflictError) GetStatus() int32   { return 409 }
+func (conflictError) GetCode() int32     { return 40901 }
+func (conflictError) GetMessage() string { return "" }
+
+func TestEncoder_AggregateClientCodes(t *testing.T) {
+	e := Encoder{ClientCodes: true}
+	_, body := e.FromError(multierror.Join(classifiedError{testErrorNotFound}, testErrorNoMsg))
+	if len(body.Errors) != 2 || body.Errors[0].Code != 404 || body.Errors[1].Code != 40402 {
+		t.Errorf("member codes = %+v, want the client code of the first member only", body.Errors)
+	}
+}
+
+func TestBody_Binary(t *testing.T) {
+	tests := []struct {
+		name          string
+		body          Body
+		cbor, msgpack string
+	}{
+		{
+			name: "details",
+			body: Body{Code: 40401, Reason: "user not found", Message: "x", Details: map[string]string{"b": "2", "a": "1"}},
+			cbor: "a4" + "64636f6465199dd1" + "66726561736f6e6e75736572206e6f7420666f756e64" + "676d6573736167656178" +
+				"6764657461696c73a2" + "61616131" + "61626132",
+			msgpack: "84" + "a4636f6465cd9dd1" + "a6726561736f6eae75736572206e6f7420666f756e64" + "a76d657373616765a178" +
+				"a764657461696c7382" + "a161a131" + "a162a132",
+		},
+		{
+			name:    "aggregate",
+			body:    Body{Code: -1, Errors: []Body{{Code: 7, Message: "y"}}},
+			cbor:    "a3" + "64636f646520" + "676d65737361676560" + "666572726f727381" + "a264636f646507676d6573736167656179",
+			msgpack: "83" + "a4636f6465ff" + "a76d657373616765a0" + "a66572726f727391" + "82a4636f646507a76d657373616765a179",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if b, err := tt.body.MarshalCBOR(); err != nil || hex.EncodeToString(b) != tt.cbor {
+				t.Errorf("MarshalCBOR = %x, %v, want %s", b, err, tt.cbor)
+			}
+			if b, err := tt.body.MarshalMsgpack(); err != nil || hex.EncodeToString(b) != tt.msgpack {
+				t.Errorf("MarshalMsgpack = %x, %v, want %s", b, err, tt.msgpack)
+			}
+		})
+	}
+}
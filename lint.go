@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/go-sphere/protoc-gen-sphere-errors/generate/errors"
+	"github.com/go-sphere/protoc-gen-sphere-errors/generator"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+// lintSets lints every file of the descriptor sets named by args, configured
+// by the plugin parameter parameter, and prints the findings to w and failures
+// to errw. It returns the exit code: 0 without findings, 1 with findings and 2
+// on failure.
+func lintSets(args []string, parameter string, w, errw io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprintln(errw, "protoc-gen-sphere-errors: -lint expects descriptor set files, e.g. from buf build -o image.binpb")
+		return 2
+	}
+	findings, err := lintRequest(args, parameter)
+	if err != nil {
+		fmt.Fprintln(errw, "protoc-gen-sphere-errors:", err)
+		return 2
+	}
+	for _, finding := range findings {
+		fmt.Fprintln(w, finding)
+	}
+	if len(findings) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// lintRequest returns the lint findings of every file of the descriptor sets
+// named by names.
+func lintRequest(names []string, parameter string) ([]string, error) {
+	req := &pluginpb.CodeGeneratorRequest{}
+	seen := map[string]bool{}
+	var mappings []string
+	for _, name := range names {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		var set descriptorpb.FileDescriptorSet
+		if err := proto.Unmarshal(data, &set); err != nil {
+			return nil, fmt.Errorf("%s: %v", name, err)
+		}
+		for _, f := range set.File {
+			if seen[f.GetName()] {
+				continue
+			}
+			seen[f.GetName()] = true
+			req.ProtoFile = append(req.ProtoFile, f)
+			req.FileToGenerate = append(req.FileToGenerate, f.GetName())
+			// No Go code is generated, so files without a go_package get a
+			// placeholder to satisfy protogen.
+			if f.GetOptions().GetGoPackage() == "" {
+				mappings = append(mappings, "M"+f.GetName()+"=lint/"+strings.TrimSuffix(f.GetName(), ".proto"))
+			}
+		}
+	}
+	if parameter != "" {
+		mappings = append(mappings, parameter)
+	}
+	req.Parameter = proto.String(strings.Join(mappings, ","))
+	p := generator.NewParams()
+	gen, err := protogen.Options{ParamFunc: p.Set}.New(req)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := p.Config()
+	if err != nil {
+		return nil, err
+	}
+	if err := errors.ResolveOptions(gen.Files, cfg.Errors); err != nil {
+		return nil, err
+	}
+	return errors.Lint(gen.Files, cfg.Errors), nil
+}
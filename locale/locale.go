@@ -0,0 +1,101 @@
+// Package locale is the runtime counterpart of the locale_fallback generator
+// option. The generated ResolveMessage functions walk the languages of a
+// request through Fallbacks, so a client asking for zh-HK can be served the zh
+// or en translation of an error message instead of none at all.
+package locale
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Fallbacks maps a language tag to the tags tried after it, in order. Tags
+// are compared case-insensitively.
+type Fallbacks map[string][]string
+
+// Expand returns langs, in order, each followed by its fallbacks and theirs,
+// without duplicates. A tag without configured fallbacks falls back to its
+// parent, such as zh-Hant for zh-Hant-HK, so the chains only need to name
+// the fallbacks crossing languages or scripts, e.g. zh-HK -> zh -> en.
+func (f Fallbacks) Expand(langs []string) []string {
+	var out []string
+	var walk func(tag string)
+	walk = func(tag string) {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || slices.ContainsFunc(out, func(s string) bool { return strings.EqualFold(s, tag) }) {
+			return
+		}
+		out = append(out, tag)
+		if next, ok := f.lookup(tag); ok {
+			for _, n := range next {
+				walk(n)
+			}
+			return
+		}
+		if i := strings.LastIndexByte(tag, '-'); i > 0 {
+			walk(tag[:i])
+		}
+	}
+	for _, tag := range langs {
+		walk(tag)
+	}
+	return out
+}
+
+// lookup returns the fallbacks of tag, matched case-insensitively.
+func (f Fallbacks) lookup(tag string) ([]string, bool) {
+	if next, ok := f[tag]; ok {
+		return next, true
+	}
+	for k, next := range f {
+		if strings.EqualFold(k, tag) {
+			return next, true
+		}
+	}
+	return nil, false
+}
+
+// ParseAcceptLanguage returns the language tags of an Accept-Language header,
+// most preferred first. Tags with q=0 and the * wildcard are left out, as are
+// malformed entries.
+func ParseAcceptLanguage(header string) []string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+	var tags []weighted
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		tag = strings.TrimSpace(tag)
+		if tag == "" || tag == "*" {
+			continue
+		}
+		q := 1.0
+		for _, p := range strings.Split(params, ";") {
+			if v, ok := strings.CutPrefix(strings.TrimSpace(p), "q="); ok {
+				var err error
+				if q, err = strconv.ParseFloat(v, 64); err != nil {
+					q = 0
+				}
+			}
+		}
+		if q > 0 {
+			tags = append(tags, weighted{tag, q})
+		}
+	}
+	slices.SortStableFunc(tags, func(a, b weighted) int {
+		switch {
+		case a.q > b.q:
+			return -1
+		case a.q < b.q:
+			return 1
+		}
+		return 0
+	})
+	out := make([]string, len(tags))
+	for i, t := range tags {
+		out[i] = t.tag
+	}
+	return out
+}
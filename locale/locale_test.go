@@ -0,0 +1,44 @@
+package locale
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestExpand(t *testing.T) {
+	f := Fallbacks{"zh-HK": {"zh-Hant", "zh"}, "zh": {"en"}}
+	for _, tt := range []struct {
+		langs []string
+		want  []string
+	}{
+		{[]string{"zh-HK"}, []string{"zh-HK", "zh-Hant", "zh", "en"}},
+		{[]string{"ZH-hk", "fr"}, []string{"ZH-hk", "zh-Hant", "zh", "en", "fr"}},
+		{[]string{"zh-Hant-TW"}, []string{"zh-Hant-TW", "zh-Hant", "zh", "en"}},
+		{[]string{"en-GB", "en"}, []string{"en-GB", "en"}},
+		{nil, nil},
+	} {
+		if got := f.Expand(tt.langs); !slices.Equal(got, tt.want) {
+			t.Errorf("Expand(%q) = %q, want %q", tt.langs, got, tt.want)
+		}
+	}
+	cyclic := Fallbacks{"a": {"b"}, "b": {"a"}}
+	if got := cyclic.Expand([]string{"a"}); !slices.Equal(got, []string{"a", "b"}) {
+		t.Errorf("cyclic Expand = %q", got)
+	}
+}
+
+func TestParseAcceptLanguage(t *testing.T) {
+	for _, tt := range []struct {
+		header string
+		want   []string
+	}{
+		{"zh-HK, zh;q=0.8, en;q=0.5", []string{"zh-HK", "zh", "en"}},
+		{"en;q=0.3, fr", []string{"fr", "en"}},
+		{"de;q=0, *;q=0.1, it;q=bad, es", []string{"es"}},
+		{"", []string{}},
+	} {
+		if got := ParseAcceptLanguage(tt.header); !slices.Equal(got, tt.want) {
+			t.Errorf("ParseAcceptLanguage(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
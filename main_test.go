@@ -0,0 +1,23 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLintSets(t *testing.T) {
+	var out, errOut strings.Builder
+	if code := lintSets([]string{"generate/errors/testdata/pb/basic_errors.pb"}, "", &out, &errOut); code != 1 {
+		t.Errorf("exit code = %d, want 1", code)
+	}
+	if !strings.Contains(out.String(), "basic_errors.proto:16: ") {
+		t.Errorf("output = %q, want findings with positions", out.String())
+	}
+	out.Reset()
+	if code := lintSets([]string{"generate/errors/testdata/pb/basic_errors.pb"}, "include_enums=tests.none.*", &out, &errOut); code != 0 || out.Len() != 0 {
+		t.Errorf("exit code = %d, output %q, want 0 and no findings", code, out.String())
+	}
+	if code := lintSets([]string{"generate/errors/testdata/pb/basic_errors.pb"}, "no_such_param=1", &out, &errOut); code != 2 || !strings.Contains(errOut.String(), "no_such_param") {
+		t.Errorf("exit code = %d, stderr %q, want 2 and the invalid parameter", code, errOut.String())
+	}
+}
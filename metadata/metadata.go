@@ -0,0 +1,99 @@
+// Package metadata is the runtime counterpart of the metadata=true generator
+// option. Generated WithMetadata and WithField helpers attach string key/value
+// pairs (request IDs, entity IDs) to an error, and transports read them back
+// with From to render them as structured response details.
+package metadata
+
+import (
+	"errors"
+	"maps"
+)
+
+// Keys of the well-known pairs set by WithRequestID and WithTrace.
+const (
+	RequestIDKey = "request_id"
+	TraceIDKey   = "trace_id"
+	SpanIDKey    = "span_id"
+)
+
+// Error wraps an error with metadata. It is returned by Wrap and by the
+// generated WithMetadata and WithField helpers. It is immutable: its With
+// methods return a new Error on top of e, so one error can be shared and
+// enriched by several goroutines, and pairs can be added in a chain:
+//
+//	return UserError_USER_ERROR_NOT_FOUND.WithRequestID(id).WithField("user_id", uid)
+type Error struct {
+	err error
+	md  map[string]string
+}
+
+// Wrap returns err carrying a copy of md. It returns nil when err is nil.
+func Wrap(err error, md map[string]string) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{err: err, md: maps.Clone(md)}
+}
+
+// Chain returns err wrapped in an Error carrying no metadata yet, ready for
+// its chainable With methods. It returns nil when err is nil.
+func Chain(err error) *Error {
+	if err == nil {
+		return nil
+	}
+	return &Error{err: err}
+}
+
+// WithField returns e carrying the additional pair key=value.
+func (e *Error) WithField(key, value string) *Error {
+	return &Error{err: e, md: map[string]string{key: value}}
+}
+
+// WithRequestID returns e carrying the request ID under RequestIDKey.
+func (e *Error) WithRequestID(id string) *Error {
+	return e.WithField(RequestIDKey, id)
+}
+
+// WithTrace returns e carrying the trace and span IDs under TraceIDKey and
+// SpanIDKey, typically taken from the OpenTelemetry span context.
+func (e *Error) WithTrace(traceID, spanID string) *Error {
+	return &Error{err: e, md: map[string]string{TraceIDKey: traceID, SpanIDKey: spanID}}
+}
+
+// WithField returns err carrying the single pair key=value. Calling it on an
+// error that already carries metadata adds the pair on top of it.
+func WithField(err error, key, value string) error {
+	return Wrap(err, map[string]string{key: value})
+}
+
+// Error returns the message of the wrapped error.
+func (e *Error) Error() string { return e.err.Error() }
+
+// Unwrap returns the wrapped error.
+func (e *Error) Unwrap() error { return e.err }
+
+// Metadata returns the pairs attached directly to e, without those of
+// errors it wraps.
+func (e *Error) Metadata() map[string]string { return maps.Clone(e.md) }
+
+// From returns the metadata attached anywhere in err's chain, merged into a
+// single map. When a key is set more than once the outermost value wins. It
+// returns nil when err carries no metadata.
+func From(err error) map[string]string {
+	var out map[string]string
+	for err != nil {
+		if e, ok := err.(*Error); ok {
+			for k, v := range e.md {
+				if _, seen := out[k]; seen {
+					continue
+				}
+				if out == nil {
+					out = map[string]string{}
+				}
+				out[k] = v
+			}
+		}
+		err = errors.Unwrap(err)
+	}
+	return out
+}
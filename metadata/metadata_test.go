@@ -0,0 +1,81 @@
+package metadata
+
+import (
+	"errors"
+	"maps"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestWrapAndFrom(t *testing.T) {
+	base := errors.New("not found")
+	err := WithField(Wrap(base, map[string]string{"request_id": "r1", "user_id": "u1"}), "user_id", "u2")
+
+	if err.Error() != "not found" {
+		t.Errorf("Error() = %q", err.Error())
+	}
+	if !errors.Is(err, base) {
+		t.Error("errors.Is lost the wrapped error")
+	}
+	md := From(err)
+	if md["request_id"] != "r1" || md["user_id"] != "u2" || len(md) != 2 {
+		t.Errorf("From = %v", md)
+	}
+}
+
+func TestWrapCopiesMetadata(t *testing.T) {
+	md := map[string]string{"k": "v"}
+	err := Wrap(errors.New("x"), md)
+	md["k"] = "changed"
+	if got := From(err)["k"]; got != "v" {
+		t.Errorf("From()[k] = %q, want v", got)
+	}
+}
+
+func TestNil(t *testing.T) {
+	if Wrap(nil, map[string]string{"k": "v"}) != nil {
+		t.Error("Wrap(nil) should be nil")
+	}
+	if From(errors.New("plain")) != nil {
+		t.Error("From of an error without metadata should be nil")
+	}
+}
+
+func TestChain(t *testing.T) {
+	base := errors.New("not found")
+	err := Chain(base).WithRequestID("r1").WithTrace("t1", "s1").WithField("request_id", "r2")
+
+	if err.Error() != "not found" || !errors.Is(err, base) {
+		t.Errorf("chained error lost the wrapped error: %v", err)
+	}
+	want := map[string]string{RequestIDKey: "r2", TraceIDKey: "t1", SpanIDKey: "s1"}
+	if got := From(err); !maps.Equal(got, want) {
+		t.Errorf("From = %v, want %v", got, want)
+	}
+	if Chain(nil) != nil {
+		t.Error("Chain(nil) should be nil")
+	}
+}
+
+func TestConcurrentWith(t *testing.T) {
+	base := Chain(errors.New("not found")).WithRequestID("r1")
+	errs := make([]error, 8)
+	var wg sync.WaitGroup
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = base.WithField("worker", strconv.Itoa(i))
+		}(i)
+	}
+	wg.Wait()
+	if got, want := From(base), map[string]string{RequestIDKey: "r1"}; !maps.Equal(got, want) {
+		t.Errorf("From(base) = %v, want %v", got, want)
+	}
+	for i, err := range errs {
+		if got := From(err)["worker"]; got != strconv.Itoa(i) {
+			t.Errorf("worker %d: From()[worker] = %q", i, got)
+		}
+	}
+}
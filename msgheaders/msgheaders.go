@@ -0,0 +1,126 @@
+// Package msgheaders is the runtime counterpart of the message_headers=true
+// generator option. A producer writes a generated error into the headers of
+// a message bus message, such as a NATS or Kafka message, with Inject, and a
+// consumer reconstructs it with the generated FromMessageHeaders of the
+// producer's Go package, so asynchronous workflows propagate typed failures
+// the way the propagation package does for RPCs.
+package msgheaders
+
+import (
+	"strconv"
+
+	"github.com/go-sphere/protoc-gen-sphere-errors/propagation"
+)
+
+// Header names of the provenance of an error written by Inject.
+const (
+	HeaderCode    = "Sphere-Error-Code"
+	HeaderOrigin  = "Sphere-Error-Origin"
+	HeaderTraceID = "Sphere-Error-Trace-Id"
+	HeaderSpanID  = "Sphere-Error-Span-Id"
+)
+
+// Carrier is the headers of a message. Adapters for the header types of
+// the common clients are provided: Header for NATS, Headers for Kafka.
+type Carrier interface {
+	// Get returns the value of the header key, or "".
+	Get(key string) string
+	// Set sets the header key to value, replacing any previous value.
+	Set(key, value string)
+}
+
+// Inject writes the code, origin and trace context of err into c. It reports
+// false, writing nothing, when the chain of err holds no generated error.
+func Inject(err error, c Carrier) bool {
+	info, ok := propagation.Describe(err)
+	if !ok {
+		return false
+	}
+	c.Set(HeaderCode, strconv.Itoa(int(info.Code)))
+	c.Set(HeaderOrigin, info.Origin)
+	if info.TraceID != "" {
+		c.Set(HeaderTraceID, info.TraceID)
+	}
+	if info.SpanID != "" {
+		c.Set(HeaderSpanID, info.SpanID)
+	}
+	return true
+}
+
+// Extract reads the provenance written by Inject from c. It reports false
+// when c carries no valid error code.
+func Extract(c Carrier) (propagation.Info, bool) {
+	code, err := strconv.ParseInt(c.Get(HeaderCode), 10, 32)
+	if err != nil {
+		return propagation.Info{}, false
+	}
+	return propagation.Info{
+		Code:    int32(code),
+		Origin:  c.Get(HeaderOrigin),
+		TraceID: c.Get(HeaderTraceID),
+		SpanID:  c.Get(HeaderSpanID),
+	}, true
+}
+
+// Raise reconstructs the error written into c by Inject, as a
+// *propagation.Error reporting the UPSTREAM origin and carrying the producer's
+// trace context as metadata. lookup resolves the code to a generated error
+// value, as the generated FromMessageHeaders functions do for their package.
+// It reports false when c carries no error or its code is unknown.
+func Raise(c Carrier, lookup func(code int32) (error, bool)) (error, bool) {
+	info, ok := Extract(c)
+	if !ok {
+		return nil, false
+	}
+	return propagation.Reraise(info, lookup)
+}
+
+// Header adapts headers of the map[string][]string shape, such as
+// nats.Header, to a Carrier: msgheaders.Header(msg.Header). Keys are used
+// as given, since NATS headers are case-sensitive; the first value of a key
+// is read.
+type Header map[string][]string
+
+// Get returns the first value of key.
+func (h Header) Get(key string) string {
+	if v := h[key]; len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+// Set replaces the values of key with value.
+func (h Header) Set(key, value string) { h[key] = []string{value} }
+
+// Headers adapts a list of Kafka record headers to a Carrier. Clients
+// declare their own header type of this shape (kafka-go's kafka.Header,
+// sarama's RecordHeader with []byte keys), converted one by one.
+type Headers []KafkaHeader
+
+// KafkaHeader is one Kafka record header.
+type KafkaHeader struct {
+	Key   string
+	Value []byte
+}
+
+// Get returns the value of the last header named key, the one Kafka clients
+// conventionally read.
+func (h *Headers) Get(key string) string {
+	for i := len(*h) - 1; i >= 0; i-- {
+		if (*h)[i].Key == key {
+			return string((*h)[i].Value)
+		}
+	}
+	return ""
+}
+
+// Set replaces every header named key with a single one holding value.
+func (h *Headers) Set(key, value string) {
+	kept := (*h)[:0]
+	for _, header := range *h {
+		if header.Key != key {
+			kept = append(kept, header)
+		}
+	}
+	*h = append(kept, KafkaHeader{Key: key, Value: []byte(value)})
+}
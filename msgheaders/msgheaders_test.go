@@ -0,0 +1,81 @@
+package msgheaders
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-sphere/protoc-gen-sphere-errors/metadata"
+	"github.com/go-sphere/protoc-gen-sphere-errors/origin"
+	"github.com/go-sphere/protoc-gen-sphere-errors/propagation"
+)
+
+// testError mimics a generated error enum.
+type testError int32
+
+const testErrorNotFound testError = 40401
+
+func (e testError) Error() string  { return "user not found" }
+func (e testError) GetCode() int32 { return int32(e) }
+func (e testError) Origin() string { return origin.Client }
+
+func lookup(code int32) (error, bool) {
+	if code == int32(testErrorNotFound) {
+		return testErrorNotFound, true
+	}
+	return nil, false
+}
+
+func TestInject(t *testing.T) {
+	h := Header{}
+	err := metadata.Chain(fmt.Errorf("consume: %w", testErrorNotFound)).WithTrace("4bf92f35", "00f067aa")
+	if !Inject(err, h) {
+		t.Fatal("Inject reported false")
+	}
+	want := Header{
+		HeaderCode:    {"40401"},
+		HeaderOrigin:  {origin.Client},
+		HeaderTraceID: {"4bf92f35"},
+		HeaderSpanID:  {"00f067aa"},
+	}
+	if fmt.Sprint(h) != fmt.Sprint(want) {
+		t.Errorf("headers = %v, want %v", h, want)
+	}
+	h = Header{}
+	if Inject(errors.New("boom"), h) || len(h) != 0 {
+		t.Errorf("Inject(plain error) wrote %v", h)
+	}
+}
+
+func TestRaise(t *testing.T) {
+	for _, c := range []Carrier{Header{}, &Headers{{Key: HeaderCode, Value: []byte("1")}}} {
+		Inject(metadata.Chain(testErrorNotFound).WithTrace("4bf92f35", "00f067aa"), c)
+		err, ok := Raise(c, lookup)
+		if !ok {
+			t.Fatalf("%T: Raise reported false", c)
+		}
+		if !errors.Is(err, testErrorNotFound) || origin.Of(err) != origin.Upstream {
+			t.Errorf("%T: Raise = %v with origin %q, want testErrorNotFound from upstream", c, err, origin.Of(err))
+		}
+		var pe *propagation.Error
+		if !errors.As(err, &pe) || pe.Info() != (propagation.Info{Code: 40401, Origin: origin.Client, TraceID: "4bf92f35", SpanID: "00f067aa"}) {
+			t.Errorf("%T: Info = %+v", c, pe.Info())
+		}
+	}
+	for _, c := range []Carrier{Header{}, Header{HeaderCode: {"x"}}, Header{HeaderCode: {"1"}}} {
+		if _, ok := Raise(c, lookup); ok {
+			t.Errorf("Raise(%v) reported true", c)
+		}
+	}
+}
+
+func TestHeaders(t *testing.T) {
+	h := Headers{{Key: "a", Value: []byte("1")}, {Key: HeaderCode, Value: []byte("2")}, {Key: HeaderCode, Value: []byte("3")}}
+	if got := h.Get(HeaderCode); got != "3" {
+		t.Errorf("Get = %q, want the last value 3", got)
+	}
+	h.Set(HeaderCode, "4")
+	if len(h) != 2 || h[0].Key != "a" || h.Get(HeaderCode) != "4" {
+		t.Errorf("Set left %v, want a and a single code header", h)
+	}
+}
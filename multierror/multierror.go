@@ -0,0 +1,73 @@
+// Package multierror is the runtime counterpart of the join_errors=true
+// generator option. The generated JoinErrors aggregates several errors, such
+// as the per-item failures of a batch endpoint, into one *Error that keeps
+// each member whole: errors.Is and errors.As match any member, and
+// httperrors renders the aggregate as a body listing the body of every
+// member.
+package multierror
+
+import (
+	"errors"
+	"strings"
+)
+
+// Error is an aggregate of errors, its members, in the order they were
+// joined.
+type Error struct {
+	errs []error
+}
+
+// Join returns an *Error of the non-nil errs, or nil when every err is nil.
+// Unlike errors.Join, an *Error among errs is flattened into its members, so
+// aggregates built step by step render as one list.
+func Join(errs ...error) error {
+	var members []error
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		if e, ok := err.(*Error); ok {
+			members = append(members, e.errs...)
+			continue
+		}
+		members = append(members, err)
+	}
+	if len(members) == 0 {
+		return nil
+	}
+	return &Error{errs: members}
+}
+
+// Error returns the messages of the members, one per line.
+func (e *Error) Error() string {
+	messages := make([]string, len(e.errs))
+	for i, err := range e.errs {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+// Unwrap returns the members, so errors.Is and errors.As match against each
+// of them.
+func (e *Error) Unwrap() []error { return e.errs }
+
+// Errors returns the members. The returned slice must not be modified.
+func (e *Error) Errors() []error { return e.errs }
+
+// coder is the code method of every generated error enum.
+type coder interface {
+	GetCode() int32
+}
+
+// Codes returns the code of the first generated error in the chain of each
+// member, 0 for members holding none.
+func (e *Error) Codes() []int32 {
+	codes := make([]int32, len(e.errs))
+	for i, err := range e.errs {
+		var c coder
+		if errors.As(err, &c) {
+			codes[i] = c.GetCode()
+		}
+	}
+	return codes
+}
@@ -0,0 +1,42 @@
+package multierror
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"testing"
+)
+
+// testError mimics a generated error enum.
+type testError int32
+
+const (
+	testErrorNotFound   testError = 40401
+	testErrorOutOfStock testError = 40901
+)
+
+func (e testError) Error() string  { return fmt.Sprintf("test error %d", int32(e)) }
+func (e testError) GetCode() int32 { return int32(e) }
+
+func TestJoin(t *testing.T) {
+	if err := Join(nil, nil); err != nil {
+		t.Errorf("Join(nil, nil) = %v, want nil", err)
+	}
+	err := Join(fmt.Errorf("item 1: %w", testErrorNotFound), nil, Join(testErrorOutOfStock, io.EOF))
+	var e *Error
+	if !errors.As(err, &e) || len(e.Errors()) != 3 {
+		t.Fatalf("Join = %#v, want an *Error of 3 members", err)
+	}
+	for _, target := range []error{testErrorNotFound, testErrorOutOfStock, io.EOF} {
+		if !errors.Is(err, target) {
+			t.Errorf("errors.Is(err, %v) = false", target)
+		}
+	}
+	if got, want := e.Codes(), []int32{40401, 40901, 0}; !slices.Equal(got, want) {
+		t.Errorf("Codes = %v, want %v", got, want)
+	}
+	if got, want := err.Error(), "item 1: test error 40401\ntest error 40901\nEOF"; got != want {
+		t.Errorf("Error = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,43 @@
+// Package origin is the runtime counterpart of the origin_helpers=true
+// generator option. Generated error enums report through Origin whether the
+// client, the server or an upstream dependency caused them, so SLO tooling can
+// leave client errors out of availability without guessing from HTTP
+// statuses.
+package origin
+
+import "errors"
+
+// Origins reported by generated error enums.
+const (
+	Client   = "CLIENT"
+	Server   = "SERVER"
+	Upstream = "UPSTREAM"
+)
+
+// originer is implemented by error enums generated with origin_helpers=true.
+type originer interface {
+	Origin() string
+}
+
+// Of returns the origin declared by err's chain. Errors that declare none,
+// including plain errors, are reported as Server errors; it returns "" for a
+// nil error.
+func Of(err error) string {
+	if err == nil {
+		return ""
+	}
+	var o originer
+	if errors.As(err, &o) {
+		return o.Origin()
+	}
+	return Server
+}
+
+// IsClientError reports whether err was caused by the client.
+func IsClientError(err error) bool { return Of(err) == Client }
+
+// IsServerError reports whether err was caused by the server itself.
+func IsServerError(err error) bool { return Of(err) == Server }
+
+// IsUpstreamError reports whether err was caused by an upstream dependency.
+func IsUpstreamError(err error) bool { return Of(err) == Upstream }
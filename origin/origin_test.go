@@ -0,0 +1,45 @@
+package origin
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+type testError string
+
+func (e testError) Error() string  { return "test" }
+func (e testError) Origin() string { return string(e) }
+
+func TestOf(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{name: "client", err: testError(Client), want: Client},
+		{name: "wrapped upstream", err: fmt.Errorf("call: %w", testError(Upstream)), want: Upstream},
+		{name: "plain error", err: errors.New("boom"), want: Server},
+		{name: "nil", err: nil, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Of(tt.err); got != tt.want {
+				t.Errorf("Of() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPredicates(t *testing.T) {
+	err := fmt.Errorf("call: %w", testError(Client))
+	if !IsClientError(err) || IsServerError(err) || IsUpstreamError(err) {
+		t.Error("expected only IsClientError")
+	}
+	if !IsServerError(errors.New("boom")) {
+		t.Error("plain errors should be server errors")
+	}
+	if IsServerError(nil) || IsClientError(nil) {
+		t.Error("nil is neither")
+	}
+}
@@ -0,0 +1,85 @@
+// Package problem is the runtime counterpart of the problem_json=true generator
+// option. Generated error enums describe themselves as RFC 9457 problem
+// details through a Problem method, and Encode writes any error as an
+// application/problem+json response:
+//
+//	{"type": "https://errors.example.com/UserError/USER_ERROR_NOT_FOUND",
+//	 "title": "user not found", "status": 404, "detail": "user does not exist",
+//	 "code": 40401}
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// ContentType is the media type of problem details documents.
+const ContentType = "application/problem+json"
+
+// BlankType is the problem type of errors without a more specific one. Its
+// title is the HTTP status phrase.
+const BlankType = "about:blank"
+
+// Details is an RFC 9457 problem details object, extended with the numeric
+// error code.
+type Details struct {
+	Type     string `json:"type,omitempty"`
+	Title    string `json:"title,omitempty"`
+	Status   int    `json:"status,omitempty"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	Code     int32  `json:"code,omitempty"`
+}
+
+// problemer is implemented by error enums generated with problem_json=true.
+type problemer interface {
+	Problem() Details
+}
+
+// internaler is implemented by error enums generated with visibility.
+type internaler interface {
+	IsInternal() bool
+}
+
+// messager is implemented by generated error enums and by the errors their
+// Join helpers return, which carry the message passed to JoinWithMessage.
+type messager interface {
+	GetMessage() string
+}
+
+// FromError returns the problem details of err. Errors whose chain holds a
+// generated error enum value use its Problem method, with the detail of the
+// outermost message of the chain, such as one set with JoinWithMessage; any
+// other error, and errors generated as internal with visibility, are
+// reported as a 500 internal error, so no internal detail leaks to clients.
+func FromError(err error) Details {
+	var p problemer
+	if errors.As(err, &p) {
+		if i, ok := p.(internaler); !ok || !i.IsInternal() {
+			d := p.Problem()
+			var m messager
+			if errors.As(err, &m) && m.GetMessage() != "" {
+				d.Detail = m.GetMessage()
+			}
+			return d
+		}
+	}
+	return Details{
+		Type:   BlankType,
+		Title:  http.StatusText(http.StatusInternalServerError),
+		Status: http.StatusInternalServerError,
+	}
+}
+
+// Encode writes err to w as an application/problem+json response. It does
+// nothing for a nil error.
+func Encode(w http.ResponseWriter, err error) {
+	if err == nil {
+		return
+	}
+	d := FromError(err)
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(d.Status)
+	_ = json.NewEncoder(w).Encode(d)
+}
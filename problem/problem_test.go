@@ -0,0 +1,78 @@
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-sphere/protoc-gen-sphere-errors/statuserror"
+)
+
+// testError mimics a generated error enum.
+type testError int32
+
+func (testError) Error() string { return "user not found" }
+
+func (e testError) Problem() Details {
+	return Details{Type: "https://errors.example.com/user-not-found", Title: "user not found", Status: 404, Detail: "user does not exist", Code: int32(e)}
+}
+
+func TestFromError(t *testing.T) {
+	d := FromError(fmt.Errorf("lookup: %w", testError(40401)))
+	if d.Type != "https://errors.example.com/user-not-found" || d.Status != 404 || d.Code != 40401 {
+		t.Errorf("FromError = %+v", d)
+	}
+	d = FromError(errors.New("db password wrong"))
+	if d.Type != BlankType || d.Title != "Internal Server Error" || d.Status != 500 || d.Detail != "" {
+		t.Errorf("FromError(plain error) = %+v", d)
+	}
+}
+
+func TestFromError_JoinWithMessage(t *testing.T) {
+	err := statuserror.New(404, 40401, "user u1 does not exist", errors.Join(testError(40401), errors.New("no rows")))
+	if d := FromError(fmt.Errorf("lookup: %w", err)); d.Detail != "user u1 does not exist" || d.Code != 40401 {
+		t.Errorf("FromError(joined) = %+v, want the custom message as detail", d)
+	}
+	err = statuserror.New(404, 40401, "", testError(40401))
+	if d := FromError(err); d.Detail != "user does not exist" {
+		t.Errorf("FromError(joined without message) = %+v, want the detail of the value", d)
+	}
+}
+
+// internalTestError mimics an enum generated with visibility, whose values
+// are internal.
+type internalTestError struct{ testError }
+
+func (internalTestError) IsInternal() bool { return true }
+
+func TestFromError_Internal(t *testing.T) {
+	if d := FromError(internalTestError{40401}); d.Type != BlankType || d.Status != 500 || d.Code != 0 {
+		t.Errorf("FromError(internal) = %+v, want the internal error", d)
+	}
+}
+
+func TestEncode(t *testing.T) {
+	rec := httptest.NewRecorder()
+	Encode(rec, testError(40401))
+	if rec.Code != 404 {
+		t.Errorf("status = %d, want 404", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != ContentType {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	var d Details
+	if err := json.Unmarshal(rec.Body.Bytes(), &d); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if d.Detail != "user does not exist" || d.Code != 40401 {
+		t.Errorf("body = %+v", d)
+	}
+
+	rec = httptest.NewRecorder()
+	Encode(rec, nil)
+	if rec.Body.Len() != 0 {
+		t.Error("Encode(nil) should write nothing")
+	}
+}
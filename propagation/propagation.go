@@ -0,0 +1,142 @@
+// Package propagation is the runtime counterpart of the propagation=true
+// generator option. A service encodes a generated error into a header with
+// Encode, and a downstream service, such as a gateway, re-raises it with the
+// generated FromPropagationHeader of the upstream's Go package instead of
+// flattening it to 502, keeping the original code, origin and trace.
+package propagation
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+
+	"github.com/go-sphere/protoc-gen-sphere-errors/metadata"
+	"github.com/go-sphere/protoc-gen-sphere-errors/origin"
+)
+
+// Header is the conventional name of the header carrying an encoded error.
+const Header = "Sphere-Error"
+
+// coder is the method set of generated error enums read by Encode.
+type coder interface {
+	error
+	GetCode() int32
+}
+
+// Info is the provenance of an error carried by a propagation header.
+type Info struct {
+	// Code is the error code of the upstream error.
+	Code int32
+	// Origin is the origin of the upstream error, as reported by origin.Of.
+	Origin string
+	// TraceID and SpanID are the trace context attached to the upstream
+	// error with the metadata package, if any.
+	TraceID string
+	SpanID  string
+}
+
+// Encode returns the header value describing err: the code of the generated
+// error in its chain, its origin and its trace context. It returns "" when
+// the chain holds no generated error.
+func Encode(err error) string {
+	info, ok := Describe(err)
+	if !ok {
+		return ""
+	}
+	v := url.Values{}
+	v.Set("code", strconv.Itoa(int(info.Code)))
+	v.Set("origin", info.Origin)
+	if info.TraceID != "" {
+		v.Set("trace_id", info.TraceID)
+	}
+	if info.SpanID != "" {
+		v.Set("span_id", info.SpanID)
+	}
+	return v.Encode()
+}
+
+// Describe returns the provenance Encode writes for err: the code of the
+// generated error in its chain, its origin and its trace context. It reports
+// false when the chain holds no generated error.
+func Describe(err error) (Info, bool) {
+	var c coder
+	if !errors.As(err, &c) {
+		return Info{}, false
+	}
+	md := metadata.From(err)
+	return Info{
+		Code:    c.GetCode(),
+		Origin:  origin.Of(err),
+		TraceID: md[metadata.TraceIDKey],
+		SpanID:  md[metadata.SpanIDKey],
+	}, true
+}
+
+// Decode parses a header value written by Encode.
+func Decode(h string) (Info, error) {
+	v, err := url.ParseQuery(h)
+	if err != nil {
+		return Info{}, err
+	}
+	code, err := strconv.ParseInt(v.Get("code"), 10, 32)
+	if err != nil {
+		return Info{}, errors.New("propagation: header has no valid code")
+	}
+	return Info{
+		Code:    int32(code),
+		Origin:  v.Get("origin"),
+		TraceID: v.Get("trace_id"),
+		SpanID:  v.Get("span_id"),
+	}, nil
+}
+
+// Error is an upstream error re-raised by Raise. It wraps the local error
+// value of the upstream's code and reports the Upstream origin, since for
+// the re-raising service the upstream caused it.
+type Error struct {
+	err  error
+	info Info
+}
+
+// Error returns the message of the re-raised error value.
+func (e *Error) Error() string { return e.err.Error() }
+
+// Unwrap returns the re-raised error value, carrying the upstream trace
+// context as metadata.
+func (e *Error) Unwrap() error { return e.err }
+
+// Origin reports origin.Upstream.
+func (e *Error) Origin() string { return origin.Upstream }
+
+// Info returns the provenance decoded from the header.
+func (e *Error) Info() Info { return e.info }
+
+// Raise rebuilds the error described by the header value h. lookup resolves
+// the code to a generated error value, as the generated FromPropagationHeader
+// functions do for their package. It reports false when h is not a valid
+// header or its code is unknown.
+func Raise(h string, lookup func(code int32) (error, bool)) (error, bool) {
+	info, err := Decode(h)
+	if err != nil {
+		return nil, false
+	}
+	return Reraise(info, lookup)
+}
+
+// Reraise rebuilds the error described by info, read from a carrier other
+// than a single header, such as the headers of a bus message. lookup
+// resolves the code as for Raise. It reports false when the code is
+// unknown.
+func Reraise(info Info, lookup func(code int32) (error, bool)) (error, bool) {
+	value, ok := lookup(info.Code)
+	if !ok {
+		return nil, false
+	}
+	if info.TraceID != "" || info.SpanID != "" {
+		value = metadata.Wrap(value, map[string]string{
+			metadata.TraceIDKey: info.TraceID,
+			metadata.SpanIDKey:  info.SpanID,
+		})
+	}
+	return &Error{err: value, info: info}, true
+}
@@ -0,0 +1,62 @@
+package propagation
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-sphere/protoc-gen-sphere-errors/metadata"
+	"github.com/go-sphere/protoc-gen-sphere-errors/origin"
+)
+
+// testError mimics a generated error enum.
+type testError int32
+
+const testErrorNotFound testError = 40401
+
+func (e testError) Error() string  { return "user not found" }
+func (e testError) GetCode() int32 { return int32(e) }
+func (e testError) Origin() string { return origin.Client }
+
+func lookup(code int32) (error, bool) {
+	if code == int32(testErrorNotFound) {
+		return testErrorNotFound, true
+	}
+	return nil, false
+}
+
+func TestEncode(t *testing.T) {
+	err := metadata.Chain(fmt.Errorf("get: %w", testErrorNotFound)).WithTrace("4bf92f35", "00f067aa")
+	if got, want := Encode(err), "code=40401&origin=CLIENT&span_id=00f067aa&trace_id=4bf92f35"; got != want {
+		t.Errorf("Encode = %q, want %q", got, want)
+	}
+	if got := Encode(errors.New("boom")); got != "" {
+		t.Errorf("Encode(plain error) = %q, want empty", got)
+	}
+}
+
+func TestRaise(t *testing.T) {
+	h := Encode(metadata.Chain(testErrorNotFound).WithTrace("4bf92f35", "00f067aa"))
+	err, ok := Raise(h, lookup)
+	if !ok {
+		t.Fatal("Raise reported false")
+	}
+	if !errors.Is(err, testErrorNotFound) {
+		t.Errorf("Raise = %v, want it to wrap testErrorNotFound", err)
+	}
+	if origin.Of(err) != origin.Upstream {
+		t.Errorf("origin = %q, want %q", origin.Of(err), origin.Upstream)
+	}
+	var pe *Error
+	if !errors.As(err, &pe) || pe.Info() != (Info{Code: 40401, Origin: origin.Client, TraceID: "4bf92f35", SpanID: "00f067aa"}) {
+		t.Errorf("Info = %+v", pe.Info())
+	}
+	if md := metadata.From(err); md[metadata.TraceIDKey] != "4bf92f35" {
+		t.Errorf("metadata = %v, want the upstream trace", md)
+	}
+	for _, h := range []string{"", "code=x", "code=1", "%zz"} {
+		if _, ok := Raise(h, lookup); ok {
+			t.Errorf("Raise(%q) reported true", h)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+// Package recovery is the runtime counterpart of the recover_error generator
+// option. The generated RecoverAsError and RecoverMiddleware helpers turn a
+// panic into the error value designated for their Go package, joined with a
+// *Panic keeping the panic value and the stack of the panicking goroutine, so
+// every service recovers panics the same way and logs tell where they came
+// from.
+package recovery
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"sync/atomic"
+
+	"github.com/go-sphere/protoc-gen-sphere-errors/metadata"
+)
+
+// PanicTypeKey is the metadata key of the Go type of the panic value, e.g.
+// "runtime.boundsError", set by Wrap.
+const PanicTypeKey = "panic_type"
+
+// depth is the maximum number of frames recorded by New.
+const depth = 64
+
+// Panic is a recovered panic. Its message is the panic value, which
+// transports never render, as the generated helpers join it to a generated
+// error.
+type Panic struct {
+	// Value is the value passed to panic.
+	Value any
+	pcs   []uintptr
+}
+
+// New returns the Panic of v, a value returned by recover, with the stack of
+// the panicking goroutine. It must be called by the deferred function
+// calling recover.
+func New(v any) *Panic {
+	pcs := make([]uintptr, depth)
+	// Skip runtime.Callers, New and the deferred function.
+	n := runtime.Callers(3, pcs)
+	return &Panic{Value: v, pcs: pcs[:n]}
+}
+
+// Error returns the panic value prefixed with "panic: ".
+func (p *Panic) Error() string { return fmt.Sprintf("panic: %v", p.Value) }
+
+// Unwrap returns the panic value when it is an error, such as a
+// runtime.Error.
+func (p *Panic) Unwrap() error {
+	err, _ := p.Value.(error)
+	return err
+}
+
+// StackTrace returns the frames of the panicking goroutine, innermost first,
+// starting at the function that panicked: the frames of the runtime raising
+// the panic are left out.
+func (p *Panic) StackTrace() []runtime.Frame {
+	var out []runtime.Frame
+	frames := runtime.CallersFrames(p.pcs)
+	for {
+		f, more := frames.Next()
+		if len(out) > 0 || !strings.HasPrefix(f.Function, "runtime.") {
+			out = append(out, f)
+		}
+		if !more {
+			return out
+		}
+	}
+}
+
+// Format implements fmt.Formatter. %+v writes the message followed by one
+// "function\n\tfile:line" entry per frame; the other verbs write the message
+// alone.
+func (p *Panic) Format(s fmt.State, verb rune) {
+	switch {
+	case verb == 'v' && s.Flag('+'):
+		_, _ = io.WriteString(s, p.Error())
+		for _, f := range p.StackTrace() {
+			_, _ = fmt.Fprintf(s, "\n%s\n\t%s:%d", f.Function, f.File, f.Line)
+		}
+	case verb == 'q':
+		_, _ = fmt.Fprintf(s, "%q", p.Error())
+	default:
+		_, _ = io.WriteString(s, p.Error())
+	}
+}
+
+// Wrap returns err, the designated error joined with p, carrying the Go type
+// of the panic value under PanicTypeKey, and reports it to the observer
+// installed with SetObserver.
+func Wrap(err error, p *Panic) error {
+	err = metadata.WithField(err, PanicTypeKey, fmt.Sprintf("%T", p.Value))
+	if observe := observer.Load(); observe != nil {
+		(*observe)(err)
+	}
+	return err
+}
+
+// observer is the function installed by SetObserver.
+var observer atomic.Pointer[func(error)]
+
+// SetObserver installs observe, called with every error built by Wrap, to
+// log or count recovered panics; From reads back the panic. A nil observe
+// removes it.
+func SetObserver(observe func(err error)) {
+	if observe == nil {
+		observer.Store(nil)
+		return
+	}
+	observer.Store(&observe)
+}
+
+// From returns the recovered panic in err's chain, or nil when err holds
+// none.
+func From(err error) *Panic {
+	var p *Panic
+	if !errors.As(err, &p) {
+		return nil
+	}
+	return p
+}
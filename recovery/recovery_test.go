@@ -0,0 +1,70 @@
+package recovery
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/go-sphere/protoc-gen-sphere-errors/metadata"
+)
+
+var errDesignated = errors.New("internal error")
+
+// recoverAsError mirrors the generated RecoverAsError.
+func recoverAsError(err *error) {
+	if v := recover(); v != nil {
+		p := New(v)
+		*err = Wrap(errors.Join(errDesignated, p), p)
+	}
+}
+
+func divide(a, b int) (q int, err error) {
+	defer recoverAsError(&err)
+	return a / b, nil
+}
+
+func TestRecover(t *testing.T) {
+	var observed error
+	SetObserver(func(err error) { observed = err })
+	defer SetObserver(nil)
+
+	_, err := divide(1, 0)
+	if !errors.Is(err, errDesignated) {
+		t.Fatalf("err = %v, want the designated error", err)
+	}
+	if observed != err {
+		t.Errorf("observer got %v, want %v", observed, err)
+	}
+	var re runtime.Error
+	if !errors.As(err, &re) {
+		t.Error("the runtime error of the panic should be in the chain")
+	}
+	if got := metadata.From(err)[PanicTypeKey]; got != "runtime.boundsError" && !strings.HasPrefix(got, "runtime.") {
+		t.Errorf("panic type = %q, want a runtime error type", got)
+	}
+	p := From(err)
+	if p == nil {
+		t.Fatal("From() returned no panic")
+	}
+	frames := p.StackTrace()
+	if len(frames) == 0 || !strings.HasSuffix(frames[0].Function, ".divide") {
+		t.Errorf("first frame = %v, want divide", frames)
+	}
+	if s := fmt.Sprintf("%+v", p); !strings.HasPrefix(s, "panic: runtime error: integer divide by zero\n") || !strings.Contains(s, ".divide\n\t") {
+		t.Errorf("%%+v = %q, want the message and the stack", s)
+	}
+	if From(errDesignated) != nil {
+		t.Error("From() of an error without panic should be nil")
+	}
+}
+
+func TestPanic_Unwrap(t *testing.T) {
+	if (&Panic{Value: "boom"}).Unwrap() != nil {
+		t.Error("a non-error panic value should not unwrap")
+	}
+	if got := (&Panic{Value: "boom"}).Error(); got != "panic: boom" {
+		t.Errorf("Error() = %q", got)
+	}
+}
@@ -0,0 +1,222 @@
+// Package registry is the runtime counterpart of the registry=true generator
+// option. Generated files register their error enums from init, and tooling
+// (admin panels, support scripts) can then enumerate every error a binary may
+// return or resolve a raw code back to its definition. Registrations are
+// lazy and safe for concurrent use, so lookups made while packages are still
+// initializing never observe a half-built registry.
+package registry
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ErrorDescriptor describes a single generated error value.
+type ErrorDescriptor struct {
+	// Enum is the fully-qualified proto name of the error enum, e.g.
+	// "shared.v1.UserError".
+	Enum string
+	// Value is the proto name of the enum value, e.g. "USER_ERROR_NOT_FOUND".
+	Value   string
+	Code    int32
+	Status  int32
+	Reason  string
+	Message string
+	// Source is the proto file and line declaring the value, e.g.
+	// "shared/v1/errors.proto:12": SourceLocation as a string.
+	Source string
+	// SourceLocation is the position of the value in its proto file.
+	SourceLocation SourceLocation
+	// Err is the generated enum value itself.
+	Err error
+	// Registrant is the Go import path of the generated package that
+	// registered the value, empty for descriptors passed to Register.
+	Registrant string
+}
+
+// SourceLocation is the position of a declaration in a proto file.
+type SourceLocation struct {
+	// File is the path of the proto file, e.g. "shared/v1/errors.proto".
+	File string
+	// Line is the 1-based line of the declaration, 0 when the generator
+	// saw no source info.
+	Line int
+}
+
+// String returns the location as "file:line", or the file alone without a
+// line.
+func (l SourceLocation) String() string {
+	if l.Line == 0 {
+		return l.File
+	}
+	return l.File + ":" + strconv.Itoa(l.Line)
+}
+
+// source is one registration: the descriptors of an enum, built on first
+// use.
+type source struct {
+	// registrant is the Go import path of the registering package, and enum
+	// the fully-qualified name of the registered enum, empty for descriptors
+	// passed to Register.
+	registrant, enum string
+	load             func() []ErrorDescriptor
+	once             sync.Once
+	descriptors      []ErrorDescriptor
+}
+
+// resolve returns the descriptors of s, loading them on the first call.
+func (s *source) resolve() []ErrorDescriptor {
+	s.once.Do(func() {
+		s.descriptors = s.load()
+		if s.registrant != "" {
+			for i := range s.descriptors {
+				s.descriptors[i].Registrant = s.registrant
+			}
+		}
+		s.load = nil
+	})
+	return s.descriptors
+}
+
+var (
+	mu      sync.RWMutex
+	sources []*source
+	byEnum  = map[string]*source{}
+	// indexed is the number of sources whose descriptors are in all and
+	// byCode.
+	indexed int
+	all     []ErrorDescriptor
+	byCode  = map[int32]int{}
+)
+
+// MustRegister registers the error enum named enum, declared by the Go
+// package registrant, whose descriptors load returns. It is called by
+// generated code from init. Registration only records load: the
+// descriptors are built on the first LookupByCode or All made after it, so
+// the result does not depend on the order packages are initialized in, and
+// every lookup sees every registration made so far.
+//
+// It panics when enum is already registered, naming both registrants, as
+// happens when two copies of a generated package, such as two major versions
+// of a module, are linked into one binary.
+func MustRegister(registrant, enum string, load func() []ErrorDescriptor) {
+	mu.Lock()
+	defer mu.Unlock()
+	if prev, ok := byEnum[enum]; ok {
+		panic(fmt.Sprintf("registry: %s registered by both %s and %s", enum, prev.registrant, registrant))
+	}
+	s := &source{registrant: registrant, enum: enum, load: load}
+	byEnum[enum] = s
+	sources = append(sources, s)
+}
+
+// Register adds descriptors to the registry, without duplicate detection.
+// Generated code calls MustRegister instead.
+func Register(descriptors ...ErrorDescriptor) {
+	mu.Lock()
+	defer mu.Unlock()
+	sources = append(sources, &source{load: func() []ErrorDescriptor { return descriptors }})
+}
+
+// index adds the descriptors of the sources registered since the last call
+// to all and byCode, in registration order. They are loaded outside the
+// lock, so a slow load blocks no other lookup. When two
+// descriptors share a code, LookupByCode keeps returning the one registered
+// first.
+func index() {
+	mu.RLock()
+	pending := sources[indexed:]
+	mu.RUnlock()
+	if len(pending) == 0 {
+		return
+	}
+	for _, s := range pending {
+		s.resolve()
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	for ; indexed < len(sources); indexed++ {
+		for _, d := range sources[indexed].resolve() {
+			if _, ok := byCode[d.Code]; !ok {
+				byCode[d.Code] = len(all)
+			}
+			all = append(all, d)
+		}
+	}
+}
+
+// LookupByCode returns the descriptor registered for code.
+func LookupByCode(code int32) (ErrorDescriptor, bool) {
+	index()
+	mu.RLock()
+	defer mu.RUnlock()
+	i, ok := byCode[code]
+	if !ok {
+		return ErrorDescriptor{}, false
+	}
+	return all[i], true
+}
+
+// All returns every registered descriptor ordered by code, then by enum and
+// value name.
+func All() []ErrorDescriptor {
+	index()
+	mu.RLock()
+	out := append([]ErrorDescriptor(nil), all...)
+	mu.RUnlock()
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Code != out[j].Code {
+			return out[i].Code < out[j].Code
+		}
+		if out[i].Enum != out[j].Enum {
+			return out[i].Enum < out[j].Enum
+		}
+		return out[i].Value < out[j].Value
+	})
+	return out
+}
+
+// Check reports every code registered by more than one enum, naming the
+// values and their registrants, e.g. from a test of the binary asserting its
+// codes are globally unique. LookupByCode resolves such codes to the value
+// registered first.
+func Check() error {
+	var problems []string
+	descriptors := All()
+	for i := 0; i < len(descriptors); {
+		j := i + 1
+		for j < len(descriptors) && descriptors[j].Code == descriptors[i].Code {
+			j++
+		}
+		if shared := descriptors[i:j]; len(shared) > 1 && shared[0].Enum != shared[len(shared)-1].Enum {
+			names := make([]string, len(shared))
+			for k, d := range shared {
+				names[k] = d.Enum + "." + d.Value + " (" + cmp.Or(d.Registrant, "unknown registrant") + ")"
+			}
+			problems = append(problems, fmt.Sprintf("code %d registered by %s", shared[0].Code, strings.Join(names, " and ")))
+		}
+		i = j
+	}
+	if len(problems) > 0 {
+		return errors.New("registry: " + strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// Registry is a handle on the registry for dependency injection: containers
+// such as wire and fx provide it to code that would otherwise call the package
+// functions. Its zero value is ready to use, and every Registry reads the same
+// process-wide registry.
+type Registry struct{}
+
+// LookupByCode returns the descriptor registered for code, as the package
+// function LookupByCode.
+func (Registry) LookupByCode(code int32) (ErrorDescriptor, bool) { return LookupByCode(code) }
+
+// All returns every registered descriptor, as the package function All.
+func (Registry) All() []ErrorDescriptor { return All() }
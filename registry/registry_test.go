@@ -0,0 +1,162 @@
+package registry
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func reset() {
+	mu.Lock()
+	defer mu.Unlock()
+	sources, byEnum, indexed = nil, map[string]*source{}, 0
+	all = nil
+	byCode = map[int32]int{}
+}
+
+func TestRegisterAndLookup(t *testing.T) {
+	reset()
+	notFound := errors.New("not found")
+	Register(
+		ErrorDescriptor{Enum: "tests.UserError", Value: "USER_ERROR_NOT_FOUND", Code: 40401, Status: 404, Err: notFound},
+		ErrorDescriptor{Enum: "tests.UserError", Value: "USER_ERROR_INVALID", Code: 40001, Status: 400},
+	)
+
+	d, ok := LookupByCode(40401)
+	if !ok {
+		t.Fatal("expected descriptor for 40401")
+	}
+	if d.Value != "USER_ERROR_NOT_FOUND" || d.Status != 404 || d.Err != notFound {
+		t.Errorf("LookupByCode(40401) = %+v", d)
+	}
+	if _, ok := LookupByCode(1); ok {
+		t.Error("expected no descriptor for unregistered code")
+	}
+}
+
+func TestLookupKeepsFirstOnDuplicate(t *testing.T) {
+	reset()
+	Register(ErrorDescriptor{Enum: "a.Error", Value: "FIRST", Code: 7})
+	Register(ErrorDescriptor{Enum: "b.Error", Value: "SECOND", Code: 7})
+	if d, _ := LookupByCode(7); d.Value != "FIRST" {
+		t.Errorf("LookupByCode(7).Value = %q, want FIRST", d.Value)
+	}
+	if n := len(All()); n != 2 {
+		t.Errorf("len(All()) = %d, want 2", n)
+	}
+}
+
+func TestMustRegisterLazy(t *testing.T) {
+	reset()
+	loads := 0
+	MustRegister("example.com/userpb", "tests.UserError", func() []ErrorDescriptor {
+		loads++
+		return []ErrorDescriptor{{Enum: "tests.UserError", Value: "USER_ERROR_NOT_FOUND", Code: 40401}}
+	})
+	if loads != 0 {
+		t.Fatalf("MustRegister loaded the descriptors eagerly")
+	}
+	if d, ok := LookupByCode(40401); !ok || d.Registrant != "example.com/userpb" {
+		t.Errorf("LookupByCode(40401) = %+v, %v, want the descriptor of its registrant", d, ok)
+	}
+	MustRegister("example.com/orderpb", "tests.OrderError", func() []ErrorDescriptor {
+		return []ErrorDescriptor{{Enum: "tests.OrderError", Value: "ORDER_ERROR_OUT_OF_STOCK", Code: 40901}}
+	})
+	if _, ok := LookupByCode(40901); !ok {
+		t.Error("a registration after the first lookup is not visible")
+	}
+	All()
+	if loads != 1 {
+		t.Errorf("descriptors loaded %d times, want once", loads)
+	}
+}
+
+func TestMustRegisterDuplicate(t *testing.T) {
+	reset()
+	load := func() []ErrorDescriptor { return nil }
+	MustRegister("example.com/userpb", "tests.UserError", load)
+	defer func() {
+		want := "registry: tests.UserError registered by both example.com/userpb and example.com/userpb/v2"
+		if r := recover(); r != want {
+			t.Errorf("panic = %v, want %q", r, want)
+		}
+	}()
+	MustRegister("example.com/userpb/v2", "tests.UserError", load)
+}
+
+func TestMustRegisterConcurrent(t *testing.T) {
+	reset()
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			MustRegister("example.com/pb", fmt.Sprintf("tests.Error%d", i), func() []ErrorDescriptor {
+				return []ErrorDescriptor{{Enum: fmt.Sprintf("tests.Error%d", i), Value: "V", Code: int32(i)}}
+			})
+		}()
+		go func() {
+			defer wg.Done()
+			LookupByCode(int32(i))
+		}()
+	}
+	wg.Wait()
+	if n := len(All()); n != 8 {
+		t.Errorf("len(All()) = %d, want 8", n)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	reset()
+	MustRegister("example.com/userpb", "tests.UserError", func() []ErrorDescriptor {
+		return []ErrorDescriptor{{Enum: "tests.UserError", Value: "USER_ERROR_NOT_FOUND", Code: 40401}}
+	})
+	if err := Check(); err != nil {
+		t.Fatalf("Check() = %v, want nil", err)
+	}
+	MustRegister("example.com/billingpb", "tests.BillingError", func() []ErrorDescriptor {
+		return []ErrorDescriptor{{Enum: "tests.BillingError", Value: "BILLING_ERROR_NOT_FOUND", Code: 40401}}
+	})
+	want := "registry: code 40401 registered by tests.BillingError.BILLING_ERROR_NOT_FOUND (example.com/billingpb) and tests.UserError.USER_ERROR_NOT_FOUND (example.com/userpb)"
+	if err := Check(); err == nil || err.Error() != want {
+		t.Errorf("Check() = %v, want %q", err, want)
+	}
+}
+
+func TestAllSorted(t *testing.T) {
+	reset()
+	Register(
+		ErrorDescriptor{Enum: "b.Error", Value: "B", Code: 2},
+		ErrorDescriptor{Enum: "a.Error", Value: "Z", Code: 1},
+		ErrorDescriptor{Enum: "a.Error", Value: "A", Code: 2},
+	)
+	got := All()
+	want := []string{"Z", "A", "B"}
+	for i, d := range got {
+		if d.Value != want[i] {
+			t.Errorf("All()[%d].Value = %q, want %q", i, d.Value, want[i])
+		}
+	}
+}
+
+func TestRegistryHandle(t *testing.T) {
+	reset()
+	Register(ErrorDescriptor{Enum: "tests.UserError", Value: "USER_ERROR_NOT_FOUND", Code: 40401})
+	var r Registry
+	if d, ok := r.LookupByCode(40401); !ok || d.Value != "USER_ERROR_NOT_FOUND" {
+		t.Errorf("Registry.LookupByCode(40401) = %+v, %v", d, ok)
+	}
+	if n := len(r.All()); n != 1 {
+		t.Errorf("len(Registry.All()) = %d, want 1", n)
+	}
+}
+
+func TestSourceLocationString(t *testing.T) {
+	if s := (SourceLocation{File: "shared/v1/errors.proto", Line: 12}).String(); s != "shared/v1/errors.proto:12" {
+		t.Errorf("String() = %q, want shared/v1/errors.proto:12", s)
+	}
+	if s := (SourceLocation{File: "shared/v1/errors.proto"}).String(); s != "shared/v1/errors.proto" {
+		t.Errorf("String() without a line = %q, want the file alone", s)
+	}
+}
@@ -0,0 +1,19 @@
+// Package retry is the runtime counterpart of the retry_helpers=true generator
+// option. Generated error enums report through IsRetryable whether a request
+// failing with them is safe to retry, and IsRetryable here answers the same
+// question for any error chain.
+package retry
+
+import "errors"
+
+// retryable is implemented by error enums generated with retry_helpers=true.
+type retryable interface {
+	IsRetryable() bool
+}
+
+// IsRetryable reports whether err's chain holds an error declaring itself
+// retryable. Errors that do not declare it are not retryable.
+func IsRetryable(err error) bool {
+	var r retryable
+	return errors.As(err, &r) && r.IsRetryable()
+}
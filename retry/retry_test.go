@@ -0,0 +1,33 @@
+package retry
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+type testError bool
+
+func (e testError) Error() string     { return "test" }
+func (e testError) IsRetryable() bool { return bool(e) }
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "retryable", err: testError(true), want: true},
+		{name: "wrapped retryable", err: fmt.Errorf("call: %w", testError(true)), want: true},
+		{name: "not retryable", err: testError(false)},
+		{name: "plain error", err: errors.New("boom")},
+		{name: "nil", err: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRetryable(tt.err); got != tt.want {
+				t.Errorf("IsRetryable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
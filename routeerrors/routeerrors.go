@@ -0,0 +1,131 @@
+// Package routeerrors is the runtime counterpart of the routes_out generator
+// option. A router generated by protoc-gen-sphere loads errors.routes.json,
+// mapping each HTTP route to the errors its method declares, and in debug
+// builds checks at startup that it serves exactly the annotated routes with
+// Validate, and that handlers only return declared errors with Check:
+//
+//	m, err := routeerrors.Load(os.DirFS("."), "errors.routes.json")
+//	...
+//	if err := m.Check("GET", "/v1/users/{id}", handlerErr); err != nil {
+//		log.Printf("debug: %v", err)
+//	}
+package routeerrors
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"slices"
+	"strings"
+)
+
+// Manifest is the decoded routes_out output.
+type Manifest struct {
+	Routes []Route `json:"routes"`
+}
+
+// Route is an HTTP route and the errors its method declares.
+type Route struct {
+	// Method is the HTTP method, e.g. "GET".
+	Method string `json:"method"`
+	// Path is the path template, e.g. "/v1/users/{id}".
+	Path string `json:"path"`
+	// Operation is the full gRPC name of the method, e.g.
+	// "/shop.v1.UserService/GetUser".
+	Operation string  `json:"operation"`
+	Errors    []Error `json:"errors"`
+}
+
+// Error is an error value declared by the method of a route.
+type Error struct {
+	// Name is the fully-qualified proto name of the value, e.g.
+	// "shop.v1.USER_ERROR_NOT_FOUND".
+	Name   string `json:"name"`
+	Code   int32  `json:"code"`
+	Status int32  `json:"status"`
+}
+
+// Key identifies a route by its HTTP method and path template.
+type Key struct {
+	Method, Path string
+}
+
+func (k Key) String() string { return k.Method + " " + k.Path }
+
+// Load decodes the manifest named name in fsys.
+func Load(fsys fs.FS, name string) (*Manifest, error) {
+	b, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	var m Manifest
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("routeerrors: decode %s: %w", name, err)
+	}
+	return &m, nil
+}
+
+// Route returns the route of method and path, and false when m has none.
+func (m *Manifest) Route(method, path string) (Route, bool) {
+	for _, r := range m.Routes {
+		if r.Method == method && r.Path == path {
+			return r, true
+		}
+	}
+	return Route{}, false
+}
+
+// Validate reports the routes registered with the router that m does not
+// list, and the routes of m that are not registered, so a router and the
+// manifest generated from other protos are caught at startup.
+func (m *Manifest) Validate(registered []Key) error {
+	var problems []string
+	seen := map[Key]bool{}
+	for _, k := range registered {
+		seen[k] = true
+		if _, ok := m.Route(k.Method, k.Path); !ok {
+			problems = append(problems, fmt.Sprintf("route %s is not in the manifest", k))
+		}
+	}
+	for _, r := range m.Routes {
+		if k := (Key{r.Method, r.Path}); !seen[k] {
+			problems = append(problems, fmt.Sprintf("route %s (%s) is not registered", k, r.Operation))
+		}
+	}
+	if len(problems) > 0 {
+		slices.Sort(problems)
+		return fmt.Errorf("routeerrors: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// coder is the code method of every generated error enum.
+type coder interface {
+	GetCode() int32
+}
+
+// Check reports whether err, returned by the handler of the route of method
+// and path, is one its method declares: it returns nil for a nil err and a
+// declared error, and otherwise an error naming the route and err. Errors
+// whose chain holds no generated error are undeclared too.
+func (m *Manifest) Check(method, path string, err error) error {
+	if err == nil {
+		return nil
+	}
+	k := Key{method, path}
+	r, ok := m.Route(method, path)
+	if !ok {
+		return fmt.Errorf("routeerrors: route %s is not in the manifest", k)
+	}
+	var c coder
+	if !errors.As(err, &c) {
+		return fmt.Errorf("routeerrors: route %s (%s) returned %q, which is no generated error", k, r.Operation, err)
+	}
+	for _, e := range r.Errors {
+		if e.Code == c.GetCode() {
+			return nil
+		}
+	}
+	return fmt.Errorf("routeerrors: route %s (%s) returned undeclared error code %d (%q)", k, r.Operation, c.GetCode(), err)
+}
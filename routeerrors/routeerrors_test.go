@@ -0,0 +1,90 @@
+package routeerrors
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// testError mimics a generated error enum.
+type testError int32
+
+const (
+	testErrorNotFound testError = 2
+	testErrorDenied   testError = 3
+)
+
+func (e testError) Error() string  { return fmt.Sprintf("test error %d", int32(e)) }
+func (e testError) GetCode() int32 { return int32(e) }
+
+const manifest = `{"routes": [
+  {"method": "GET", "path": "/v1/users/{id}", "operation": "/shop.v1.UserService/GetUser",
+   "errors": [{"name": "shop.v1.USER_ERROR_NOT_FOUND", "code": 2, "status": 404}]},
+  {"method": "DELETE", "path": "/v1/users/{id}", "operation": "/shop.v1.UserService/DeleteUser", "errors": []}
+]}`
+
+func load(t *testing.T) *Manifest {
+	t.Helper()
+	m, err := Load(fstest.MapFS{"errors.routes.json": {Data: []byte(manifest)}}, "errors.routes.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestLoad(t *testing.T) {
+	m := load(t)
+	r, ok := m.Route("GET", "/v1/users/{id}")
+	if !ok || r.Operation != "/shop.v1.UserService/GetUser" || len(r.Errors) != 1 || r.Errors[0].Status != 404 {
+		t.Errorf("Route = %+v, %v", r, ok)
+	}
+	if _, err := Load(fstest.MapFS{"bad.json": {Data: []byte("[")}}, "bad.json"); err == nil {
+		t.Error("expected an error for an invalid manifest")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	m := load(t)
+	if err := m.Validate([]Key{{"GET", "/v1/users/{id}"}, {"DELETE", "/v1/users/{id}"}}); err != nil {
+		t.Errorf("Validate = %v", err)
+	}
+	err := m.Validate([]Key{{"GET", "/v1/users/{id}"}, {"POST", "/v1/users"}})
+	for _, want := range []string{
+		"route POST /v1/users is not in the manifest",
+		"route DELETE /v1/users/{id} (/shop.v1.UserService/DeleteUser) is not registered",
+	} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Validate = %v, want it to contain %q", err, want)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	m := load(t)
+	tests := []struct {
+		method, path string
+		err          error
+		wantErr      string
+	}{
+		{"GET", "/v1/users/{id}", nil, ""},
+		{"GET", "/v1/users/{id}", fmt.Errorf("get: %w", testErrorNotFound), ""},
+		{"GET", "/v1/users/{id}", testErrorDenied, "route GET /v1/users/{id} (/shop.v1.UserService/GetUser) returned undeclared error code 3"},
+		{"DELETE", "/v1/users/{id}", testErrorNotFound, "returned undeclared error code 2"},
+		{"GET", "/v1/users/{id}", errors.New("boom"), `returned "boom", which is no generated error`},
+		{"GET", "/v1/orders", testErrorNotFound, "route GET /v1/orders is not in the manifest"},
+	}
+	for _, tt := range tests {
+		err := m.Check(tt.method, tt.path, tt.err)
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("Check(%s %s, %v) = %v", tt.method, tt.path, tt.err, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("Check(%s %s, %v) = %v, want it to contain %q", tt.method, tt.path, tt.err, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+// Package stack is the runtime counterpart of the with_stack=true generator
+// option. The generated constructors wrap the errors they build with Wrap,
+// which records the call stack, so an error logged far from where it was
+// created still tells where it came from. Generating without with_stack
+// leaves the constructors untouched and costs nothing.
+package stack
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+)
+
+// depth is the maximum number of frames recorded by Wrap.
+const depth = 32
+
+// Error wraps an error with the stack of the goroutine that created it. It is
+// returned by Wrap and formats its stack after the message with %+v.
+type Error struct {
+	err error
+	pcs []uintptr
+}
+
+// Wrap returns err carrying the stack of its caller: the first frame is the
+// function calling Wrap, e.g. the generated Join constructor. It returns nil
+// when err is nil and err itself when err already carries a stack, so errors
+// built from one another keep the innermost stack.
+func Wrap(err error) error {
+	if err == nil {
+		return nil
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		return err
+	}
+	pcs := make([]uintptr, depth)
+	// Skip runtime.Callers and Wrap.
+	n := runtime.Callers(2, pcs)
+	return &Error{err: err, pcs: pcs[:n]}
+}
+
+// Error returns the message of the wrapped error.
+func (e *Error) Error() string { return e.err.Error() }
+
+// Unwrap returns the wrapped error.
+func (e *Error) Unwrap() error { return e.err }
+
+// StackTrace returns the frames recorded when e was created, innermost
+// first.
+func (e *Error) StackTrace() []runtime.Frame {
+	var out []runtime.Frame
+	frames := runtime.CallersFrames(e.pcs)
+	for {
+		f, more := frames.Next()
+		out = append(out, f)
+		if !more {
+			return out
+		}
+	}
+}
+
+// Format implements fmt.Formatter. %+v writes the message followed by one
+// "function\n\tfile:line" entry per frame; the other verbs write the message
+// alone.
+func (e *Error) Format(s fmt.State, verb rune) {
+	switch {
+	case verb == 'v' && s.Flag('+'):
+		_, _ = io.WriteString(s, e.Error())
+		for _, f := range e.StackTrace() {
+			_, _ = fmt.Fprintf(s, "\n%s\n\t%s:%d", f.Function, f.File, f.Line)
+		}
+	case verb == 'q':
+		_, _ = fmt.Fprintf(s, "%q", e.Error())
+	default:
+		_, _ = io.WriteString(s, e.Error())
+	}
+}
+
+// From returns the stack recorded anywhere in err's chain, or nil when err
+// carries none.
+func From(err error) []runtime.Frame {
+	var e *Error
+	if !errors.As(err, &e) {
+		return nil
+	}
+	return e.StackTrace()
+}
@@ -0,0 +1,62 @@
+package stack
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func newError() error {
+	return Wrap(errors.New("boom"))
+}
+
+func TestWrap(t *testing.T) {
+	if Wrap(nil) != nil {
+		t.Error("Wrap(nil) should be nil")
+	}
+	base := errors.New("boom")
+	err := Wrap(base)
+	if !errors.Is(err, base) {
+		t.Error("Wrap should keep the wrapped error in the chain")
+	}
+	if err.Error() != "boom" {
+		t.Errorf("Error() = %q, want %q", err.Error(), "boom")
+	}
+}
+
+func TestFrom(t *testing.T) {
+	err := fmt.Errorf("handler: %w", newError())
+	frames := From(err)
+	if len(frames) == 0 {
+		t.Fatal("From() returned no frames")
+	}
+	if !strings.HasSuffix(frames[0].Function, ".newError") {
+		t.Errorf("first frame = %s, want newError", frames[0].Function)
+	}
+	if From(errors.New("boom")) != nil {
+		t.Error("From() of an error without stack should be nil")
+	}
+}
+
+func TestWrap_KeepsInnermostStack(t *testing.T) {
+	inner := newError()
+	outer := Wrap(fmt.Errorf("outer: %w", inner))
+	if got, want := From(outer)[0].Function, From(inner)[0].Function; got != want {
+		t.Errorf("outer stack starts at %s, want the inner %s", got, want)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	err := newError()
+	if got := fmt.Sprintf("%v", err); got != "boom" {
+		t.Errorf("%%v = %q, want %q", got, "boom")
+	}
+	if got := fmt.Sprintf("%q", err); got != `"boom"` {
+		t.Errorf("%%q = %q, want %q", got, `"boom"`)
+	}
+	got := fmt.Sprintf("%+v", err)
+	if !strings.HasPrefix(got, "boom\n") || !strings.Contains(got, "stack_test.go:") {
+		t.Errorf("%%+v = %q, want the message followed by the stack", got)
+	}
+}
@@ -0,0 +1,45 @@
+// Package statuserror is the standard-library runtime of the runtime=stdlib
+// generator option: a plain error carrying the HTTP status, code and message
+// of a generated error, with no dependency outside the standard library.
+package statuserror
+
+// Error is the error returned by the generated Join helpers under
+// runtime=stdlib. Its methods mirror those of the generated error enums, so
+// callers can read the status, code and message through an interface. It has
+// none of the optional methods of the enums, such as GetReason or
+// IsInternal: the encoders of httperrors, grpcerrors and problem look those
+// up on the enum value, which Unwrap reaches.
+type Error struct {
+	status  int32
+	code    int32
+	message string
+	err     error
+}
+
+// New returns an Error wrapping err. It has the signature the generated code
+// expects of new_errors_func.
+func New(status, code int32, message string, err error) error {
+	return &Error{status: status, code: code, message: message, err: err}
+}
+
+// Error returns the message, or the wrapped error's text when the message is
+// empty.
+func (e *Error) Error() string {
+	if e.message == "" && e.err != nil {
+		return e.err.Error()
+	}
+	return e.message
+}
+
+// Unwrap returns the wrapped error, so errors.Is and errors.As reach the
+// generated enum value and any cause joined with it.
+func (e *Error) Unwrap() error { return e.err }
+
+// GetStatus returns the HTTP status.
+func (e *Error) GetStatus() int32 { return e.status }
+
+// GetCode returns the error code.
+func (e *Error) GetCode() int32 { return e.code }
+
+// GetMessage returns the message.
+func (e *Error) GetMessage() string { return e.message }
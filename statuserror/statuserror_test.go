@@ -0,0 +1,48 @@
+package statuserror
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	cause := errors.New("no rows")
+	err := New(404, 40401, "user does not exist", cause)
+
+	if err.Error() != "user does not exist" {
+		t.Errorf("Error() = %q", err.Error())
+	}
+	if !errors.Is(err, cause) {
+		t.Error("errors.Is lost the wrapped error")
+	}
+	var se *Error
+	if !errors.As(err, &se) {
+		t.Fatal("errors.As did not find *Error")
+	}
+	if se.GetStatus() != 404 || se.GetCode() != 40401 || se.GetMessage() != "user does not exist" {
+		t.Errorf("got %d %d %q", se.GetStatus(), se.GetCode(), se.GetMessage())
+	}
+}
+
+func TestErrorFallsBackToWrapped(t *testing.T) {
+	if got := New(500, 1, "", errors.New("boom")).Error(); got != "boom" {
+		t.Errorf("Error() = %q, want boom", got)
+	}
+}
+
+// reasonError mimics a generated error enum value with a reason.
+type reasonError int32
+
+func (reasonError) Error() string     { return "user not found" }
+func (reasonError) GetReason() string { return "USER_NOT_FOUND" }
+
+func TestErrorReachesValue(t *testing.T) {
+	err := New(404, 40401, "user u1 does not exist", errors.Join(reasonError(1), errors.New("no rows")))
+	if _, ok := err.(interface{ GetReason() string }); ok {
+		t.Fatal("*Error has GetReason, want it only on the value")
+	}
+	var r interface{ GetReason() string }
+	if !errors.As(err, &r) || r.GetReason() != "USER_NOT_FOUND" {
+		t.Errorf("errors.As did not reach the reason of the value")
+	}
+}
@@ -0,0 +1,68 @@
+// Package typederror is the runtime of the runtime=typed generator option:
+// an error parametrized over the generated error enum it was built from, so
+// its Code method returns the enum value itself rather than an int32, and
+// switches over it are checked for exhaustiveness by tools such as
+// exhaustive.
+package typederror
+
+import "errors"
+
+// ErrorCode is the method set of the generated error enums Error is
+// parametrized over.
+type ErrorCode interface {
+	~int32
+	error
+	GetStatus() int32
+	GetCode() int32
+	GetMessage() string
+}
+
+// Error is the error returned by the generated Join helpers under
+// runtime=typed. Besides Code, its methods mirror those of the generated error
+// enums, so callers can also read the status, code and message through an
+// interface.
+type Error[T ErrorCode] struct {
+	code    T
+	message string
+	err     error
+}
+
+// New returns an Error of code wrapping err.
+func New[T ErrorCode](code T, message string, err error) *Error[T] {
+	return &Error[T]{code: code, message: message, err: err}
+}
+
+// As returns the first *Error[T] in the chain of err, and false when there
+// is none.
+func As[T ErrorCode](err error) (*Error[T], bool) {
+	var e *Error[T]
+	if !errors.As(err, &e) {
+		return nil, false
+	}
+	return e, true
+}
+
+// Code returns the error enum value the error was built from.
+func (e *Error[T]) Code() T { return e.code }
+
+// Error returns the message, or the wrapped error's text when the message is
+// empty.
+func (e *Error[T]) Error() string {
+	if e.message == "" && e.err != nil {
+		return e.err.Error()
+	}
+	return e.message
+}
+
+// Unwrap returns the wrapped error, so errors.Is and errors.As reach the
+// generated enum value and any cause joined with it.
+func (e *Error[T]) Unwrap() error { return e.err }
+
+// GetStatus returns the HTTP status of Code.
+func (e *Error[T]) GetStatus() int32 { return e.code.GetStatus() }
+
+// GetCode returns the numeric error code of Code.
+func (e *Error[T]) GetCode() int32 { return e.code.GetCode() }
+
+// GetMessage returns the message.
+func (e *Error[T]) GetMessage() string { return e.message }
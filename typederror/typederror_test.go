@@ -0,0 +1,59 @@
+package typederror
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// testError mimics a generated error enum.
+type testError int32
+
+const (
+	testErrorNotFound testError = 2
+	testErrorDenied   testError = 3
+)
+
+func (e testError) Error() string      { return fmt.Sprintf("test error %d", int32(e)) }
+func (e testError) GetStatus() int32   { return 404 }
+func (e testError) GetCode() int32     { return 40400 + int32(e) }
+func (e testError) GetMessage() string { return "" }
+
+func TestNew(t *testing.T) {
+	cause := errors.New("no rows")
+	err := fmt.Errorf("get user: %w", New(testErrorNotFound, "user does not exist", errors.Join(testErrorNotFound, cause)))
+
+	e, ok := As[testError](err)
+	if !ok {
+		t.Fatal("As did not find *Error[testError]")
+	}
+	if e.Code() != testErrorNotFound {
+		t.Errorf("Code() = %v, want testErrorNotFound", e.Code())
+	}
+	if e.GetStatus() != 404 || e.GetCode() != 40402 || e.GetMessage() != "user does not exist" || e.Error() != "user does not exist" {
+		t.Errorf("got %d %d %q %q", e.GetStatus(), e.GetCode(), e.GetMessage(), e.Error())
+	}
+	if !errors.Is(err, testErrorNotFound) || !errors.Is(err, cause) {
+		t.Error("errors.Is lost the wrapped errors")
+	}
+}
+
+// otherError is a second error enum, to check As tells enums apart.
+type otherError int32
+
+func (e otherError) Error() string      { return "other" }
+func (e otherError) GetStatus() int32   { return 500 }
+func (e otherError) GetCode() int32     { return int32(e) }
+func (e otherError) GetMessage() string { return "" }
+
+func TestAs_OtherEnum(t *testing.T) {
+	if _, ok := As[otherError](New(testErrorDenied, "", testErrorDenied)); ok {
+		t.Error("As[otherError] matched an *Error[testError]")
+	}
+}
+
+func TestErrorFallsBackToWrapped(t *testing.T) {
+	if got := New(testErrorDenied, "", errors.New("boom")).Error(); got != "boom" {
+		t.Errorf("Error() = %q, want boom", got)
+	}
+}